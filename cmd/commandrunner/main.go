@@ -110,8 +110,9 @@ func main() {
 			BaseParameters: task.BaseParameters{
 				WorkingDirectory: tempDir,
 			},
-			FilePath: tempFileName,
-			Content:  "Original content in relative file.\nThis line will stay the same.",
+			FilePath:  tempFileName,
+			Content:   "Original content in relative file.\nThis line will stay the same.",
+			Overwrite: true, // happy-write-1 already created this file
 		}),
 		task.NewPatchFileTask("relative-patch-1", "Patch file using relative path", task.PatchFileParameters{
 			BaseParameters: task.BaseParameters{
@@ -267,14 +268,11 @@ func main() {
 
 		// Process and print the single final JSON result
 		fmt.Println("Final Result (JSON):")
-		jsonResult, err := json.MarshalIndent(finalResult, "", "  ") // Pretty print JSON
-		if err != nil {
-			log.Printf("ERROR: Failed to marshal final result to JSON for %s (%s): %v", cmdType, cmdID, err)
+		// Stream the JSON so large ResultData is not buffered a second time
+		if err := finalResult.WriteJSON(os.Stdout); err != nil {
+			log.Printf("ERROR: Failed to write final result as JSON for %s (%s): %v", cmdType, cmdID, err)
 			// Print basic info if JSON fails
-			fmt.Printf("  Fallback Final Result: Status=%s, Msg='%s', Err='%s', DataLen=%d\n",
-				finalResult.Status, finalResult.Message, finalResult.Error, len(finalResult.ResultData))
-		} else {
-			fmt.Println(string(jsonResult))
+			fmt.Printf("  Fallback Final Result: %s\n", task.FormatResult(finalResult, task.ResultFormatText))
 		}
 
 		// Print the task after execution to show mutations
@@ -6,12 +6,18 @@ package task
 import (
 	"bufio"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 )
 
@@ -19,35 +25,69 @@ import (
 const (
 	// Command validation errors
 	errBashInvalidCommandType = "invalid command type: expected BashExecCommand, got %T"
+	errBashInvalidGlob        = "invalid required glob '%s': %w"
+	errBashGlobNoMatch        = "required glob '%s' matched no files"
+	errBashWorkingDirMissing  = "working directory '%s' does not exist"
+	errBashWorkingDirNotDir   = "working directory '%s' is not a directory"
+	errBashNegativeLimit      = "resource limits cannot be negative: cpu_time_limit_seconds=%d, memory_limit_bytes=%d"
+	errBashLimitsUnsupported  = "resource limits are not supported on this platform"
 
 	// Execution setup errors
 	errBashStdoutPipe   = "failed to get stdout pipe: %w"
 	errBashStderrPipe   = "failed to get stderr pipe: %w"
 	errBashStartCommand = "Failed to start command: %v"
+	errBashGatePipe     = "failed to create resource limit pipe: %w"
+	errBashSetLimits    = "Failed to apply resource limits: %v"
 
 	// Status messages
-	msgBashCancelled = "Command execution cancelled."
-	msgBashTimedOut  = "Command execution timed out after %v."
-	msgBashFailed    = "Command failed with exit code %d: %v"
-	msgBashSucceeded = "Command completed successfully in %v."
+	msgBashCancelled  = "Command execution cancelled."
+	msgBashTimedOut   = "Command execution timed out after %v."
+	msgBashFailed     = "Command failed with exit code %d: %v"
+	msgBashNotAllowed = "Command exited with code %d, which is not in the allowed exit codes %v"
+	msgBashCPULimit   = "Command was killed after exceeding its CPU time limit of %ds"
+	msgBashMemLimit   = " The command ran with a memory limit of %d bytes and may have exceeded it."
+	msgBashSucceeded  = "Command completed successfully in %v."
+	msgBashHeartbeat  = "Command still running after %v."
+
+	msgBashOutputTruncated = " Output truncated at %d bytes."
+	msgBashExitCode        = " Exit code: %d."
+	msgBashBackgroundPID   = " Background process PID: %d."
+	msgBashNoBackgroundPID = " No background process was started."
+
+	errBashReadPID  = "failed to read background process PID: %w"
+	errBashWritePID = "failed to write PID file '%s': %w"
+
+	// defaultBashTimeout bounds command execution when no DefaultTimeout is configured.
+	defaultBashTimeout = 5 * time.Minute
 )
 
 // BashExecExecutor handles the execution of BashExecCommand.
 // It implements the CommandExecutor interface for shell command execution.
 type BashExecExecutor struct {
-	// Dependencies can be added here if needed later, e.g., logger.
+	config ExecutorConfig
 }
 
+var _ TaskExecutor = (*BashExecExecutor)(nil)
+
 // NewBashExecExecutor creates a new BashExecExecutor.
 func NewBashExecExecutor() *BashExecExecutor {
 	return &BashExecExecutor{}
 }
 
+// NewBashExecExecutorWithConfig creates a new BashExecExecutor using the shared executor config.
+// A configured RootDir becomes the directory the command runs in, and DefaultTimeout
+// replaces the internal execution timeout.
+func NewBashExecExecutorWithConfig(cfg ExecutorConfig) *BashExecExecutor {
+	return &BashExecExecutor{config: cfg}
+}
+
 // bashScriptTemplate is the template used to wrap user commands in a bash script.
 // It sets up error handling and reporting through the EXIT trap.
-// The template expects two format arguments:
-// 1. A command ID to use in the temporary CWD file
-// 2. The actual bash command(s) to execute
+// The template expects four format arguments:
+// 1. The shell-quoted path of the temporary CWD file
+// 2. Extra trap commands, such as the one recording the background PID
+// 3. Commands holding the script back until its resource limits are applied
+// 4. The actual bash command(s) to execute
 const bashScriptTemplate = `#!/bin/bash
 
 # --- Configuration ---
@@ -64,8 +104,8 @@ report_final_cwd() {
   echo "# Final Working Directory: $(pwd -P)" >&2
   echo "############################################" >&2
   # Write final CWD to a temporary file for the Go process to read
-  echo "$(pwd -P)" > /tmp/%s.cwd
-}
+  echo "$(pwd -P)" > %s
+%s}
 trap report_final_cwd EXIT
 
 # --- Main Script Logic ---
@@ -73,7 +113,7 @@ trap report_final_cwd EXIT
 echo "Starting main script execution..." >&2 
 echo "Initial directory: $(pwd)" >&2
 echo "---" >&2
-
+%s
 # === YOUR BASH COMMANDS START HERE ===
 
 %s
@@ -104,6 +144,22 @@ func (e *BashExecExecutor) Execute(ctx context.Context, bashCmd *Task) (<-chan O
 		return terminalChan, err
 	}
 
+	transform, err := lookupLineTransform(bashCmd.Parameters.(BashExecParameters).LineTransform)
+	if err != nil {
+		return nil, err
+	}
+	for _, pattern := range bashCmd.Parameters.(BashExecParameters).RequiredGlobs {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf(errBashInvalidGlob, pattern, err)
+		}
+	}
+
+	if params := bashCmd.Parameters.(BashExecParameters); params.CPUTimeLimitSeconds < 0 || params.MemoryLimitBytes < 0 {
+		return nil, fmt.Errorf(errBashNegativeLimit, params.CPUTimeLimitSeconds, params.MemoryLimitBytes)
+	} else if params.limitsResources() && !resourceLimitsSupported {
+		return nil, invalidf(errBashLimitsUnsupported)
+	}
+
 	// Buffered channel (size 1) for streaming results + final status.
 	// Buffer allows final send even if receiver isn't immediately ready.
 	results := make(chan OutputResult, 1)
@@ -111,40 +167,99 @@ func (e *BashExecExecutor) Execute(ctx context.Context, bashCmd *Task) (<-chan O
 	// Start execution and streaming in a goroutine
 	go func() {
 		defer close(results)
+		startedAt := e.config.clock().Now()
 
 		// Update task status to Running
 		bashCmd.Status = StatusRunning
 
-		// Setup context with timeout
-		const internalTimeout = 5 * time.Minute
-		execCtx, cancel := context.WithTimeout(ctx, internalTimeout)
+		// Setup context with the task deadline and timeout
+		ctx, cancelDeadline := e.config.withDeadline(ctx, bashCmd)
+		defer cancelDeadline()
+		internalTimeout := defaultBashTimeout
+		if e.config.DefaultTimeout > 0 {
+			internalTimeout = e.config.DefaultTimeout
+		}
+		execCtx, cancel := withClockDeadline(ctx, e.config.clock(), e.config.clock().Now().Add(internalTimeout))
 		defer cancel() // Ensure resources associated with the timeout context are released
 
+		// Fail before starting the command if a glob it relies on matches nothing
+		if err := e.checkRequiredGlobs(bashCmd.Parameters.(BashExecParameters)); err != nil {
+			finalResult := e.CreateErrorResult(bashCmd, err)
+			bashCmd.Status = StatusFailed
+			finalResult.setTimes(e.config.clock(), startedAt)
+			bashCmd.UpdateOutput(&finalResult)
+			e.config.send(ctx, results, finalResult)
+			return
+		}
+
 		// Setup command with pipes for output
-		execCmd, combinedPipe, err := setupCommand(execCtx, bashCmd)
+		cwdFilePath := filepath.Join(e.config.tempDir(), bashCmd.TaskId+".cwd")
+		pidFilePath := filepath.Join(e.config.tempDir(), bashCmd.TaskId+".pid")
+		execCmd, combinedPipe, err := setupCommand(execCtx, bashCmd, cwdFilePath, pidFilePath)
 		if err != nil {
 			finalResult := createErrorResult(bashCmd, err.Error())
 			// Update task output
 			bashCmd.Status = StatusFailed
+			finalResult.setTimes(e.config.clock(), startedAt)
 			bashCmd.UpdateOutput(&finalResult)
-			results <- finalResult
+			e.config.send(ctx, results, finalResult)
 			return
 		}
+		if dir := bashCmd.Parameters.(BashExecParameters).WorkingDirectory; dir != "" {
+			resolved, err := e.config.resolvePath(dir, "")
+			if err == nil {
+				err = checkWorkingDirectory(resolved)
+			}
+			if err != nil {
+				finalResult := e.CreateErrorResult(bashCmd, err)
+				bashCmd.Status = StatusFailed
+				finalResult.setTimes(e.config.clock(), startedAt)
+				bashCmd.UpdateOutput(&finalResult)
+				e.config.send(ctx, results, finalResult)
+				return
+			}
+			execCmd.Dir = resolved
+		} else if e.config.RootDir != "" {
+			execCmd.Dir = e.config.RootDir
+		}
+		e.config.logf("bash task %s: starting command", bashCmd.TaskId)
 
 		// Start command execution and track time
-		startTime := time.Now()
-		if err := execCmd.Start(); err != nil {
-			finalResult := createErrorResult(bashCmd, fmt.Sprintf(errBashStartCommand, err))
+		startTime := e.config.clock().Now()
+		gate, err := holdCommand(execCmd, bashCmd.Parameters.(BashExecParameters))
+		if err == nil {
+			err = execCmd.Start()
+			if err != nil {
+				gate.abort()
+				err = fmt.Errorf(errBashStartCommand, err)
+			}
+		}
+		if err == nil {
+			if err = gate.release(execCmd.Process.Pid); err != nil {
+				// The script exits on its own once the gate closes; make sure it does
+				execCmd.Process.Kill()
+				execCmd.Wait()
+				err = fmt.Errorf(errBashSetLimits, err)
+			}
+		}
+		if err != nil {
+			finalResult := createErrorResult(bashCmd, err.Error())
 			// Update task output
 			bashCmd.Status = StatusFailed
+			finalResult.setTimes(e.config.clock(), startedAt)
 			bashCmd.UpdateOutput(&finalResult)
-			results <- finalResult
+			e.config.send(ctx, results, finalResult)
 			return
 		}
 
+		stopHeartbeat := e.startHeartbeat(execCtx, bashCmd, startTime, results)
+
 		// Stream command output to results channel
 		var readerWg sync.WaitGroup
-		streamCommandOutput(execCtx, combinedPipe, bashCmd, results, &readerWg)
+		budget := newOutputBudget(e.config.MaxOutputBytes)
+		captureOutput := bashCmd.Parameters.(BashExecParameters).capturesOutput()
+		jsonLines := bashCmd.Parameters.(BashExecParameters).JSONLines
+		e.streamCommandOutput(execCtx, combinedPipe, bashCmd, results, &readerWg, budget, captureOutput, transform, jsonLines)
 
 		// Wait for reader goroutine to finish, respecting context cancellation
 		waitErr := waitGroupWithContext(execCtx, &readerWg)
@@ -155,30 +270,175 @@ func (e *BashExecExecutor) Execute(ctx context.Context, bashCmd *Task) (<-chan O
 
 		// Wait for command completion and process final status
 		waitErr = execCmd.Wait() // This will return an error if the context caused termination
-		duration := time.Since(startTime)
+		stopHeartbeat()
+		duration := e.config.since(startTime)
 
 		// Send final result
-		finalResult := processFinalResult(execCtx, execCmd, bashCmd, waitErr, duration, internalTimeout)
+		finalResult := processFinalResult(execCtx, execCmd, bashCmd, cwdFilePath, waitErr, duration, internalTimeout)
+		if budget.truncated() {
+			finalResult.Truncated = true
+			finalResult.Message += fmt.Sprintf(msgBashOutputTruncated, e.config.MaxOutputBytes)
+		}
+		if params := bashCmd.Parameters.(BashExecParameters); params.capturesPID() {
+			e.reportBackgroundPID(&finalResult, params, pidFilePath)
+		}
+		if !captureOutput && execCmd.ProcessState != nil && execCtx.Err() == nil {
+			// Without streamed output the exit code is the only signal callers get
+			finalResult.Message += fmt.Sprintf(msgBashExitCode, execCmd.ProcessState.ExitCode())
+		}
+		e.config.logf("bash task %s: finished with status %s", bashCmd.TaskId, finalResult.Status)
 
 		// Update task status and output
 		bashCmd.Status = finalResult.Status
+		finalResult.setTimes(e.config.clock(), startedAt)
 		bashCmd.UpdateOutput(&finalResult)
 
-		results <- finalResult
+		e.config.send(ctx, results, finalResult)
 	}()
 
 	return results, nil
 }
 
+// checkRequiredGlobs expands each of params.RequiredGlobs and returns a validation
+// error for the first one that matches no files.
+func (e *BashExecExecutor) checkRequiredGlobs(params BashExecParameters) error {
+	for _, pattern := range params.RequiredGlobs {
+		resolved, err := e.config.resolvePath(pattern, params.WorkingDirectory)
+		if err != nil {
+			return fmt.Errorf(errBashInvalidGlob, pattern, err)
+		}
+		matches, err := filepath.Glob(resolved)
+		if err != nil {
+			return invalidf(errBashInvalidGlob, pattern, err)
+		}
+		if len(matches) == 0 {
+			return invalidf(errBashGlobNoMatch, pattern)
+		}
+	}
+	return nil
+}
+
+// checkWorkingDirectory verifies that dir exists and is a directory, so that a bad
+// working directory is reported as such rather than as a failure to start bash.
+func checkWorkingDirectory(dir string) error {
+	info, err := os.Stat(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return invalidf(errBashWorkingDirMissing, dir)
+	}
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return invalidf(errBashWorkingDirNotDir, dir)
+	}
+	return nil
+}
+
+// limitGate holds a command's script back until its resource limits are applied, so
+// that the limits cover everything the command does while being set on the process
+// from outside, where the command cannot raise them again. The script blocks reading
+// fd 3, the read end of a pipe, until the executor writes to it.
+type limitGate struct {
+	params BashExecParameters
+	r, w   *os.File
+}
+
+// gateCommands returns the script lines waiting on the gate, if the task sets limits.
+func gateCommands(params BashExecParameters) string {
+	if !params.limitsResources() {
+		return ""
+	}
+	return "read -r -u 3 _ || exit 1\nexec 3<&-\n"
+}
+
+// holdCommand passes a gate to execCmd as fd 3. It returns a nil gate, whose methods
+// do nothing, when the task sets no limits.
+func holdCommand(execCmd *exec.Cmd, params BashExecParameters) (*limitGate, error) {
+	if !params.limitsResources() {
+		return nil, nil
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf(errBashGatePipe, err)
+	}
+	execCmd.ExtraFiles = []*os.File{r}
+	return &limitGate{params: params, r: r, w: w}, nil
+}
+
+// release applies the limits to the started process pid and lets its script continue.
+// On error the gate is closed without releasing it, and the script exits.
+func (g *limitGate) release(pid int) error {
+	if g == nil {
+		return nil
+	}
+	defer g.w.Close()
+	g.r.Close()
+	if err := setProcessLimits(pid, g.params); err != nil {
+		return err
+	}
+	_, err := g.w.Write([]byte("\n"))
+	return err
+}
+
+// abort closes the gate of a command that failed to start.
+func (g *limitGate) abort() {
+	if g != nil {
+		g.r.Close()
+		g.w.Close()
+	}
+}
+
+// exceededCPULimit reports whether the command, or the process it was running when
+// bash exited, was killed for exceeding the task's CPU time limit. The kernel sends
+// SIGXCPU at the limit; SIGKILL only counts once the CPU time the command used, as
+// measured when it was reaped, has reached the limit, so that a command killed
+// from elsewhere is not reported as over its limit.
+func exceededCPULimit(params BashExecParameters, exitErr *exec.ExitError) bool {
+	if params.CPUTimeLimitSeconds <= 0 {
+		return false
+	}
+	var signal syscall.Signal
+	if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		signal = status.Signal()
+	} else if code := exitErr.ExitCode(); code > 128 {
+		// bash reports a child killed by a signal as 128 plus the signal number
+		signal = syscall.Signal(code - 128)
+	}
+	switch signal {
+	case syscall.SIGXCPU:
+		return true
+	case syscall.SIGKILL:
+		used := exitErr.UserTime() + exitErr.SystemTime()
+		return used >= time.Duration(params.CPUTimeLimitSeconds)*time.Second
+	}
+	return false
+}
+
 // setupCommand prepares the exec.Command for execution with the bash script.
+// When the task captures a PID, the script's exit trap writes it to pidFilePath.
 // It configures stdout and stderr pipes and returns the command, a combined reader for
 // stdout and stderr, and any error that occurred during setup.
-func setupCommand(ctx context.Context, bashCmd *Task) (*exec.Cmd, io.Reader, error) {
+func setupCommand(ctx context.Context, bashCmd *Task, cwdFilePath, pidFilePath string) (*exec.Cmd, io.Reader, error) {
 	// Construct the full script
-	fullScript := fmt.Sprintf(bashScriptTemplate, bashCmd.TaskId, bashCmd.Parameters.(BashExecParameters).Command)
+	params := bashCmd.Parameters.(BashExecParameters)
+	trapCommands := ""
+	if params.capturesPID() {
+		// $! is empty when the command started no background process
+		trapCommands = fmt.Sprintf("  echo \"${!:-}\" > %s\n", shellQuote(pidFilePath))
+	}
+	fullScript := fmt.Sprintf(bashScriptTemplate, shellQuote(cwdFilePath), trapCommands, gateCommands(params), params.Command)
+
+	// A login shell reads the profile files first, picking up their PATH and functions
+	flags := "-c"
+	if bashCmd.Parameters.(BashExecParameters).LoginShell {
+		flags = "-lc"
+	}
 
 	// Prepare command for streaming using the execution context
-	execCmd := exec.CommandContext(ctx, "/bin/bash", "-c", fullScript)
+	execCmd := exec.CommandContext(ctx, "/bin/bash", flags, fullScript)
+	if env := bashCmd.Parameters.(BashExecParameters).Env; len(env) > 0 {
+		execCmd.Env = mergeEnv(os.Environ(), env)
+	}
 
 	stdoutPipe, err := execCmd.StdoutPipe()
 	if err != nil {
@@ -199,8 +459,14 @@ func setupCommand(ctx context.Context, bashCmd *Task) (*exec.Cmd, io.Reader, err
 // streamCommandOutput reads from the provided reader and sends each line to the results channel.
 // The function respects context cancellation and reports errors appropriately.
 // It uses the provided WaitGroup to signal when all output has been processed.
-func streamCommandOutput(ctx context.Context, reader io.Reader, cmd *Task,
-	results chan<- OutputResult, wg *sync.WaitGroup) {
+// Output beyond the budget is read and discarded so the command never blocks on a full pipe.
+// When forward is false, output is still consumed and counted against the budget
+// but no RUNNING results are sent.
+// A non-nil transform is applied to each line before it is counted and forwarded.
+// With jsonLines, a line holding a JSON object or array is forwarded with the decoded
+// value as its Payload.
+func (e *BashExecExecutor) streamCommandOutput(ctx context.Context, reader io.Reader, cmd *Task,
+	results chan<- OutputResult, wg *sync.WaitGroup, budget *outputBudget, forward bool, transform func(string) string, jsonLines bool) {
 
 	wg.Add(1)
 	go func() {
@@ -208,7 +474,15 @@ func streamCommandOutput(ctx context.Context, reader io.Reader, cmd *Task,
 		scanner := bufio.NewScanner(reader)
 
 		for scanner.Scan() {
-			line := scanner.Text()
+			text := scanner.Text()
+			if transform != nil {
+				text = transform(text)
+			}
+			// Add newline back as scanner strips it
+			line, ok := budget.take(text + "\n")
+			if !ok || line == "" || !forward {
+				continue
+			}
 			// Check if the context was cancelled before sending the next line
 			select {
 			case <-ctx.Done():
@@ -216,30 +490,53 @@ func streamCommandOutput(ctx context.Context, reader io.Reader, cmd *Task,
 				return
 			default:
 				// Context still active, send the result
-				results <- OutputResult{
+				result := OutputResult{
 					TaskID:     cmd.TaskId,
 					Status:     StatusRunning,
-					ResultData: line + "\n", // Add newline back as scanner strips it
+					ResultData: line,
 				}
+				if jsonLines {
+					result.Payload, _ = parseJSONLine(line)
+				}
+				e.config.send(ctx, results, cmd.describe(result))
 			}
 		}
 
 		scannerErr := scanner.Err()
 		if scannerErr != nil && ctx.Err() == nil {
 			// Don't send error if context was cancelled, as that's the primary error
-			results <- createErrorResult(cmd, fmt.Sprintf("Error reading command output: %v", scannerErr))
+			e.config.send(ctx, results, createErrorResult(cmd, fmt.Sprintf("Error reading command output: %v", scannerErr)))
 		}
 	}()
 }
 
+// parseJSONLine decodes line if it holds a single JSON object or array.
+// Scalars and anything that is not valid JSON are reported as not parsed.
+func parseJSONLine(line string) (any, bool) {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+		return nil, false
+	}
+	var value any
+	if err := json.Unmarshal([]byte(trimmed), &value); err != nil {
+		return nil, false
+	}
+	return value, true
+}
+
 // processFinalResult determines the final status of a command execution and creates
 // an appropriate OutputResult. It handles various error conditions including timeouts,
 // cancellations, and command execution failures.
 // It also attempts to read the final working directory from the temporary file.
-func processFinalResult(ctx context.Context, cmd *exec.Cmd, bashCmd *Task,
+func processFinalResult(ctx context.Context, cmd *exec.Cmd, bashCmd *Task, cwdFilePath string,
 	waitErr error, duration time.Duration, timeout time.Duration) OutputResult {
 
+	params := bashCmd.Parameters.(BashExecParameters)
 	finalStatus := StatusSucceeded // Assume success initially
+	var failure FailureKind
+	// Exit status of a command that exited on its own
+	var exitCode int
+	var exited bool
 	errMsg := ""
 	message := fmt.Sprintf(msgBashSucceeded, duration.Round(time.Millisecond))
 
@@ -247,26 +544,47 @@ func processFinalResult(ctx context.Context, cmd *exec.Cmd, bashCmd *Task,
 	contextErr := ctx.Err()
 	if contextErr == context.DeadlineExceeded {
 		finalStatus = StatusFailed
+		failure = FailureTimedOut
 		errMsg = fmt.Sprintf(msgBashTimedOut, timeout)
 		message = "Command execution timed out."
 	} else if contextErr == context.Canceled {
 		finalStatus = StatusFailed
+		failure = FailureCancelled
 		errMsg = msgBashCancelled
 		message = "Command execution cancelled."
+	} else if exitErr, ok := waitErr.(*exec.ExitError); ok && params.allowsExitCode(exitErr.ExitCode()) {
+		// A non-zero exit the task expects, such as grep finding no match
+		exitCode = exitErr.ExitCode()
+		exited = true
 	} else if waitErr != nil {
 		// Context was okay, so this is a command execution error (like non-zero exit)
 		finalStatus = StatusFailed
+		failure = FailureExecutionError
 		if exitErr, ok := waitErr.(*exec.ExitError); ok {
 			errMsg = fmt.Sprintf(msgBashFailed, exitErr.ExitCode(), waitErr.Error())
+			if exceededCPULimit(params, exitErr) {
+				errMsg = fmt.Sprintf(msgBashCPULimit, params.CPUTimeLimitSeconds)
+			} else if params.MemoryLimitBytes > 0 {
+				errMsg += fmt.Sprintf(msgBashMemLimit, params.MemoryLimitBytes)
+			}
+			exitCode = exitErr.ExitCode()
+			exited = exitCode >= 0
 		} else {
 			// Other errors (e.g., I/O problems reported by Wait)
 			errMsg = fmt.Sprintf("Command execution failed after wait: %v", waitErr)
 		}
 		message = "Command execution failed."
+	} else if !params.allowsExitCode(0) {
+		finalStatus = StatusFailed
+		failure = FailureExecutionError
+		errMsg = fmt.Sprintf(msgBashNotAllowed, 0, params.AllowedExitCodes)
+		message = "Command execution failed."
+		exited = true
+	} else {
+		exited = true
 	}
 
 	// Read CWD file (attempt even on error/cancel, might have been written before kill)
-	cwdFilePath := fmt.Sprintf("/tmp/%s.cwd", bashCmd.TaskId)
 	cwdBytes, readErr := os.ReadFile(cwdFilePath)
 	if readErr == nil {
 		finalCwd := strings.TrimSpace(string(cwdBytes))
@@ -276,11 +594,124 @@ func processFinalResult(ctx context.Context, cmd *exec.Cmd, bashCmd *Task,
 		message += " (Could not read final CWD)."
 	}
 
-	return OutputResult{
-		TaskID:  bashCmd.TaskId,
-		Status:  finalStatus,
-		Message: message,
-		Error:   errMsg,
+	result := OutputResult{
+		TaskID:      bashCmd.TaskId,
+		Status:      finalStatus,
+		Message:     message,
+		Error:       errMsg,
+		FailureKind: failure,
+	}
+	if exited {
+		result.ExitCode = &exitCode
+	}
+	return result
+}
+
+// BackgroundProcess is the Payload of a BASH_EXEC result when the task captures the
+// PID of the process its command started in the background.
+type BackgroundProcess struct {
+	PID int `json:"pid"`
+	// PIDFile is the resolved path the PID was written to, if any.
+	PIDFile string `json:"pid_file,omitempty"`
+}
+
+// reportBackgroundPID reads the PID recorded by the exit trap into the result's payload
+// and writes it to the task's PID file. A PID that cannot be read or written fails a
+// command that otherwise succeeded.
+func (e *BashExecExecutor) reportBackgroundPID(result *OutputResult, params BashExecParameters, pidFilePath string) {
+	process, err := e.backgroundProcess(params, pidFilePath)
+	switch {
+	case err != nil:
+		if result.Status == StatusSucceeded {
+			result.Status = StatusFailed
+			result.Error = err.Error()
+			result.FailureKind = failureKind(err)
+		}
+	case process.PID == 0:
+		result.Message += msgBashNoBackgroundPID
+	default:
+		result.Payload = process
+		result.Message += fmt.Sprintf(msgBashBackgroundPID, process.PID)
+	}
+}
+
+// backgroundProcess returns the process recorded in pidFilePath, which has a zero PID
+// when the command started no background process.
+func (e *BashExecExecutor) backgroundProcess(params BashExecParameters, pidFilePath string) (BackgroundProcess, error) {
+	content, err := os.ReadFile(pidFilePath)
+	os.Remove(pidFilePath)
+	if err != nil {
+		return BackgroundProcess{}, fmt.Errorf(errBashReadPID, err)
+	}
+	text := strings.TrimSpace(string(content))
+	if text == "" {
+		return BackgroundProcess{}, nil
+	}
+	pid, err := strconv.Atoi(text)
+	if err != nil {
+		return BackgroundProcess{}, fmt.Errorf(errBashReadPID, err)
+	}
+
+	process := BackgroundProcess{PID: pid}
+	if params.PIDFile != "" {
+		path, err := e.config.resolvePath(params.PIDFile, params.WorkingDirectory)
+		if err != nil {
+			return BackgroundProcess{}, err
+		}
+		if err := os.WriteFile(path, []byte(text+"\n"), e.config.fileMode()); err != nil {
+			return BackgroundProcess{}, fmt.Errorf(errBashWritePID, path, err)
+		}
+		process.PIDFile = path
+	}
+	return process, nil
+}
+
+// shellQuote quotes s as a single bash word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// startHeartbeat sends a RUNNING result every HeartbeatInterval until the returned
+// function is called, so consumers can tell a quiet command from a stalled one.
+// The returned function waits for the heartbeat goroutine to exit.
+func (e *BashExecExecutor) startHeartbeat(ctx context.Context, bashCmd *Task, startTime time.Time, results chan<- OutputResult) func() {
+	if e.config.HeartbeatInterval <= 0 {
+		return func() {}
+	}
+
+	clock := e.config.clock()
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-clock.After(e.config.HeartbeatInterval):
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			}
+
+			heartbeat := bashCmd.describe(OutputResult{
+				TaskID:  bashCmd.TaskId,
+				Status:  StatusRunning,
+				Message: fmt.Sprintf(msgBashHeartbeat, e.config.since(startTime).Round(time.Millisecond)),
+			})
+			select {
+			case results <- heartbeat:
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		wg.Wait()
 	}
 }
 
@@ -305,12 +736,13 @@ func waitGroupWithContext(ctx context.Context, wg *sync.WaitGroup) error {
 
 // createErrorResult creates a standardized error OutputResult for a BashExecCommand.
 func createErrorResult(cmd *Task, errMsg string) OutputResult {
-	return OutputResult{
-		TaskID:  cmd.TaskId,
-		Status:  StatusFailed,
-		Message: "Command execution failed.",
-		Error:   errMsg,
-	}
+	return cmd.describe(OutputResult{
+		TaskID:      cmd.TaskId,
+		Status:      StatusFailed,
+		Message:     "Command execution failed.",
+		Error:       errMsg,
+		FailureKind: FailureExecutionError,
+	})
 }
 
 // CreateErrorResult creates an error result for a failed command execution.
@@ -320,10 +752,11 @@ func (e *BashExecExecutor) CreateErrorResult(cmd *Task, err error) OutputResult
 	if err != nil {
 		errMsg = err.Error()
 	}
-	return OutputResult{
-		TaskID:  cmd.TaskId,
-		Status:  StatusFailed,
-		Message: fmt.Sprintf("Command execution failed: %v", err),
-		Error:   errMsg,
-	}
+	return cmd.describe(OutputResult{
+		TaskID:      cmd.TaskId,
+		Status:      StatusFailed,
+		Message:     fmt.Sprintf("Command execution failed: %v", err),
+		Error:       errMsg,
+		FailureKind: failureKind(err),
+	})
 }
@@ -2,9 +2,13 @@ package task
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
+	"syscall"
 	"testing"
 	"time"
 
@@ -449,3 +453,599 @@ func TestBashExecExecutor_Execute_TerminalTaskHandling(t *testing.T) {
 		})
 	}
 }
+
+func TestBashExecExecutor_Execute_QuietMode(t *testing.T) {
+	captureOutput := false
+	testCases := []struct {
+		name           string
+		command        string
+		expectedStatus TaskStatus
+		expectedCode   string
+	}{
+		{name: "Success", command: "echo line1\necho line2", expectedStatus: StatusSucceeded, expectedCode: "Exit code: 0."},
+		{name: "Failure", command: "echo about to fail\nexit 3", expectedStatus: StatusFailed, expectedCode: "Exit code: 3."},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			executor := NewBashExecExecutor()
+			cmd := NewBashExecTask("bash-quiet-"+tc.name, "Quiet command", BashExecParameters{
+				Command:       tc.command,
+				CaptureOutput: &captureOutput,
+			})
+
+			resultsChan, err := executor.Execute(context.Background(), cmd)
+			require.NoError(t, err)
+
+			var all []OutputResult
+			for result := range resultsChan {
+				all = append(all, result)
+			}
+
+			require.Len(t, all, 1, "Quiet mode should only emit the final result")
+			assert.Equal(t, tc.expectedStatus, all[0].Status)
+			assert.Contains(t, all[0].Message, tc.expectedCode)
+			assert.Empty(t, all[0].ResultData)
+		})
+	}
+}
+
+func TestBashExecExecutor_Execute_QuietModeOutputLimit(t *testing.T) {
+	captureOutput := false
+	executor := NewBashExecExecutorWithConfig(ExecutorConfig{MaxOutputBytes: 16})
+	cmd := NewBashExecTask("bash-quiet-limit", "Quiet command over limit", BashExecParameters{
+		Command:       "for i in $(seq 1 100); do echo \"output line $i\"; done",
+		CaptureOutput: &captureOutput,
+	})
+
+	resultsChan, err := executor.Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	var all []OutputResult
+	for result := range resultsChan {
+		all = append(all, result)
+	}
+
+	require.Len(t, all, 1, "Quiet mode should only emit the final result")
+	assert.Equal(t, StatusSucceeded, all[0].Status, all[0].Error)
+	assert.Contains(t, all[0].Message, "Output truncated at 16 bytes.")
+}
+
+func TestBashExecExecutor_Execute_OutputLimit(t *testing.T) {
+	executor := NewBashExecExecutorWithConfig(ExecutorConfig{MaxOutputBytes: 20})
+	cmd := NewBashExecTask("bash-limit", "Command over limit", BashExecParameters{
+		Command: "for i in $(seq 1 100); do echo \"output line $i\"; done",
+	})
+
+	resultsChan, err := executor.Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	finalResult, output, received := collectStreamingResults(t, resultsChan, 10*time.Second)
+	require.True(t, received)
+	assert.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+	assert.Equal(t, "output line 1\noutput", output)
+	assert.Contains(t, finalResult.Message, "Output truncated at 20 bytes.")
+	assert.True(t, finalResult.Truncated)
+}
+
+func TestBashExecExecutor_Execute_CustomTempDir(t *testing.T) {
+	// Include a space and a quote to verify the path is quoted in the script
+	tempDir := filepath.Join(t.TempDir(), "scratch dir's")
+	require.NoError(t, os.Mkdir(tempDir, 0755))
+	workDir := t.TempDir()
+
+	executor := NewBashExecExecutorWithConfig(ExecutorConfig{TempDir: tempDir})
+	cmd := NewBashExecTask("bash-custom-temp", "Custom temp dir", BashExecParameters{
+		Command: fmt.Sprintf("cd %s", shellQuote(workDir)),
+	})
+
+	resultsChan, err := executor.Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	var finalResult OutputResult
+	for result := range resultsChan {
+		finalResult = result
+	}
+	require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+
+	cwdBytes, err := os.ReadFile(filepath.Join(tempDir, "bash-custom-temp.cwd"))
+	require.NoError(t, err, "CWD file should be written to the configured temp dir")
+	expectedDir, err := filepath.EvalSymlinks(workDir)
+	require.NoError(t, err)
+	assert.Equal(t, expectedDir, strings.TrimSpace(string(cwdBytes)))
+	assert.Contains(t, finalResult.Message, "Final CWD: "+expectedDir)
+}
+
+func TestBashExecExecutor_Execute_Env(t *testing.T) {
+	t.Setenv("AGENT_ENV_INHERITED", "inherited")
+	t.Setenv("AGENT_ENV_OVERRIDDEN", "original")
+
+	cmd := NewBashExecTask("bash-env", "Task environment", BashExecParameters{
+		BaseParameters: BaseParameters{Env: map[string]string{
+			"AGENT_ENV_OVERRIDDEN": "overridden",
+			"AGENT_ENV_ADDED":      "added",
+		}},
+		Command: `echo "$AGENT_ENV_INHERITED $AGENT_ENV_OVERRIDDEN $AGENT_ENV_ADDED"`,
+	})
+
+	resultsChan, err := NewBashExecExecutor().Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	var finalResult OutputResult
+	var firstLine string
+	for result := range resultsChan {
+		if firstLine == "" && result.ResultData != "" {
+			firstLine = result.ResultData
+		}
+		finalResult = result
+	}
+	require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+	assert.Equal(t, "inherited overridden added\n", firstLine)
+}
+
+func TestBashExecExecutor_Execute_LineTransform(t *testing.T) {
+	testCases := []struct {
+		name          string
+		lineTransform string
+		expected      string
+	}{
+		{name: "StripANSI", lineTransform: LineTransformStripANSI, expected: "red plain bold\n"},
+		{name: "PassThrough", lineTransform: LineTransformNone, expected: "\x1b[31mred\x1b[0m plain \x1b[1;4mbold\x1b[0m\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := NewBashExecTask("bash-transform-"+tc.name, "Colored output", BashExecParameters{
+				Command:       `printf '\033[31mred\033[0m plain \033[1;4mbold\033[0m\n'`,
+				LineTransform: tc.lineTransform,
+			})
+
+			resultsChan, err := NewBashExecExecutor().Execute(context.Background(), cmd)
+			require.NoError(t, err)
+
+			var finalResult OutputResult
+			var firstLine string
+			for result := range resultsChan {
+				if firstLine == "" && result.ResultData != "" {
+					firstLine = result.ResultData
+				}
+				finalResult = result
+			}
+			require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+			assert.Equal(t, tc.expected, firstLine)
+		})
+	}
+}
+
+func TestBashExecExecutor_Execute_UnknownLineTransform(t *testing.T) {
+	cmd := NewBashExecTask("bash-transform-unknown", "Unknown transform", BashExecParameters{
+		Command:       "echo hi",
+		LineTransform: "rot13",
+	})
+
+	resultsChan, err := NewBashExecExecutor().Execute(context.Background(), cmd)
+	require.Error(t, err)
+	assert.Nil(t, resultsChan)
+	assert.Contains(t, err.Error(), "unknown line transform")
+}
+
+func TestBashExecExecutor_Execute_RequiredGlobs(t *testing.T) {
+	rootDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(rootDir, "a.log"), []byte("alpha\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(rootDir, "b.log"), []byte("beta\n"), 0644))
+	executor := NewBashExecExecutorWithConfig(ExecutorConfig{RootDir: rootDir})
+
+	t.Run("Matching", func(t *testing.T) {
+		cmd := NewBashExecTask("bash-glob-match", "Matching glob", BashExecParameters{
+			Command:       "cat *.log",
+			RequiredGlobs: []string{"*.log"},
+		})
+		resultsChan, err := executor.Execute(context.Background(), cmd)
+		require.NoError(t, err)
+
+		finalResult, output, received := collectStreamingResults(t, resultsChan, 10*time.Second)
+		require.True(t, received, "Did not receive final result")
+		require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+		assert.Contains(t, output, "alpha")
+		assert.Contains(t, output, "beta")
+	})
+
+	t.Run("NoMatch", func(t *testing.T) {
+		marker := filepath.Join(rootDir, "ran")
+		cmd := NewBashExecTask("bash-glob-nomatch", "Unmatched glob", BashExecParameters{
+			Command:       "touch " + shellQuote(marker) + " && cat *.txt",
+			RequiredGlobs: []string{"*.log", "*.txt"},
+		})
+		resultsChan, err := executor.Execute(context.Background(), cmd)
+		require.NoError(t, err)
+
+		finalResult, _, received := collectStreamingResults(t, resultsChan, 10*time.Second)
+		require.True(t, received, "Did not receive final result")
+		assert.Equal(t, StatusFailed, finalResult.Status)
+		assert.Equal(t, FailureValidationError, finalResult.FailureKind)
+		assert.Contains(t, finalResult.Error, "required glob '*.txt' matched no files")
+		assert.NoFileExists(t, marker, "Command should not run when a required glob matches nothing")
+	})
+
+	t.Run("InvalidPattern", func(t *testing.T) {
+		cmd := NewBashExecTask("bash-glob-invalid", "Malformed glob", BashExecParameters{
+			Command:       "true",
+			RequiredGlobs: []string{"[a-"},
+		})
+		resultsChan, err := executor.Execute(context.Background(), cmd)
+		require.Error(t, err)
+		assert.Nil(t, resultsChan)
+		assert.Contains(t, err.Error(), "invalid required glob")
+	})
+}
+
+func TestStripANSI(t *testing.T) {
+	assert.Equal(t, "plain", stripANSI("plain"))
+	assert.Equal(t, "green text", stripANSI("\x1b[32mgreen\x1b[0m text"))
+	assert.Equal(t, "link", stripANSI("\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\"))
+	assert.Equal(t, "cleared", stripANSI("\x1b[2Kcleared"))
+}
+
+func TestBashExecExecutor_Execute_WorkingDirectory(t *testing.T) {
+	rootDir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(rootDir, "project"), 0755))
+	executor := NewBashExecExecutorWithConfig(ExecutorConfig{RootDir: rootDir})
+
+	cmd := NewBashExecTask("bash-wd", "Run in working directory", BashExecParameters{
+		Command:        "pwd",
+		BaseParameters: BaseParameters{WorkingDirectory: "project"},
+	})
+	resultsChan, err := executor.Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	finalResult, output, received := collectStreamingResults(t, resultsChan, 10*time.Second)
+	require.True(t, received, "Did not receive final result")
+	require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+	expected, err := filepath.EvalSymlinks(filepath.Join(rootDir, "project"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(output, expected+"\n"), "Command should start in the working directory, got %q", output)
+
+	// A working directory outside the root is rejected before the command starts
+	cmd = NewBashExecTask("bash-wd-outside", "Escape the root", BashExecParameters{
+		Command:        "pwd",
+		BaseParameters: BaseParameters{WorkingDirectory: ".."},
+	})
+	resultsChan, err = executor.Execute(context.Background(), cmd)
+	require.NoError(t, err)
+	finalResult, _, received = collectStreamingResults(t, resultsChan, 10*time.Second)
+	require.True(t, received, "Did not receive final result")
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Equal(t, FailureValidationError, finalResult.FailureKind)
+}
+
+func TestBashExecExecutor_Execute_WorkingDirectoryRelativeWrite(t *testing.T) {
+	workDir := t.TempDir()
+	executor := NewBashExecExecutor()
+
+	cmd := NewBashExecTask("bash-wd-write", "Write a relative file", BashExecParameters{
+		Command:        "mkdir -p out && echo written > out/result.txt",
+		BaseParameters: BaseParameters{WorkingDirectory: workDir},
+	})
+	resultsChan, err := executor.Execute(context.Background(), cmd)
+	require.NoError(t, err)
+	finalResult, _, received := collectStreamingResults(t, resultsChan, 10*time.Second)
+	require.True(t, received, "Did not receive final result")
+	require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+
+	content, err := os.ReadFile(filepath.Join(workDir, "out", "result.txt"))
+	require.NoError(t, err, "The relative path should be resolved against the working directory")
+	assert.Equal(t, "written\n", string(content))
+
+	for name, dir := range map[string]string{
+		"does not exist":     filepath.Join(workDir, "missing"),
+		"is not a directory": filepath.Join(workDir, "out", "result.txt"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			cmd := NewBashExecTask("bash-wd-bad", "Run in a bad working directory", BashExecParameters{
+				Command:        "echo should not run",
+				BaseParameters: BaseParameters{WorkingDirectory: dir},
+			})
+			resultsChan, err := executor.Execute(context.Background(), cmd)
+			require.NoError(t, err)
+			finalResult, output, received := collectStreamingResults(t, resultsChan, 10*time.Second)
+			require.True(t, received, "Did not receive final result")
+			assert.Equal(t, StatusFailed, finalResult.Status)
+			assert.Equal(t, FailureValidationError, finalResult.FailureKind)
+			assert.Equal(t, "working directory '"+dir+"' "+name, finalResult.Error)
+			assert.NotContains(t, output, "should not run")
+		})
+	}
+}
+
+func TestBashExecExecutor_Execute_LoginShell(t *testing.T) {
+	home := t.TempDir()
+	profile := "greet() { echo \"hello from profile\"; }\nexport PROFILE_LOADED=yes\n"
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".bash_profile"), []byte(profile), 0644))
+	workDir := t.TempDir()
+	executor := NewBashExecExecutor()
+
+	run := func(t *testing.T, loginShell bool) (OutputResult, string) {
+		t.Helper()
+		cmd := NewBashExecTask("bash-login", "Use a profile function", BashExecParameters{
+			Command:        "cd " + shellQuote(workDir) + " && greet && echo \"loaded=$PROFILE_LOADED\"",
+			LoginShell:     loginShell,
+			BaseParameters: BaseParameters{Env: map[string]string{"HOME": home}},
+		})
+		resultsChan, err := executor.Execute(context.Background(), cmd)
+		require.NoError(t, err)
+		finalResult, output, received := collectStreamingResults(t, resultsChan, 10*time.Second)
+		require.True(t, received, "Did not receive final result")
+		return finalResult, output
+	}
+
+	t.Run("Login", func(t *testing.T) {
+		finalResult, output := run(t, true)
+		require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+		assert.Contains(t, output, "hello from profile")
+		assert.Contains(t, output, "loaded=yes")
+		// The directory change is still reported through the exit trap
+		expectedDir, err := filepath.EvalSymlinks(workDir)
+		require.NoError(t, err)
+		assert.Contains(t, finalResult.Message, expectedDir)
+	})
+
+	t.Run("NonLogin", func(t *testing.T) {
+		finalResult, output := run(t, false)
+		assert.Equal(t, StatusFailed, finalResult.Status)
+		assert.Contains(t, output, "greet: command not found")
+	})
+}
+
+func TestBashExecExecutor_Execute_CapturePID(t *testing.T) {
+	executor := NewBashExecExecutor()
+	pidFile := filepath.Join(t.TempDir(), "sleep.pid")
+
+	cmd := NewBashExecTask("bash-pid", "Start a background sleep", BashExecParameters{
+		// The background process must not keep the output pipes open
+		Command: "sleep 30 >/dev/null 2>&1 &\necho started",
+		PIDFile: pidFile,
+	})
+	resultsChan, err := executor.Execute(context.Background(), cmd)
+	require.NoError(t, err)
+	finalResult, output, received := collectStreamingResults(t, resultsChan, 10*time.Second)
+	require.True(t, received, "Did not receive final result")
+	require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+	assert.True(t, strings.HasPrefix(output, "started\n"))
+
+	process, ok := finalResult.Payload.(BackgroundProcess)
+	require.True(t, ok, "payload is %T", finalResult.Payload)
+	require.Positive(t, process.PID)
+	t.Cleanup(func() { syscall.Kill(process.PID, syscall.SIGKILL) })
+	assert.Contains(t, finalResult.Message, fmt.Sprintf("Background process PID: %d.", process.PID))
+	assert.Equal(t, pidFile, process.PIDFile)
+
+	// The process outlived the command and is still running
+	require.NoError(t, syscall.Kill(process.PID, 0))
+	content, err := os.ReadFile(pidFile)
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("%d\n", process.PID), string(content))
+
+	t.Run("NoBackgroundProcess", func(t *testing.T) {
+		cmd := NewBashExecTask("bash-no-pid", "Run in the foreground", BashExecParameters{Command: "true", CapturePID: true})
+		resultsChan, err := executor.Execute(context.Background(), cmd)
+		require.NoError(t, err)
+		finalResult, _, received := collectStreamingResults(t, resultsChan, 10*time.Second)
+		require.True(t, received, "Did not receive final result")
+		require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+		assert.Nil(t, finalResult.Payload)
+		assert.Contains(t, finalResult.Message, "No background process was started.")
+	})
+}
+
+func TestBashExecExecutor_Execute_JSONLines(t *testing.T) {
+	runJSONLines := func(t *testing.T, command string) []OutputResult {
+		t.Helper()
+		cmd := NewBashExecTask("bash-jsonl", "Print JSON lines", BashExecParameters{Command: command, JSONLines: true})
+		resultsChan, err := NewBashExecExecutor().Execute(context.Background(), cmd)
+		require.NoError(t, err)
+
+		var running []OutputResult
+		for result := range resultsChan {
+			if result.Status == StatusRunning && result.ResultData != "" {
+				running = append(running, result)
+			} else if result.Status.IsTerminal() {
+				require.Equal(t, StatusSucceeded, result.Status, result.Error)
+			}
+		}
+		return running
+	}
+
+	t.Run("AllJSON", func(t *testing.T) {
+		running := runJSONLines(t, `echo '{"event":"start","step":1}'; echo '{"event":"done","ok":true}'`)
+		require.GreaterOrEqual(t, len(running), 2)
+		assert.Equal(t, map[string]any{"event": "start", "step": float64(1)}, running[0].Payload)
+		assert.Equal(t, "{\"event\":\"start\",\"step\":1}\n", running[0].ResultData)
+		assert.Equal(t, map[string]any{"event": "done", "ok": true}, running[1].Payload)
+	})
+
+	t.Run("Mixed", func(t *testing.T) {
+		running := runJSONLines(t, `echo 'building...'; echo '[1, 2]'; echo '{"broken":'; echo 42`)
+		require.GreaterOrEqual(t, len(running), 4)
+		assert.Nil(t, running[0].Payload)
+		assert.Equal(t, "building...\n", running[0].ResultData)
+		assert.Equal(t, []any{float64(1), float64(2)}, running[1].Payload)
+		assert.Nil(t, running[2].Payload, "Invalid JSON is passed through as text")
+		assert.Equal(t, "{\"broken\":\n", running[2].ResultData)
+		assert.Nil(t, running[3].Payload, "Scalars are passed through as text")
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		cmd := NewBashExecTask("bash-no-jsonl", "Print JSON", BashExecParameters{Command: `echo '{"a":1}'`})
+		resultsChan, err := NewBashExecExecutor().Execute(context.Background(), cmd)
+		require.NoError(t, err)
+		for result := range resultsChan {
+			assert.Nil(t, result.Payload)
+		}
+	})
+}
+
+func TestBashExecExecutor_Execute_AllowedExitCodes(t *testing.T) {
+	runWithExitCodes := func(t *testing.T, command string, allowed []int) OutputResult {
+		t.Helper()
+		cmd := NewBashExecTask("bash-exit-codes", "Run grep", BashExecParameters{Command: command, AllowedExitCodes: allowed})
+		resultsChan, err := NewBashExecExecutor().Execute(context.Background(), cmd)
+		require.NoError(t, err)
+
+		finalResult, _, received := collectStreamingResults(t, resultsChan, 5*time.Second)
+		require.True(t, received, "Did not receive final result")
+		return finalResult
+	}
+	noMatch := "echo hello | grep goodbye"
+
+	t.Run("Allowed", func(t *testing.T) {
+		finalResult := runWithExitCodes(t, noMatch, []int{0, 1})
+		assert.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+		assert.Empty(t, finalResult.Error)
+		require.NotNil(t, finalResult.ExitCode)
+		assert.Equal(t, 1, *finalResult.ExitCode)
+	})
+
+	t.Run("NotAllowed", func(t *testing.T) {
+		finalResult := runWithExitCodes(t, noMatch, nil)
+		assert.Equal(t, StatusFailed, finalResult.Status)
+		assert.Equal(t, FailureExecutionError, finalResult.FailureKind)
+		assert.Contains(t, finalResult.Error, "exit code 1")
+		require.NotNil(t, finalResult.ExitCode)
+		assert.Equal(t, 1, *finalResult.ExitCode)
+	})
+
+	t.Run("ZeroNotListed", func(t *testing.T) {
+		finalResult := runWithExitCodes(t, "echo hello | grep hello", []int{1})
+		assert.Equal(t, StatusFailed, finalResult.Status)
+		assert.Contains(t, finalResult.Error, "not in the allowed exit codes [1]")
+		require.NotNil(t, finalResult.ExitCode)
+		assert.Equal(t, 0, *finalResult.ExitCode)
+	})
+}
+
+func TestBashExecExecutor_Execute_ResourceLimits(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("resource limits are only tested on Linux")
+	}
+	runWithLimits := func(t *testing.T, params BashExecParameters) OutputResult {
+		t.Helper()
+		resultsChan, err := NewBashExecExecutor().Execute(context.Background(), NewBashExecTask("bash-limits", "Run under limits", params))
+		require.NoError(t, err)
+
+		finalResult, _, received := collectStreamingResults(t, resultsChan, 20*time.Second)
+		require.True(t, received, "Did not receive final result")
+		return finalResult
+	}
+
+	t.Run("Memory", func(t *testing.T) {
+		// tail buffers the single 512 MiB line in memory
+		finalResult := runWithLimits(t, BashExecParameters{
+			Command:          "head -c 536870912 /dev/zero | tail -n 1 > /dev/null",
+			MemoryLimitBytes: 64 << 20,
+		})
+		assert.Equal(t, StatusFailed, finalResult.Status)
+		assert.Equal(t, FailureExecutionError, finalResult.FailureKind)
+		assert.Contains(t, finalResult.Error, "memory limit of 67108864 bytes")
+	})
+
+	t.Run("CPU", func(t *testing.T) {
+		finalResult := runWithLimits(t, BashExecParameters{
+			Command:             "sha256sum /dev/zero",
+			CPUTimeLimitSeconds: 1,
+		})
+		assert.Equal(t, StatusFailed, finalResult.Status)
+		assert.Equal(t, "Command was killed after exceeding its CPU time limit of 1s", finalResult.Error)
+	})
+
+	t.Run("KilledElsewhere", func(t *testing.T) {
+		// SIGKILL from outside, well within the CPU limit, is not the limit at work
+		finalResult := runWithLimits(t, BashExecParameters{
+			Command:             "kill -9 $$",
+			CPUTimeLimitSeconds: 5,
+		})
+		assert.Equal(t, StatusFailed, finalResult.Status)
+		assert.NotContains(t, finalResult.Error, "CPU time limit")
+	})
+
+	t.Run("AppliedToProcess", func(t *testing.T) {
+		resultsChan, err := NewBashExecExecutor().Execute(context.Background(), NewBashExecTask("bash-limits", "", BashExecParameters{
+			Command:             "ulimit -S -t; ulimit -H -t; ulimit -v",
+			CPUTimeLimitSeconds: 5,
+			MemoryLimitBytes:    256 << 20,
+		}))
+		require.NoError(t, err)
+		finalResult, output, received := collectStreamingResults(t, resultsChan, 20*time.Second)
+		require.True(t, received, "Did not receive final result")
+		assert.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+		assert.Contains(t, output, "5\n6\n262144\n")
+	})
+
+	t.Run("Negative", func(t *testing.T) {
+		_, err := NewBashExecExecutor().Execute(context.Background(), NewBashExecTask("bash-limits", "", BashExecParameters{
+			Command:          "true",
+			MemoryLimitBytes: -1,
+		}))
+		assert.ErrorContains(t, err, "resource limits cannot be negative")
+	})
+
+	t.Run("WithinLimits", func(t *testing.T) {
+		finalResult := runWithLimits(t, BashExecParameters{
+			Command:             "echo ok",
+			CPUTimeLimitSeconds: 5,
+			MemoryLimitBytes:    256 << 20,
+		})
+		assert.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+	})
+}
+
+func TestBashExecExecutor_Execute_ExitCode(t *testing.T) {
+	run := func(t *testing.T, ctx context.Context, executor *BashExecExecutor, command string) OutputResult {
+		t.Helper()
+		resultsChan, err := executor.Execute(ctx, NewBashExecTask("bash-exit-code", "Report the exit code", BashExecParameters{Command: command}))
+		require.NoError(t, err)
+		finalResult, _, received := collectStreamingResults(t, resultsChan, 10*time.Second)
+		require.True(t, received, "Did not receive final result")
+		return finalResult
+	}
+
+	t.Run("Failure", func(t *testing.T) {
+		finalResult := run(t, context.Background(), NewBashExecExecutor(), "exit 123")
+		assert.Equal(t, StatusFailed, finalResult.Status)
+		require.NotNil(t, finalResult.ExitCode)
+		assert.Equal(t, 123, *finalResult.ExitCode)
+
+		encoded, err := json.Marshal(finalResult)
+		require.NoError(t, err)
+		assert.Contains(t, string(encoded), `"exit_code":123`)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		finalResult := run(t, context.Background(), NewBashExecExecutor(), "echo ok")
+		assert.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+		require.NotNil(t, finalResult.ExitCode)
+		assert.Equal(t, 0, *finalResult.ExitCode)
+	})
+
+	t.Run("TimedOut", func(t *testing.T) {
+		executor := NewBashExecExecutorWithConfig(ExecutorConfig{DefaultTimeout: 100 * time.Millisecond})
+		finalResult := run(t, context.Background(), executor, "sleep 5")
+		assert.Equal(t, FailureTimedOut, finalResult.FailureKind)
+		assert.Nil(t, finalResult.ExitCode, "A command killed on timeout has no exit code")
+
+		encoded, err := json.Marshal(finalResult)
+		require.NoError(t, err)
+		assert.NotContains(t, string(encoded), "exit_code")
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			cancel()
+		}()
+		finalResult := run(t, ctx, NewBashExecExecutor(), "sleep 5")
+		assert.Equal(t, StatusFailed, finalResult.Status)
+		assert.Nil(t, finalResult.ExitCode, "A cancelled command has no exit code")
+	})
+}
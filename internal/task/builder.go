@@ -0,0 +1,1364 @@
+package task
+
+import (
+	"io"
+	"time"
+)
+
+// taskFields holds the fields every task builder sets.
+type taskFields struct {
+	taskId      string
+	description string
+	base        BaseParameters
+}
+
+// setEnv adds an environment variable to the task's BaseParameters.
+func (f *taskFields) setEnv(name, value string) {
+	if f.base.Env == nil {
+		f.base.Env = make(map[string]string)
+	}
+	f.base.Env[name] = value
+}
+
+// BashExecBuilder builds a BASH_EXEC task that runs command.
+type BashExecBuilder struct {
+	taskFields
+	params BashExecParameters
+}
+
+// NewBashExecBuilder starts a BASH_EXEC task that runs command.
+func NewBashExecBuilder(command string) *BashExecBuilder {
+	return &BashExecBuilder{params: BashExecParameters{Command: command}}
+}
+
+// ID sets the task ID.
+func (b *BashExecBuilder) ID(taskId string) *BashExecBuilder {
+	b.taskId = taskId
+	return b
+}
+
+// Description sets the task description.
+func (b *BashExecBuilder) Description(description string) *BashExecBuilder {
+	b.description = description
+	return b
+}
+
+// WorkingDirectory sets the directory relative paths are resolved against.
+func (b *BashExecBuilder) WorkingDirectory(dir string) *BashExecBuilder {
+	b.base.WorkingDirectory = dir
+	return b
+}
+
+// Env adds an environment variable for the task.
+func (b *BashExecBuilder) Env(name, value string) *BashExecBuilder {
+	b.setEnv(name, value)
+	return b
+}
+
+// CaptureOutput sets whether output lines are streamed as RUNNING results.
+func (b *BashExecBuilder) CaptureOutput(capture bool) *BashExecBuilder {
+	b.params.CaptureOutput = &capture
+	return b
+}
+
+// LineTransform applies a built-in transform, such as "strip-ansi", to each output line.
+func (b *BashExecBuilder) LineTransform(name string) *BashExecBuilder {
+	b.params.LineTransform = name
+	return b
+}
+
+// RequiredGlobs adds glob patterns that must match before the command starts.
+func (b *BashExecBuilder) RequiredGlobs(patterns ...string) *BashExecBuilder {
+	b.params.RequiredGlobs = append(b.params.RequiredGlobs, patterns...)
+	return b
+}
+
+// LoginShell runs the command with bash -lc.
+func (b *BashExecBuilder) LoginShell() *BashExecBuilder {
+	b.params.LoginShell = true
+	return b
+}
+
+// CapturePID reports the PID of the command's last background process.
+func (b *BashExecBuilder) CapturePID() *BashExecBuilder {
+	b.params.CapturePID = true
+	return b
+}
+
+// PIDFile writes the PID of the command's last background process to path.
+func (b *BashExecBuilder) PIDFile(path string) *BashExecBuilder {
+	b.params.PIDFile = path
+	return b
+}
+
+// JSONLines decodes output lines that hold JSON objects or arrays into payloads.
+func (b *BashExecBuilder) JSONLines() *BashExecBuilder {
+	b.params.JSONLines = true
+	return b
+}
+
+// AllowedExitCodes sets the exit codes that count as success instead of only 0.
+func (b *BashExecBuilder) AllowedExitCodes(codes ...int) *BashExecBuilder {
+	b.params.AllowedExitCodes = append(b.params.AllowedExitCodes, codes...)
+	return b
+}
+
+// CPUTimeLimit caps the CPU time of the command and each process it starts.
+func (b *BashExecBuilder) CPUTimeLimit(seconds int) *BashExecBuilder {
+	b.params.CPUTimeLimitSeconds = seconds
+	return b
+}
+
+// MemoryLimit caps the virtual memory of the command and each process it starts.
+func (b *BashExecBuilder) MemoryLimit(bytes int64) *BashExecBuilder {
+	b.params.MemoryLimitBytes = bytes
+	return b
+}
+
+// Build returns the task.
+func (b *BashExecBuilder) Build() *Task {
+	params := b.params
+	params.BaseParameters = b.base
+	return NewBashExecTask(b.taskId, b.description, params)
+}
+
+// FileReadBuilder builds a FILE_READ task that reads path.
+type FileReadBuilder struct {
+	taskFields
+	params FileReadParameters
+}
+
+// NewFileReadBuilder starts a FILE_READ task that reads path.
+func NewFileReadBuilder(path string) *FileReadBuilder {
+	return &FileReadBuilder{params: FileReadParameters{FilePath: path}}
+}
+
+// ID sets the task ID.
+func (b *FileReadBuilder) ID(taskId string) *FileReadBuilder {
+	b.taskId = taskId
+	return b
+}
+
+// Description sets the task description.
+func (b *FileReadBuilder) Description(description string) *FileReadBuilder {
+	b.description = description
+	return b
+}
+
+// WorkingDirectory sets the directory relative paths are resolved against.
+func (b *FileReadBuilder) WorkingDirectory(dir string) *FileReadBuilder {
+	b.base.WorkingDirectory = dir
+	return b
+}
+
+// Env adds an environment variable for the task.
+func (b *FileReadBuilder) Env(name, value string) *FileReadBuilder {
+	b.setEnv(name, value)
+	return b
+}
+
+// Lines reads only lines start through end, counted from 1. An end of 0 reads to the end of the file.
+func (b *FileReadBuilder) Lines(start, end int) *FileReadBuilder {
+	b.params.StartLine = start
+	b.params.EndLine = end
+	return b
+}
+
+// StartByte starts reading at the byte offset.
+func (b *FileReadBuilder) StartByte(offset int64) *FileReadBuilder {
+	b.params.StartByte = offset
+	return b
+}
+
+// HeadBytes reads at most the first n bytes.
+func (b *FileReadBuilder) HeadBytes(n int64) *FileReadBuilder {
+	b.params.HeadBytes = n
+	return b
+}
+
+// Encoding decodes the file from the named encoding.
+func (b *FileReadBuilder) Encoding(encoding string) *FileReadBuilder {
+	b.params.Encoding = encoding
+	return b
+}
+
+// Incremental reads only what was appended since the previous read.
+func (b *FileReadBuilder) Incremental() *FileReadBuilder {
+	b.params.Incremental = true
+	return b
+}
+
+// MaxLines stops reading after n lines.
+func (b *FileReadBuilder) MaxLines(n int) *FileReadBuilder {
+	b.params.MaxLines = n
+	return b
+}
+
+// Markers reads only the section between the start and end marker lines.
+func (b *FileReadBuilder) Markers(start, end string) *FileReadBuilder {
+	b.params.StartMarker = start
+	b.params.EndMarker = end
+	return b
+}
+
+// OnMissingEndMarker sets what happens when the end marker is not found.
+func (b *FileReadBuilder) OnMissingEndMarker(action string) *FileReadBuilder {
+	b.params.OnMissingEndMarker = action
+	return b
+}
+
+// TrimTrailingWhitespace removes trailing spaces and tabs from each line.
+func (b *FileReadBuilder) TrimTrailingWhitespace() *FileReadBuilder {
+	b.params.TrimTrailingWhitespace = true
+	return b
+}
+
+// ExpandTabs replaces tabs with spaces up to the next multiple of width.
+func (b *FileReadBuilder) ExpandTabs(width int) *FileReadBuilder {
+	b.params.ExpandTabs = width
+	return b
+}
+
+// AutoDetectEncoding detects the file's charset and decodes it to UTF-8.
+func (b *FileReadBuilder) AutoDetectEncoding() *FileReadBuilder {
+	b.params.AutoDetectEncoding = true
+	return b
+}
+
+// LinesPerChunk sends n lines in each RUNNING result instead of one.
+func (b *FileReadBuilder) LinesPerChunk(n int) *FileReadBuilder {
+	b.params.LinesPerChunk = n
+	return b
+}
+
+// OutputFormat selects how the lines are returned, such as FileReadOutputLinesJSON.
+func (b *FileReadBuilder) OutputFormat(format string) *FileReadBuilder {
+	b.params.OutputFormat = format
+	return b
+}
+
+// Build returns the task.
+func (b *FileReadBuilder) Build() *Task {
+	params := b.params
+	params.BaseParameters = b.base
+	return NewFileReadTask(b.taskId, b.description, params)
+}
+
+// FileWriteBuilder builds a FILE_WRITE task that writes content to path.
+type FileWriteBuilder struct {
+	taskFields
+	params FileWriteParameters
+}
+
+// NewFileWriteBuilder starts a FILE_WRITE task that writes content to path.
+func NewFileWriteBuilder(path, content string) *FileWriteBuilder {
+	return &FileWriteBuilder{params: FileWriteParameters{FilePath: path, Content: content}}
+}
+
+// ID sets the task ID.
+func (b *FileWriteBuilder) ID(taskId string) *FileWriteBuilder {
+	b.taskId = taskId
+	return b
+}
+
+// Description sets the task description.
+func (b *FileWriteBuilder) Description(description string) *FileWriteBuilder {
+	b.description = description
+	return b
+}
+
+// WorkingDirectory sets the directory relative paths are resolved against.
+func (b *FileWriteBuilder) WorkingDirectory(dir string) *FileWriteBuilder {
+	b.base.WorkingDirectory = dir
+	return b
+}
+
+// Env adds an environment variable for the task.
+func (b *FileWriteBuilder) Env(name, value string) *FileWriteBuilder {
+	b.setEnv(name, value)
+	return b
+}
+
+// Overwrite allows an existing file to be replaced.
+func (b *FileWriteBuilder) Overwrite() *FileWriteBuilder {
+	b.params.Overwrite = true
+	return b
+}
+
+// Append adds the content to the end of the file instead of replacing it.
+func (b *FileWriteBuilder) Append() *FileWriteBuilder {
+	b.params.Append = true
+	return b
+}
+
+// Checksum hashes the content as it is written, with ChecksumSHA256 or ChecksumSHA512.
+func (b *FileWriteBuilder) Checksum(algorithm string) *FileWriteBuilder {
+	b.params.Checksum = algorithm
+	return b
+}
+
+// Build returns the task.
+func (b *FileWriteBuilder) Build() *Task {
+	params := b.params
+	params.BaseParameters = b.base
+	return NewFileWriteTask(b.taskId, b.description, params)
+}
+
+// WriteFilesBuilder builds a WRITE_FILES task.
+type WriteFilesBuilder struct {
+	taskFields
+	params WriteFilesParameters
+}
+
+// NewWriteFilesBuilder starts a WRITE_FILES task.
+func NewWriteFilesBuilder() *WriteFilesBuilder {
+	return &WriteFilesBuilder{params: WriteFilesParameters{}}
+}
+
+// ID sets the task ID.
+func (b *WriteFilesBuilder) ID(taskId string) *WriteFilesBuilder {
+	b.taskId = taskId
+	return b
+}
+
+// Description sets the task description.
+func (b *WriteFilesBuilder) Description(description string) *WriteFilesBuilder {
+	b.description = description
+	return b
+}
+
+// WorkingDirectory sets the directory relative paths are resolved against.
+func (b *WriteFilesBuilder) WorkingDirectory(dir string) *WriteFilesBuilder {
+	b.base.WorkingDirectory = dir
+	return b
+}
+
+// Env adds an environment variable for the task.
+func (b *WriteFilesBuilder) Env(name, value string) *WriteFilesBuilder {
+	b.setEnv(name, value)
+	return b
+}
+
+// File adds a file to write.
+func (b *WriteFilesBuilder) File(path, content string) *WriteFilesBuilder {
+	b.params.Files = append(b.params.Files, FileWriteEntry{Path: path, Content: content})
+	return b
+}
+
+// Transactional writes all files or none.
+func (b *WriteFilesBuilder) Transactional() *WriteFilesBuilder {
+	b.params.Transactional = true
+	return b
+}
+
+// Build returns the task.
+func (b *WriteFilesBuilder) Build() *Task {
+	params := b.params
+	params.BaseParameters = b.base
+	return NewWriteFilesTask(b.taskId, b.description, params)
+}
+
+// PatchFileBuilder builds a PATCH_FILE task that applies patch to path.
+type PatchFileBuilder struct {
+	taskFields
+	params PatchFileParameters
+}
+
+// NewPatchFileBuilder starts a PATCH_FILE task that applies patch to path.
+func NewPatchFileBuilder(path, patch string) *PatchFileBuilder {
+	return &PatchFileBuilder{params: PatchFileParameters{FilePath: path, Patch: patch}}
+}
+
+// ID sets the task ID.
+func (b *PatchFileBuilder) ID(taskId string) *PatchFileBuilder {
+	b.taskId = taskId
+	return b
+}
+
+// Description sets the task description.
+func (b *PatchFileBuilder) Description(description string) *PatchFileBuilder {
+	b.description = description
+	return b
+}
+
+// WorkingDirectory sets the directory relative paths are resolved against.
+func (b *PatchFileBuilder) WorkingDirectory(dir string) *PatchFileBuilder {
+	b.base.WorkingDirectory = dir
+	return b
+}
+
+// Env adds an environment variable for the task.
+func (b *PatchFileBuilder) Env(name, value string) *PatchFileBuilder {
+	b.setEnv(name, value)
+	return b
+}
+
+// PatchPath reads the patch from a file when the patch is empty.
+func (b *PatchFileBuilder) PatchPath(patchPath string) *PatchFileBuilder {
+	b.params.PatchPath = patchPath
+	return b
+}
+
+// PatchReader reads the patch from r when the patch is empty.
+func (b *PatchFileBuilder) PatchReader(r io.Reader) *PatchFileBuilder {
+	b.params.PatchReader = r
+	return b
+}
+
+// ExpectedResult sets the hex-encoded SHA-256 the patched content must have.
+func (b *PatchFileBuilder) ExpectedResult(sha256 string) *PatchFileBuilder {
+	b.params.ExpectedResult = sha256
+	return b
+}
+
+// ExpectedSHA adds hex-encoded SHA-256 hashes of the file versions the patch may be applied to.
+func (b *PatchFileBuilder) ExpectedSHA(hashes ...string) *PatchFileBuilder {
+	b.params.ExpectedSHA = append(b.params.ExpectedSHA, hashes...)
+	return b
+}
+
+// IgnoreTrailingWhitespace matches lines while ignoring trailing spaces and tabs.
+func (b *PatchFileBuilder) IgnoreTrailingWhitespace() *PatchFileBuilder {
+	b.params.IgnoreTrailingWhitespace = true
+	return b
+}
+
+// IncludeDiff returns the effective diff in ResultData.
+func (b *PatchFileBuilder) IncludeDiff() *PatchFileBuilder {
+	b.params.IncludeDiff = true
+	return b
+}
+
+// BaseDirectory applies a multi-file patch to the tree rooted at dir. Pass an empty path to the builder with it.
+func (b *PatchFileBuilder) BaseDirectory(dir string) *PatchFileBuilder {
+	b.params.BaseDirectory = dir
+	return b
+}
+
+// Strip removes n leading path components from the file names in the patch.
+func (b *PatchFileBuilder) Strip(n int) *PatchFileBuilder {
+	b.params.Strip = n
+	return b
+}
+
+// BestEffort skips hunks that do not match and applies the rest.
+func (b *PatchFileBuilder) BestEffort() *PatchFileBuilder {
+	b.params.BestEffort = true
+	return b
+}
+
+// Build returns the task.
+func (b *PatchFileBuilder) Build() *Task {
+	params := b.params
+	params.BaseParameters = b.base
+	return NewPatchFileTask(b.taskId, b.description, params)
+}
+
+// ListDirectoryBuilder builds a LIST_DIRECTORY task that lists path.
+type ListDirectoryBuilder struct {
+	taskFields
+	params ListDirectoryParameters
+}
+
+// NewListDirectoryBuilder starts a LIST_DIRECTORY task that lists path.
+func NewListDirectoryBuilder(path string) *ListDirectoryBuilder {
+	return &ListDirectoryBuilder{params: ListDirectoryParameters{Path: path}}
+}
+
+// ID sets the task ID.
+func (b *ListDirectoryBuilder) ID(taskId string) *ListDirectoryBuilder {
+	b.taskId = taskId
+	return b
+}
+
+// Description sets the task description.
+func (b *ListDirectoryBuilder) Description(description string) *ListDirectoryBuilder {
+	b.description = description
+	return b
+}
+
+// WorkingDirectory sets the directory relative paths are resolved against.
+func (b *ListDirectoryBuilder) WorkingDirectory(dir string) *ListDirectoryBuilder {
+	b.base.WorkingDirectory = dir
+	return b
+}
+
+// Env adds an environment variable for the task.
+func (b *ListDirectoryBuilder) Env(name, value string) *ListDirectoryBuilder {
+	b.setEnv(name, value)
+	return b
+}
+
+// SortBy orders the entries, such as ListSortSizeDesc.
+func (b *ListDirectoryBuilder) SortBy(order string) *ListDirectoryBuilder {
+	b.params.SortBy = order
+	return b
+}
+
+// Build returns the task.
+func (b *ListDirectoryBuilder) Build() *Task {
+	params := b.params
+	params.BaseParameters = b.base
+	return NewListDirectoryTask(b.taskId, b.description, params)
+}
+
+// RequestUserInputBuilder builds a REQUEST_USER_INPUT task that asks prompt.
+type RequestUserInputBuilder struct {
+	taskFields
+	params RequestUserInputParameters
+}
+
+// NewRequestUserInputBuilder starts a REQUEST_USER_INPUT task that asks prompt.
+func NewRequestUserInputBuilder(prompt string) *RequestUserInputBuilder {
+	return &RequestUserInputBuilder{params: RequestUserInputParameters{Prompt: prompt}}
+}
+
+// ID sets the task ID.
+func (b *RequestUserInputBuilder) ID(taskId string) *RequestUserInputBuilder {
+	b.taskId = taskId
+	return b
+}
+
+// Description sets the task description.
+func (b *RequestUserInputBuilder) Description(description string) *RequestUserInputBuilder {
+	b.description = description
+	return b
+}
+
+// WorkingDirectory sets the directory relative paths are resolved against.
+func (b *RequestUserInputBuilder) WorkingDirectory(dir string) *RequestUserInputBuilder {
+	b.base.WorkingDirectory = dir
+	return b
+}
+
+// Env adds an environment variable for the task.
+func (b *RequestUserInputBuilder) Env(name, value string) *RequestUserInputBuilder {
+	b.setEnv(name, value)
+	return b
+}
+
+// Build returns the task.
+func (b *RequestUserInputBuilder) Build() *Task {
+	params := b.params
+	params.BaseParameters = b.base
+	return NewRequestUserInputTask(b.taskId, b.description, params)
+}
+
+// TouchBuilder builds a TOUCH task that updates the times of path.
+type TouchBuilder struct {
+	taskFields
+	params TouchParameters
+}
+
+// NewTouchBuilder starts a TOUCH task that updates the times of path.
+func NewTouchBuilder(path string) *TouchBuilder {
+	return &TouchBuilder{params: TouchParameters{FilePath: path}}
+}
+
+// ID sets the task ID.
+func (b *TouchBuilder) ID(taskId string) *TouchBuilder {
+	b.taskId = taskId
+	return b
+}
+
+// Description sets the task description.
+func (b *TouchBuilder) Description(description string) *TouchBuilder {
+	b.description = description
+	return b
+}
+
+// WorkingDirectory sets the directory relative paths are resolved against.
+func (b *TouchBuilder) WorkingDirectory(dir string) *TouchBuilder {
+	b.base.WorkingDirectory = dir
+	return b
+}
+
+// Env adds an environment variable for the task.
+func (b *TouchBuilder) Env(name, value string) *TouchBuilder {
+	b.setEnv(name, value)
+	return b
+}
+
+// CreateIfMissing creates an empty file when path does not exist.
+func (b *TouchBuilder) CreateIfMissing() *TouchBuilder {
+	b.params.CreateIfMissing = true
+	return b
+}
+
+// Time sets the times to t instead of the current time.
+func (b *TouchBuilder) Time(t time.Time) *TouchBuilder {
+	b.params.Time = &t
+	return b
+}
+
+// Build returns the task.
+func (b *TouchBuilder) Build() *Task {
+	params := b.params
+	params.BaseParameters = b.base
+	return NewTouchTask(b.taskId, b.description, params)
+}
+
+// DiskUsageBuilder builds a DISK_USAGE task that sizes the tree at path.
+type DiskUsageBuilder struct {
+	taskFields
+	params DiskUsageParameters
+}
+
+// NewDiskUsageBuilder starts a DISK_USAGE task that sizes the tree at path.
+func NewDiskUsageBuilder(path string) *DiskUsageBuilder {
+	return &DiskUsageBuilder{params: DiskUsageParameters{Path: path}}
+}
+
+// ID sets the task ID.
+func (b *DiskUsageBuilder) ID(taskId string) *DiskUsageBuilder {
+	b.taskId = taskId
+	return b
+}
+
+// Description sets the task description.
+func (b *DiskUsageBuilder) Description(description string) *DiskUsageBuilder {
+	b.description = description
+	return b
+}
+
+// WorkingDirectory sets the directory relative paths are resolved against.
+func (b *DiskUsageBuilder) WorkingDirectory(dir string) *DiskUsageBuilder {
+	b.base.WorkingDirectory = dir
+	return b
+}
+
+// Env adds an environment variable for the task.
+func (b *DiskUsageBuilder) Env(name, value string) *DiskUsageBuilder {
+	b.setEnv(name, value)
+	return b
+}
+
+// Build returns the task.
+func (b *DiskUsageBuilder) Build() *Task {
+	params := b.params
+	params.BaseParameters = b.base
+	return NewDiskUsageTask(b.taskId, b.description, params)
+}
+
+// WhichBuilder builds a WHICH task that locates the executable name.
+type WhichBuilder struct {
+	taskFields
+	params WhichParameters
+}
+
+// NewWhichBuilder starts a WHICH task that locates the executable name.
+func NewWhichBuilder(name string) *WhichBuilder {
+	return &WhichBuilder{params: WhichParameters{Name: name}}
+}
+
+// ID sets the task ID.
+func (b *WhichBuilder) ID(taskId string) *WhichBuilder {
+	b.taskId = taskId
+	return b
+}
+
+// Description sets the task description.
+func (b *WhichBuilder) Description(description string) *WhichBuilder {
+	b.description = description
+	return b
+}
+
+// WorkingDirectory sets the directory relative paths are resolved against.
+func (b *WhichBuilder) WorkingDirectory(dir string) *WhichBuilder {
+	b.base.WorkingDirectory = dir
+	return b
+}
+
+// Env adds an environment variable for the task.
+func (b *WhichBuilder) Env(name, value string) *WhichBuilder {
+	b.setEnv(name, value)
+	return b
+}
+
+// Build returns the task.
+func (b *WhichBuilder) Build() *Task {
+	params := b.params
+	params.BaseParameters = b.base
+	return NewWhichTask(b.taskId, b.description, params)
+}
+
+// EvalBuilder builds an EVAL task that evaluates expression.
+type EvalBuilder struct {
+	taskFields
+	params EvalParameters
+}
+
+// NewEvalBuilder starts an EVAL task that evaluates expression.
+func NewEvalBuilder(expression string) *EvalBuilder {
+	return &EvalBuilder{params: EvalParameters{Expression: expression}}
+}
+
+// ID sets the task ID.
+func (b *EvalBuilder) ID(taskId string) *EvalBuilder {
+	b.taskId = taskId
+	return b
+}
+
+// Description sets the task description.
+func (b *EvalBuilder) Description(description string) *EvalBuilder {
+	b.description = description
+	return b
+}
+
+// WorkingDirectory sets the directory relative paths are resolved against.
+func (b *EvalBuilder) WorkingDirectory(dir string) *EvalBuilder {
+	b.base.WorkingDirectory = dir
+	return b
+}
+
+// Env adds an environment variable for the task.
+func (b *EvalBuilder) Env(name, value string) *EvalBuilder {
+	b.setEnv(name, value)
+	return b
+}
+
+// Variable makes value available to the expression as name.
+func (b *EvalBuilder) Variable(name string, value any) *EvalBuilder {
+	if b.params.Variables == nil {
+		b.params.Variables = make(map[string]any)
+	}
+	b.params.Variables[name] = value
+	return b
+}
+
+// Build returns the task.
+func (b *EvalBuilder) Build() *Task {
+	params := b.params
+	params.BaseParameters = b.base
+	return NewEvalTask(b.taskId, b.description, params)
+}
+
+// NormalizeEOLBuilder builds a NORMALIZE_EOL task that rewrites the line endings of path to target.
+type NormalizeEOLBuilder struct {
+	taskFields
+	params NormalizeEOLParameters
+}
+
+// NewNormalizeEOLBuilder starts a NORMALIZE_EOL task that rewrites the line endings of path to target.
+func NewNormalizeEOLBuilder(path, target string) *NormalizeEOLBuilder {
+	return &NormalizeEOLBuilder{params: NormalizeEOLParameters{FilePath: path, Target: target}}
+}
+
+// ID sets the task ID.
+func (b *NormalizeEOLBuilder) ID(taskId string) *NormalizeEOLBuilder {
+	b.taskId = taskId
+	return b
+}
+
+// Description sets the task description.
+func (b *NormalizeEOLBuilder) Description(description string) *NormalizeEOLBuilder {
+	b.description = description
+	return b
+}
+
+// WorkingDirectory sets the directory relative paths are resolved against.
+func (b *NormalizeEOLBuilder) WorkingDirectory(dir string) *NormalizeEOLBuilder {
+	b.base.WorkingDirectory = dir
+	return b
+}
+
+// Env adds an environment variable for the task.
+func (b *NormalizeEOLBuilder) Env(name, value string) *NormalizeEOLBuilder {
+	b.setEnv(name, value)
+	return b
+}
+
+// Build returns the task.
+func (b *NormalizeEOLBuilder) Build() *Task {
+	params := b.params
+	params.BaseParameters = b.base
+	return NewNormalizeEOLTask(b.taskId, b.description, params)
+}
+
+// FileCompareAndSwapBuilder builds a FILE_COMPARE_AND_SWAP task that replaces the content of path with newContent if it is still expectedContent.
+type FileCompareAndSwapBuilder struct {
+	taskFields
+	params FileCompareAndSwapParameters
+}
+
+// NewFileCompareAndSwapBuilder starts a FILE_COMPARE_AND_SWAP task that replaces the content of path with newContent if it is still expectedContent.
+func NewFileCompareAndSwapBuilder(path, expectedContent, newContent string) *FileCompareAndSwapBuilder {
+	return &FileCompareAndSwapBuilder{params: FileCompareAndSwapParameters{FilePath: path, ExpectedContent: expectedContent, NewContent: newContent}}
+}
+
+// ID sets the task ID.
+func (b *FileCompareAndSwapBuilder) ID(taskId string) *FileCompareAndSwapBuilder {
+	b.taskId = taskId
+	return b
+}
+
+// Description sets the task description.
+func (b *FileCompareAndSwapBuilder) Description(description string) *FileCompareAndSwapBuilder {
+	b.description = description
+	return b
+}
+
+// WorkingDirectory sets the directory relative paths are resolved against.
+func (b *FileCompareAndSwapBuilder) WorkingDirectory(dir string) *FileCompareAndSwapBuilder {
+	b.base.WorkingDirectory = dir
+	return b
+}
+
+// Env adds an environment variable for the task.
+func (b *FileCompareAndSwapBuilder) Env(name, value string) *FileCompareAndSwapBuilder {
+	b.setEnv(name, value)
+	return b
+}
+
+// Build returns the task.
+func (b *FileCompareAndSwapBuilder) Build() *Task {
+	params := b.params
+	params.BaseParameters = b.base
+	return NewFileCompareAndSwapTask(b.taskId, b.description, params)
+}
+
+// ReadStructuredBuilder builds a READ_STRUCTURED task that parses path.
+type ReadStructuredBuilder struct {
+	taskFields
+	params ReadStructuredParameters
+}
+
+// NewReadStructuredBuilder starts a READ_STRUCTURED task that parses path.
+func NewReadStructuredBuilder(path string) *ReadStructuredBuilder {
+	return &ReadStructuredBuilder{params: ReadStructuredParameters{FilePath: path}}
+}
+
+// ID sets the task ID.
+func (b *ReadStructuredBuilder) ID(taskId string) *ReadStructuredBuilder {
+	b.taskId = taskId
+	return b
+}
+
+// Description sets the task description.
+func (b *ReadStructuredBuilder) Description(description string) *ReadStructuredBuilder {
+	b.description = description
+	return b
+}
+
+// WorkingDirectory sets the directory relative paths are resolved against.
+func (b *ReadStructuredBuilder) WorkingDirectory(dir string) *ReadStructuredBuilder {
+	b.base.WorkingDirectory = dir
+	return b
+}
+
+// Env adds an environment variable for the task.
+func (b *ReadStructuredBuilder) Env(name, value string) *ReadStructuredBuilder {
+	b.setEnv(name, value)
+	return b
+}
+
+// Format sets the format instead of inferring it from the file extension.
+func (b *ReadStructuredBuilder) Format(format string) *ReadStructuredBuilder {
+	b.params.Format = format
+	return b
+}
+
+// Build returns the task.
+func (b *ReadStructuredBuilder) Build() *Task {
+	params := b.params
+	params.BaseParameters = b.base
+	return NewReadStructuredTask(b.taskId, b.description, params)
+}
+
+// ExtractJSONBuilder builds an EXTRACT_JSON task that selects path from the JSON document input.
+type ExtractJSONBuilder struct {
+	taskFields
+	params ExtractJSONParameters
+}
+
+// NewExtractJSONBuilder starts an EXTRACT_JSON task that selects path from the JSON document input.
+func NewExtractJSONBuilder(input, path string) *ExtractJSONBuilder {
+	return &ExtractJSONBuilder{params: ExtractJSONParameters{Input: input, Path: path}}
+}
+
+// ID sets the task ID.
+func (b *ExtractJSONBuilder) ID(taskId string) *ExtractJSONBuilder {
+	b.taskId = taskId
+	return b
+}
+
+// Description sets the task description.
+func (b *ExtractJSONBuilder) Description(description string) *ExtractJSONBuilder {
+	b.description = description
+	return b
+}
+
+// WorkingDirectory sets the directory relative paths are resolved against.
+func (b *ExtractJSONBuilder) WorkingDirectory(dir string) *ExtractJSONBuilder {
+	b.base.WorkingDirectory = dir
+	return b
+}
+
+// Env adds an environment variable for the task.
+func (b *ExtractJSONBuilder) Env(name, value string) *ExtractJSONBuilder {
+	b.setEnv(name, value)
+	return b
+}
+
+// Build returns the task.
+func (b *ExtractJSONBuilder) Build() *Task {
+	params := b.params
+	params.BaseParameters = b.base
+	return NewExtractJSONTask(b.taskId, b.description, params)
+}
+
+// ValidatePatchBuilder builds a VALIDATE_PATCH task that checks patch.
+type ValidatePatchBuilder struct {
+	taskFields
+	params ValidatePatchParameters
+}
+
+// NewValidatePatchBuilder starts a VALIDATE_PATCH task that checks patch.
+func NewValidatePatchBuilder(patch string) *ValidatePatchBuilder {
+	return &ValidatePatchBuilder{params: ValidatePatchParameters{Patch: patch}}
+}
+
+// ID sets the task ID.
+func (b *ValidatePatchBuilder) ID(taskId string) *ValidatePatchBuilder {
+	b.taskId = taskId
+	return b
+}
+
+// Description sets the task description.
+func (b *ValidatePatchBuilder) Description(description string) *ValidatePatchBuilder {
+	b.description = description
+	return b
+}
+
+// WorkingDirectory sets the directory relative paths are resolved against.
+func (b *ValidatePatchBuilder) WorkingDirectory(dir string) *ValidatePatchBuilder {
+	b.base.WorkingDirectory = dir
+	return b
+}
+
+// Env adds an environment variable for the task.
+func (b *ValidatePatchBuilder) Env(name, value string) *ValidatePatchBuilder {
+	b.setEnv(name, value)
+	return b
+}
+
+// Build returns the task.
+func (b *ValidatePatchBuilder) Build() *Task {
+	params := b.params
+	params.BaseParameters = b.base
+	return NewValidatePatchTask(b.taskId, b.description, params)
+}
+
+// ManifestBuilder builds a MANIFEST task that writes a manifest to outputPath.
+type ManifestBuilder struct {
+	taskFields
+	params ManifestParameters
+}
+
+// NewManifestBuilder starts a MANIFEST task that writes a manifest to outputPath.
+func NewManifestBuilder(outputPath string) *ManifestBuilder {
+	return &ManifestBuilder{params: ManifestParameters{OutputPath: outputPath}}
+}
+
+// ID sets the task ID.
+func (b *ManifestBuilder) ID(taskId string) *ManifestBuilder {
+	b.taskId = taskId
+	return b
+}
+
+// Description sets the task description.
+func (b *ManifestBuilder) Description(description string) *ManifestBuilder {
+	b.description = description
+	return b
+}
+
+// WorkingDirectory sets the directory relative paths are resolved against.
+func (b *ManifestBuilder) WorkingDirectory(dir string) *ManifestBuilder {
+	b.base.WorkingDirectory = dir
+	return b
+}
+
+// Env adds an environment variable for the task.
+func (b *ManifestBuilder) Env(name, value string) *ManifestBuilder {
+	b.setEnv(name, value)
+	return b
+}
+
+// Path sets the root of the tree to hash instead of the working directory.
+func (b *ManifestBuilder) Path(root string) *ManifestBuilder {
+	b.params.Path = root
+	return b
+}
+
+// Format sets the manifest format, such as ManifestFormatJSON.
+func (b *ManifestBuilder) Format(format string) *ManifestBuilder {
+	b.params.Format = format
+	return b
+}
+
+// Build returns the task.
+func (b *ManifestBuilder) Build() *Task {
+	params := b.params
+	params.BaseParameters = b.base
+	return NewManifestTask(b.taskId, b.description, params)
+}
+
+// ReadLinkBuilder builds a READ_LINK task that reads the symbolic link at path.
+type ReadLinkBuilder struct {
+	taskFields
+	params ReadLinkParameters
+}
+
+// NewReadLinkBuilder starts a READ_LINK task that reads the symbolic link at path.
+func NewReadLinkBuilder(path string) *ReadLinkBuilder {
+	return &ReadLinkBuilder{params: ReadLinkParameters{Path: path}}
+}
+
+// ID sets the task ID.
+func (b *ReadLinkBuilder) ID(taskId string) *ReadLinkBuilder {
+	b.taskId = taskId
+	return b
+}
+
+// Description sets the task description.
+func (b *ReadLinkBuilder) Description(description string) *ReadLinkBuilder {
+	b.description = description
+	return b
+}
+
+// WorkingDirectory sets the directory relative paths are resolved against.
+func (b *ReadLinkBuilder) WorkingDirectory(dir string) *ReadLinkBuilder {
+	b.base.WorkingDirectory = dir
+	return b
+}
+
+// Env adds an environment variable for the task.
+func (b *ReadLinkBuilder) Env(name, value string) *ReadLinkBuilder {
+	b.setEnv(name, value)
+	return b
+}
+
+// Build returns the task.
+func (b *ReadLinkBuilder) Build() *Task {
+	params := b.params
+	params.BaseParameters = b.base
+	return NewReadLinkTask(b.taskId, b.description, params)
+}
+
+// PatchFilesBuilder builds a PATCH_FILES task that applies patch to several files.
+type PatchFilesBuilder struct {
+	taskFields
+	params PatchFilesParameters
+}
+
+// NewPatchFilesBuilder starts a PATCH_FILES task that applies patch to several files.
+func NewPatchFilesBuilder(patch string) *PatchFilesBuilder {
+	return &PatchFilesBuilder{params: PatchFilesParameters{Patch: patch}}
+}
+
+// ID sets the task ID.
+func (b *PatchFilesBuilder) ID(taskId string) *PatchFilesBuilder {
+	b.taskId = taskId
+	return b
+}
+
+// Description sets the task description.
+func (b *PatchFilesBuilder) Description(description string) *PatchFilesBuilder {
+	b.description = description
+	return b
+}
+
+// WorkingDirectory sets the directory relative paths are resolved against.
+func (b *PatchFilesBuilder) WorkingDirectory(dir string) *PatchFilesBuilder {
+	b.base.WorkingDirectory = dir
+	return b
+}
+
+// Env adds an environment variable for the task.
+func (b *PatchFilesBuilder) Env(name, value string) *PatchFilesBuilder {
+	b.setEnv(name, value)
+	return b
+}
+
+// File adds files to patch.
+func (b *PatchFilesBuilder) File(paths ...string) *PatchFilesBuilder {
+	b.params.Files = append(b.params.Files, paths...)
+	return b
+}
+
+// Transactional patches all files or none.
+func (b *PatchFilesBuilder) Transactional() *PatchFilesBuilder {
+	b.params.Transactional = true
+	return b
+}
+
+// IgnoreTrailingWhitespace ignores trailing spaces and tabs when matching lines.
+func (b *PatchFilesBuilder) IgnoreTrailingWhitespace() *PatchFilesBuilder {
+	b.params.IgnoreTrailingWhitespace = true
+	return b
+}
+
+// Build returns the task.
+func (b *PatchFilesBuilder) Build() *Task {
+	params := b.params
+	params.BaseParameters = b.base
+	return NewPatchFilesTask(b.taskId, b.description, params)
+}
+
+// TruncateBuilder builds a TRUNCATE task that sets the size of path.
+type TruncateBuilder struct {
+	taskFields
+	params TruncateParameters
+}
+
+// NewTruncateBuilder starts a TRUNCATE task that sets the size of path to size bytes.
+func NewTruncateBuilder(path string, size int64) *TruncateBuilder {
+	return &TruncateBuilder{params: TruncateParameters{FilePath: path, Size: size}}
+}
+
+// ID sets the task ID.
+func (b *TruncateBuilder) ID(taskId string) *TruncateBuilder {
+	b.taskId = taskId
+	return b
+}
+
+// Description sets the task description.
+func (b *TruncateBuilder) Description(description string) *TruncateBuilder {
+	b.description = description
+	return b
+}
+
+// WorkingDirectory sets the directory relative paths are resolved against.
+func (b *TruncateBuilder) WorkingDirectory(dir string) *TruncateBuilder {
+	b.base.WorkingDirectory = dir
+	return b
+}
+
+// Env adds an environment variable for the task.
+func (b *TruncateBuilder) Env(name, value string) *TruncateBuilder {
+	b.setEnv(name, value)
+	return b
+}
+
+// Build returns the task.
+func (b *TruncateBuilder) Build() *Task {
+	params := b.params
+	params.BaseParameters = b.base
+	return NewTruncateTask(b.taskId, b.description, params)
+}
+
+// CompareTreesBuilder builds a COMPARE_TREES task that compares right against left.
+type CompareTreesBuilder struct {
+	taskFields
+	params CompareTreesParameters
+}
+
+// NewCompareTreesBuilder starts a COMPARE_TREES task that compares the tree at right against the one at left.
+func NewCompareTreesBuilder(left, right string) *CompareTreesBuilder {
+	return &CompareTreesBuilder{params: CompareTreesParameters{Left: left, Right: right}}
+}
+
+// ID sets the task ID.
+func (b *CompareTreesBuilder) ID(taskId string) *CompareTreesBuilder {
+	b.taskId = taskId
+	return b
+}
+
+// Description sets the task description.
+func (b *CompareTreesBuilder) Description(description string) *CompareTreesBuilder {
+	b.description = description
+	return b
+}
+
+// WorkingDirectory sets the directory relative paths are resolved against.
+func (b *CompareTreesBuilder) WorkingDirectory(dir string) *CompareTreesBuilder {
+	b.base.WorkingDirectory = dir
+	return b
+}
+
+// Env adds an environment variable for the task.
+func (b *CompareTreesBuilder) Env(name, value string) *CompareTreesBuilder {
+	b.setEnv(name, value)
+	return b
+}
+
+// Build returns the task.
+func (b *CompareTreesBuilder) Build() *Task {
+	params := b.params
+	params.BaseParameters = b.base
+	return NewCompareTreesTask(b.taskId, b.description, params)
+}
+
+// FileMoveBuilder builds a FILE_MOVE task.
+type FileMoveBuilder struct {
+	taskFields
+	params FileMoveParameters
+}
+
+// NewFileMoveBuilder starts a FILE_MOVE task that moves source to destination.
+func NewFileMoveBuilder(source, destination string) *FileMoveBuilder {
+	return &FileMoveBuilder{params: FileMoveParameters{Source: source, Destination: destination}}
+}
+
+// ID sets the task ID.
+func (b *FileMoveBuilder) ID(taskId string) *FileMoveBuilder {
+	b.taskId = taskId
+	return b
+}
+
+// Description sets the task description.
+func (b *FileMoveBuilder) Description(description string) *FileMoveBuilder {
+	b.description = description
+	return b
+}
+
+// WorkingDirectory sets the directory relative paths are resolved against.
+func (b *FileMoveBuilder) WorkingDirectory(dir string) *FileMoveBuilder {
+	b.base.WorkingDirectory = dir
+	return b
+}
+
+// Env adds an environment variable for the task.
+func (b *FileMoveBuilder) Env(name, value string) *FileMoveBuilder {
+	b.setEnv(name, value)
+	return b
+}
+
+// Overwrite allows an existing destination to be replaced.
+func (b *FileMoveBuilder) Overwrite() *FileMoveBuilder {
+	b.params.Overwrite = true
+	return b
+}
+
+// Build returns the task.
+func (b *FileMoveBuilder) Build() *Task {
+	params := b.params
+	params.BaseParameters = b.base
+	return NewFileMoveTask(b.taskId, b.description, params)
+}
+
+// FileCopyBuilder builds a FILE_COPY task.
+type FileCopyBuilder struct {
+	taskFields
+	params FileCopyParameters
+}
+
+// NewFileCopyBuilder starts a FILE_COPY task that copies source to destination.
+func NewFileCopyBuilder(source, destination string) *FileCopyBuilder {
+	return &FileCopyBuilder{params: FileCopyParameters{Source: source, Destination: destination}}
+}
+
+// ID sets the task ID.
+func (b *FileCopyBuilder) ID(taskId string) *FileCopyBuilder {
+	b.taskId = taskId
+	return b
+}
+
+// Description sets the task description.
+func (b *FileCopyBuilder) Description(description string) *FileCopyBuilder {
+	b.description = description
+	return b
+}
+
+// WorkingDirectory sets the directory relative paths are resolved against.
+func (b *FileCopyBuilder) WorkingDirectory(dir string) *FileCopyBuilder {
+	b.base.WorkingDirectory = dir
+	return b
+}
+
+// Env adds an environment variable for the task.
+func (b *FileCopyBuilder) Env(name, value string) *FileCopyBuilder {
+	b.setEnv(name, value)
+	return b
+}
+
+// Recursive allows the source to be a directory tree.
+func (b *FileCopyBuilder) Recursive() *FileCopyBuilder {
+	b.params.Recursive = true
+	return b
+}
+
+// Overwrite allows existing files at the destination to be replaced.
+func (b *FileCopyBuilder) Overwrite() *FileCopyBuilder {
+	b.params.Overwrite = true
+	return b
+}
+
+// Include limits the copy to files matching the given glob patterns.
+func (b *FileCopyBuilder) Include(patterns ...string) *FileCopyBuilder {
+	b.params.Include = append(b.params.Include, patterns...)
+	return b
+}
+
+// Exclude skips files and directories matching the given glob patterns.
+func (b *FileCopyBuilder) Exclude(patterns ...string) *FileCopyBuilder {
+	b.params.Exclude = append(b.params.Exclude, patterns...)
+	return b
+}
+
+// Build returns the task.
+func (b *FileCopyBuilder) Build() *Task {
+	params := b.params
+	params.BaseParameters = b.base
+	return NewFileCopyTask(b.taskId, b.description, params)
+}
+
+// GroupBuilder builds a GROUP task.
+type GroupBuilder struct {
+	taskId      string
+	description string
+	children    []*Task
+	params      GroupParameters
+}
+
+// NewGroupBuilder starts a GROUP task that runs children in order.
+func NewGroupBuilder(children ...*Task) *GroupBuilder {
+	return &GroupBuilder{children: children}
+}
+
+// ID sets the task ID.
+func (b *GroupBuilder) ID(taskId string) *GroupBuilder {
+	b.taskId = taskId
+	return b
+}
+
+// Description sets the task description.
+func (b *GroupBuilder) Description(description string) *GroupBuilder {
+	b.description = description
+	return b
+}
+
+// Child adds a task to run after the others.
+func (b *GroupBuilder) Child(child *Task) *GroupBuilder {
+	b.children = append(b.children, child)
+	return b
+}
+
+// ForwardChildOutput re-emits the output of the children on the group's stream.
+func (b *GroupBuilder) ForwardChildOutput() *GroupBuilder {
+	b.params.ForwardChildOutput = true
+	return b
+}
+
+// MaxRuntime caps the wall-clock time of the whole group.
+func (b *GroupBuilder) MaxRuntime(d time.Duration) *GroupBuilder {
+	b.params.MaxRuntimeMs = d.Milliseconds()
+	return b
+}
+
+// Parallel runs the children concurrently, at most maxConcurrency at a time.
+// A maxConcurrency of zero defaults to the number of CPUs.
+func (b *GroupBuilder) Parallel(maxConcurrency int) *GroupBuilder {
+	b.params.Parallel = true
+	b.params.MaxConcurrency = maxConcurrency
+	return b
+}
+
+// Build returns the task. Its Parameters are only set when an option was used.
+func (b *GroupBuilder) Build() *Task {
+	t := NewGroupTask(b.taskId, b.description, b.children)
+	if b.params != (GroupParameters{}) {
+		t.Parameters = b.params
+	}
+	return t
+}
@@ -0,0 +1,196 @@
+package task
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilders_MatchStructLiterals(t *testing.T) {
+	quiet := false
+	touchTime := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	reader := strings.NewReader("--- a/f\n+++ b/f\n")
+	base := BaseParameters{WorkingDirectory: "/work", Env: map[string]string{"MODE": "ci"}}
+	child := NewWhichTask("which-go", "", WhichParameters{Name: "go"})
+
+	testCases := []struct {
+		name     string
+		built    *Task
+		expected *Task
+	}{
+		{
+			name: "BashExec",
+			built: NewBashExecBuilder("make test").ID("bash").Description("Run tests").WorkingDirectory("/work").Env("MODE", "ci").
+				CaptureOutput(false).LineTransform("strip-ansi").RequiredGlobs("*.go", "go.mod").LoginShell().CapturePID().PIDFile("make.pid").JSONLines().AllowedExitCodes(0, 1).CPUTimeLimit(30).MemoryLimit(1 << 30).Build(),
+			expected: NewBashExecTask("bash", "Run tests", BashExecParameters{
+				BaseParameters: base, Command: "make test", CaptureOutput: &quiet, LineTransform: "strip-ansi",
+				RequiredGlobs: []string{"*.go", "go.mod"}, LoginShell: true, CapturePID: true, PIDFile: "make.pid",
+				JSONLines: true, AllowedExitCodes: []int{0, 1}, CPUTimeLimitSeconds: 30, MemoryLimitBytes: 1 << 30,
+			}),
+		},
+		{
+			name: "FileRead",
+			built: NewFileReadBuilder("main.go").ID("read").Lines(2, 5).StartByte(10).HeadBytes(1024).Encoding("text").Incremental().
+				MaxLines(3).Markers("BEGIN", "END").OnMissingEndMarker("error").TrimTrailingWhitespace().ExpandTabs(4).AutoDetectEncoding().LinesPerChunk(10).OutputFormat(FileReadOutputLinesJSON).Build(),
+			expected: NewFileReadTask("read", "", FileReadParameters{
+				FilePath: "main.go", StartLine: 2, EndLine: 5, StartByte: 10, HeadBytes: 1024, Encoding: "text", Incremental: true,
+				MaxLines: 3, StartMarker: "BEGIN", EndMarker: "END", OnMissingEndMarker: "error", TrimTrailingWhitespace: true, ExpandTabs: 4,
+				AutoDetectEncoding: true, LinesPerChunk: 10, OutputFormat: FileReadOutputLinesJSON,
+			}),
+		},
+		{
+			name:     "FileWrite",
+			built:    NewFileWriteBuilder("out.txt", "data").ID("write").Overwrite().Checksum(ChecksumSHA256).Build(),
+			expected: NewFileWriteTask("write", "", FileWriteParameters{FilePath: "out.txt", Content: "data", Overwrite: true, Checksum: ChecksumSHA256}),
+		},
+		{
+			name:     "FileWriteAppend",
+			built:    NewFileWriteBuilder("log.txt", "line\n").ID("append").Append().Build(),
+			expected: NewFileWriteTask("append", "", FileWriteParameters{FilePath: "log.txt", Content: "line\n", Append: true}),
+		},
+		{
+			name:  "WriteFiles",
+			built: NewWriteFilesBuilder().ID("files").File("a.txt", "a").File("b.txt", "b").Transactional().Build(),
+			expected: NewWriteFilesTask("files", "", WriteFilesParameters{
+				Files: []FileWriteEntry{{Path: "a.txt", Content: "a"}, {Path: "b.txt", Content: "b"}}, Transactional: true,
+			}),
+		},
+		{
+			name: "PatchFile",
+			built: NewPatchFileBuilder("f.txt", "").ID("patch").PatchPath("f.patch").PatchReader(reader).ExpectedResult("abc").
+				ExpectedSHA("def", "012").IgnoreTrailingWhitespace().IncludeDiff().BaseDirectory("/repo").Strip(1).BestEffort().Build(),
+			expected: NewPatchFileTask("patch", "", PatchFileParameters{
+				FilePath: "f.txt", PatchPath: "f.patch", PatchReader: reader, ExpectedResult: "abc", ExpectedSHA: []string{"def", "012"},
+				IgnoreTrailingWhitespace: true, IncludeDiff: true, BaseDirectory: "/repo", Strip: 1, BestEffort: true,
+			}),
+		},
+		{
+			name:     "ListDirectory",
+			built:    NewListDirectoryBuilder("src").ID("list").SortBy(ListSortSizeDesc).Build(),
+			expected: NewListDirectoryTask("list", "", ListDirectoryParameters{Path: "src", SortBy: ListSortSizeDesc}),
+		},
+		{
+			name:     "RequestUserInput",
+			built:    NewRequestUserInputBuilder("Continue?").ID("ask").Build(),
+			expected: NewRequestUserInputTask("ask", "", RequestUserInputParameters{Prompt: "Continue?"}),
+		},
+		{
+			name:     "Touch",
+			built:    NewTouchBuilder("stamp").ID("touch").CreateIfMissing().Time(touchTime).Build(),
+			expected: NewTouchTask("touch", "", TouchParameters{FilePath: "stamp", CreateIfMissing: true, Time: &touchTime}),
+		},
+		{
+			name:     "DiskUsage",
+			built:    NewDiskUsageBuilder("build").ID("du").Build(),
+			expected: NewDiskUsageTask("du", "", DiskUsageParameters{Path: "build"}),
+		},
+		{
+			name:     "Which",
+			built:    NewWhichBuilder("go").ID("which").Build(),
+			expected: NewWhichTask("which", "", WhichParameters{Name: "go"}),
+		},
+		{
+			name:     "Eval",
+			built:    NewEvalBuilder("count > limit").ID("eval").Variable("count", 3).Variable("limit", 2).Build(),
+			expected: NewEvalTask("eval", "", EvalParameters{Expression: "count > limit", Variables: map[string]any{"count": 3, "limit": 2}}),
+		},
+		{
+			name:     "NormalizeEOL",
+			built:    NewNormalizeEOLBuilder("win.txt", EOLTargetLF).ID("eol").Build(),
+			expected: NewNormalizeEOLTask("eol", "", NormalizeEOLParameters{FilePath: "win.txt", Target: EOLTargetLF}),
+		},
+		{
+			name:     "FileCompareAndSwap",
+			built:    NewFileCompareAndSwapBuilder("lock", "old", "new").ID("cas").Build(),
+			expected: NewFileCompareAndSwapTask("cas", "", FileCompareAndSwapParameters{FilePath: "lock", ExpectedContent: "old", NewContent: "new"}),
+		},
+		{
+			name:     "ReadStructured",
+			built:    NewReadStructuredBuilder("config").ID("structured").Format("yaml").Build(),
+			expected: NewReadStructuredTask("structured", "", ReadStructuredParameters{FilePath: "config", Format: "yaml"}),
+		},
+		{
+			name:     "ExtractJSON",
+			built:    NewExtractJSONBuilder(`{"a": 1}`, "a").ID("extract").Build(),
+			expected: NewExtractJSONTask("extract", "", ExtractJSONParameters{Input: `{"a": 1}`, Path: "a"}),
+		},
+		{
+			name:     "ValidatePatch",
+			built:    NewValidatePatchBuilder("--- a\n+++ b\n").ID("validate").Build(),
+			expected: NewValidatePatchTask("validate", "", ValidatePatchParameters{Patch: "--- a\n+++ b\n"}),
+		},
+		{
+			name:     "Manifest",
+			built:    NewManifestBuilder("SHA256SUMS").ID("manifest").Path("dist").Format(ManifestFormatJSON).Build(),
+			expected: NewManifestTask("manifest", "", ManifestParameters{OutputPath: "SHA256SUMS", Path: "dist", Format: ManifestFormatJSON}),
+		},
+		{
+			name:     "ReadLink",
+			built:    NewReadLinkBuilder("current").ID("link").WorkingDirectory("/work").Build(),
+			expected: NewReadLinkTask("link", "", ReadLinkParameters{Path: "current", BaseParameters: BaseParameters{WorkingDirectory: "/work"}}),
+		},
+		{
+			name:  "PatchFiles",
+			built: NewPatchFilesBuilder("diff").ID("bulk").File("a.go", "b.go").File("c.go").Transactional().IgnoreTrailingWhitespace().Build(),
+			expected: NewPatchFilesTask("bulk", "", PatchFilesParameters{
+				Files: []string{"a.go", "b.go", "c.go"}, Patch: "diff", Transactional: true, IgnoreTrailingWhitespace: true,
+			}),
+		},
+		{
+			name:     "Truncate",
+			built:    NewTruncateBuilder("app.log", 0).ID("truncate").Description("Rotate the log").Build(),
+			expected: NewTruncateTask("truncate", "Rotate the log", TruncateParameters{FilePath: "app.log"}),
+		},
+		{
+			name:     "CompareTrees",
+			built:    NewCompareTreesBuilder("src", "dist").ID("compare").WorkingDirectory("/work").Build(),
+			expected: NewCompareTreesTask("compare", "", CompareTreesParameters{BaseParameters: BaseParameters{WorkingDirectory: "/work"}, Left: "src", Right: "dist"}),
+		},
+		{
+			name:     "FileMove",
+			built:    NewFileMoveBuilder("build/app", "dist/app").ID("move").Overwrite().Build(),
+			expected: NewFileMoveTask("move", "", FileMoveParameters{Source: "build/app", Destination: "dist/app", Overwrite: true}),
+		},
+		{
+			name:     "FileCopy",
+			built:    NewFileCopyBuilder("assets", "dist/assets").ID("copy").Recursive().Exclude(".git").Include("*.css", "*.html").Build(),
+			expected: NewFileCopyTask("copy", "", FileCopyParameters{Source: "assets", Destination: "dist/assets", Recursive: true, Include: []string{"*.css", "*.html"}, Exclude: []string{".git"}}),
+		},
+		{
+			name:     "Group",
+			built:    NewGroupBuilder().ID("group").Description("Checks").Child(child).Build(),
+			expected: NewGroupTask("group", "Checks", []*Task{child}),
+		},
+		{
+			name:  "GroupWithParameters",
+			built: NewGroupBuilder(child).ID("group").ForwardChildOutput().MaxRuntime(2 * time.Second).Build(),
+			expected: func() *Task {
+				group := NewGroupTask("group", "", []*Task{child})
+				group.Parameters = GroupParameters{ForwardChildOutput: true, MaxRuntimeMs: 2000}
+				return group
+			}(),
+		},
+		{
+			name:  "ParallelGroup",
+			built: NewGroupBuilder(child).ID("group").Parallel(3).Build(),
+			expected: func() *Task {
+				group := NewGroupTask("group", "", []*Task{child})
+				group.Parameters = GroupParameters{Parallel: true, MaxConcurrency: 3}
+				return group
+			}(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.built)
+		})
+	}
+}
+
+func TestBuilders_Defaults(t *testing.T) {
+	assert.Equal(t, NewFileReadTask("", "", FileReadParameters{FilePath: "main.go"}), NewFileReadBuilder("main.go").Build())
+	assert.Equal(t, NewGroupTask("", "", nil), NewGroupBuilder().Build())
+}
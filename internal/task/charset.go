@@ -0,0 +1,213 @@
+package task
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Charsets reported by detectCharset.
+const (
+	charsetUTF8    = "UTF-8"
+	charsetUTF16LE = "UTF-16LE"
+	charsetUTF16BE = "UTF-16BE"
+	charsetLatin1  = "ISO-8859-1"
+)
+
+const (
+	// charsetSampleSize is how much of the start of the content the charset is detected from.
+	charsetSampleSize = 64 * 1024
+	// utf16ZeroShare is the share of code units that must have a zero high byte for
+	// content without a byte order mark to be taken as UTF-16, as in mostly-ASCII text.
+	utf16ZeroShare = 0.4
+	// latin1ControlShare is the largest share of control bytes Latin-1 text may contain.
+	latin1ControlShare = 0.02
+)
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// detectCharset guesses the charset of the content r reads from a sample of at most
+// charsetSampleSize bytes at its start, and returns a reader of the content decoded to
+// UTF-8 as it is read, without any byte order mark. charset is empty when no charset
+// is a confident match, such as for binary data, in which case the reader returns the
+// content unchanged.
+func detectCharset(r io.Reader) (decoded io.Reader, charset string, err error) {
+	sample := make([]byte, charsetSampleSize)
+	n, err := io.ReadFull(r, sample)
+	complete := errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+	if err != nil && !complete {
+		return nil, "", err
+	}
+	sample = sample[:n]
+	content := io.MultiReader(bytes.NewReader(sample), r)
+	skipBOM := func(bom []byte) io.Reader {
+		return io.MultiReader(bytes.NewReader(sample[len(bom):]), r)
+	}
+
+	switch {
+	case bytes.HasPrefix(sample, bomUTF8):
+		return skipBOM(bomUTF8), charsetUTF8, nil
+	case bytes.HasPrefix(sample, bomUTF16LE):
+		return newDecodingReader(skipBOM(bomUTF16LE), utf16Decoder(binary.LittleEndian)), charsetUTF16LE, nil
+	case bytes.HasPrefix(sample, bomUTF16BE):
+		return newDecodingReader(skipBOM(bomUTF16BE), utf16Decoder(binary.BigEndian)), charsetUTF16BE, nil
+	}
+
+	// UTF-16 is checked before UTF-8 because the zero bytes of UTF-16 text are valid UTF-8
+	if charset, ok := sniffUTF16(sample); ok {
+		order := binary.ByteOrder(binary.LittleEndian)
+		if charset == charsetUTF16BE {
+			order = binary.BigEndian
+		}
+		return newDecodingReader(content, utf16Decoder(order)), charset, nil
+	}
+	if validUTF8Sample(sample, complete) {
+		return content, charsetUTF8, nil
+	}
+	if looksLikeLatin1(sample) {
+		return newDecodingReader(content, decodeLatin1), charsetLatin1, nil
+	}
+	return content, "", nil
+}
+
+// validUTF8Sample reports whether sample is valid UTF-8. Unless the sample is the
+// complete content, a rune cut short at its end is ignored.
+func validUTF8Sample(sample []byte, complete bool) bool {
+	if !complete {
+		for i := len(sample) - 1; i >= max(0, len(sample)-utf8.UTFMax); i-- {
+			if utf8.RuneStart(sample[i]) {
+				if !utf8.FullRune(sample[i:]) {
+					sample = sample[:i]
+				}
+				break
+			}
+		}
+	}
+	return utf8.Valid(sample)
+}
+
+// sniffUTF16 reports whether a sample without a byte order mark looks like UTF-16 text,
+// judging by how many code units have a zero high byte.
+func sniffUTF16(sample []byte) (string, bool) {
+	units := len(sample) / 2
+	if units == 0 || len(sample)%2 != 0 {
+		return "", false
+	}
+	var evenZeros, oddZeros int
+	for i := 0; i+1 < len(sample); i += 2 {
+		if sample[i] == 0 {
+			evenZeros++
+		}
+		if sample[i+1] == 0 {
+			oddZeros++
+		}
+	}
+	threshold := int(float64(units) * utf16ZeroShare)
+	switch {
+	case oddZeros > threshold && evenZeros == 0:
+		return charsetUTF16LE, true
+	case evenZeros > threshold && oddZeros == 0:
+		return charsetUTF16BE, true
+	}
+	return "", false
+}
+
+// looksLikeLatin1 reports whether a sample reads as ISO-8859-1 text, that is it has
+// no NUL bytes and few control characters other than whitespace.
+func looksLikeLatin1(sample []byte) bool {
+	controls := 0
+	for _, b := range sample {
+		switch {
+		case b == 0:
+			return false
+		case b == '\t' || b == '\n' || b == '\r' || b == '\f':
+		case b < 0x20 || (b >= 0x7F && b < 0xA0):
+			controls++
+		}
+	}
+	return float64(controls) <= float64(len(sample))*latin1ControlShare
+}
+
+// decodingReader converts the content of r to UTF-8 one chunk at a time. decode
+// appends the UTF-8 form of src to dst and returns it with the number of bytes of src
+// it consumed; bytes it leaves, such as half of a UTF-16 surrogate pair, are decoded
+// with the next chunk. atEOF is set once r has no more content.
+type decodingReader struct {
+	r      io.Reader
+	decode func(dst, src []byte, atEOF bool) ([]byte, int)
+	raw    []byte
+	in     []byte
+	out    []byte
+	err    error
+}
+
+// decodeChunkSize is how many bytes a decodingReader reads from its source at a time.
+const decodeChunkSize = 32 * 1024
+
+func newDecodingReader(r io.Reader, decode func(dst, src []byte, atEOF bool) ([]byte, int)) *decodingReader {
+	return &decodingReader{r: r, decode: decode, raw: make([]byte, decodeChunkSize)}
+}
+
+func (d *decodingReader) Read(p []byte) (int, error) {
+	for len(d.out) == 0 {
+		if d.err != nil {
+			return 0, d.err
+		}
+		n, err := d.r.Read(d.raw)
+		d.in = append(d.in, d.raw[:n]...)
+		d.err = err
+		var used int
+		d.out, used = d.decode(d.out[:0], d.in, err != nil)
+		d.in = d.in[:copy(d.in, d.in[used:])]
+	}
+	n := copy(p, d.out)
+	d.out = d.out[n:]
+	return n, nil
+}
+
+// utf16Decoder returns a decodingReader decode function for UTF-16 in the given byte
+// order. Unpaired surrogates become U+FFFD, and a trailing odd byte is dropped.
+func utf16Decoder(order binary.ByteOrder) func(dst, src []byte, atEOF bool) ([]byte, int) {
+	return func(dst, src []byte, atEOF bool) ([]byte, int) {
+		i := 0
+		for ; i+1 < len(src); i += 2 {
+			r := rune(order.Uint16(src[i:]))
+			if utf16.IsSurrogate(r) && r < 0xDC00 {
+				if i+3 >= len(src) {
+					if !atEOF {
+						// The low surrogate is in the next chunk
+						break
+					}
+				} else if pair := utf16.DecodeRune(r, rune(order.Uint16(src[i+2:]))); pair != utf8.RuneError {
+					dst = utf8.AppendRune(dst, pair)
+					i += 2
+					continue
+				}
+				r = utf8.RuneError
+			} else if utf16.IsSurrogate(r) {
+				r = utf8.RuneError
+			}
+			dst = utf8.AppendRune(dst, r)
+		}
+		if atEOF {
+			return dst, len(src)
+		}
+		return dst, i
+	}
+}
+
+// decodeLatin1 is a decodingReader decode function for ISO-8859-1, in which every
+// byte is the code point of the same value.
+func decodeLatin1(dst, src []byte, atEOF bool) ([]byte, int) {
+	for _, c := range src {
+		dst = utf8.AppendRune(dst, rune(c))
+	}
+	return dst, len(src)
+}
@@ -0,0 +1,108 @@
+package task
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingReader counts the bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func TestDetectCharset_DecodesAcrossReads(t *testing.T) {
+	const text = "a😀b\nünïcode 𝄞\n"
+	var utf16LE []byte
+	for _, unit := range utf16.Encode([]rune(text)) {
+		utf16LE = binary.LittleEndian.AppendUint16(utf16LE, unit)
+	}
+
+	testCases := []struct {
+		name            string
+		content         []byte
+		expectedCharset string
+		expectedText    string
+	}{
+		{name: "UTF-16LE", content: append([]byte{0xFF, 0xFE}, utf16LE...), expectedCharset: charsetUTF16LE, expectedText: text},
+		{name: "UnpairedSurrogate", content: []byte{0xFF, 0xFE, 0x3D, 0xD8, 'a', 0}, expectedCharset: charsetUTF16LE, expectedText: "�a"},
+		{name: "OddTrailingByte", content: []byte{0xFE, 0xFF, 0, 'a', 'b'}, expectedCharset: charsetUTF16BE, expectedText: "a"},
+		{name: "Latin-1", content: []byte("caf\xe9\n"), expectedCharset: charsetLatin1, expectedText: "café\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// One byte at a time splits every surrogate pair across reads
+			decoded, charset, err := detectCharset(iotest.OneByteReader(bytes.NewReader(tc.content)))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedCharset, charset)
+
+			out, err := io.ReadAll(iotest.OneByteReader(decoded))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedText, string(out))
+		})
+	}
+}
+
+func TestDetectCharset_ReadsOnDemand(t *testing.T) {
+	var content []byte
+	content = binary.BigEndian.AppendUint16(content, 0xFEFF)
+	line := strings.Repeat("x", 99) + "\n"
+	for range 10_000 {
+		for _, unit := range utf16.Encode([]rune(line)) {
+			content = binary.BigEndian.AppendUint16(content, unit)
+		}
+	}
+	source := &countingReader{r: bytes.NewReader(content)}
+
+	decoded, charset, err := detectCharset(source)
+	require.NoError(t, err)
+	assert.Equal(t, charsetUTF16BE, charset)
+
+	first, err := bufio.NewReader(decoded).ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, line, first)
+	assert.LessOrEqual(t, source.n, int64(charsetSampleSize+decodeChunkSize),
+		"only the sample and one chunk should be read for the first line of a %d byte file", len(content))
+}
+
+func TestDetectCharset_SampleDecides(t *testing.T) {
+	// Content past the sample is not inspected: invalid UTF-8 there is passed through
+	content := strings.Repeat("é", charsetSampleSize/2) + "\xff"
+	decoded, charset, err := detectCharset(strings.NewReader(content))
+	require.NoError(t, err)
+	assert.Equal(t, charsetUTF8, charset)
+	out, err := io.ReadAll(decoded)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(out))
+
+	// A multi-byte rune cut by the end of the sample does not make it invalid
+	content = "a" + strings.Repeat("é", charsetSampleSize/2)
+	_, charset, err = detectCharset(strings.NewReader(content))
+	require.NoError(t, err)
+	assert.Equal(t, charsetUTF8, charset)
+
+	// Binary content matches no charset and is returned unchanged
+	content = "\x89PNG\x1a\x00\x00IHDR\x00\n"
+	decoded, charset, err = detectCharset(strings.NewReader(content))
+	require.NoError(t, err)
+	assert.Empty(t, charset)
+	out, err = io.ReadAll(decoded)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(out))
+}
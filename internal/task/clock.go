@@ -0,0 +1,71 @@
+package task
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Clock provides the current time and timers to executors.
+// Tests can substitute a fake implementation to control timeouts,
+// heartbeats and reported durations without real sleeps.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// After returns a channel that receives the current time once d has elapsed.
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is the Clock backed by the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// withClockDeadline derives a context that is done once clock reaches deadline.
+// Like context.WithDeadline, its Err reports context.DeadlineExceeded after that.
+func withClockDeadline(ctx context.Context, clock Clock, deadline time.Time) (context.Context, context.CancelFunc) {
+	if _, ok := clock.(realClock); ok {
+		return context.WithDeadline(ctx, deadline)
+	}
+
+	inner, cancel := context.WithCancel(ctx)
+	c := &clockDeadlineContext{Context: inner, deadline: deadline}
+	timer := clock.After(deadline.Sub(clock.Now()))
+	go func() {
+		select {
+		case <-timer:
+			c.mu.Lock()
+			c.err = context.DeadlineExceeded
+			c.mu.Unlock()
+			cancel()
+		case <-inner.Done():
+		}
+	}()
+	return c, cancel
+}
+
+// clockDeadlineContext is a context whose deadline is driven by a Clock.
+type clockDeadlineContext struct {
+	context.Context
+	deadline time.Time
+
+	mu  sync.Mutex
+	err error
+}
+
+func (c *clockDeadlineContext) Deadline() (time.Time, bool) {
+	if parent, ok := c.Context.Deadline(); ok && parent.Before(c.deadline) {
+		return parent, true
+	}
+	return c.deadline, true
+}
+
+func (c *clockDeadlineContext) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return c.err
+	}
+	return c.Context.Err()
+}
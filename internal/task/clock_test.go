@@ -0,0 +1,146 @@
+package task
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a Clock whose time only moves when Advance is called.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeClockWaiter
+}
+
+type fakeClockWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, fakeClockWaiter{at: c.now.Add(d), ch: ch})
+	return ch
+}
+
+// Advance moves the clock forward by d and fires every timer that became due.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			pending = append(pending, w)
+			continue
+		}
+		w.ch <- c.now
+	}
+	c.waiters = pending
+}
+
+// waitForTimers blocks until at least n timers are pending.
+func (c *fakeClock) waitForTimers(t *testing.T, n int) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return len(c.waiters) >= n
+	}, 5*time.Second, time.Millisecond, "Timed out waiting for %d pending timers", n)
+}
+
+func TestWithClockDeadline_FakeClock(t *testing.T) {
+	clock := newFakeClock()
+	deadline := clock.Now().Add(time.Hour)
+	ctx, cancel := withClockDeadline(context.Background(), clock, deadline)
+	defer cancel()
+
+	got, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.Equal(t, deadline, got)
+
+	clock.Advance(59 * time.Minute)
+	assert.NoError(t, ctx.Err(), "Context should not expire before the deadline")
+
+	clock.Advance(time.Minute)
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Context was not cancelled at the deadline")
+	}
+	assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+}
+
+func TestBashExecExecutor_FakeClockHeartbeat(t *testing.T) {
+	clock := newFakeClock()
+	executor := NewBashExecExecutorWithConfig(ExecutorConfig{Clock: clock, HeartbeatInterval: time.Minute})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cmd := NewBashExecTask("bash-heartbeat", "Quiet long-running command", BashExecParameters{Command: "sleep 30"})
+	resultsChan, err := executor.Execute(ctx, cmd)
+	require.NoError(t, err)
+
+	// One timer for the execution timeout and one for the first heartbeat
+	clock.waitForTimers(t, 2)
+	clock.Advance(time.Minute)
+
+	select {
+	case result := <-resultsChan:
+		assert.Equal(t, StatusRunning, result.Status)
+		assert.Equal(t, "Command still running after 1m0s.", result.Message)
+		assert.Empty(t, result.ResultData)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Did not receive heartbeat")
+	}
+
+	cancel()
+	for range resultsChan {
+	}
+}
+
+func TestBashExecExecutor_FakeClockTimeout(t *testing.T) {
+	clock := newFakeClock()
+	startedAt := clock.Now()
+	executor := NewBashExecExecutorWithConfig(ExecutorConfig{Clock: clock, DefaultTimeout: time.Hour})
+
+	cmd := NewBashExecTask("bash-fake-timeout", "Command that outlives its timeout", BashExecParameters{Command: "sleep 30"})
+	start := time.Now()
+	resultsChan, err := executor.Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	clock.waitForTimers(t, 1)
+	clock.Advance(time.Hour)
+
+	var finalResult OutputResult
+	for result := range resultsChan {
+		finalResult = result
+	}
+	assert.Less(t, time.Since(start), 10*time.Second, "Timeout should not wait for the command")
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Equal(t, "Command execution timed out.", finalResult.Message)
+	assert.Equal(t, "Command execution timed out after 1h0m0s.", finalResult.Error)
+	assert.Equal(t, startedAt, finalResult.StartedAt)
+	assert.Equal(t, time.Hour, finalResult.Duration())
+}
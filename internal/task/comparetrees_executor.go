@@ -0,0 +1,200 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Error constants for CompareTreesExecutor
+const (
+	// Command validation errors
+	errCompareTreesInvalidCommandType = "invalid command type for CompareTreesExecutor: %T"
+	errCompareTreesEmptyRoot          = "left and right cannot be empty"
+
+	// File operation errors
+	errCompareTreesResolvePath = "failed to resolve path: %w"
+
+	// Status messages
+	msgCompareTreesCancelled = "Tree comparison cancelled."
+	msgCompareTreesTimedOut  = "Tree comparison timed out."
+	msgCompareTreesFailed    = "Tree comparison failed: %v"
+	msgCompareTreesIdentical = "Trees '%s' and '%s' are identical (%d files)."
+	msgCompareTreesDiffer    = "Trees '%s' and '%s' differ: %d added, %d removed, %d changed."
+)
+
+// TreeDiff is the Payload of a COMPARE_TREES result. Paths are relative to the
+// compared roots, use forward slashes and are sorted.
+type TreeDiff struct {
+	// Added lists files present only under the right root.
+	Added []string `json:"added"`
+	// Removed lists files present only under the left root.
+	Removed []string `json:"removed"`
+	// Changed lists files present under both roots with different content.
+	Changed []string `json:"changed"`
+}
+
+// Identical reports whether the trees hold the same files with the same content.
+func (d TreeDiff) Identical() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// CompareTreesExecutor handles the execution of CompareTreesTask.
+// It hashes the regular files below two directories and reports how they differ.
+type CompareTreesExecutor struct {
+	config ExecutorConfig
+}
+
+var _ TaskExecutor = (*CompareTreesExecutor)(nil)
+
+// NewCompareTreesExecutor creates a new CompareTreesExecutor.
+func NewCompareTreesExecutor() *CompareTreesExecutor {
+	return &CompareTreesExecutor{}
+}
+
+// NewCompareTreesExecutorWithConfig creates a new CompareTreesExecutor using the shared executor config.
+func NewCompareTreesExecutorWithConfig(cfg ExecutorConfig) *CompareTreesExecutor {
+	return &CompareTreesExecutor{config: cfg}
+}
+
+// Execute implements the TaskExecutor interface for CompareTreesTask.
+// The task succeeds whether or not the trees differ. The final result's Payload
+// holds the TreeDiff and ResultData lists it as "A <path>", "D <path>" and
+// "M <path>" lines, in path order.
+func (e *CompareTreesExecutor) Execute(ctx context.Context, compareCmd *Task) (<-chan OutputResult, error) {
+	if compareCmd.Type != TaskCompareTrees {
+		return nil, fmt.Errorf(errCompareTreesInvalidCommandType, compareCmd)
+	}
+
+	// Check if task is already in a terminal state
+	terminalChan, err := HandleTerminalTask(compareCmd.TaskId, compareCmd.Status, compareCmd.Output)
+	if err != nil || terminalChan != nil {
+		return terminalChan, err
+	}
+
+	params := compareCmd.Parameters.(CompareTreesParameters)
+	if params.Left == "" || params.Right == "" {
+		return nil, errors.New(errCompareTreesEmptyRoot)
+	}
+
+	results := make(chan OutputResult, 1)
+	go func() {
+		defer close(results)
+
+		ctx, cancel := e.config.withTimeout(ctx, compareCmd)
+		defer cancel()
+
+		startedAt := e.config.clock().Now()
+		compareCmd.Status = StatusRunning
+		finalResult := e.compare(ctx, compareCmd.TaskId, params)
+
+		compareCmd.Status = finalResult.Status
+		finalResult.setTimes(e.config.clock(), startedAt)
+		compareCmd.UpdateOutput(&finalResult)
+		e.config.send(ctx, results, finalResult)
+	}()
+
+	return results, nil
+}
+
+// compare hashes both trees and returns the final result.
+func (e *CompareTreesExecutor) compare(ctx context.Context, taskID string, params CompareTreesParameters) OutputResult {
+	left, err := e.config.resolvePath(params.Left, params.WorkingDirectory)
+	if err != nil {
+		return createCompareTreesErrorResult(taskID, fmt.Errorf(errCompareTreesResolvePath, err))
+	}
+	right, err := e.config.resolvePath(params.Right, params.WorkingDirectory)
+	if err != nil {
+		return createCompareTreesErrorResult(taskID, fmt.Errorf(errCompareTreesResolvePath, err))
+	}
+
+	leftEntries, err := hashTree(ctx, left, "")
+	if err != nil {
+		return createCompareTreesErrorResult(taskID, err)
+	}
+	rightEntries, err := hashTree(ctx, right, "")
+	if err != nil {
+		return createCompareTreesErrorResult(taskID, err)
+	}
+
+	diff := diffTrees(leftEntries, rightEntries)
+	message := fmt.Sprintf(msgCompareTreesDiffer, left, right, len(diff.Added), len(diff.Removed), len(diff.Changed))
+	if diff.Identical() {
+		message = fmt.Sprintf(msgCompareTreesIdentical, left, right, len(leftEntries))
+	}
+	return OutputResult{
+		TaskID:     taskID,
+		Status:     StatusSucceeded,
+		Message:    message,
+		ResultData: formatTreeDiff(diff),
+		Payload:    diff,
+	}
+}
+
+// diffTrees compares two manifests, each sorted by path, in a single merge pass.
+func diffTrees(left, right []ManifestEntry) TreeDiff {
+	diff := TreeDiff{Added: []string{}, Removed: []string{}, Changed: []string{}}
+	i, j := 0, 0
+	for i < len(left) || j < len(right) {
+		switch {
+		case j == len(right) || (i < len(left) && left[i].Path < right[j].Path):
+			diff.Removed = append(diff.Removed, left[i].Path)
+			i++
+		case i == len(left) || right[j].Path < left[i].Path:
+			diff.Added = append(diff.Added, right[j].Path)
+			j++
+		default:
+			if left[i].Digest != right[j].Digest {
+				diff.Changed = append(diff.Changed, left[i].Path)
+			}
+			i++
+			j++
+		}
+	}
+	return diff
+}
+
+// formatTreeDiff renders diff as one "<status> <path>" line per differing file, in path order.
+func formatTreeDiff(diff TreeDiff) string {
+	type line struct{ status, path string }
+	var lines []line
+	for _, path := range diff.Added {
+		lines = append(lines, line{"A", path})
+	}
+	for _, path := range diff.Removed {
+		lines = append(lines, line{"D", path})
+	}
+	for _, path := range diff.Changed {
+		lines = append(lines, line{"M", path})
+	}
+	sort.Slice(lines, func(i, j int) bool { return lines[i].path < lines[j].path })
+
+	var b strings.Builder
+	for _, l := range lines {
+		fmt.Fprintf(&b, "%s %s\n", l.status, l.path)
+	}
+	return b.String()
+}
+
+// createCompareTreesErrorResult constructs the final OutputResult for a failed CompareTreesTask.
+func createCompareTreesErrorResult(taskID string, err error) OutputResult {
+	var message string
+	switch {
+	case errors.Is(err, context.Canceled):
+		message = msgCompareTreesCancelled
+	case errors.Is(err, context.DeadlineExceeded):
+		message = msgCompareTreesTimedOut
+	default:
+		message = fmt.Sprintf(msgCompareTreesFailed, err)
+	}
+	return OutputResult{
+		TaskID:      taskID,
+		Status:      StatusFailed,
+		Message:     message,
+		Error:       err.Error(),
+		Err:         err,
+		FailureKind: failureKind(err),
+	}
+}
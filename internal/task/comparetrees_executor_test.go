@@ -0,0 +1,115 @@
+package task
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTree creates the given files, keyed by slash-separated relative path, below dir.
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func runCompareTrees(t *testing.T, ctx context.Context, params CompareTreesParameters) OutputResult {
+	t.Helper()
+	cmd := NewCompareTreesTask("compare-trees", "Compare two trees", params)
+	resultsChan, err := NewCompareTreesExecutor().Execute(ctx, cmd)
+	require.NoError(t, err)
+
+	finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+	return finalResult
+}
+
+var baseTree = map[string]string{
+	"README.md":       "# project\n",
+	"src/main.go":     "package main\n",
+	"src/lib/util.go": "package lib\n",
+}
+
+func TestCompareTreesExecutor_Execute_Identical(t *testing.T) {
+	left, right := t.TempDir(), t.TempDir()
+	writeTree(t, left, baseTree)
+	writeTree(t, right, baseTree)
+
+	finalResult := runCompareTrees(t, context.Background(), CompareTreesParameters{Left: left, Right: right})
+	require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+	assert.Contains(t, finalResult.Message, "are identical (3 files)")
+	assert.Empty(t, finalResult.ResultData)
+
+	diff, ok := finalResult.Payload.(TreeDiff)
+	require.True(t, ok, "Payload should be a TreeDiff, got %T", finalResult.Payload)
+	assert.True(t, diff.Identical())
+}
+
+func TestCompareTreesExecutor_Execute_AddedFile(t *testing.T) {
+	left, right := t.TempDir(), t.TempDir()
+	writeTree(t, left, baseTree)
+	writeTree(t, right, baseTree)
+	writeTree(t, right, map[string]string{"src/lib/extra.go": "package lib\n"})
+
+	finalResult := runCompareTrees(t, context.Background(), CompareTreesParameters{
+		BaseParameters: BaseParameters{WorkingDirectory: filepath.Dir(left)},
+		Left:           filepath.Base(left),
+		Right:          right,
+	})
+	require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+	assert.Contains(t, finalResult.Message, "1 added, 0 removed, 0 changed")
+	assert.Equal(t, "A src/lib/extra.go\n", finalResult.ResultData)
+	assert.Equal(t, TreeDiff{Added: []string{"src/lib/extra.go"}, Removed: []string{}, Changed: []string{}}, finalResult.Payload)
+
+	// Swapping the sides reports the file as removed
+	finalResult = runCompareTrees(t, context.Background(), CompareTreesParameters{Left: right, Right: left})
+	assert.Equal(t, TreeDiff{Added: []string{}, Removed: []string{"src/lib/extra.go"}, Changed: []string{}}, finalResult.Payload)
+}
+
+func TestCompareTreesExecutor_Execute_ModifiedFile(t *testing.T) {
+	left, right := t.TempDir(), t.TempDir()
+	writeTree(t, left, baseTree)
+	writeTree(t, right, baseTree)
+	writeTree(t, right, map[string]string{"src/main.go": "package main\n\nfunc main() {}\n"})
+	require.NoError(t, os.Remove(filepath.Join(right, "README.md")))
+
+	finalResult := runCompareTrees(t, context.Background(), CompareTreesParameters{Left: left, Right: right})
+	require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+	assert.Contains(t, finalResult.Message, "0 added, 1 removed, 1 changed")
+	assert.Equal(t, "D README.md\nM src/main.go\n", finalResult.ResultData)
+	assert.Equal(t, TreeDiff{Added: []string{}, Removed: []string{"README.md"}, Changed: []string{"src/main.go"}}, finalResult.Payload)
+}
+
+func TestCompareTreesExecutor_Execute_Failures(t *testing.T) {
+	t.Run("EmptyRoot", func(t *testing.T) {
+		_, err := NewCompareTreesExecutor().Execute(context.Background(), NewCompareTreesTask("compare-empty", "", CompareTreesParameters{Left: t.TempDir()}))
+		assert.ErrorContains(t, err, "left and right cannot be empty")
+	})
+
+	t.Run("NotADirectory", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "file.txt")
+		require.NoError(t, os.WriteFile(file, []byte("x"), 0644))
+		finalResult := runCompareTrees(t, context.Background(), CompareTreesParameters{Left: t.TempDir(), Right: file})
+		assert.Equal(t, StatusFailed, finalResult.Status)
+		assert.Contains(t, finalResult.Error, "is not a directory")
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		left := t.TempDir()
+		writeTree(t, left, baseTree)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		finalResult := runCompareTrees(t, ctx, CompareTreesParameters{Left: left, Right: left})
+		assert.Equal(t, StatusFailed, finalResult.Status)
+		assert.Equal(t, FailureCancelled, finalResult.FailureKind)
+		assert.Equal(t, msgCompareTreesCancelled, finalResult.Message)
+	})
+}
@@ -0,0 +1,75 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Error constants for compensations
+const (
+	errBackupFailed  = "failed to back up file '%s': %w"
+	errRestoreFailed = "failed to restore file '%s': %w"
+)
+
+// Compensation undoes the effect of a task that completed successfully.
+type Compensation func(ctx context.Context) error
+
+// Compensator is implemented by executors whose tasks can be undone.
+// PrepareCompensation is called before the task runs so it can capture the
+// state needed to restore it, and returns the Compensation that performs the restore.
+type Compensator interface {
+	PrepareCompensation(ctx context.Context, task *Task) (Compensation, error)
+}
+
+// fileBackupCompensation captures the current content and mode of filePath.
+// The returned Compensation restores them, or removes the file if it did not exist.
+// Non-regular files such as named pipes cannot be restored and yield a nil Compensation.
+func fileBackupCompensation(filePath string) (Compensation, error) {
+	info, err := os.Stat(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return func(ctx context.Context) error {
+			if err := os.Remove(filePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf(errRestoreFailed, filePath, err)
+			}
+			return nil
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf(errBackupFailed, filePath, err)
+	}
+	if !info.Mode().IsRegular() {
+		// Data written to a named pipe or device cannot be taken back
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf(errBackupFailed, filePath, err)
+	}
+	mode := info.Mode().Perm()
+
+	return func(ctx context.Context) error {
+		if err := os.WriteFile(filePath, content, mode); err != nil {
+			return fmt.Errorf(errRestoreFailed, filePath, err)
+		}
+		if err := os.Chmod(filePath, mode); err != nil {
+			return fmt.Errorf(errRestoreFailed, filePath, err)
+		}
+		return nil
+	}, nil
+}
+
+// combineCompensations returns a Compensation that runs each one in reverse order.
+func combineCompensations(compensations []Compensation) Compensation {
+	return func(ctx context.Context) error {
+		var errs []error
+		for i := len(compensations) - 1; i >= 0; i-- {
+			if err := compensations[i](ctx); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}
+}
@@ -0,0 +1,238 @@
+package task
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"ai-agent-v3/internal/task/fileutils"
+)
+
+// Logger is the minimal logging interface used by executors.
+// It is satisfied by *log.Logger from the standard library.
+type Logger interface {
+	Printf(format string, v ...any)
+}
+
+// ExecutorConfig holds settings shared by every executor created through
+// NewMapRegistryWithConfig. The zero value keeps each executor's default behavior.
+type ExecutorConfig struct {
+	// RootDir confines file paths to this directory when set, including paths that
+	// lead outside it through symbolic links.
+	// Relative paths without a WorkingDirectory are resolved against it.
+	RootDir string
+	// DefaultTimeout bounds the execution time of each task when greater than zero.
+	DefaultTimeout time.Duration
+	// Logger receives diagnostic messages. Logging is disabled when nil.
+	Logger Logger
+	// MaxOutputBytes caps the amount of streamed ResultData per task when greater than zero.
+	MaxOutputBytes int64
+	// FileMode is the permission mode for files created by executors.
+	// Defaults to DefaultFilePermissions when zero.
+	FileMode os.FileMode
+	// DirMode is the permission mode for directories created by executors.
+	// Defaults to DefaultDirPermissions when zero.
+	DirMode os.FileMode
+	// TempDir is the directory for scratch files such as the BashExec CWD file.
+	// Defaults to os.TempDir() when empty.
+	TempDir string
+	// Patcher applies patches for PATCH_FILE tasks.
+	// Defaults to the built-in unified diff patcher when nil.
+	Patcher Patcher
+	// Clock supplies the time used for timeouts, deadlines, heartbeats and reported durations.
+	// Defaults to the system clock when nil.
+	Clock Clock
+	// HeartbeatInterval makes BASH_EXEC send a RUNNING result reporting the elapsed time
+	// at this interval while the command runs, when greater than zero.
+	HeartbeatInterval time.Duration
+	// MaxGroupDepth limits how deeply GROUP tasks may be nested.
+	// Defaults to DefaultMaxGroupDepth when zero.
+	MaxGroupDepth int
+	// RedactFileContent makes diagnostic messages describe file contents by their size
+	// and SHA-256 hash instead of including the raw bytes.
+	RedactFileContent bool
+	// ResultSendTimeout is how long an executor keeps trying to deliver a result after the
+	// task's context is done. A result the consumer does not receive in time is dropped so
+	// an abandoned results channel cannot block the executor forever.
+	// Defaults to DefaultResultSendTimeout when zero.
+	ResultSendTimeout time.Duration
+	// FileLocks serializes executors that modify the same file. Executors built from configs
+	// sharing one FileLocks never write a path concurrently.
+	// Defaults to a process-wide set shared by every executor when nil.
+	FileLocks *FileLocks
+	// UserInput is where REQUEST_USER_INPUT reads the user's answers, one line each.
+	// Defaults to os.Stdin when nil.
+	UserInput io.Reader
+}
+
+// fileMode returns the configured mode for newly created files.
+func (c ExecutorConfig) fileMode() os.FileMode {
+	if c.FileMode != 0 {
+		return c.FileMode
+	}
+	return DefaultFilePermissions
+}
+
+// userInput returns the configured source of user input.
+func (c ExecutorConfig) userInput() io.Reader {
+	if c.UserInput != nil {
+		return c.UserInput
+	}
+	return os.Stdin
+}
+
+// dirMode returns the configured mode for newly created directories.
+func (c ExecutorConfig) dirMode() os.FileMode {
+	if c.DirMode != 0 {
+		return c.DirMode
+	}
+	return DefaultDirPermissions
+}
+
+// tempDir returns the configured directory for scratch files.
+func (c ExecutorConfig) tempDir() string {
+	if c.TempDir != "" {
+		return c.TempDir
+	}
+	return os.TempDir()
+}
+
+// patcher returns the configured Patcher or the built-in one.
+func (c ExecutorConfig) patcher() Patcher {
+	if c.Patcher != nil {
+		return c.Patcher
+	}
+	return &defaultPatcher{}
+}
+
+// clock returns the configured Clock or the system clock.
+func (c ExecutorConfig) clock() Clock {
+	if c.Clock != nil {
+		return c.Clock
+	}
+	return realClock{}
+}
+
+// fileLocks returns the configured FileLocks or the process-wide default.
+func (c ExecutorConfig) fileLocks() *FileLocks {
+	if c.FileLocks != nil {
+		return c.FileLocks
+	}
+	return defaultFileLocks
+}
+
+// since returns the time elapsed on the configured clock since t.
+func (c ExecutorConfig) since(t time.Time) time.Duration {
+	return c.clock().Now().Sub(t)
+}
+
+// withTimeout derives a context bounded by DefaultTimeout and the task's deadline,
+// whichever comes first. If neither is set, the context is only made cancellable.
+func (c ExecutorConfig) withTimeout(ctx context.Context, t *Task) (context.Context, context.CancelFunc) {
+	ctx, cancelDeadline := c.withDeadline(ctx, t)
+	if c.DefaultTimeout > 0 {
+		timeoutCtx, cancel := withClockDeadline(ctx, c.clock(), c.clock().Now().Add(c.DefaultTimeout))
+		return timeoutCtx, func() {
+			cancel()
+			cancelDeadline()
+		}
+	}
+	return ctx, cancelDeadline
+}
+
+// withDeadline derives a context bounded by the task's DeadlineUnix, if set.
+func (c ExecutorConfig) withDeadline(ctx context.Context, t *Task) (context.Context, context.CancelFunc) {
+	if t.DeadlineUnix > 0 {
+		return withClockDeadline(ctx, c.clock(), time.Unix(t.DeadlineUnix, 0))
+	}
+	return context.WithCancel(ctx)
+}
+
+// maxGroupDepth returns the configured limit on nested GROUP tasks.
+func (c ExecutorConfig) maxGroupDepth() int {
+	if c.MaxGroupDepth > 0 {
+		return c.MaxGroupDepth
+	}
+	return DefaultMaxGroupDepth
+}
+
+// resultSendTimeout returns the configured grace period for delivering results after cancellation.
+func (c ExecutorConfig) resultSendTimeout() time.Duration {
+	if c.ResultSendTimeout > 0 {
+		return c.ResultSendTimeout
+	}
+	return DefaultResultSendTimeout
+}
+
+// send delivers result on results, giving up once ctx is done and the consumer has not
+// received it within ResultSendTimeout. It reports whether the result was delivered.
+func (c ExecutorConfig) send(ctx context.Context, results chan<- OutputResult, result OutputResult) bool {
+	if sendResult(ctx, results, result, c.clock(), c.resultSendTimeout()) {
+		return true
+	}
+	c.logf("task %s: dropped %s result because the consumer stopped receiving", result.TaskID, result.Status)
+	return false
+}
+
+// resolvePath resolves a task path against its working directory and RootDir.
+func (c ExecutorConfig) resolvePath(filePath, workingDir string) (string, error) {
+	resolved, err := fileutils.ResolvePathWithinRoot(filePath, workingDir, c.RootDir)
+	if err != nil {
+		return "", &validationError{err: err}
+	}
+	return resolved, nil
+}
+
+// logf writes a diagnostic message if a Logger is configured.
+func (c ExecutorConfig) logf(format string, v ...any) {
+	if c.Logger != nil {
+		c.Logger.Printf(format, v...)
+	}
+}
+
+// loggedContent formats file content for a diagnostic message, honoring RedactFileContent.
+func (c ExecutorConfig) loggedContent(content []byte) string {
+	if c.RedactFileContent {
+		return fmt.Sprintf("<redacted %d bytes, sha256 %x>", len(content), sha256.Sum256(content))
+	}
+	return fmt.Sprintf("%q", content)
+}
+
+// outputBudget tracks how many bytes of streamed output a task may still emit.
+type outputBudget struct {
+	remaining int64
+	limited   bool
+	exhausted bool
+}
+
+// newOutputBudget creates a budget of max bytes. A max of zero or less means unlimited.
+func newOutputBudget(max int64) *outputBudget {
+	return &outputBudget{remaining: max, limited: max > 0}
+}
+
+// take returns the portion of s that fits in the remaining budget.
+// The second return value is false once the budget has been exhausted.
+func (b *outputBudget) take(s string) (string, bool) {
+	if !b.limited {
+		return s, true
+	}
+	if b.exhausted {
+		return "", false
+	}
+	if int64(len(s)) > b.remaining {
+		s = s[:b.remaining]
+		b.remaining = 0
+		b.exhausted = true
+		return s, true
+	}
+	b.remaining -= int64(len(s))
+	return s, true
+}
+
+// truncated reports whether output was dropped because the budget ran out.
+func (b *outputBudget) truncated() bool {
+	return b.exhausted
+}
@@ -0,0 +1,171 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+)
+
+// Error constants for DiskUsageExecutor
+const (
+	// Command validation errors
+	errDiskUsageInvalidCommandType = "invalid command type for DiskUsageExecutor: %T"
+
+	// File operation errors
+	errDiskUsageResolvePath = "failed to resolve path: %w"
+	errDiskUsageNotDir      = "path '%s' is not a directory"
+	errDiskUsageWalkFailed  = "failed to walk '%s': %w"
+
+	// Status messages
+	msgDiskUsageCancelled = "Disk usage calculation cancelled."
+	msgDiskUsageTimedOut  = "Disk usage calculation timed out."
+	msgDiskUsageFailed    = "Disk usage calculation failed: %v"
+	msgDiskUsageSucceeded = "Measured '%s': %d bytes in %d files and %d directories."
+)
+
+// DiskUsage is the JSON document returned in the ResultData of a DiskUsageTask.
+type DiskUsage struct {
+	Path       string `json:"path"`
+	TotalBytes int64  `json:"total_bytes"`
+	FileCount  int64  `json:"file_count"`
+	DirCount   int64  `json:"dir_count"`
+}
+
+// DiskUsageExecutor handles the execution of DiskUsageTask.
+// It walks a directory tree and reports its total size and entry counts.
+type DiskUsageExecutor struct {
+	config ExecutorConfig
+}
+
+var _ TaskExecutor = (*DiskUsageExecutor)(nil)
+
+// NewDiskUsageExecutor creates a new DiskUsageExecutor.
+func NewDiskUsageExecutor() *DiskUsageExecutor {
+	return &DiskUsageExecutor{}
+}
+
+// NewDiskUsageExecutorWithConfig creates a new DiskUsageExecutor using the shared executor config.
+func NewDiskUsageExecutorWithConfig(cfg ExecutorConfig) *DiskUsageExecutor {
+	return &DiskUsageExecutor{config: cfg}
+}
+
+// Execute implements the TaskExecutor interface for DiskUsageTask.
+// The final result's ResultData holds a JSON-encoded DiskUsage.
+func (e *DiskUsageExecutor) Execute(ctx context.Context, duCmd *Task) (<-chan OutputResult, error) {
+	if duCmd.Type != TaskDiskUsage {
+		return nil, fmt.Errorf(errDiskUsageInvalidCommandType, duCmd)
+	}
+
+	// Check if task is already in a terminal state
+	terminalChan, err := HandleTerminalTask(duCmd.TaskId, duCmd.Status, duCmd.Output)
+	if err != nil || terminalChan != nil {
+		return terminalChan, err
+	}
+
+	results := make(chan OutputResult, 1)
+	go func() {
+		defer close(results)
+
+		ctx, cancel := e.config.withTimeout(ctx, duCmd)
+		defer cancel()
+
+		startedAt := e.config.clock().Now()
+		duCmd.Status = StatusRunning
+		usage, err := e.measure(ctx, duCmd.Parameters.(DiskUsageParameters))
+
+		finalResult := createDiskUsageResult(duCmd.TaskId, usage, err)
+		duCmd.Status = finalResult.Status
+		finalResult.setTimes(e.config.clock(), startedAt)
+		duCmd.UpdateOutput(&finalResult)
+		e.config.send(ctx, results, finalResult)
+	}()
+
+	return results, nil
+}
+
+// measure walks the tree rooted at params.Path. The root itself is not counted.
+// Only regular files contribute to TotalBytes; other non-directory entries such as
+// symlinks are counted as files but not followed.
+func (e *DiskUsageExecutor) measure(ctx context.Context, params DiskUsageParameters) (DiskUsage, error) {
+	path := params.Path
+	if path == "" {
+		path = "."
+	}
+	root, err := e.config.resolvePath(path, params.WorkingDirectory)
+	if err != nil {
+		return DiskUsage{}, fmt.Errorf(errDiskUsageResolvePath, err)
+	}
+
+	usage := DiskUsage{Path: root}
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if p == root {
+			if !d.IsDir() {
+				return fmt.Errorf(errDiskUsageNotDir, root)
+			}
+			return nil
+		}
+		if d.IsDir() {
+			usage.DirCount++
+			return nil
+		}
+
+		usage.FileCount++
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			usage.TotalBytes += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return DiskUsage{}, err
+		}
+		return DiskUsage{}, fmt.Errorf(errDiskUsageWalkFailed, root, err)
+	}
+	return usage, nil
+}
+
+// createDiskUsageResult constructs the final OutputResult for a DiskUsageTask.
+func createDiskUsageResult(taskID string, usage DiskUsage, err error) OutputResult {
+	var data []byte
+	if err == nil {
+		data, err = json.Marshal(usage)
+	}
+	if err == nil {
+		return OutputResult{
+			TaskID:     taskID,
+			Status:     StatusSucceeded,
+			Message:    fmt.Sprintf(msgDiskUsageSucceeded, usage.Path, usage.TotalBytes, usage.FileCount, usage.DirCount),
+			ResultData: string(data),
+		}
+	}
+
+	var message string
+	switch {
+	case errors.Is(err, context.Canceled):
+		message = msgDiskUsageCancelled
+	case errors.Is(err, context.DeadlineExceeded):
+		message = msgDiskUsageTimedOut
+	default:
+		message = fmt.Sprintf(msgDiskUsageFailed, err)
+	}
+	return OutputResult{
+		TaskID:      taskID,
+		Status:      StatusFailed,
+		Message:     message,
+		Error:       err.Error(),
+		FailureKind: failureKind(err),
+	}
+}
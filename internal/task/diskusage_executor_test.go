@@ -0,0 +1,74 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runDiskUsage(t *testing.T, executor *DiskUsageExecutor, ctx context.Context, params DiskUsageParameters) (OutputResult, DiskUsage) {
+	t.Helper()
+	cmd := NewDiskUsageTask("du-test", "Measure tree", params)
+	resultsChan, err := executor.Execute(ctx, cmd)
+	require.NoError(t, err)
+
+	finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+
+	var usage DiskUsage
+	if finalResult.Status == StatusSucceeded {
+		require.NoError(t, json.Unmarshal([]byte(finalResult.ResultData), &usage))
+	}
+	return finalResult, usage
+}
+
+func TestDiskUsageExecutor_Execute_KnownTree(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "a", "b"), 0755))
+	require.NoError(t, os.Mkdir(filepath.Join(root, "c"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "top.txt"), make([]byte, 10), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a", "mid.txt"), make([]byte, 200), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a", "b", "deep.txt"), make([]byte, 3000), 0644))
+
+	finalResult, usage := runDiskUsage(t, NewDiskUsageExecutor(), context.Background(), DiskUsageParameters{Path: root})
+	require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+
+	assert.Equal(t, root, usage.Path)
+	assert.Equal(t, int64(3210), usage.TotalBytes)
+	assert.Equal(t, int64(3), usage.FileCount)
+	assert.Equal(t, int64(3), usage.DirCount)
+}
+
+func TestDiskUsageExecutor_Execute_EmptyDirectory(t *testing.T) {
+	root := t.TempDir()
+
+	finalResult, usage := runDiskUsage(t, NewDiskUsageExecutor(), context.Background(), DiskUsageParameters{
+		BaseParameters: BaseParameters{WorkingDirectory: root},
+	})
+	require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+	assert.Equal(t, DiskUsage{Path: root}, usage)
+}
+
+func TestDiskUsageExecutor_Execute_NotADirectory(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "file.txt")
+	require.NoError(t, os.WriteFile(filePath, []byte("x"), 0644))
+
+	finalResult, _ := runDiskUsage(t, NewDiskUsageExecutor(), context.Background(), DiskUsageParameters{Path: filePath})
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Contains(t, finalResult.Error, "is not a directory")
+}
+
+func TestDiskUsageExecutor_Execute_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	finalResult, _ := runDiskUsage(t, NewDiskUsageExecutor(), ctx, DiskUsageParameters{Path: t.TempDir()})
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Equal(t, msgDiskUsageCancelled, finalResult.Message)
+}
@@ -0,0 +1,33 @@
+package task
+
+import (
+	"os"
+	"slices"
+	"strings"
+)
+
+// mergeEnv returns base with the variables in overrides set, replacing any
+// existing entries with the same name. The result is sorted for stable output.
+func mergeEnv(base []string, overrides map[string]string) []string {
+	merged := make([]string, 0, len(base)+len(overrides))
+	for _, kv := range base {
+		name, _, _ := strings.Cut(kv, "=")
+		if _, ok := overrides[name]; !ok {
+			merged = append(merged, kv)
+		}
+	}
+	for name, value := range overrides {
+		merged = append(merged, name+"="+value)
+	}
+	slices.Sort(merged)
+	return merged
+}
+
+// getEnv returns the value of name from the task environment, falling back to
+// the agent's own environment when the task does not set it.
+func getEnv(env map[string]string, name string) string {
+	if value, ok := env[name]; ok {
+		return value
+	}
+	return os.Getenv(name)
+}
@@ -0,0 +1,122 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// Error constants for EvalExecutor
+const (
+	// Command validation errors
+	errEvalInvalidCommandType = "invalid command type for EvalExecutor: %T"
+	errEvalParseFailed        = "invalid expression: %w"
+
+	// Evaluation errors
+	errEvalNotBoolean = "expression evaluated to a %s, expected a boolean"
+
+	// Status messages
+	msgEvalFailed    = "Expression evaluation failed: %v"
+	msgEvalSucceeded = "Expression evaluated to %t."
+)
+
+// EvalExecutor handles the execution of EvalTask.
+// It evaluates a restricted boolean expression and returns "true" or "false"
+// in ResultData, so plans can branch without running a shell.
+type EvalExecutor struct {
+	config ExecutorConfig
+}
+
+var _ TaskExecutor = (*EvalExecutor)(nil)
+
+// NewEvalExecutor creates a new EvalExecutor.
+func NewEvalExecutor() *EvalExecutor {
+	return &EvalExecutor{}
+}
+
+// NewEvalExecutorWithConfig creates a new EvalExecutor using the shared executor config.
+func NewEvalExecutorWithConfig(cfg ExecutorConfig) *EvalExecutor {
+	return &EvalExecutor{config: cfg}
+}
+
+// Execute implements the TaskExecutor interface for EvalTask.
+// Syntax errors are reported immediately; errors while evaluating, such as an
+// undefined variable, produce a FAILED result.
+func (e *EvalExecutor) Execute(ctx context.Context, evalCmd *Task) (<-chan OutputResult, error) {
+	if evalCmd.Type != TaskEval {
+		return nil, fmt.Errorf(errEvalInvalidCommandType, evalCmd)
+	}
+
+	// Check if task is already in a terminal state
+	terminalChan, err := HandleTerminalTask(evalCmd.TaskId, evalCmd.Status, evalCmd.Output)
+	if err != nil || terminalChan != nil {
+		return terminalChan, err
+	}
+
+	params := evalCmd.Parameters.(EvalParameters)
+	expr, err := parseExpression(params.Expression)
+	if err != nil {
+		return nil, fmt.Errorf(errEvalParseFailed, err)
+	}
+
+	results := make(chan OutputResult, 1)
+	go func() {
+		defer close(results)
+
+		startedAt := e.config.clock().Now()
+		evalCmd.Status = StatusRunning
+		value, err := e.evaluate(expr, params)
+
+		finalResult := createEvalResult(evalCmd.TaskId, value, err)
+		evalCmd.Status = finalResult.Status
+		finalResult.setTimes(e.config.clock(), startedAt)
+		evalCmd.UpdateOutput(&finalResult)
+		e.config.send(ctx, results, finalResult)
+	}()
+
+	return results, nil
+}
+
+// evaluate runs expr against the task's variables. Paths passed to fileExists
+// are resolved like any other task path.
+func (e *EvalExecutor) evaluate(expr exprNode, params EvalParameters) (bool, error) {
+	env := &exprEnv{
+		variables: params.Variables,
+		fileExists: func(path string) (bool, error) {
+			resolved, err := e.config.resolvePath(path, params.WorkingDirectory)
+			if err != nil {
+				return false, err
+			}
+			return statExists(resolved)
+		},
+	}
+
+	value, err := expr.eval(env)
+	if err != nil {
+		return false, err
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf(errEvalNotBoolean, exprTypeName(value))
+	}
+	return b, nil
+}
+
+// createEvalResult constructs the final OutputResult for an EvalTask.
+func createEvalResult(taskID string, value bool, err error) OutputResult {
+	if err != nil {
+		return OutputResult{
+			TaskID:      taskID,
+			Status:      StatusFailed,
+			Message:     fmt.Sprintf(msgEvalFailed, err),
+			Error:       err.Error(),
+			FailureKind: failureKind(err),
+		}
+	}
+	return OutputResult{
+		TaskID:     taskID,
+		Status:     StatusSucceeded,
+		Message:    fmt.Sprintf(msgEvalSucceeded, value),
+		ResultData: strconv.FormatBool(value),
+	}
+}
@@ -0,0 +1,120 @@
+package task
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvalExecutor_Execute(t *testing.T) {
+	tempDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "go.mod"), []byte("module x\n"), 0644))
+
+	testCases := []struct {
+		name           string
+		params         EvalParameters
+		expectedStatus TaskStatus
+		expectedData   string
+		errorContains  string
+	}{
+		{
+			name: "TrueWithFileAndVariable",
+			params: EvalParameters{
+				BaseParameters: BaseParameters{WorkingDirectory: tempDir},
+				Expression:     `fileExists("go.mod") && exitCode == 0`,
+				Variables:      map[string]any{"exitCode": 0},
+			},
+			expectedStatus: StatusSucceeded,
+			expectedData:   "true",
+		},
+		{
+			name: "False",
+			params: EvalParameters{
+				BaseParameters: BaseParameters{WorkingDirectory: tempDir},
+				Expression:     `fileExists("missing") || exitCode != 0`,
+				Variables:      map[string]any{"exitCode": 0},
+			},
+			expectedStatus: StatusSucceeded,
+			expectedData:   "false",
+		},
+		{
+			name:           "UndefinedVariable",
+			params:         EvalParameters{Expression: `exitCode == 0`},
+			expectedStatus: StatusFailed,
+			errorContains:  "undefined variable 'exitCode'",
+		},
+		{
+			name:           "NotBoolean",
+			params:         EvalParameters{Expression: `"text"`},
+			expectedStatus: StatusFailed,
+			errorContains:  "evaluated to a string, expected a boolean",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := NewEvalTask("eval-"+tc.name, tc.name, tc.params)
+			resultsChan, err := NewEvalExecutor().Execute(context.Background(), cmd)
+			require.NoError(t, err)
+
+			finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+			require.True(t, received, "Did not receive final result")
+			assert.Equal(t, tc.expectedStatus, finalResult.Status, finalResult.Error)
+			assert.Equal(t, tc.expectedData, finalResult.ResultData)
+			if tc.errorContains != "" {
+				assert.Contains(t, finalResult.Error, tc.errorContains)
+			}
+			assert.Equal(t, tc.expectedStatus, cmd.Status)
+		})
+	}
+}
+
+func TestEvalExecutor_Execute_SyntaxError(t *testing.T) {
+	cmd := NewEvalTask("eval-syntax", "Invalid expression", EvalParameters{Expression: `exitCode ==`})
+	resultsChan, err := NewEvalExecutor().Execute(context.Background(), cmd)
+	require.Error(t, err)
+	assert.Nil(t, resultsChan)
+	assert.Contains(t, err.Error(), "invalid expression")
+}
+
+func TestEvalExecutor_Execute_RootDirConfinement(t *testing.T) {
+	executor := NewEvalExecutorWithConfig(ExecutorConfig{RootDir: t.TempDir()})
+	cmd := NewEvalTask("eval-root", "Path outside root", EvalParameters{Expression: `fileExists("/etc/passwd")`})
+	resultsChan, err := executor.Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Contains(t, finalResult.Error, "outside the root directory")
+}
+
+func TestEvalExecutor_Execute_PriorResult(t *testing.T) {
+	registry := NewMapRegistry()
+	count := NewBashExecTask("count", "Produce a value", BashExecParameters{Command: "echo 3"})
+	check := NewEvalTask("check", "Check the value", EvalParameters{Expression: `${count.result:1} >= 2`})
+	check.DependsOn = []string{"count"}
+	group := NewGroupTask("eval-group", "Evaluate a prior result", []*Task{count, check})
+
+	executor, err := registry.GetExecutor(TaskGroup)
+	require.NoError(t, err)
+	resultsChan, err := executor.Execute(context.Background(), group)
+	require.NoError(t, err)
+	for range resultsChan {
+	}
+
+	assert.Equal(t, StatusSucceeded, check.Status, check.Output.Error)
+	assert.Equal(t, "true", check.Output.ResultData)
+}
+
+func TestEvalExecutor_Execute_InvalidCommandType(t *testing.T) {
+	cmd := NewFileReadTask("eval-invalid", "Wrong type", FileReadParameters{FilePath: "x"})
+	resultsChan, err := NewEvalExecutor().Execute(context.Background(), cmd)
+	assert.Error(t, err)
+	assert.Nil(t, resultsChan)
+}
@@ -3,23 +3,49 @@ package task
 import (
 	"context"
 	"fmt"
+	"time"
 )
 
 // TaskExecutor defines the interface for executing a specific type of command.
 // Each command type (like BashExec, FileRead, etc.) will have its own implementation
-// of this interface. The Execute method takes a command struct (as an any type, requiring type assertion
-// within the implementation) and returns a channel that streams OutputResult updates.
+// of this interface, and code outside the package can implement it for a custom
+// TaskType and add it with MapRegistry.Register. Execute type-asserts the task's
+// Parameters to the parameters struct of the type it handles and returns a channel
+// that streams OutputResult updates, closing it after the final result.
 // It returns an error immediately if the command cannot be initiated (e.g., invalid type).
 type TaskExecutor interface {
 	// Execute starts the command execution process.
-	// It accepts a command struct (type `any`) which should be cast to the specific
-	// command type the executor handles.
+	// The task's Parameters should be cast to the parameters type the executor handles.
 	// It returns a channel (`<-chan OutputResult`) through which execution status
 	// and results are reported asynchronously.
 	// An error is returned immediately if the command is invalid or cannot be started.
 	Execute(ctx context.Context, task *Task) (<-chan OutputResult, error)
 }
 
+// DefaultResultSendTimeout is how long a result send waits for the consumer after the
+// task's context is done when ExecutorConfig.ResultSendTimeout is zero.
+const DefaultResultSendTimeout = time.Second
+
+// sendResult delivers result on results. It blocks while ctx is live, so a slow consumer
+// applies backpressure. Once ctx is done it waits at most timeout for the consumer, which
+// lets a consumer that is still reading receive the final result of a cancelled task
+// while a consumer that abandoned the channel cannot block the producer forever.
+// It reports whether the result was delivered.
+func sendResult(ctx context.Context, results chan<- OutputResult, result OutputResult, clock Clock, timeout time.Duration) bool {
+	select {
+	case results <- result:
+		return true
+	case <-ctx.Done():
+	}
+
+	select {
+	case results <- result:
+		return true
+	case <-clock.After(timeout):
+		return false
+	}
+}
+
 // HandleTerminalTask checks if a task is in a terminal state (SUCCEEDED or FAILED)
 // and if so, returns a channel with the task's Output.
 // This helper function should be used by all executors to avoid executing tasks that are already complete.
@@ -53,3 +79,42 @@ func HandleTerminalTask(taskID string, status TaskStatus, output OutputResult) (
 	// Task is not in a terminal state, should proceed with normal execution
 	return nil, nil
 }
+
+// RunAndCapture executes task with the matching executor from registry and waits for it to finish.
+// It returns the concatenated ResultData of every streamed result together with the final result.
+// An error is returned if the task cannot be started or if it finishes in the FAILED state;
+// the captured output and final result are still returned in the latter case.
+func RunAndCapture(ctx context.Context, registry TaskRegistry, task *Task) ([]byte, OutputResult, error) {
+	return runAndCapture(ctx, registry, task, nil)
+}
+
+// runAndCapture is RunAndCapture, also passing each streamed result to observe, if set.
+func runAndCapture(ctx context.Context, registry TaskRegistry, task *Task, observe func(OutputResult)) ([]byte, OutputResult, error) {
+	executor, err := registry.GetExecutor(task.Type)
+	if err != nil {
+		return nil, OutputResult{}, err
+	}
+
+	ctx, s := startSpan(ctx, task)
+	resultsChan, err := executor.Execute(ctx, task)
+	if err != nil {
+		return nil, OutputResult{}, err
+	}
+
+	observeStamped := func(result OutputResult) {
+		if observe != nil {
+			observe(s.stamp(result))
+		}
+	}
+	final := s.stamp(combineOutputResults(ctx, resultsChan, observeStamped))
+	task.Output = s.stamp(task.Output)
+	output := []byte(final.ResultData)
+	if final.Status == StatusFailed {
+		errMsg := final.Error
+		if errMsg == "" {
+			errMsg = final.Message
+		}
+		return output, final, fmt.Errorf("task %s failed: %s", task.TaskId, errMsg)
+	}
+	return output, final, nil
+}
@@ -3,8 +3,12 @@ package task_test
 import (
 	"ai-agent-v3/internal/task"
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 	"time"
 )
@@ -25,6 +29,23 @@ func TestExecutorRegistrationAndRetrieval(t *testing.T) {
 		{task.TaskPatchFile, "*task.PatchFileExecutor"},
 		{task.TaskListDirectory, "*task.ListDirectoryExecutor"},
 		{task.TaskRequestUserInput, "*task.RequestUserInputExecutor"},
+		{task.TaskWriteFiles, "*task.WriteFilesExecutor"},
+		{task.TaskTouch, "*task.TouchExecutor"},
+		{task.TaskDiskUsage, "*task.DiskUsageExecutor"},
+		{task.TaskWhich, "*task.WhichExecutor"},
+		{task.TaskEval, "*task.EvalExecutor"},
+		{task.TaskNormalizeEOL, "*task.NormalizeEOLExecutor"},
+		{task.TaskFileCompareAndSwap, "*task.FileCompareAndSwapExecutor"},
+		{task.TaskReadStructured, "*task.ReadStructuredExecutor"},
+		{task.TaskExtractJSON, "*task.ExtractJSONExecutor"},
+		{task.TaskValidatePatch, "*task.ValidatePatchExecutor"},
+		{task.TaskManifest, "*task.ManifestExecutor"},
+		{task.TaskReadLink, "*task.ReadLinkExecutor"},
+		{task.TaskPatchFiles, "*task.PatchFilesExecutor"},
+		{task.TaskTruncate, "*task.TruncateExecutor"},
+		{task.TaskCompareTrees, "*task.CompareTreesExecutor"},
+		{task.TaskFileMove, "*task.FileMoveExecutor"},
+		{task.TaskFileCopy, "*task.FileCopyExecutor"},
 	}
 
 	for _, tc := range testCases {
@@ -101,3 +122,328 @@ func TestBasicExecutorExecution(t *testing.T) {
 			cmd.TaskId, cmd.Output.TaskID)
 	}
 }
+
+func TestRunAndCapture(t *testing.T) {
+	registry := task.NewMapRegistry()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	t.Run("FileRead", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "capture.txt")
+		if err := os.WriteFile(filePath, []byte("first\nsecond\n"), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+
+		output, final, err := task.RunAndCapture(ctx, registry, task.NewFileReadTask("capture-read", "Read file", task.FileReadParameters{
+			FilePath: filePath,
+		}))
+		if err != nil {
+			t.Fatalf("RunAndCapture failed: %v", err)
+		}
+		if string(output) != "first\nsecond\n" {
+			t.Errorf("Expected file content, got %q", output)
+		}
+		if final.Status != task.StatusSucceeded {
+			t.Errorf("Expected status %s, got %s", task.StatusSucceeded, final.Status)
+		}
+	})
+
+	t.Run("BashExec", func(t *testing.T) {
+		output, final, err := task.RunAndCapture(ctx, registry, task.NewBashExecTask("capture-bash", "Echo", task.BashExecParameters{
+			Command: "echo captured stdout",
+		}))
+		if err != nil {
+			t.Fatalf("RunAndCapture failed: %v", err)
+		}
+		if !strings.HasPrefix(string(output), "captured stdout\n") {
+			t.Errorf("Expected output to start with stdout, got %q", output)
+		}
+		if final.Status != task.StatusSucceeded {
+			t.Errorf("Expected status %s, got %s", task.StatusSucceeded, final.Status)
+		}
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		missing := filepath.Join(t.TempDir(), "missing.txt")
+		_, final, err := task.RunAndCapture(ctx, registry, task.NewFileReadTask("capture-fail", "Read missing file", task.FileReadParameters{
+			FilePath: missing,
+		}))
+		if err == nil {
+			t.Fatal("Expected an error for a failing task")
+		}
+		if !strings.Contains(err.Error(), "capture-fail") {
+			t.Errorf("Expected error to name the task, got %v", err)
+		}
+		if final.Status != task.StatusFailed {
+			t.Errorf("Expected status %s, got %s", task.StatusFailed, final.Status)
+		}
+	})
+}
+
+// taskEcho is a task type defined outside the task package.
+const taskEcho task.TaskType = "ECHO"
+
+// echoParameters are the parameters of an ECHO task.
+type echoParameters struct {
+	task.BaseParameters
+	Message string `json:"message"`
+	Repeat  int    `json:"repeat"`
+}
+
+// echoExecutor streams the message Repeat times and succeeds.
+type echoExecutor struct{}
+
+func (echoExecutor) Execute(ctx context.Context, t *task.Task) (<-chan task.OutputResult, error) {
+	params, ok := t.Parameters.(echoParameters)
+	if !ok {
+		return nil, fmt.Errorf("invalid parameters for ECHO: %T", t.Parameters)
+	}
+	results := make(chan task.OutputResult, 1)
+	go func() {
+		defer close(results)
+		for i := 0; i < params.Repeat; i++ {
+			results <- task.OutputResult{TaskID: t.TaskId, Status: task.StatusRunning, ResultData: params.Message + "\n"}
+		}
+		t.Status = task.StatusSucceeded
+		final := task.OutputResult{TaskID: t.TaskId, Status: task.StatusSucceeded, Message: "Echoed."}
+		t.UpdateOutput(&final)
+		results <- final
+	}()
+	return results, nil
+}
+
+// TestCustomTaskType registers a task type from outside the package and runs it
+// after a JSON round trip, on its own and inside a GROUP.
+func TestCustomTaskType(t *testing.T) {
+	task.RegisterParameterDecoder(taskEcho, func(data json.RawMessage) (interface{}, error) {
+		var params echoParameters
+		err := json.Unmarshal(data, &params)
+		return params, err
+	})
+	registry := task.NewMapRegistry()
+	registry.Register(taskEcho, echoExecutor{})
+
+	echo := &task.Task{
+		BaseTask:   task.BaseTask{TaskId: "echo-1", Type: taskEcho, Description: "Echo twice"},
+		Parameters: echoParameters{Message: "hello", Repeat: 2},
+	}
+	encoded, err := echo.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON failed: %v", err)
+	}
+	decoded, err := task.FromJSON(encoded)
+	if err != nil {
+		t.Fatalf("FromJSON failed: %v", err)
+	}
+	if decoded.Type != taskEcho {
+		t.Fatalf("Expected type %s, got %s", taskEcho, decoded.Type)
+	}
+	if params, ok := decoded.Parameters.(echoParameters); !ok || params.Message != "hello" || params.Repeat != 2 {
+		t.Fatalf("Expected decoded echo parameters, got %#v", decoded.Parameters)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	t.Run("Direct", func(t *testing.T) {
+		output, final, err := task.RunAndCapture(ctx, registry, decoded)
+		if err != nil {
+			t.Fatalf("RunAndCapture failed: %v", err)
+		}
+		if string(output) != "hello\nhello\n" {
+			t.Errorf("Expected the message twice, got %q", output)
+		}
+		if final.Status != task.StatusSucceeded || decoded.Status != task.StatusSucceeded {
+			t.Errorf("Expected status %s, got %s (task %s)", task.StatusSucceeded, final.Status, decoded.Status)
+		}
+	})
+
+	t.Run("InGroup", func(t *testing.T) {
+		group, err := task.FromJSON(`{"task_id": "group-1", "type": "GROUP", "children": [
+			{"task_id": "echo-2", "type": "ECHO", "parameters": {"message": "from group", "repeat": 1}}
+		]}`)
+		if err != nil {
+			t.Fatalf("FromJSON failed: %v", err)
+		}
+		_, final, err := task.RunAndCapture(ctx, registry, group)
+		if err != nil {
+			t.Fatalf("RunAndCapture failed: %v", err)
+		}
+		if final.Status != task.StatusSucceeded {
+			t.Fatalf("Expected status %s, got %s: %s", task.StatusSucceeded, final.Status, final.Error)
+		}
+		if !strings.Contains(final.ResultData, "from group") {
+			t.Errorf("Expected the group output to contain the echo, got %q", final.ResultData)
+		}
+	})
+
+	t.Run("UnknownTypeWithoutDecoder", func(t *testing.T) {
+		unknown, err := task.FromJSON(`{"task_id": "x", "type": "UNREGISTERED", "parameters": {"a": 1}}`)
+		if err != nil {
+			t.Fatalf("FromJSON failed: %v", err)
+		}
+		if unknown.Parameters != nil {
+			t.Errorf("Expected parameters of a type without a decoder to be dropped, got %#v", unknown.Parameters)
+		}
+		if _, err := registry.GetExecutor(unknown.Type); err == nil {
+			t.Error("Expected no executor for an unregistered type")
+		}
+	})
+}
+
+// TestAbandonedResultsChannel verifies that executors whose consumer stops reading
+// do not leak goroutines once the context is cancelled.
+func TestAbandonedResultsChannel(t *testing.T) {
+	tempDir := t.TempDir()
+	bigFile := filepath.Join(tempDir, "big.txt")
+	if err := os.WriteFile(bigFile, []byte(strings.Repeat("line\n", 10000)), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	registry := task.NewMapRegistryWithConfig(task.ExecutorConfig{ResultSendTimeout: 10 * time.Millisecond})
+
+	testCases := []struct {
+		name string
+		task func() *task.Task
+	}{
+		{"BashExec", func() *task.Task {
+			return task.NewBashExecTask("abandon-bash", "Endless output", task.BashExecParameters{
+				Command: "while true; do echo tick; done",
+			})
+		}},
+		{"FileRead", func() *task.Task {
+			return task.NewFileReadTask("abandon-read", "Read large file", task.FileReadParameters{
+				FilePath: bigFile,
+			})
+		}},
+		{"Group", func() *task.Task {
+			return task.NewGroupTask("abandon-group", "Group with endless output", []*task.Task{
+				task.NewBashExecTask("abandon-child", "Endless output", task.BashExecParameters{
+					Command: "while true; do echo tick; done",
+				}),
+			})
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			baseline := runtime.NumGoroutine()
+
+			executor, err := registry.GetExecutor(tc.task().Type)
+			if err != nil {
+				t.Fatalf("Failed to get executor: %v", err)
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			resultsChan, err := executor.Execute(ctx, tc.task())
+			if err != nil {
+				t.Fatalf("Execute returned an unexpected error: %v", err)
+			}
+
+			// Receive a single result, then abandon the channel and cancel
+			<-resultsChan
+			cancel()
+
+			deadline := time.Now().Add(5 * time.Second)
+			for runtime.NumGoroutine() > baseline {
+				if time.Now().After(deadline) {
+					t.Fatalf("Goroutines leaked: %d running, expected at most %d", runtime.NumGoroutine(), baseline)
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		})
+	}
+}
+
+// TestResultsCarryTaskMetadata verifies that every result an executor sends for a task
+// echoes that task's type and description.
+func TestResultsCarryTaskMetadata(t *testing.T) {
+	dir := t.TempDir()
+	textFile := filepath.Join(dir, "notes.txt")
+	jsonFile := filepath.Join(dir, "data.json")
+	if err := os.WriteFile(textFile, []byte("one\ntwo\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if err := os.WriteFile(jsonFile, []byte(`{"a": 1}`), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	linkFile := filepath.Join(dir, "link")
+	if err := os.Symlink("notes.txt", linkFile); err != nil {
+		t.Fatalf("Failed to create test symlink: %v", err)
+	}
+	registry := task.NewMapRegistryWithConfig(task.ExecutorConfig{UserInput: strings.NewReader("yes\n")})
+
+	tasks := []*task.Task{
+		task.NewBashExecTask("meta-bash", "Echo a line", task.BashExecParameters{Command: "echo hi"}),
+		task.NewFileReadTask("meta-read", "Read notes", task.FileReadParameters{FilePath: textFile}),
+		task.NewFileWriteTask("meta-write", "Write a file", task.FileWriteParameters{FilePath: filepath.Join(dir, "out.txt"), Content: "x"}),
+		task.NewPatchFileTask("meta-patch", "Patch notes", task.PatchFileParameters{
+			FilePath: textFile,
+			Patch:    "--- a/notes.txt\n+++ b/notes.txt\n@@ -1,2 +1,2 @@\n one\n-two\n+three\n",
+		}),
+		task.NewListDirectoryTask("meta-list", "List the directory", task.ListDirectoryParameters{Path: dir}),
+		task.NewRequestUserInputTask("meta-input", "Ask the user", task.RequestUserInputParameters{Prompt: "Continue?"}),
+		task.NewWriteFilesTask("meta-writes", "Write several files", task.WriteFilesParameters{
+			Files: []task.FileWriteEntry{{Path: filepath.Join(dir, "w1.txt"), Content: "1"}},
+		}),
+		task.NewTouchTask("meta-touch", "Touch a file", task.TouchParameters{FilePath: filepath.Join(dir, "touched"), CreateIfMissing: true}),
+		task.NewDiskUsageTask("meta-du", "Measure the directory", task.DiskUsageParameters{Path: dir}),
+		task.NewWhichTask("meta-which", "Find bash", task.WhichParameters{Name: "bash"}),
+		task.NewEvalTask("meta-eval", "Evaluate a condition", task.EvalParameters{Expression: "1 < 2"}),
+		task.NewNormalizeEOLTask("meta-eol", "Normalize line endings", task.NormalizeEOLParameters{FilePath: textFile, Target: task.EOLTargetLF}),
+		task.NewFileCompareAndSwapTask("meta-cas", "Create a file", task.FileCompareAndSwapParameters{FilePath: filepath.Join(dir, "cas.txt"), NewContent: "x"}),
+		task.NewReadStructuredTask("meta-structured", "Parse JSON", task.ReadStructuredParameters{FilePath: jsonFile}),
+		task.NewExtractJSONTask("meta-extract", "Extract a field", task.ExtractJSONParameters{Input: `{"a": 1}`, Path: "$.a"}),
+		task.NewValidatePatchTask("meta-validate", "Validate a patch", task.ValidatePatchParameters{
+			Patch: "--- a/x.txt\n+++ b/x.txt\n@@ -1 +1 @@\n-a\n+b\n",
+		}),
+		task.NewManifestTask("meta-manifest", "Write a manifest", task.ManifestParameters{Path: dir, OutputPath: filepath.Join(dir, "SHA256SUMS")}),
+		task.NewReadLinkTask("meta-readlink", "Read a link", task.ReadLinkParameters{Path: linkFile}),
+		task.NewPatchFilesTask("meta-patchfiles", "Patch a file", task.PatchFilesParameters{
+			Files: []string{textFile},
+			Patch: "--- a/notes.txt\n+++ b/notes.txt\n@@ -1,2 +1,2 @@\n one\n-three\n+four\n",
+		}),
+		task.NewTruncateTask("meta-truncate", "Empty a file", task.TruncateParameters{FilePath: filepath.Join(dir, "w1.txt")}),
+		task.NewCompareTreesTask("meta-compare-trees", "Compare a tree with itself", task.CompareTreesParameters{Left: dir, Right: dir}),
+		task.NewFileMoveTask("meta-move", "Rename a file", task.FileMoveParameters{Source: filepath.Join(dir, "w1.txt"), Destination: filepath.Join(dir, "moved", "w1.txt")}),
+		task.NewFileCopyTask("meta-copy", "Copy a file", task.FileCopyParameters{Source: textFile, Destination: filepath.Join(dir, "copies", "notes.txt")}),
+		task.NewGroupTask("meta-group", "Group of one", []*task.Task{
+			task.NewBashExecTask("meta-group-child", "Child command", task.BashExecParameters{Command: "echo child"}),
+		}),
+	}
+
+	for _, tk := range tasks {
+		t.Run(string(tk.Type), func(t *testing.T) {
+			executor, err := registry.GetExecutor(tk.Type)
+			if err != nil {
+				t.Fatalf("Failed to get executor: %v", err)
+			}
+			resultsChan, err := executor.Execute(context.Background(), tk)
+			if err != nil {
+				t.Fatalf("Execute returned an unexpected error: %v", err)
+			}
+
+			own := 0
+			var final task.OutputResult
+			for result := range resultsChan {
+				if result.TaskID != tk.TaskId {
+					continue
+				}
+				own++
+				final = result
+				if result.TaskType != tk.Type || result.Description != tk.Description {
+					t.Errorf("Result %q carries type %q and description %q, expected %q and %q",
+						result.Message, result.TaskType, result.Description, tk.Type, tk.Description)
+				}
+			}
+			if own == 0 {
+				t.Fatal("No results received for the task")
+			}
+			if final.Status != task.StatusSucceeded {
+				t.Errorf("Expected task to succeed, got %s: %s", final.Status, final.Error)
+			}
+			// Executors that record the final result on the task must record the metadata too
+			if tk.Output.TaskID != "" && (tk.Output.TaskType != tk.Type || tk.Output.Description != tk.Description) {
+				t.Errorf("Task output carries type %q and description %q", tk.Output.TaskType, tk.Output.Description)
+			}
+		})
+	}
+}
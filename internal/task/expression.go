@@ -0,0 +1,492 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Error constants for expression parsing and evaluation
+const (
+	errExprUnexpectedChar  = "unexpected character %q at position %d"
+	errExprUnterminated    = "unterminated string starting at position %d"
+	errExprUnexpectedToken = "unexpected %s at position %d"
+	errExprUndefinedVar    = "undefined variable '%s'"
+	errExprUnknownFunc     = "unknown function '%s'"
+	errExprFuncArgs        = "function '%s' expects %d argument(s), got %d"
+	errExprFuncArgType     = "function '%s' expects a %s argument, got %s"
+	errExprOperandType     = "operator '%s' cannot be applied to %s and %s"
+	errExprNotBool         = "operator '%s' expects a boolean, got %s"
+	errExprUnsupportedVar  = "variable '%s' has unsupported type %T"
+)
+
+// exprEnv supplies variables and the functions an expression may call.
+type exprEnv struct {
+	variables map[string]any
+	// fileExists reports whether path names an existing file or directory.
+	fileExists func(path string) (bool, error)
+}
+
+// exprNode is a node of a parsed expression.
+// Evaluation yields a bool, a float64 or a string.
+type exprNode interface {
+	eval(env *exprEnv) (any, error)
+}
+
+// parseExpression parses a restricted boolean expression. The grammar is:
+//
+//	or      = and { "||" and }
+//	and     = unary { "&&" unary }
+//	unary   = "!" unary | compare
+//	compare = primary [ ( "==" | "!=" | "<" | "<=" | ">" | ">=" ) primary ]
+//	primary = number | string | "true" | "false" | ident [ "(" [ or { "," or } ] ")" ] | "(" or ")"
+//
+// Strings are double-quoted with Go escape sequences. Identifiers followed by
+// parentheses are function calls; the only function is fileExists(path).
+func parseExpression(input string) (exprNode, error) {
+	tokens, err := tokenizeExpression(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf(errExprUnexpectedToken, tok.describe(), tok.pos)
+	}
+	return node, nil
+}
+
+// --- Tokenizer ---
+
+type exprTokenKind int
+
+const (
+	tokenEOF exprTokenKind = iota
+	tokenNumber
+	tokenString
+	tokenIdent
+	tokenOperator
+)
+
+type exprToken struct {
+	kind  exprTokenKind
+	text  string
+	value any
+	pos   int
+}
+
+func (t exprToken) describe() string {
+	if t.kind == tokenEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// exprOperators lists the operator tokens, two-character operators first.
+var exprOperators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "(", ")", ","}
+
+func tokenizeExpression(input string) ([]exprToken, error) {
+	var tokens []exprToken
+	for pos := 0; pos < len(input); {
+		c := rune(input[pos])
+		switch {
+		case unicode.IsSpace(c):
+			pos++
+
+		case c == '"':
+			end := pos + 1
+			for end < len(input) && input[end] != '"' {
+				if input[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(input) {
+				return nil, fmt.Errorf(errExprUnterminated, pos)
+			}
+			text := input[pos : end+1]
+			value, err := strconv.Unquote(text)
+			if err != nil {
+				return nil, fmt.Errorf(errExprUnexpectedToken, text, pos)
+			}
+			tokens = append(tokens, exprToken{kind: tokenString, text: text, value: value, pos: pos})
+			pos = end + 1
+
+		case unicode.IsDigit(c) || (c == '-' && pos+1 < len(input) && unicode.IsDigit(rune(input[pos+1]))):
+			end := pos + 1
+			for end < len(input) && (unicode.IsDigit(rune(input[end])) || input[end] == '.') {
+				end++
+			}
+			value, err := strconv.ParseFloat(input[pos:end], 64)
+			if err != nil {
+				return nil, fmt.Errorf(errExprUnexpectedToken, input[pos:end], pos)
+			}
+			tokens = append(tokens, exprToken{kind: tokenNumber, text: input[pos:end], value: value, pos: pos})
+			pos = end
+
+		case unicode.IsLetter(c) || c == '_':
+			end := pos + 1
+			for end < len(input) && (unicode.IsLetter(rune(input[end])) || unicode.IsDigit(rune(input[end])) || input[end] == '_' || input[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, exprToken{kind: tokenIdent, text: input[pos:end], pos: pos})
+			pos = end
+
+		default:
+			matched := false
+			for _, op := range exprOperators {
+				if strings.HasPrefix(input[pos:], op) {
+					tokens = append(tokens, exprToken{kind: tokenOperator, text: op, pos: pos})
+					pos += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf(errExprUnexpectedChar, c, pos)
+			}
+		}
+	}
+	return append(tokens, exprToken{kind: tokenEOF, pos: len(input)}), nil
+}
+
+// --- Parser ---
+
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+}
+
+func (p *exprParser) peek() exprToken {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() exprToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes the next token if it is the operator op.
+func (p *exprParser) accept(op string) bool {
+	if tok := p.peek(); tok.kind == tokenOperator && tok.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) expect(op string) error {
+	if !p.accept(op) {
+		tok := p.peek()
+		return fmt.Errorf(errExprUnexpectedToken, tok.describe(), tok.pos)
+	}
+	return nil
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if p.accept("!") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *exprParser) parseCompare() (exprNode, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.accept(op) {
+			right, err := p.parsePrimary()
+			if err != nil {
+				return nil, err
+			}
+			return &compareNode{op: op, left: left, right: right}, nil
+		}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenNumber, tokenString:
+		return &literalNode{value: tok.value}, nil
+
+	case tokenIdent:
+		switch tok.text {
+		case "true":
+			return &literalNode{value: true}, nil
+		case "false":
+			return &literalNode{value: false}, nil
+		}
+		if !p.accept("(") {
+			return &variableNode{name: tok.text}, nil
+		}
+		return p.parseCall(tok.text)
+
+	case tokenOperator:
+		if tok.text == "(" {
+			node, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return node, nil
+		}
+	}
+	return nil, fmt.Errorf(errExprUnexpectedToken, tok.describe(), tok.pos)
+}
+
+// exprFunctionArity maps each function an expression may call to its argument count.
+var exprFunctionArity = map[string]int{
+	"fileExists": 1,
+}
+
+// parseCall parses the arguments of a call to name after its opening parenthesis.
+func (p *exprParser) parseCall(name string) (exprNode, error) {
+	arity, ok := exprFunctionArity[name]
+	if !ok {
+		return nil, fmt.Errorf(errExprUnknownFunc, name)
+	}
+
+	call := &callNode{name: name}
+	if !p.accept(")") {
+		for {
+			arg, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			call.args = append(call.args, arg)
+			if p.accept(")") {
+				break
+			}
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(call.args) != arity {
+		return nil, fmt.Errorf(errExprFuncArgs, name, arity, len(call.args))
+	}
+	return call, nil
+}
+
+// --- Evaluation ---
+
+type literalNode struct {
+	value any
+}
+
+func (n *literalNode) eval(env *exprEnv) (any, error) {
+	return n.value, nil
+}
+
+type variableNode struct {
+	name string
+}
+
+func (n *variableNode) eval(env *exprEnv) (any, error) {
+	value, ok := env.variables[n.name]
+	if !ok {
+		return nil, fmt.Errorf(errExprUndefinedVar, n.name)
+	}
+	return normalizeExprValue(n.name, value)
+}
+
+// normalizeExprValue converts a variable to one of the evaluated types.
+func normalizeExprValue(name string, value any) (any, error) {
+	switch v := value.(type) {
+	case bool, string, float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case float32:
+		return float64(v), nil
+	}
+	return nil, fmt.Errorf(errExprUnsupportedVar, name, value)
+}
+
+type notNode struct {
+	operand exprNode
+}
+
+func (n *notNode) eval(env *exprEnv) (any, error) {
+	value, err := n.operand.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return nil, fmt.Errorf(errExprNotBool, "!", exprTypeName(value))
+	}
+	return !b, nil
+}
+
+// logicalNode is "&&" or "||". The right operand is only evaluated when needed.
+type logicalNode struct {
+	op          string
+	left, right exprNode
+}
+
+func (n *logicalNode) eval(env *exprEnv) (any, error) {
+	left, err := n.evalBool(env, n.left)
+	if err != nil {
+		return nil, err
+	}
+	if (n.op == "&&" && !left) || (n.op == "||" && left) {
+		return left, nil
+	}
+	return n.evalBool(env, n.right)
+}
+
+func (n *logicalNode) evalBool(env *exprEnv, operand exprNode) (bool, error) {
+	value, err := operand.eval(env)
+	if err != nil {
+		return false, err
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf(errExprNotBool, n.op, exprTypeName(value))
+	}
+	return b, nil
+}
+
+type compareNode struct {
+	op          string
+	left, right exprNode
+}
+
+func (n *compareNode) eval(env *exprEnv) (any, error) {
+	left, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch l := left.(type) {
+	case float64:
+		if r, ok := right.(float64); ok {
+			return compareOrdered(n.op, l, r), nil
+		}
+	case string:
+		if r, ok := right.(string); ok {
+			return compareOrdered(n.op, l, r), nil
+		}
+	case bool:
+		if r, ok := right.(bool); ok && (n.op == "==" || n.op == "!=") {
+			return (l == r) == (n.op == "=="), nil
+		}
+	}
+	return nil, fmt.Errorf(errExprOperandType, n.op, exprTypeName(left), exprTypeName(right))
+}
+
+func compareOrdered[T float64 | string](op string, l, r T) bool {
+	switch op {
+	case "==":
+		return l == r
+	case "!=":
+		return l != r
+	case "<":
+		return l < r
+	case "<=":
+		return l <= r
+	case ">":
+		return l > r
+	default:
+		return l >= r
+	}
+}
+
+type callNode struct {
+	name string
+	args []exprNode
+}
+
+func (n *callNode) eval(env *exprEnv) (any, error) {
+	switch n.name {
+	case "fileExists":
+		arg, err := n.args[0].eval(env)
+		if err != nil {
+			return nil, err
+		}
+		path, ok := arg.(string)
+		if !ok {
+			return nil, fmt.Errorf(errExprFuncArgType, n.name, "string", exprTypeName(arg))
+		}
+		return env.fileExists(path)
+	}
+	return nil, fmt.Errorf(errExprUnknownFunc, n.name)
+}
+
+// exprTypeName names the type of an evaluated value for error messages.
+func exprTypeName(value any) string {
+	switch value.(type) {
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// statExists reports whether path exists, treating only "not exist" as false.
+func statExists(path string) (bool, error) {
+	_, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -0,0 +1,96 @@
+package task
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExpression_Evaluate(t *testing.T) {
+	existing := map[string]bool{"present.txt": true}
+	env := &exprEnv{
+		variables: map[string]any{
+			"exitCode": 0,
+			"retries":  float64(2),
+			"branch":   "main",
+			"verbose":  true,
+			"build.ok": false,
+		},
+		fileExists: func(path string) (bool, error) { return existing[path], nil },
+	}
+
+	testCases := []struct {
+		expression string
+		expected   any
+	}{
+		{`true`, true},
+		{`!false`, true},
+		{`exitCode == 0`, true},
+		{`retries < 3 && retries >= 2`, true},
+		{`retries > -1.5`, true},
+		{`branch == "main" || branch == "release"`, true},
+		{`branch != "main"`, false},
+		{`"abc" < "abd"`, true},
+		{`verbose == true && !build.ok`, true},
+		{`fileExists("present.txt") && exitCode == 0`, true},
+		{`fileExists("missing.txt")`, false},
+		{`!(fileExists("missing.txt") || exitCode != 0)`, true},
+		{`retries`, float64(2)},
+		// The right operand is not evaluated once the result is known
+		{`false && undefined`, false},
+		{`true || undefined`, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expression, func(t *testing.T) {
+			node, err := parseExpression(tc.expression)
+			require.NoError(t, err)
+			value, err := node.eval(env)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, value)
+		})
+	}
+}
+
+func TestParseExpression_Errors(t *testing.T) {
+	env := &exprEnv{
+		variables:  map[string]any{"count": 1, "name": "x", "list": []string{"a"}},
+		fileExists: func(path string) (bool, error) { return false, nil },
+	}
+
+	testCases := []struct {
+		name          string
+		expression    string
+		parseError    bool
+		errorContains string
+	}{
+		{"UndefinedVariable", `exitCode == 0`, false, "undefined variable 'exitCode'"},
+		{"TypeMismatch", `count == "1"`, false, "cannot be applied to number and string"},
+		{"NonBooleanOperand", `count && true`, false, "operator '&&' expects a boolean, got number"},
+		{"UnsupportedVariable", `list == 1`, false, "unsupported type"},
+		{"ArgumentType", `fileExists(count)`, false, "expects a string argument"},
+		{"UnknownFunction", `exec("rm -rf /")`, true, "unknown function 'exec'"},
+		{"WrongArity", `fileExists("a", "b")`, true, "expects 1 argument(s), got 2"},
+		{"UnterminatedString", `name == "x`, true, "unterminated string"},
+		{"UnexpectedCharacter", `count + 1`, true, "unexpected character '+'"},
+		{"TrailingTokens", `true false`, true, "unexpected \"false\""},
+		{"MissingParen", `(true`, true, "unexpected end of expression"},
+		{"Empty", ``, true, "unexpected end of expression"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			node, err := parseExpression(tc.expression)
+			if tc.parseError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errorContains)
+				return
+			}
+			require.NoError(t, err)
+			_, err = node.eval(env)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.errorContains)
+		})
+	}
+}
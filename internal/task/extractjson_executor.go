@@ -0,0 +1,265 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Error constants for ExtractJSONExecutor
+const (
+	// Command validation errors
+	errExtractJSONInvalidCommandType = "invalid command type for ExtractJSONExecutor: %T"
+	errExtractJSONEmptyPath          = "path cannot be empty"
+	errExtractJSONInvalidPath        = "invalid path '%s' at offset %d: %s"
+
+	// Extraction errors
+	errExtractJSONKeyNotFound     = "path '%s' not found: no key %q in object at '%s'"
+	errExtractJSONIndexOutOfRange = "path '%s' not found: index %d out of range for array of length %d at '%s'"
+	errExtractJSONNotObject       = "path '%s' not found: value at '%s' is %s, not an object"
+	errExtractJSONNotArray        = "path '%s' not found: value at '%s' is %s, not an array"
+	errExtractJSONEncodeFailed    = "failed to encode extracted value: %w"
+
+	// Status messages
+	msgExtractJSONCancelled = "JSON extraction cancelled."
+	msgExtractJSONTimedOut  = "JSON extraction timed out."
+	msgExtractJSONFailed    = "JSON extraction failed: %v"
+	msgExtractJSONSucceeded = "Extracted '%s'."
+)
+
+// jsonPathSegment is a single step of a parsed path: an object key or an array index.
+type jsonPathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// ExtractJSONExecutor handles the execution of ExtractJSONTask.
+// It selects a single value from a JSON document using a simple JSONPath expression.
+type ExtractJSONExecutor struct {
+	config ExecutorConfig
+}
+
+var _ TaskExecutor = (*ExtractJSONExecutor)(nil)
+
+// NewExtractJSONExecutor creates a new ExtractJSONExecutor.
+func NewExtractJSONExecutor() *ExtractJSONExecutor {
+	return NewExtractJSONExecutorWithConfig(ExecutorConfig{})
+}
+
+// NewExtractJSONExecutorWithConfig creates a new ExtractJSONExecutor using the shared executor config.
+func NewExtractJSONExecutorWithConfig(cfg ExecutorConfig) *ExtractJSONExecutor {
+	return &ExtractJSONExecutor{config: cfg}
+}
+
+// Execute implements the TaskExecutor interface for ExtractJSONTask.
+// On success Payload holds the extracted value. ResultData holds it as plain text when
+// it is a string and as compact JSON otherwise, so it can be used in later result references.
+func (e *ExtractJSONExecutor) Execute(ctx context.Context, extractCmd *Task) (<-chan OutputResult, error) {
+	if extractCmd.Type != TaskExtractJSON {
+		return nil, fmt.Errorf(errExtractJSONInvalidCommandType, extractCmd)
+	}
+
+	// Check if task is already in a terminal state
+	terminalChan, err := HandleTerminalTask(extractCmd.TaskId, extractCmd.Status, extractCmd.Output)
+	if err != nil || terminalChan != nil {
+		return terminalChan, err
+	}
+
+	params := extractCmd.Parameters.(ExtractJSONParameters)
+	segments, err := parseJSONPath(params.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(chan OutputResult, 1)
+	go func() {
+		defer close(results)
+
+		ctx, cancel := e.config.withTimeout(ctx, extractCmd)
+		defer cancel()
+
+		startedAt := e.config.clock().Now()
+		extractCmd.Status = StatusRunning
+		value, err := extractJSON(ctx, params, segments)
+
+		finalResult := createExtractJSONResult(extractCmd.TaskId, params.Path, value, err)
+		extractCmd.Status = finalResult.Status
+		finalResult.setTimes(e.config.clock(), startedAt)
+		extractCmd.UpdateOutput(&finalResult)
+		e.config.send(ctx, results, finalResult)
+	}()
+
+	return results, nil
+}
+
+// extractJSON parses params.Input and walks it along segments.
+func extractJSON(ctx context.Context, params ExtractJSONParameters, segments []jsonPathSegment) (any, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	value, err := parseJSONDocument([]byte(params.Input))
+	if err != nil {
+		return nil, err
+	}
+
+	walked := "$"
+	for _, segment := range segments {
+		if segment.isIndex {
+			array, ok := value.([]any)
+			if !ok {
+				return nil, fmt.Errorf(errExtractJSONNotArray, params.Path, walked, jsonKind(value))
+			}
+			if segment.index >= len(array) {
+				return nil, fmt.Errorf(errExtractJSONIndexOutOfRange, params.Path, segment.index, len(array), walked)
+			}
+			value = array[segment.index]
+			walked += "[" + strconv.Itoa(segment.index) + "]"
+			continue
+		}
+
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf(errExtractJSONNotObject, params.Path, walked, jsonKind(value))
+		}
+		if value, ok = object[segment.key]; !ok {
+			return nil, fmt.Errorf(errExtractJSONKeyNotFound, params.Path, segment.key, walked)
+		}
+		walked += "." + segment.key
+	}
+	return value, nil
+}
+
+// parseJSONPath parses a path such as `$.items[0].name` or `config["key.with.dots"]`.
+// The leading `$` is optional, and `$` alone selects the whole document.
+func parseJSONPath(path string) ([]jsonPathSegment, error) {
+	if path == "" {
+		return nil, invalidf(errExtractJSONEmptyPath)
+	}
+	invalid := func(offset int, reason string) error {
+		return invalidf(errExtractJSONInvalidPath, path, offset, reason)
+	}
+
+	var segments []jsonPathSegment
+	readKey := func(start int) (int, error) {
+		end := start
+		for end < len(path) && path[end] != '.' && path[end] != '[' {
+			end++
+		}
+		if end == start {
+			return 0, invalid(start, "expected a key")
+		}
+		segments = append(segments, jsonPathSegment{key: path[start:end]})
+		return end, nil
+	}
+
+	i := 0
+	var err error
+	switch path[0] {
+	case '$':
+		i = 1
+	case '.', '[':
+	default:
+		// A bare leading key, as in `items[0]`, is shorthand for `$.items[0]`
+		if i, err = readKey(0); err != nil {
+			return nil, err
+		}
+	}
+
+	for i < len(path) {
+		switch path[i] {
+		case '.':
+			if i, err = readKey(i + 1); err != nil {
+				return nil, err
+			}
+
+		case '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, invalid(i, "unterminated '['")
+			}
+			inner := path[i+1 : i+end]
+			switch {
+			case len(inner) >= 2 && (inner[0] == '"' || inner[0] == '\'') && inner[len(inner)-1] == inner[0]:
+				segments = append(segments, jsonPathSegment{key: inner[1 : len(inner)-1]})
+			default:
+				index, err := strconv.Atoi(inner)
+				if err != nil || index < 0 {
+					return nil, invalid(i+1, "expected a non-negative index or a quoted key")
+				}
+				segments = append(segments, jsonPathSegment{index: index, isIndex: true})
+			}
+			i += end + 1
+
+		default:
+			return nil, invalid(i, "expected '.' or '['")
+		}
+	}
+	return segments, nil
+}
+
+// jsonKind describes the JSON type of a decoded value for error messages.
+func jsonKind(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "a boolean"
+	case float64:
+		return "a number"
+	case string:
+		return "a string"
+	case []any:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
+
+// createExtractJSONResult constructs the final OutputResult for an ExtractJSONTask.
+func createExtractJSONResult(taskID, path string, value any, err error) OutputResult {
+	var resultData string
+	if err == nil {
+		if s, ok := value.(string); ok {
+			resultData = s
+		} else {
+			var encoded []byte
+			if encoded, err = json.Marshal(value); err != nil {
+				err = fmt.Errorf(errExtractJSONEncodeFailed, err)
+			}
+			resultData = string(encoded)
+		}
+	}
+
+	if err == nil {
+		return OutputResult{
+			TaskID:     taskID,
+			Status:     StatusSucceeded,
+			Message:    fmt.Sprintf(msgExtractJSONSucceeded, path),
+			ResultData: resultData,
+			Payload:    value,
+		}
+	}
+
+	var message string
+	switch {
+	case errors.Is(err, context.Canceled):
+		message = msgExtractJSONCancelled
+	case errors.Is(err, context.DeadlineExceeded):
+		message = msgExtractJSONTimedOut
+	default:
+		message = fmt.Sprintf(msgExtractJSONFailed, err)
+	}
+	return OutputResult{
+		TaskID:      taskID,
+		Status:      StatusFailed,
+		Message:     message,
+		Error:       err.Error(),
+		Err:         err,
+		FailureKind: failureKind(err),
+	}
+}
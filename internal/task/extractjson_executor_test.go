@@ -0,0 +1,118 @@
+package task
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const extractJSONDocument = `{
+  "name": "agent",
+  "build": {"version": {"major": 1, "minor": 4}},
+  "items": [{"id": "first"}, {"id": "second", "tags": ["x", "y"]}],
+  "key.with.dots": true
+}`
+
+func runExtractJSON(t *testing.T, input, path string) OutputResult {
+	t.Helper()
+	cmd := NewExtractJSONTask("extract", "Extract a value", ExtractJSONParameters{Input: input, Path: path})
+	resultsChan, err := NewExtractJSONExecutor().Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+	assert.Equal(t, finalResult.Status, cmd.Status)
+	return finalResult
+}
+
+func TestExtractJSONExecutor_Execute(t *testing.T) {
+	testCases := []struct {
+		name       string
+		path       string
+		resultData string
+		payload    any
+	}{
+		{name: "NestedField", path: "$.build.version.minor", resultData: "4", payload: float64(4)},
+		{name: "BareNestedField", path: "build.version", resultData: `{"major":1,"minor":4}`, payload: map[string]any{"major": float64(1), "minor": float64(4)}},
+		{name: "ArrayElement", path: "$.items[1].id", resultData: "second", payload: "second"},
+		{name: "NestedArrayElement", path: "$.items[1].tags[0]", resultData: "x", payload: "x"},
+		{name: "QuotedKey", path: `$["key.with.dots"]`, resultData: "true", payload: true},
+		{name: "ObjectElement", path: "$.items[0]", resultData: `{"id":"first"}`, payload: map[string]any{"id": "first"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			finalResult := runExtractJSON(t, extractJSONDocument, tc.path)
+			require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+			assert.Equal(t, tc.resultData, finalResult.ResultData)
+			assert.Equal(t, tc.payload, finalResult.Payload)
+		})
+	}
+}
+
+func TestExtractJSONExecutor_Execute_MissingPath(t *testing.T) {
+	testCases := []struct {
+		name    string
+		path    string
+		message string
+	}{
+		{name: "MissingKey", path: "$.build.version.patch", message: `no key "patch" in object at '$.build.version'`},
+		{name: "IndexOutOfRange", path: "$.items[2]", message: "index 2 out of range for array of length 2 at '$.items'"},
+		{name: "NotAnObject", path: "$.name.first", message: "value at '$.name' is a string, not an object"},
+		{name: "NotAnArray", path: "$.build[0]", message: "value at '$.build' is an object, not an array"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			finalResult := runExtractJSON(t, extractJSONDocument, tc.path)
+			assert.Equal(t, StatusFailed, finalResult.Status)
+			assert.Equal(t, FailureExecutionError, finalResult.FailureKind)
+			assert.Contains(t, finalResult.Error, "path '"+tc.path+"' not found")
+			assert.Contains(t, finalResult.Error, tc.message)
+		})
+	}
+}
+
+func TestExtractJSONExecutor_Execute_InvalidInput(t *testing.T) {
+	finalResult := runExtractJSON(t, `{"a": [1, }`, "$.a")
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Contains(t, finalResult.Error, "invalid json at line 1, column 11")
+}
+
+func TestExtractJSONExecutor_Execute_InvalidPath(t *testing.T) {
+	executor := NewExtractJSONExecutor()
+	for _, path := range []string{"", "$.", "$.items[", "$.items[-1]", "$..name", "$items"} {
+		_, err := executor.Execute(context.Background(), NewExtractJSONTask("extract-invalid", "Invalid path", ExtractJSONParameters{
+			Input: "{}",
+			Path:  path,
+		}))
+		assert.Error(t, err, "path %q should be rejected", path)
+	}
+
+	_, err := executor.Execute(context.Background(), NewEvalTask("extract-type", "Wrong type", EvalParameters{Expression: "true"}))
+	assert.Error(t, err)
+}
+
+func TestExtractJSONExecutor_Execute_PriorResult(t *testing.T) {
+	registry := NewMapRegistry()
+	produce := NewBashExecTask("produce", "Print JSON", BashExecParameters{Command: `echo '{"release": {"tag": "v1.2.0"}}'`})
+	extract := NewExtractJSONTask("extract", "Extract the tag", ExtractJSONParameters{
+		Input: "${produce.result:1}",
+		Path:  "$.release.tag",
+	})
+	extract.DependsOn = []string{"produce"}
+	group := NewGroupTask("extract-group", "Extract from a prior result", []*Task{produce, extract})
+
+	executor, err := registry.GetExecutor(TaskGroup)
+	require.NoError(t, err)
+	resultsChan, err := executor.Execute(context.Background(), group)
+	require.NoError(t, err)
+	for range resultsChan {
+	}
+
+	assert.Equal(t, StatusSucceeded, extract.Status, extract.Output.Error)
+	assert.Equal(t, "v1.2.0", extract.Output.ResultData)
+}
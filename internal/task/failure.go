@@ -0,0 +1,77 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// validationError marks an error caused by invalid task parameters.
+// Its message is that of the wrapped error.
+type validationError struct {
+	err error
+}
+
+func (e *validationError) Error() string {
+	return e.err.Error()
+}
+
+func (e *validationError) Unwrap() error {
+	return e.err
+}
+
+// invalidf formats an error that classifies as FailureValidationError.
+func invalidf(format string, args ...any) error {
+	return &validationError{err: fmt.Errorf(format, args...)}
+}
+
+// failureKind classifies err for OutputResult.FailureKind.
+// Cancellation and timeouts are recognized through the context errors they wrap.
+func failureKind(err error) FailureKind {
+	var invalid *validationError
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return FailureTimedOut
+	case errors.Is(err, context.Canceled):
+		return FailureCancelled
+	case errors.As(err, &invalid):
+		return FailureValidationError
+	default:
+		return FailureExecutionError
+	}
+}
+
+// BudgetExceededError reports that a group or plan ran past its MaxRuntimeMs.
+// It wraps context.DeadlineExceeded, so it classifies as FailureTimedOut.
+type BudgetExceededError struct {
+	// Budget is the runtime budget that was exceeded.
+	Budget time.Duration
+	// Completed lists the IDs of the tasks that finished within the budget, in order.
+	Completed []string
+	// Cancelled lists the IDs of the tasks that were interrupted or never started.
+	Cancelled []string
+}
+
+func (e *BudgetExceededError) Error() string {
+	return fmt.Sprintf("runtime budget of %v exceeded; completed: [%s]; cancelled: [%s]",
+		e.Budget, strings.Join(e.Completed, ", "), strings.Join(e.Cancelled, ", "))
+}
+
+func (e *BudgetExceededError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// newBudgetExceededError splits tasks at the first one the budget interrupted.
+func newBudgetExceededError(budget time.Duration, tasks []*Task, interrupted int) *BudgetExceededError {
+	err := &BudgetExceededError{Budget: budget}
+	for i, t := range tasks {
+		if i < interrupted {
+			err.Completed = append(err.Completed, t.TaskId)
+		} else {
+			err.Cancelled = append(err.Cancelled, t.TaskId)
+		}
+	}
+	return err
+}
@@ -0,0 +1,113 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailureKind_Executors(t *testing.T) {
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	testCases := []struct {
+		name     string
+		ctx      context.Context
+		config   ExecutorConfig
+		task     *Task
+		expected FailureKind
+	}{
+		{
+			name:     "CancelledRead",
+			ctx:      cancelledCtx,
+			task:     NewFileReadTask("read-cancelled", "Cancelled read", FileReadParameters{FilePath: createTempFile(t, "content\n")}),
+			expected: FailureCancelled,
+		},
+		{
+			name:     "TimedOutBash",
+			ctx:      context.Background(),
+			config:   ExecutorConfig{DefaultTimeout: 50 * time.Millisecond},
+			task:     NewBashExecTask("bash-timeout", "Slow command", BashExecParameters{Command: "sleep 5"}),
+			expected: FailureTimedOut,
+		},
+		{
+			name:     "NonZeroExit",
+			ctx:      context.Background(),
+			task:     NewBashExecTask("bash-exit", "Failing command", BashExecParameters{Command: "exit 3"}),
+			expected: FailureExecutionError,
+		},
+		{
+			name:     "EmptyPath",
+			ctx:      context.Background(),
+			task:     NewFileReadTask("read-empty-path", "Read without a path", FileReadParameters{}),
+			expected: FailureValidationError,
+		},
+		{
+			name:     "InvalidLineRange",
+			ctx:      context.Background(),
+			task:     NewFileReadTask("read-bad-range", "Read an inverted range", FileReadParameters{FilePath: createTempFile(t, "a\n"), StartLine: 3, EndLine: 1}),
+			expected: FailureValidationError,
+		},
+		{
+			name:     "PathOutsideRoot",
+			ctx:      context.Background(),
+			config:   ExecutorConfig{RootDir: t.TempDir()},
+			task:     NewFileWriteTask("write-outside", "Escape the root", FileWriteParameters{FilePath: "../outside.txt", Content: "x"}),
+			expected: FailureValidationError,
+		},
+		{
+			name:     "MissingFile",
+			ctx:      context.Background(),
+			task:     NewFileReadTask("read-missing", "Read a missing file", FileReadParameters{FilePath: filepath.Join(t.TempDir(), "missing.txt")}),
+			expected: FailureExecutionError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			executor, err := NewMapRegistryWithConfig(tc.config).GetExecutor(tc.task.Type)
+			require.NoError(t, err)
+			resultsChan, err := executor.Execute(tc.ctx, tc.task)
+			require.NoError(t, err)
+
+			// Drain the channel directly; RunAndCapture stops early on a cancelled context
+			var final OutputResult
+			for result := range resultsChan {
+				final = result
+			}
+			assert.Equal(t, StatusFailed, final.Status)
+			assert.Equal(t, tc.expected, final.FailureKind, final.Error)
+			assert.Equal(t, tc.expected, tc.task.Output.FailureKind)
+		})
+	}
+}
+
+func TestFailureKind_SucceededResultHasNone(t *testing.T) {
+	_, final, err := RunAndCapture(context.Background(), NewMapRegistry(), NewBashExecTask("bash-ok", "Succeeding command", BashExecParameters{Command: "true"}))
+	require.NoError(t, err)
+	assert.Empty(t, final.FailureKind)
+}
+
+func TestFailureKind_GroupReportsFirstChildFailure(t *testing.T) {
+	registry := NewMapRegistry()
+	group := NewGroupTask("group", "Group with an invalid child", []*Task{
+		NewFileReadTask("read-empty-path", "Read without a path", FileReadParameters{}),
+	})
+	_, final, err := RunAndCapture(context.Background(), registry, group)
+	require.Error(t, err)
+	assert.Equal(t, FailureValidationError, final.FailureKind)
+}
+
+func TestFailureKindOf(t *testing.T) {
+	assert.Equal(t, FailureCancelled, failureKind(fmt.Errorf("wrapped: %w", context.Canceled)))
+	assert.Equal(t, FailureTimedOut, failureKind(fmt.Errorf("wrapped: %w", context.DeadlineExceeded)))
+	assert.Equal(t, FailureValidationError, failureKind(fmt.Errorf("wrapped: %w", invalidf("bad value %d", 3))))
+	assert.Equal(t, FailureExecutionError, failureKind(errors.New("disk full")))
+	assert.Equal(t, "bad value 3", invalidf("bad value %d", 3).Error())
+}
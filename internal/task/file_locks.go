@@ -0,0 +1,30 @@
+package task
+
+import (
+	"path/filepath"
+	"sync"
+)
+
+// FileLocks serializes modifications of the same file across executors.
+// Executors that share a FileLocks, through ExecutorConfig.FileLocks or the
+// process-wide default, never modify one path at the same time.
+type FileLocks struct {
+	locks sync.Map // Map of cleaned file paths to *sync.Mutex
+}
+
+// defaultFileLocks is used by every executor whose config sets no FileLocks.
+var defaultFileLocks = NewFileLocks()
+
+// NewFileLocks creates an empty set of file locks.
+func NewFileLocks() *FileLocks {
+	return &FileLocks{}
+}
+
+// Lock blocks until the lock for path is held and returns the function that releases it.
+// Paths are compared after filepath.Clean, so "a/../b" and "b" share a lock.
+func (l *FileLocks) Lock(path string) (unlock func()) {
+	value, _ := l.locks.LoadOrStore(filepath.Clean(path), &sync.Mutex{})
+	mutex := value.(*sync.Mutex)
+	mutex.Lock()
+	return mutex.Unlock
+}
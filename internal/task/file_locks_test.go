@@ -0,0 +1,155 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileLocks_Lock_SerializesSamePath(t *testing.T) {
+	locks := NewFileLocks()
+	dir := t.TempDir()
+
+	unlock := locks.Lock(filepath.Join(dir, "a.txt"))
+	acquired := make(chan struct{})
+	go func() {
+		// A path that cleans to the same file shares the lock
+		release := locks.Lock(filepath.Join(dir, "sub", "..", "a.txt"))
+		close(acquired)
+		release()
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("Lock on the same path was acquired while held")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Other paths are not blocked
+	locks.Lock(filepath.Join(dir, "b.txt"))()
+
+	unlock()
+	select {
+	case <-acquired:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Lock was not acquired after release")
+	}
+}
+
+// drainResults returns the last result sent on results.
+func drainResults(t *testing.T, results <-chan OutputResult) OutputResult {
+	t.Helper()
+	var last OutputResult
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case result, ok := <-results:
+			if !ok {
+				return last
+			}
+			last = result
+		case <-timeout:
+			t.Error("Timed out waiting for results")
+			return last
+		}
+	}
+}
+
+func TestFileLocks_WriteWaitsForSharedLock(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "locked.txt")
+	require.NoError(t, os.WriteFile(filePath, []byte("before\n"), 0644))
+
+	cfg := ExecutorConfig{FileLocks: NewFileLocks()}
+	unlock := cfg.FileLocks.Lock(filePath)
+	results, err := NewFileWriteExecutorWithConfig(cfg).Execute(context.Background(), NewFileWriteTask("write-locked", "", FileWriteParameters{
+		FilePath:  filePath,
+		Content:   "after\n",
+		Overwrite: true,
+	}))
+	require.NoError(t, err)
+
+	select {
+	case result := <-results:
+		t.Fatalf("Write finished while the file was locked: %s", result.Status)
+	case <-time.After(50 * time.Millisecond):
+	}
+	content, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, "before\n", string(content))
+
+	unlock()
+	finalResult := drainResults(t, results)
+	assert.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+	content, err = os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, "after\n", string(content))
+}
+
+// TestFileLocks_WriteAndPatchInterleaved races FILE_WRITE against PATCH_FILE on one file.
+// Run it with -race: without a shared lock the patch can read a partly written file.
+func TestFileLocks_WriteAndPatchInterleaved(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i < 100000; i++ {
+		fmt.Fprintf(&b, "line %d\n", i)
+	}
+	original := b.String()
+	patched := strings.Replace(original, "line 99999\n", "line 99999 patched\n", 1)
+	patch := "--- a/shared.txt\n+++ b/shared.txt\n@@ -99999,2 +99999,2 @@\n line 99998\n-line 99999\n+line 99999 patched\n"
+
+	filePath := filepath.Join(t.TempDir(), "shared.txt")
+	require.NoError(t, os.WriteFile(filePath, []byte(original), 0644))
+
+	cfg := ExecutorConfig{FileLocks: NewFileLocks()}
+	writer := NewFileWriteExecutorWithConfig(cfg)
+	patcher := NewPatchFileExecutorWithConfig(cfg)
+
+	for round := 0; round < 10; round++ {
+		var writeResult, patchResult OutputResult
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			results, err := writer.Execute(context.Background(), NewFileWriteTask(fmt.Sprintf("write-%d", round), "", FileWriteParameters{
+				FilePath:  filePath,
+				Content:   original,
+				Overwrite: true,
+			}))
+			if !assert.NoError(t, err) {
+				return
+			}
+			writeResult = drainResults(t, results)
+		}()
+		go func() {
+			defer wg.Done()
+			results, err := patcher.Execute(context.Background(), NewPatchFileTask(fmt.Sprintf("patch-%d", round), "", PatchFileParameters{
+				FilePath: filePath,
+				Patch:    patch,
+			}))
+			if !assert.NoError(t, err) {
+				return
+			}
+			patchResult = drainResults(t, results)
+		}()
+		wg.Wait()
+
+		require.Equal(t, StatusSucceeded, writeResult.Status, writeResult.Error)
+		content, err := os.ReadFile(filePath)
+		require.NoError(t, err)
+		switch string(content) {
+		case original:
+			// The patch ran first, or failed against a file an earlier round already patched
+		case patched:
+			assert.Equal(t, StatusSucceeded, patchResult.Status, "Round %d: patched content without a successful patch", round)
+		default:
+			t.Fatalf("Round %d: inconsistent content of %d bytes (patch: %s %s)", round, len(content), patchResult.Status, patchResult.Error)
+		}
+	}
+}
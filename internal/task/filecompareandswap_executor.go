@@ -0,0 +1,204 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Error constants for FileCompareAndSwapExecutor
+const (
+	// Command validation errors
+	errFileCompareAndSwapInvalidCommandType = "invalid command type for FileCompareAndSwapExecutor: %T"
+	errFileCompareAndSwapEmptyFilePath      = "file path cannot be empty"
+
+	// File operation errors
+	errFileCompareAndSwapResolveFilePath = "failed to resolve file path: %w"
+	errFileCompareAndSwapIsDirectory     = "destination '%s' is a directory"
+	errFileCompareAndSwapLockFailed      = "failed to lock file '%s': %w"
+	errFileCompareAndSwapReadFailed      = "failed to read file '%s': %w"
+	errFileCompareAndSwapWriteFailed     = "failed to replace file '%s': %w"
+
+	// Status messages
+	msgFileCompareAndSwapCancelled = "Compare-and-swap cancelled."
+	msgFileCompareAndSwapTimedOut  = "Compare-and-swap timed out."
+	msgFileCompareAndSwapConflict  = "Compare-and-swap rejected: %v"
+	msgFileCompareAndSwapFailed    = "Compare-and-swap failed: %v"
+	msgFileCompareAndSwapSucceeded = "Replaced content of '%s'."
+	msgFileCompareAndSwapCreated   = "Created '%s'."
+)
+
+// ErrContentConflict is reported by FILE_COMPARE_AND_SWAP when the file's current
+// content no longer matches the expected content. It can be matched with errors.Is
+// against OutputResult.Err.
+var ErrContentConflict = errors.New("file content does not match expected content")
+
+// FileCompareAndSwapExecutor handles the execution of FileCompareAndSwapTask.
+// It replaces a file's content only if the file still holds the expected content,
+// so that changes made by someone else since it was read are never overwritten.
+type FileCompareAndSwapExecutor struct {
+	fs     FileSystem
+	config ExecutorConfig
+}
+
+var (
+	_ TaskExecutor = (*FileCompareAndSwapExecutor)(nil)
+	_ Compensator  = (*FileCompareAndSwapExecutor)(nil)
+)
+
+// NewFileCompareAndSwapExecutor creates a new FileCompareAndSwapExecutor.
+func NewFileCompareAndSwapExecutor() *FileCompareAndSwapExecutor {
+	return NewFileCompareAndSwapExecutorWithConfig(ExecutorConfig{})
+}
+
+// NewFileCompareAndSwapExecutorWithConfig creates a new FileCompareAndSwapExecutor using the shared executor config.
+func NewFileCompareAndSwapExecutorWithConfig(cfg ExecutorConfig) *FileCompareAndSwapExecutor {
+	return &FileCompareAndSwapExecutor{
+		fs:     &defaultFileSystem{locks: cfg.fileLocks(), dirMode: cfg.dirMode()},
+		config: cfg,
+	}
+}
+
+// Execute implements the TaskExecutor interface for FileCompareAndSwapTask.
+func (e *FileCompareAndSwapExecutor) Execute(ctx context.Context, casCmd *Task) (<-chan OutputResult, error) {
+	if casCmd.Type != TaskFileCompareAndSwap {
+		return nil, fmt.Errorf(errFileCompareAndSwapInvalidCommandType, casCmd)
+	}
+
+	// Check if task is already in a terminal state
+	terminalChan, err := HandleTerminalTask(casCmd.TaskId, casCmd.Status, casCmd.Output)
+	if err != nil || terminalChan != nil {
+		return terminalChan, err
+	}
+
+	params := casCmd.Parameters.(FileCompareAndSwapParameters)
+	if params.FilePath == "" {
+		return nil, errors.New(errFileCompareAndSwapEmptyFilePath)
+	}
+
+	results := make(chan OutputResult, 1)
+	go func() {
+		defer close(results)
+
+		ctx, cancel := e.config.withTimeout(ctx, casCmd)
+		defer cancel()
+
+		startedAt := e.config.clock().Now()
+		casCmd.Status = StatusRunning
+		filePath, created, err := e.swap(ctx, params)
+
+		finalResult := createFileCompareAndSwapResult(casCmd.TaskId, filePath, created, err)
+		casCmd.Status = finalResult.Status
+		finalResult.setTimes(e.config.clock(), startedAt)
+		casCmd.UpdateOutput(&finalResult)
+		e.config.send(ctx, results, finalResult)
+	}()
+
+	return results, nil
+}
+
+// swap replaces the file described by params if its content matches ExpectedContent.
+// It returns the resolved path and whether the file had to be created.
+func (e *FileCompareAndSwapExecutor) swap(ctx context.Context, params FileCompareAndSwapParameters) (string, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return "", false, err
+	}
+
+	filePath, err := e.config.resolvePath(params.FilePath, params.WorkingDirectory)
+	if err != nil {
+		return "", false, fmt.Errorf(errFileCompareAndSwapResolveFilePath, err)
+	}
+
+	unlock, err := e.fs.LockFile(filePath)
+	if err != nil {
+		return filePath, false, fmt.Errorf(errFileCompareAndSwapLockFailed, filePath, err)
+	}
+	defer unlock()
+
+	// A missing file is treated as empty so that an empty ExpectedContent creates it
+	mode := e.config.fileMode()
+	var current []byte
+	created := false
+	info, err := e.fs.Stat(filePath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		created = true
+	case err != nil:
+		return filePath, false, fmt.Errorf(errFileCompareAndSwapReadFailed, filePath, err)
+	case info.IsDir():
+		return filePath, false, invalidf(errFileCompareAndSwapIsDirectory, filePath)
+	default:
+		mode = info.Mode().Perm()
+		if current, err = e.fs.ReadFile(filePath); err != nil {
+			return filePath, false, fmt.Errorf(errFileCompareAndSwapReadFailed, filePath, err)
+		}
+	}
+
+	if !bytes.Equal(current, []byte(params.ExpectedContent)) {
+		return filePath, false, fmt.Errorf("'%s': %w", filePath, ErrContentConflict)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return filePath, false, err
+	}
+
+	// Stage and rename so readers never observe a partially written file
+	tempPath, err := stageFile(filePath, params.NewContent, mode)
+	if err != nil {
+		return filePath, false, fmt.Errorf(errFileCompareAndSwapWriteFailed, filePath, err)
+	}
+	if err := os.Rename(tempPath, filePath); err != nil {
+		os.Remove(tempPath)
+		return filePath, false, fmt.Errorf(errFileCompareAndSwapWriteFailed, filePath, err)
+	}
+	return filePath, created, nil
+}
+
+// PrepareCompensation implements Compensator by backing up the target file
+// so that a rollback restores its previous content or removes it if it was created.
+func (e *FileCompareAndSwapExecutor) PrepareCompensation(ctx context.Context, casCmd *Task) (Compensation, error) {
+	params := casCmd.Parameters.(FileCompareAndSwapParameters)
+	filePath, err := e.config.resolvePath(params.FilePath, params.WorkingDirectory)
+	if err != nil {
+		return nil, err
+	}
+	return fileBackupCompensation(filePath)
+}
+
+// createFileCompareAndSwapResult constructs the final OutputResult for a FileCompareAndSwapTask.
+func createFileCompareAndSwapResult(taskID, filePath string, created bool, err error) OutputResult {
+	if err == nil {
+		message := fmt.Sprintf(msgFileCompareAndSwapSucceeded, filePath)
+		if created {
+			message = fmt.Sprintf(msgFileCompareAndSwapCreated, filePath)
+		}
+		return OutputResult{
+			TaskID:     taskID,
+			Status:     StatusSucceeded,
+			Message:    message,
+			ResultData: filePath,
+		}
+	}
+
+	var message string
+	switch {
+	case errors.Is(err, context.Canceled):
+		message = msgFileCompareAndSwapCancelled
+	case errors.Is(err, context.DeadlineExceeded):
+		message = msgFileCompareAndSwapTimedOut
+	case errors.Is(err, ErrContentConflict):
+		message = fmt.Sprintf(msgFileCompareAndSwapConflict, err)
+	default:
+		message = fmt.Sprintf(msgFileCompareAndSwapFailed, err)
+	}
+	return OutputResult{
+		TaskID:      taskID,
+		Status:      StatusFailed,
+		Message:     message,
+		Error:       err.Error(),
+		Err:         err,
+		FailureKind: failureKind(err),
+	}
+}
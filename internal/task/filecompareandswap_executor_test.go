@@ -0,0 +1,107 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runFileCompareAndSwap(t *testing.T, params FileCompareAndSwapParameters) (*Task, OutputResult) {
+	t.Helper()
+	cmd := NewFileCompareAndSwapTask("cas", "Compare and swap", params)
+	resultsChan, err := NewFileCompareAndSwapExecutor().Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+	return cmd, finalResult
+}
+
+func TestFileCompareAndSwapExecutor_Execute_Matching(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "config.txt")
+	require.NoError(t, os.WriteFile(filePath, []byte("version=1\n"), 0600))
+
+	cmd, finalResult := runFileCompareAndSwap(t, FileCompareAndSwapParameters{
+		FilePath:        filePath,
+		ExpectedContent: "version=1\n",
+		NewContent:      "version=2\n",
+	})
+	require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+	assert.Equal(t, StatusSucceeded, cmd.Status)
+	assert.Contains(t, finalResult.Message, "Replaced content")
+
+	content, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, "version=2\n", string(content))
+	info, err := os.Stat(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "Mode should be preserved")
+}
+
+func TestFileCompareAndSwapExecutor_Execute_Conflict(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "config.txt")
+	require.NoError(t, os.WriteFile(filePath, []byte("version=3\n"), 0644))
+
+	cmd, finalResult := runFileCompareAndSwap(t, FileCompareAndSwapParameters{
+		FilePath:        filePath,
+		ExpectedContent: "version=1\n",
+		NewContent:      "version=2\n",
+	})
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Equal(t, StatusFailed, cmd.Status)
+	assert.Equal(t, FailureExecutionError, finalResult.FailureKind)
+	assert.True(t, errors.Is(finalResult.Err, ErrContentConflict), "Err should wrap ErrContentConflict")
+	assert.Contains(t, finalResult.Message, "rejected")
+
+	content, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, "version=3\n", string(content), "Concurrent change must not be overwritten")
+
+	// A missing file only matches an empty expected content
+	missingPath := filepath.Join(t.TempDir(), "missing.txt")
+	_, finalResult = runFileCompareAndSwap(t, FileCompareAndSwapParameters{
+		FilePath:        missingPath,
+		ExpectedContent: "version=1\n",
+		NewContent:      "version=2\n",
+	})
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.True(t, errors.Is(finalResult.Err, ErrContentConflict))
+	assert.NoFileExists(t, missingPath)
+}
+
+func TestFileCompareAndSwapExecutor_Execute_CreatesMissingFile(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "new.txt")
+
+	_, finalResult := runFileCompareAndSwap(t, FileCompareAndSwapParameters{
+		FilePath:   filePath,
+		NewContent: "hello\n",
+	})
+	require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+	assert.Contains(t, finalResult.Message, "Created")
+	assert.Equal(t, filePath, finalResult.ResultData)
+
+	content, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, "hello\n", string(content))
+}
+
+func TestFileCompareAndSwapExecutor_Execute_InvalidParameters(t *testing.T) {
+	executor := NewFileCompareAndSwapExecutor()
+
+	_, err := executor.Execute(context.Background(), NewFileCompareAndSwapTask("cas-path", "No path", FileCompareAndSwapParameters{}))
+	assert.ErrorContains(t, err, "file path cannot be empty")
+
+	_, err = executor.Execute(context.Background(), NewTouchTask("cas-type", "Wrong type", TouchParameters{FilePath: "x"}))
+	assert.Error(t, err)
+
+	_, finalResult := runFileCompareAndSwap(t, FileCompareAndSwapParameters{FilePath: t.TempDir()})
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Equal(t, FailureValidationError, finalResult.FailureKind)
+	assert.Contains(t, finalResult.Error, "is a directory")
+}
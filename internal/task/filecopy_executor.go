@@ -0,0 +1,438 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Error constants for FileCopyExecutor
+const (
+	// Command validation errors
+	errFileCopyInvalidCommandType = "invalid command type for FileCopyExecutor: %T"
+	errFileCopyMissingPath        = "source and destination are required"
+	errFileCopySamePath           = "source and destination are the same path '%s'"
+	errFileCopyIntoItself         = "cannot copy directory '%s' into itself at '%s'"
+	errFileCopySourceIsDirectory  = "source '%s' is a directory; set recursive to copy it"
+	errFileCopyInvalidGlob        = "invalid glob pattern '%s': %w"
+
+	// File operation errors
+	errFileCopyResolveSource      = "failed to resolve source path: %w"
+	errFileCopyResolveDestination = "failed to resolve destination path: %w"
+	errFileCopySourceMissing      = "source '%s' does not exist"
+	errFileCopyStatFailed         = "failed to stat '%s': %w"
+	errFileCopyDestinationExists  = "destination '%s' already exists and overwrite=false"
+	errFileCopyCreateDirFailed    = "failed to create directory '%s': %w"
+	errFileCopyFailed             = "failed to copy '%s' to '%s': %w"
+	errFileCopyUnsupportedFile    = "cannot copy '%s': not a regular file, directory or symbolic link"
+
+	// Status messages
+	msgFileCopyCancelled = "Copy cancelled."
+	msgFileCopyTimedOut  = "Copy timed out."
+	msgFileCopyFailed    = "Copy failed: %v"
+	msgFileCopyProgress  = "Copied '%s' to '%s'."
+	msgFileCopySucceeded = "Copied %d files from '%s' to '%s' in %v."
+)
+
+// copyBufferPool is a sync.Pool for reusing the chunk buffers files are copied through
+var copyBufferPool = sync.Pool{
+	New: func() interface{} {
+		// Default chunk size of 32KB
+		buf := make([]byte, 32*1024)
+		return &buf
+	},
+}
+
+// FileCopyExecutor handles the execution of FileCopyTask.
+// It copies a file, or with Recursive a directory tree, streaming a result per file copied.
+type FileCopyExecutor struct {
+	config ExecutorConfig
+}
+
+var _ TaskExecutor = (*FileCopyExecutor)(nil)
+
+// NewFileCopyExecutor creates a new FileCopyExecutor.
+func NewFileCopyExecutor() *FileCopyExecutor {
+	return &FileCopyExecutor{}
+}
+
+// NewFileCopyExecutorWithConfig creates a new FileCopyExecutor using the shared executor config.
+func NewFileCopyExecutorWithConfig(cfg ExecutorConfig) *FileCopyExecutor {
+	return &FileCopyExecutor{config: cfg}
+}
+
+// Execute implements the TaskExecutor interface for FileCopyTask.
+// Every file or symbolic link copied produces a RUNNING result whose ResultData is
+// its destination path followed by a newline.
+func (e *FileCopyExecutor) Execute(ctx context.Context, copyCmd *Task) (<-chan OutputResult, error) {
+	if copyCmd.Type != TaskFileCopy {
+		return nil, fmt.Errorf(errFileCopyInvalidCommandType, copyCmd)
+	}
+
+	// Check if task is already in a terminal state
+	terminalChan, err := HandleTerminalTask(copyCmd.TaskId, copyCmd.Status, copyCmd.Output)
+	if err != nil || terminalChan != nil {
+		return terminalChan, err
+	}
+
+	params := copyCmd.Parameters.(FileCopyParameters)
+	if params.Source == "" || params.Destination == "" {
+		return nil, invalidf(errFileCopyMissingPath)
+	}
+	for _, pattern := range append(params.Include, params.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, invalidf(errFileCopyInvalidGlob, pattern, err)
+		}
+	}
+
+	results := make(chan OutputResult, 1)
+	go func() {
+		defer close(results)
+
+		ctx, cancel := e.config.withTimeout(ctx, copyCmd)
+		defer cancel()
+
+		startedAt := e.config.clock().Now()
+		copyCmd.Status = StatusRunning
+		copied, err := e.copy(ctx, params, func(source, destination string) {
+			e.config.send(ctx, results, copyCmd.describe(OutputResult{
+				TaskID:     copyCmd.TaskId,
+				Status:     StatusRunning,
+				Message:    fmt.Sprintf(msgFileCopyProgress, source, destination),
+				ResultData: destination + "\n",
+			}))
+		})
+
+		finalResult := createFileCopyResult(copyCmd.TaskId, params, copied, err, e.config.since(startedAt))
+		copyCmd.Status = finalResult.Status
+		finalResult.setTimes(e.config.clock(), startedAt)
+		copyCmd.UpdateOutput(&finalResult)
+		e.config.send(ctx, results, finalResult)
+	}()
+
+	return results, nil
+}
+
+// copy copies the file or tree described by params, calling progress after each
+// file, and returns the number of files copied.
+func (e *FileCopyExecutor) copy(ctx context.Context, params FileCopyParameters, progress func(source, destination string)) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	source, err := e.config.resolvePath(params.Source, params.WorkingDirectory)
+	if err != nil {
+		return 0, fmt.Errorf(errFileCopyResolveSource, err)
+	}
+	destination, err := e.config.resolvePath(params.Destination, params.WorkingDirectory)
+	if err != nil {
+		return 0, fmt.Errorf(errFileCopyResolveDestination, err)
+	}
+	if filepath.Clean(source) == filepath.Clean(destination) {
+		return 0, invalidf(errFileCopySamePath, source)
+	}
+
+	info, err := os.Lstat(source)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, fmt.Errorf(errFileCopySourceMissing, source)
+		}
+		return 0, fmt.Errorf(errFileCopyStatFailed, source, err)
+	}
+	if info.IsDir() {
+		if !params.Recursive {
+			return 0, invalidf(errFileCopySourceIsDirectory, source)
+		}
+		// Walking a tree while copying into it would never end
+		if rel, err := filepath.Rel(source, destination); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return 0, invalidf(errFileCopyIntoItself, source, destination)
+		}
+	}
+	if _, err := os.Lstat(destination); err == nil {
+		if !params.Overwrite {
+			return 0, fmt.Errorf(errFileCopyDestinationExists, destination)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return 0, fmt.Errorf(errFileCopyStatFailed, destination, err)
+	}
+
+	if dir := filepath.Dir(destination); dir != "" {
+		if err := os.MkdirAll(dir, e.config.dirMode()); err != nil {
+			return 0, fmt.Errorf(errFileCopyCreateDirFailed, dir, err)
+		}
+	}
+
+	copied := 0
+	copier := treeCopier{
+		replace: params.Overwrite,
+		include: params.Include,
+		exclude: params.Exclude,
+		locks:   e.config.fileLocks(),
+		copied: func(source, destination string) {
+			copied++
+			progress(source, destination)
+		},
+	}
+	if err := copier.copy(ctx, source, destination); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return copied, ctxErr
+		}
+		return copied, err
+	}
+	return copied, nil
+}
+
+// treeCopier copies files, symbolic links and directory trees, preserving modes.
+// Symbolic links are copied as links, not followed.
+type treeCopier struct {
+	// replace allows existing files at the destination to be replaced; existing
+	// directories are always merged into
+	replace bool
+	// include and exclude filter the entries below the root of the copy, as
+	// described on FileCopyParameters
+	include []string
+	exclude []string
+	// locks, if set, is held for each destination file while it is written
+	locks *FileLocks
+	// copied, if set, is called after each file or symbolic link is copied
+	copied func(source, destination string)
+}
+
+// copy copies the file, symbolic link or directory tree at source to destination.
+func (c treeCopier) copy(ctx context.Context, source, destination string) error {
+	type dirMode struct {
+		path string
+		mode os.FileMode
+	}
+	var dirs []dirMode
+	err := filepath.WalkDir(source, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(destination, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if rel != "." && !c.selected(filepath.ToSlash(rel), info.IsDir()) {
+			if info.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		switch {
+		case info.IsDir():
+			// Directories are created writable so their contents can be copied in,
+			// and given the source's mode once everything is copied
+			dirs = append(dirs, dirMode{path: target, mode: info.Mode().Perm()})
+			if err := c.makeDir(target, info.Mode().Perm()|0700); err != nil {
+				return fmt.Errorf(errFileCopyCreateDirFailed, target, err)
+			}
+			return nil
+		case info.Mode()&fs.ModeSymlink != 0:
+			err = c.copySymlink(path, target)
+		case info.Mode().IsRegular():
+			err = c.copyFile(ctx, path, target, info.Mode().Perm())
+		default:
+			return fmt.Errorf(errFileCopyUnsupportedFile, path)
+		}
+		if err != nil {
+			return fmt.Errorf(errFileCopyFailed, path, target, err)
+		}
+		if c.copied != nil {
+			c.copied(path, target)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if err := os.Chmod(dirs[i].path, dirs[i].mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// selected reports whether the entry at rel, relative to the root of the copy, is copied.
+func (c treeCopier) selected(rel string, isDir bool) bool {
+	if matchesAnyGlob(c.exclude, rel) {
+		return false
+	}
+	return isDir || len(c.include) == 0 || matchesAnyGlob(c.include, rel)
+}
+
+// matchesAnyGlob reports whether one of patterns matches rel, a slash-separated
+// relative path. Patterns without a slash are matched against its base name.
+func matchesAnyGlob(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		name := rel
+		if !strings.Contains(pattern, "/") {
+			name = path.Base(rel)
+		}
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// makeDir creates the directory destination, merging into an existing directory.
+// An existing symbolic link is never followed: it is replaced when replacing is
+// allowed, so that nothing is copied through it to wherever it points.
+func (c treeCopier) makeDir(destination string, mode os.FileMode) error {
+	err := os.Mkdir(destination, mode)
+	if err == nil || !errors.Is(err, os.ErrExist) {
+		return err
+	}
+	existing, statErr := os.Lstat(destination)
+	if statErr != nil {
+		return statErr
+	}
+	if existing.IsDir() {
+		return nil
+	}
+	if !c.replace {
+		return err
+	}
+	if err := os.Remove(destination); err != nil {
+		return err
+	}
+	return os.Mkdir(destination, mode)
+}
+
+// copySymlink recreates the symbolic link at source at destination.
+func (c treeCopier) copySymlink(source, destination string) error {
+	link, err := os.Readlink(source)
+	if err != nil {
+		return err
+	}
+	if c.replace {
+		if err := os.Remove(destination); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return os.Symlink(link, destination)
+}
+
+// copyFile streams the regular file at source to destination in chunks and gives it mode.
+func (c treeCopier) copyFile(ctx context.Context, source, destination string, mode os.FileMode) error {
+	if c.locks != nil {
+		unlock := c.locks.Lock(destination)
+		defer unlock()
+	}
+
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if !c.replace {
+		out, err := os.OpenFile(destination, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+		if err != nil {
+			return err
+		}
+		return writeCopy(ctx, out, in, mode)
+	}
+
+	// A replacement is staged next to destination and renamed over it, so that an
+	// existing symbolic link is replaced rather than written through
+	out, err := os.CreateTemp(filepath.Dir(destination), "."+filepath.Base(destination)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	if err := writeCopy(ctx, out, in, mode); err != nil {
+		os.Remove(out.Name())
+		return err
+	}
+	if err := os.Rename(out.Name(), destination); err != nil {
+		os.Remove(out.Name())
+		return err
+	}
+	return nil
+}
+
+// writeCopy copies src into out, gives out mode and closes it.
+func writeCopy(ctx context.Context, out *os.File, src io.Reader, mode os.FileMode) error {
+	if err := copyChunks(ctx, out, src); err != nil {
+		out.Close()
+		return err
+	}
+	// The umask narrows the mode of new files, as does CreateTemp
+	if err := out.Chmod(mode); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// copyChunks copies src to dst through a pooled buffer, checking ctx between chunks.
+func copyChunks(ctx context.Context, dst io.Writer, src io.Reader) error {
+	bufPtr := copyBufferPool.Get().(*[]byte)
+	defer copyBufferPool.Put(bufPtr)
+	buf := *bufPtr
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n, err := src.Read(buf)
+		if n > 0 {
+			if _, writeErr := dst.Write(buf[:n]); writeErr != nil {
+				return writeErr
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// createFileCopyResult constructs the final OutputResult for a FileCopyTask.
+func createFileCopyResult(taskID string, params FileCopyParameters, copied int, err error, duration time.Duration) OutputResult {
+	if err == nil {
+		return OutputResult{
+			TaskID:  taskID,
+			Status:  StatusSucceeded,
+			Message: fmt.Sprintf(msgFileCopySucceeded, copied, params.Source, params.Destination, duration.Round(time.Millisecond)),
+		}
+	}
+
+	var message string
+	switch {
+	case errors.Is(err, context.Canceled):
+		message = msgFileCopyCancelled
+	case errors.Is(err, context.DeadlineExceeded):
+		message = msgFileCopyTimedOut
+	default:
+		message = fmt.Sprintf(msgFileCopyFailed, err)
+	}
+	return OutputResult{
+		TaskID:      taskID,
+		Status:      StatusFailed,
+		Message:     message,
+		Error:       err.Error(),
+		FailureKind: failureKind(err),
+	}
+}
@@ -0,0 +1,254 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runFileCopy executes a FILE_COPY task and returns the ResultData of its RUNNING
+// results along with the final result.
+func runFileCopy(t *testing.T, params FileCopyParameters) ([]string, OutputResult) {
+	t.Helper()
+	cmd := NewFileCopyTask("copy", "Copy files", params)
+	resultsChan, err := NewFileCopyExecutor().Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	var progress []string
+	var finalResult OutputResult
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case result, ok := <-resultsChan:
+			if !ok {
+				return progress, finalResult
+			}
+			if result.Status == StatusRunning {
+				progress = append(progress, result.ResultData)
+			} else {
+				finalResult = result
+			}
+		case <-timeout:
+			t.Fatal("Timed out waiting for copy results")
+		}
+	}
+}
+
+func TestFileCopyExecutor_Execute_File(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "tool")
+	destination := filepath.Join(dir, "bin", "tool")
+	// Larger than one chunk, so the copy streams through the pooled buffer several times
+	content := bytes.Repeat([]byte("0123456789abcdef"), 10000)
+	require.NoError(t, os.WriteFile(source, content, 0750))
+
+	progress, finalResult := runFileCopy(t, FileCopyParameters{Source: source, Destination: destination})
+	require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+	assert.Contains(t, finalResult.Message, "Copied 1 files")
+	assert.Equal(t, []string{destination + "\n"}, progress)
+
+	copied, err := os.ReadFile(destination)
+	require.NoError(t, err)
+	assert.Equal(t, content, copied)
+	info, err := os.Stat(destination)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0750), info.Mode().Perm())
+	assert.FileExists(t, source)
+}
+
+func TestFileCopyExecutor_Execute_Recursive(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "assets")
+	destination := filepath.Join(dir, "dist", "assets")
+	require.NoError(t, os.MkdirAll(filepath.Join(source, "css"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(source, "empty"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(source, "index.html"), []byte("<html>"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(source, "css", "site.css"), []byte("body{}"), 0600))
+	require.NoError(t, os.Symlink("index.html", filepath.Join(source, "default.html")))
+
+	t.Run("Requires recursive", func(t *testing.T) {
+		_, finalResult := runFileCopy(t, FileCopyParameters{Source: source, Destination: destination})
+		assert.Equal(t, StatusFailed, finalResult.Status)
+		assert.Equal(t, FailureValidationError, finalResult.FailureKind)
+		assert.Contains(t, finalResult.Error, "set recursive")
+		assert.NoDirExists(t, destination)
+	})
+
+	t.Run("Copies the tree", func(t *testing.T) {
+		progress, finalResult := runFileCopy(t, FileCopyParameters{Source: source, Destination: destination, Recursive: true})
+		require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+		assert.Contains(t, finalResult.Message, "Copied 3 files")
+
+		sort.Strings(progress)
+		assert.Equal(t, []string{
+			filepath.Join(destination, "css", "site.css") + "\n",
+			filepath.Join(destination, "default.html") + "\n",
+			filepath.Join(destination, "index.html") + "\n",
+		}, progress)
+
+		content, err := os.ReadFile(filepath.Join(destination, "css", "site.css"))
+		require.NoError(t, err)
+		assert.Equal(t, "body{}", string(content))
+		info, err := os.Stat(filepath.Join(destination, "css", "site.css"))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+		info, err = os.Stat(filepath.Join(destination, "empty"))
+		require.NoError(t, err)
+		assert.True(t, info.IsDir())
+		assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+		target, err := os.Readlink(filepath.Join(destination, "default.html"))
+		require.NoError(t, err)
+		assert.Equal(t, "index.html", target)
+	})
+
+	t.Run("Refuses an existing destination", func(t *testing.T) {
+		_, finalResult := runFileCopy(t, FileCopyParameters{Source: source, Destination: destination, Recursive: true})
+		assert.Equal(t, StatusFailed, finalResult.Status)
+		assert.Contains(t, finalResult.Error, "already exists and overwrite=false")
+	})
+
+	t.Run("Overwrite merges into the destination", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(source, "index.html"), []byte("<html>v2"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(destination, "extra.txt"), []byte("kept"), 0644))
+
+		_, finalResult := runFileCopy(t, FileCopyParameters{Source: source, Destination: destination, Recursive: true, Overwrite: true})
+		require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+
+		content, err := os.ReadFile(filepath.Join(destination, "index.html"))
+		require.NoError(t, err)
+		assert.Equal(t, "<html>v2", string(content))
+		assert.FileExists(t, filepath.Join(destination, "extra.txt"))
+	})
+
+	t.Run("Refuses to copy into itself", func(t *testing.T) {
+		_, finalResult := runFileCopy(t, FileCopyParameters{Source: source, Destination: filepath.Join(source, "backup"), Recursive: true})
+		assert.Equal(t, StatusFailed, finalResult.Status)
+		assert.Equal(t, FailureValidationError, finalResult.FailureKind)
+		assert.Contains(t, finalResult.Error, "into itself")
+		assert.NoDirExists(t, filepath.Join(source, "backup"))
+	})
+}
+
+func TestFileCopyExecutor_Execute_Failures(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := NewFileCopyExecutor().Execute(context.Background(), NewFileCopyTask("copy-empty", "", FileCopyParameters{Destination: "b"}))
+	assert.ErrorContains(t, err, "source and destination are required")
+
+	_, finalResult := runFileCopy(t, FileCopyParameters{Source: filepath.Join(dir, "missing"), Destination: filepath.Join(dir, "b")})
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Contains(t, finalResult.Error, "does not exist")
+
+	source := filepath.Join(dir, "a.txt")
+	require.NoError(t, os.WriteFile(source, []byte("a"), 0644))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	resultsChan, err := NewFileCopyExecutor().Execute(ctx, NewFileCopyTask("copy-cancelled", "", FileCopyParameters{Source: source, Destination: filepath.Join(dir, "b.txt")}))
+	require.NoError(t, err)
+	finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Equal(t, msgFileCopyCancelled, finalResult.Message)
+	assert.NoFileExists(t, filepath.Join(dir, "b.txt"))
+}
+
+func TestFileCopyExecutor_Execute_OverwriteDoesNotFollowSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	outside := t.TempDir()
+	outsideFile := filepath.Join(outside, "victim.txt")
+	require.NoError(t, os.WriteFile(outsideFile, []byte("untouched"), 0644))
+	outsideDir := filepath.Join(outside, "victim-dir")
+	require.NoError(t, os.Mkdir(outsideDir, 0755))
+
+	source := filepath.Join(dir, "src")
+	require.NoError(t, os.MkdirAll(filepath.Join(source, "sub"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(source, "f"), []byte("new"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(source, "sub", "g"), []byte("nested"), 0644))
+
+	destination := filepath.Join(dir, "dst")
+	require.NoError(t, os.Mkdir(destination, 0755))
+	require.NoError(t, os.Symlink(outsideFile, filepath.Join(destination, "f")))
+	require.NoError(t, os.Symlink(outsideDir, filepath.Join(destination, "sub")))
+
+	_, finalResult := runFileCopy(t, FileCopyParameters{Source: source, Destination: destination, Recursive: true, Overwrite: true})
+	require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+
+	content, err := os.ReadFile(outsideFile)
+	require.NoError(t, err)
+	assert.Equal(t, "untouched", string(content), "The copy must not write through a symlinked destination")
+	assert.NoFileExists(t, filepath.Join(outsideDir, "g"))
+
+	info, err := os.Lstat(filepath.Join(destination, "f"))
+	require.NoError(t, err)
+	assert.True(t, info.Mode().IsRegular(), "The symlink should be replaced by the copied file")
+	content, err = os.ReadFile(filepath.Join(destination, "f"))
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(content))
+	info, err = os.Lstat(filepath.Join(destination, "sub"))
+	require.NoError(t, err)
+	assert.True(t, info.IsDir())
+	assert.FileExists(t, filepath.Join(destination, "sub", "g"))
+}
+
+func TestFileCopyExecutor_Execute_Filters(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "project")
+	for name, content := range map[string]string{
+		"main.go":                   "package main",
+		"README.md":                 "# project",
+		"internal/util.go":          "package internal",
+		"internal/util_test.go":     "package internal",
+		"internal/data.json":        "{}",
+		"node_modules/lib/index.js": "module.exports = {}",
+		"node_modules/lib/gen.go":   "package lib",
+		".git/HEAD":                 "ref: refs/heads/main",
+	} {
+		path := filepath.Join(source, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	// copiedFiles lists the files below root as slash-separated relative paths
+	copiedFiles := func(t *testing.T, root string) []string {
+		var files []string
+		require.NoError(t, filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			rel, err := filepath.Rel(root, path)
+			files = append(files, filepath.ToSlash(rel))
+			return err
+		}))
+		sort.Strings(files)
+		return files
+	}
+
+	t.Run("Exclude directories", func(t *testing.T) {
+		destination := filepath.Join(dir, "excluded")
+		_, finalResult := runFileCopy(t, FileCopyParameters{Source: source, Destination: destination, Recursive: true, Exclude: []string{"node_modules", ".git"}})
+		require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+		assert.Contains(t, finalResult.Message, "Copied 5 files")
+		assert.Equal(t, []string{"README.md", "internal/data.json", "internal/util.go", "internal/util_test.go", "main.go"}, copiedFiles(t, destination))
+		assert.NoDirExists(t, filepath.Join(destination, "node_modules"))
+	})
+
+	t.Run("Include only Go files", func(t *testing.T) {
+		destination := filepath.Join(dir, "go-only")
+		_, finalResult := runFileCopy(t, FileCopyParameters{Source: source, Destination: destination, Recursive: true, Include: []string{"*.go"}, Exclude: []string{"node_modules", "internal/*_test.go"}})
+		require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+		assert.Equal(t, []string{"internal/util.go", "main.go"}, copiedFiles(t, destination))
+	})
+
+	t.Run("Invalid pattern", func(t *testing.T) {
+		_, err := NewFileCopyExecutor().Execute(context.Background(), NewFileCopyTask("copy-glob", "", FileCopyParameters{Source: source, Destination: filepath.Join(dir, "bad"), Recursive: true, Include: []string{"["}}))
+		assert.ErrorContains(t, err, "invalid glob pattern '['")
+		assert.Equal(t, FailureValidationError, failureKind(err))
+	})
+}
@@ -0,0 +1,210 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"syscall"
+)
+
+// Error constants for FileMoveExecutor
+const (
+	// Command validation errors
+	errFileMoveInvalidCommandType = "invalid command type for FileMoveExecutor: %T"
+	errFileMoveMissingPath        = "source and destination are required"
+	errFileMoveSamePath           = "source and destination are the same path '%s'"
+
+	// File operation errors
+	errFileMoveResolveSource      = "failed to resolve source path: %w"
+	errFileMoveResolveDestination = "failed to resolve destination path: %w"
+	errFileMoveSourceMissing      = "source '%s' does not exist"
+	errFileMoveStatFailed         = "failed to stat '%s': %w"
+	errFileMoveDestinationExists  = "destination '%s' already exists and overwrite=false"
+	errFileMoveCreateDirFailed    = "failed to create directory '%s': %w"
+	errFileMoveRenameFailed       = "failed to move '%s' to '%s': %w"
+	errFileMoveCopyFailed         = "failed to copy '%s' across devices: %w"
+	errFileMoveRemoveFailed       = "copied '%s' to '%s' but failed to remove the source: %w"
+
+	// Status messages
+	msgFileMoveCancelled    = "Move cancelled."
+	msgFileMoveTimedOut     = "Move timed out."
+	msgFileMoveFailed       = "Move failed: %v"
+	msgFileMoveSucceeded    = "Moved '%s' to '%s'."
+	msgFileMoveCopiedAcross = "Moved '%s' to '%s' by copying across devices."
+)
+
+// FileMoveExecutor handles the execution of FileMoveTask.
+// It renames a file or directory, copying it when the destination is on another device.
+type FileMoveExecutor struct {
+	config ExecutorConfig
+	// rename is os.Rename; tests replace it to simulate moves across devices
+	rename func(oldpath, newpath string) error
+}
+
+var _ TaskExecutor = (*FileMoveExecutor)(nil)
+
+// NewFileMoveExecutor creates a new FileMoveExecutor.
+func NewFileMoveExecutor() *FileMoveExecutor {
+	return NewFileMoveExecutorWithConfig(ExecutorConfig{})
+}
+
+// NewFileMoveExecutorWithConfig creates a new FileMoveExecutor using the shared executor config.
+func NewFileMoveExecutorWithConfig(cfg ExecutorConfig) *FileMoveExecutor {
+	return &FileMoveExecutor{config: cfg, rename: os.Rename}
+}
+
+// Execute implements the TaskExecutor interface for FileMoveTask.
+func (e *FileMoveExecutor) Execute(ctx context.Context, moveCmd *Task) (<-chan OutputResult, error) {
+	if moveCmd.Type != TaskFileMove {
+		return nil, fmt.Errorf(errFileMoveInvalidCommandType, moveCmd)
+	}
+
+	// Check if task is already in a terminal state
+	terminalChan, err := HandleTerminalTask(moveCmd.TaskId, moveCmd.Status, moveCmd.Output)
+	if err != nil || terminalChan != nil {
+		return terminalChan, err
+	}
+
+	if params := moveCmd.Parameters.(FileMoveParameters); params.Source == "" || params.Destination == "" {
+		return nil, invalidf(errFileMoveMissingPath)
+	}
+
+	results := make(chan OutputResult, 1)
+	go func() {
+		defer close(results)
+
+		ctx, cancel := e.config.withTimeout(ctx, moveCmd)
+		defer cancel()
+
+		startedAt := e.config.clock().Now()
+		moveCmd.Status = StatusRunning
+		message, err := e.move(ctx, moveCmd.Parameters.(FileMoveParameters))
+
+		finalResult := createFileMoveResult(moveCmd.TaskId, message, err)
+		moveCmd.Status = finalResult.Status
+		finalResult.setTimes(e.config.clock(), startedAt)
+		moveCmd.UpdateOutput(&finalResult)
+		e.config.send(ctx, results, finalResult)
+	}()
+
+	return results, nil
+}
+
+// move moves the file or directory described by params and returns a success message.
+func (e *FileMoveExecutor) move(ctx context.Context, params FileMoveParameters) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	source, err := e.config.resolvePath(params.Source, params.WorkingDirectory)
+	if err != nil {
+		return "", fmt.Errorf(errFileMoveResolveSource, err)
+	}
+	destination, err := e.config.resolvePath(params.Destination, params.WorkingDirectory)
+	if err != nil {
+		return "", fmt.Errorf(errFileMoveResolveDestination, err)
+	}
+	if filepath.Clean(source) == filepath.Clean(destination) {
+		return "", invalidf(errFileMoveSamePath, source)
+	}
+
+	// Lock in path order so that opposite moves between the same paths cannot deadlock
+	lockPaths := []string{filepath.Clean(source), filepath.Clean(destination)}
+	sort.Strings(lockPaths)
+	for _, path := range lockPaths {
+		unlock := e.config.fileLocks().Lock(path)
+		defer unlock()
+	}
+
+	if _, err := os.Lstat(source); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf(errFileMoveSourceMissing, source)
+		}
+		return "", fmt.Errorf(errFileMoveStatFailed, source, err)
+	}
+	if _, err := os.Lstat(destination); err == nil {
+		if !params.Overwrite {
+			return "", fmt.Errorf(errFileMoveDestinationExists, destination)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf(errFileMoveStatFailed, destination, err)
+	}
+
+	if dir := filepath.Dir(destination); dir != "" {
+		if err := os.MkdirAll(dir, e.config.dirMode()); err != nil {
+			return "", fmt.Errorf(errFileMoveCreateDirFailed, dir, err)
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	err = e.rename(source, destination)
+	if err == nil {
+		return fmt.Sprintf(msgFileMoveSucceeded, source, destination), nil
+	}
+	if !errors.Is(err, syscall.EXDEV) {
+		return "", fmt.Errorf(errFileMoveRenameFailed, source, destination, err)
+	}
+
+	if err := e.moveAcrossDevices(ctx, source, destination); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(msgFileMoveCopiedAcross, source, destination), nil
+}
+
+// moveAcrossDevices copies source next to destination, renames the copy into place
+// and then removes source. A failed copy leaves both source and destination untouched.
+func (e *FileMoveExecutor) moveAcrossDevices(ctx context.Context, source, destination string) error {
+	staging, err := os.MkdirTemp(filepath.Dir(destination), ".move-*")
+	if err != nil {
+		return fmt.Errorf(errFileMoveCopyFailed, source, err)
+	}
+	defer os.RemoveAll(staging)
+
+	staged := filepath.Join(staging, filepath.Base(destination))
+	if err := (treeCopier{}).copy(ctx, source, staged); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf(errFileMoveCopyFailed, source, err)
+	}
+	if err := os.Rename(staged, destination); err != nil {
+		return fmt.Errorf(errFileMoveRenameFailed, source, destination, err)
+	}
+	if err := os.RemoveAll(source); err != nil {
+		return fmt.Errorf(errFileMoveRemoveFailed, source, destination, err)
+	}
+	return nil
+}
+
+// createFileMoveResult constructs the final OutputResult for a FileMoveTask.
+func createFileMoveResult(taskID, message string, err error) OutputResult {
+	if err == nil {
+		return OutputResult{
+			TaskID:  taskID,
+			Status:  StatusSucceeded,
+			Message: message,
+		}
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		message = msgFileMoveCancelled
+	case errors.Is(err, context.DeadlineExceeded):
+		message = msgFileMoveTimedOut
+	default:
+		message = fmt.Sprintf(msgFileMoveFailed, err)
+	}
+	return OutputResult{
+		TaskID:      taskID,
+		Status:      StatusFailed,
+		Message:     message,
+		Error:       err.Error(),
+		FailureKind: failureKind(err),
+	}
+}
@@ -0,0 +1,164 @@
+package task
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runFileMove(t *testing.T, executor *FileMoveExecutor, params FileMoveParameters) OutputResult {
+	t.Helper()
+	cmd := NewFileMoveTask("move", "Move a file", params)
+	resultsChan, err := executor.Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+	return finalResult
+}
+
+// crossDeviceRename fails every rename like a move between filesystems does.
+func crossDeviceRename(oldpath, newpath string) error {
+	return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: syscall.EXDEV}
+}
+
+func TestFileMoveExecutor_Execute_Renames(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "app.tar")
+	require.NoError(t, os.WriteFile(source, []byte("artifact"), 0644))
+
+	finalResult := runFileMove(t, NewFileMoveExecutor(), FileMoveParameters{
+		BaseParameters: BaseParameters{WorkingDirectory: dir},
+		Source:         "app.tar",
+		Destination:    "dist/release/app.tar",
+	})
+	require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+	assert.Contains(t, finalResult.Message, "Moved")
+
+	assert.NoFileExists(t, source)
+	content, err := os.ReadFile(filepath.Join(dir, "dist", "release", "app.tar"))
+	require.NoError(t, err)
+	assert.Equal(t, "artifact", string(content))
+}
+
+func TestFileMoveExecutor_Execute_Overwrite(t *testing.T) {
+	setup := func(t *testing.T) (string, string) {
+		dir := t.TempDir()
+		source := filepath.Join(dir, "new.txt")
+		destination := filepath.Join(dir, "current.txt")
+		require.NoError(t, os.WriteFile(source, []byte("new"), 0644))
+		require.NoError(t, os.WriteFile(destination, []byte("current"), 0644))
+		return source, destination
+	}
+
+	t.Run("Refused by default", func(t *testing.T) {
+		source, destination := setup(t)
+		finalResult := runFileMove(t, NewFileMoveExecutor(), FileMoveParameters{Source: source, Destination: destination})
+		assert.Equal(t, StatusFailed, finalResult.Status)
+		assert.Contains(t, finalResult.Error, "already exists and overwrite=false")
+
+		assert.FileExists(t, source)
+		content, err := os.ReadFile(destination)
+		require.NoError(t, err)
+		assert.Equal(t, "current", string(content))
+	})
+
+	t.Run("Replaces when allowed", func(t *testing.T) {
+		source, destination := setup(t)
+		finalResult := runFileMove(t, NewFileMoveExecutor(), FileMoveParameters{Source: source, Destination: destination, Overwrite: true})
+		require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+
+		assert.NoFileExists(t, source)
+		content, err := os.ReadFile(destination)
+		require.NoError(t, err)
+		assert.Equal(t, "new", string(content))
+	})
+}
+
+func TestFileMoveExecutor_Execute_AcrossDevices(t *testing.T) {
+	t.Run("File", func(t *testing.T) {
+		dir := t.TempDir()
+		source := filepath.Join(dir, "run.sh")
+		destination := filepath.Join(dir, "bin", "run.sh")
+		require.NoError(t, os.WriteFile(source, []byte("#!/bin/sh\n"), 0755))
+
+		executor := NewFileMoveExecutor()
+		executor.rename = crossDeviceRename
+		finalResult := runFileMove(t, executor, FileMoveParameters{Source: source, Destination: destination})
+		require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+		assert.Contains(t, finalResult.Message, "copying across devices")
+
+		assert.NoFileExists(t, source)
+		info, err := os.Stat(destination)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+		content, err := os.ReadFile(destination)
+		require.NoError(t, err)
+		assert.Equal(t, "#!/bin/sh\n", string(content))
+
+		// The staging directory is cleaned up
+		entries, err := os.ReadDir(filepath.Dir(destination))
+		require.NoError(t, err)
+		assert.Len(t, entries, 1)
+	})
+
+	t.Run("Directory", func(t *testing.T) {
+		dir := t.TempDir()
+		source := filepath.Join(dir, "build")
+		destination := filepath.Join(dir, "out", "build")
+		require.NoError(t, os.MkdirAll(filepath.Join(source, "nested"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(source, "a.txt"), []byte("a"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(source, "nested", "b.txt"), []byte("b"), 0600))
+		require.NoError(t, os.Symlink("a.txt", filepath.Join(source, "link")))
+
+		executor := NewFileMoveExecutor()
+		executor.rename = crossDeviceRename
+		finalResult := runFileMove(t, executor, FileMoveParameters{Source: source, Destination: destination})
+		require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+
+		assert.NoDirExists(t, source)
+		content, err := os.ReadFile(filepath.Join(destination, "nested", "b.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, "b", string(content))
+		info, err := os.Stat(filepath.Join(destination, "nested", "b.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+		target, err := os.Readlink(filepath.Join(destination, "link"))
+		require.NoError(t, err)
+		assert.Equal(t, "a.txt", target)
+	})
+}
+
+func TestFileMoveExecutor_Execute_Failures(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := NewFileMoveExecutor().Execute(context.Background(), NewFileMoveTask("move-empty", "", FileMoveParameters{Source: "a"}))
+	assert.ErrorContains(t, err, "source and destination are required")
+
+	finalResult := runFileMove(t, NewFileMoveExecutor(), FileMoveParameters{Source: filepath.Join(dir, "missing"), Destination: filepath.Join(dir, "b")})
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Contains(t, finalResult.Error, "does not exist")
+
+	same := filepath.Join(dir, "same.txt")
+	require.NoError(t, os.WriteFile(same, []byte("x"), 0644))
+	finalResult = runFileMove(t, NewFileMoveExecutor(), FileMoveParameters{Source: same, Destination: same, Overwrite: true})
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Equal(t, FailureValidationError, finalResult.FailureKind)
+	assert.FileExists(t, same)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	resultsChan, err := NewFileMoveExecutor().Execute(ctx, NewFileMoveTask("move-cancelled", "", FileMoveParameters{Source: same, Destination: filepath.Join(dir, "moved.txt")}))
+	require.NoError(t, err)
+	finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Equal(t, msgFileMoveCancelled, finalResult.Message)
+	assert.FileExists(t, same)
+}
@@ -2,13 +2,18 @@ package task
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"math"
 	"os"
+	"strings"
+	"syscall"
 	"time"
-
-	"ai-agent-v3/internal/task/fileutils"
 )
 
 const (
@@ -17,27 +22,69 @@ const (
 	errInvalidStartLine   = "invalid start line: %d (must be >= 0)"
 	errInvalidEndLine     = "invalid end line: %d (must be >= 0)"
 	errInvalidLineRange   = "invalid line range: start line %d is after end line %d"
+	errInvalidStartByte   = "invalid start byte: %d (must be >= 0)"
+	errSeekFailed         = "failed to seek to byte %d: %w"
+	errInvalidHeadBytes   = "invalid head bytes: %d (must be >= 0)"
+	errHeadBytesWithLines = "head_bytes cannot be combined with start_line or end_line"
+	errInvalidEncoding    = "unsupported encoding '%s' (supported: base64)"
+	errEncodingWithLines  = "encoding '%s' cannot be combined with start_line or end_line"
+	errIncrementalOptions = "incremental cannot be combined with start_line, end_line, head_bytes or encoding"
+	errInvalidMaxLines    = "invalid max lines: %d (must be >= 0)"
+	errMaxLinesOptions    = "max_lines cannot be combined with head_bytes or encoding"
+	errMarkersOptions     = "start_marker and end_marker cannot be combined with start_line, end_line, head_bytes, incremental or max_lines"
+	errInvalidMissingEnd  = "unsupported on_missing_end_marker '%s' (supported: error, eof)"
+	errInvalidExpandTabs  = "invalid expand tabs: %d (must be >= 0)"
+	errWhitespaceOptions  = "trim_trailing_whitespace and expand_tabs cannot be combined with head_bytes, encoding, start_marker or end_marker"
+	errStartMarkerMissing = "start marker %q not found"
+	errEndMarkerMissing   = "end marker %q not found"
+	errReadFailed         = "error reading file: %w"
 	errFileOpenFailed     = "failed to open file '%s': %w"
+	errPathIsDirectory    = "path '%s' is a directory, use LIST_DIRECTORY"
 	errFileTooShort       = "file has fewer lines than start line %d"
 	errScanFailed         = "error scanning file: %w"
-
+	errNoWriter           = "no writer opened '%s': %w"
+	errAutoDetectOptions  = "auto_detect_encoding cannot be combined with start_byte, head_bytes, encoding, incremental, start_marker or end_marker"
+	errInvalidLinesChunk  = "invalid lines per chunk: %d (must be >= 0)"
+	errLinesChunkOptions  = "lines_per_chunk cannot be combined with head_bytes, encoding, start_marker or end_marker"
+	errInvalidOutput      = "unsupported output format '%s' (supported: lines-json)"
+	errOutputOptions      = "output_format '%s' cannot be combined with head_bytes, encoding, start_marker, end_marker or lines_per_chunk"
 	// Status messages
 	msgReadingCancelled = "File reading cancelled."
 	msgReadingTimedOut  = "File reading timed out."
 	msgReadingFailed    = "File reading failed: %v"
 	msgReadingSucceeded = "File reading finished successfully in %v."
+	msgReadingTruncated = " Output truncated at %d bytes."
+	msgReadingCapped    = " Output capped at %d lines."
+	msgReadingRestarted = " File is shorter than the baseline of %d bytes, read from the beginning."
+	msgReadingCharset   = " Detected charset: %s."
+	msgReadingNoCharset = " Charset could not be detected, returned raw bytes."
+
+	// headChunkSize is the largest chunk streamed at a time when reading HeadBytes.
+	headChunkSize = 32 * 1024
+	// base64ChunkSize is the number of bytes encoded at a time. It is a multiple of three
+	// so that the encoded chunks concatenate to a single valid base64 string.
+	base64ChunkSize = 48 * 1024
+	// initialLineBufferSize is the scanner's starting buffer; it grows as needed for longer lines.
+	initialLineBufferSize = 64 * 1024
 )
 
 // FileReadExecutor handles the execution of FileReadCommand.
 type FileReadExecutor struct {
-	// Dependencies for reading files can be added here.
+	config ExecutorConfig
 }
 
+var _ TaskExecutor = (*FileReadExecutor)(nil)
+
 // NewFileReadExecutor creates a new FileReadExecutor.
 func NewFileReadExecutor() *FileReadExecutor {
 	return &FileReadExecutor{}
 }
 
+// NewFileReadExecutorWithConfig creates a new FileReadExecutor using the shared executor config.
+func NewFileReadExecutorWithConfig(cfg ExecutorConfig) *FileReadExecutor {
+	return &FileReadExecutor{config: cfg}
+}
+
 // Execute reads the file specified in the FileReadCommand, streaming its content.
 // It expects the cmd argument to be of type *FileReadTask.
 // Returns a channel for results and an error if the command type is wrong or execution setup fails.
@@ -62,21 +109,60 @@ func (e *FileReadExecutor) Execute(ctx context.Context, fileReadCmd *Task) (<-ch
 func (e *FileReadExecutor) executeFileRead(ctx context.Context, cmd *Task, results chan<- OutputResult) {
 	defer close(results)
 
+	ctx, cancel := e.config.withTimeout(ctx, cmd)
+	defer cancel()
+
 	// Update task status to Running
 	cmd.Status = StatusRunning
 
-	startTime := time.Now()
+	startTime := e.config.clock().Now()
 	var finalErr error
+	budget := newOutputBudget(e.config.MaxOutputBytes)
+	params := cmd.Parameters.(FileReadParameters)
+	offset := params.StartByte
+	restarted := false
+	capped := false
+	charset := ""
+	// Lines collected for FileReadOutputLinesJSON instead of being streamed
+	var lines *[]string
+	if params.OutputFormat == FileReadOutputLinesJSON {
+		lines = &[]string{}
+	}
 
 	defer func() {
-		finalResult := e.createFinalResult(cmd, startTime, finalErr)
+		finalResult := e.createFinalResult(ctx, cmd, startTime, finalErr)
+		if finalErr == nil && lines != nil {
+			encoded, _ := json.Marshal(*lines)
+			finalResult.ResultData = string(encoded)
+		}
+		if finalErr == nil && restarted {
+			finalResult.Message += fmt.Sprintf(msgReadingRestarted, params.StartByte)
+		}
+		if finalErr == nil && capped {
+			finalResult.Truncated = true
+			finalResult.Message += fmt.Sprintf(msgReadingCapped, params.MaxLines)
+		}
+		if finalErr == nil && budget.truncated() {
+			finalResult.Truncated = true
+			finalResult.Message += fmt.Sprintf(msgReadingTruncated, e.config.MaxOutputBytes)
+		}
+		if finalErr == nil && params.AutoDetectEncoding {
+			if charset != "" {
+				finalResult.Message += fmt.Sprintf(msgReadingCharset, charset)
+			} else {
+				finalResult.Message += msgReadingNoCharset
+			}
+		}
+		// Report how far into the file we got so an interrupted read can be resumed
+		finalResult.OffsetReached = offset
 
 		// Update the task status and output
 		cmd.Status = finalResult.Status
+		finalResult.setTimes(e.config.clock(), startTime)
 		cmd.UpdateOutput(&finalResult)
 
 		// Send the result
-		results <- finalResult
+		e.config.send(ctx, results, finalResult)
 	}()
 
 	if err := ctx.Err(); err != nil {
@@ -84,121 +170,584 @@ func (e *FileReadExecutor) executeFileRead(ctx context.Context, cmd *Task, resul
 		return
 	}
 
-	if err := validateLineNumbers(cmd.Parameters.(FileReadParameters)); err != nil {
+	if err := validateLineNumbers(params); err != nil {
 		finalErr = fmt.Errorf("line number validation failed: %w", err)
 		return
 	}
+	if params.StartByte < 0 {
+		finalErr = invalidf(errInvalidStartByte, params.StartByte)
+		return
+	}
+	if params.HeadBytes < 0 {
+		finalErr = invalidf(errInvalidHeadBytes, params.HeadBytes)
+		return
+	}
+	if params.HeadBytes > 0 && (params.StartLine > 0 || params.EndLine > 0) {
+		finalErr = invalidf(errHeadBytesWithLines)
+		return
+	}
+	if params.Encoding != FileReadEncodingText && params.Encoding != FileReadEncodingBase64 {
+		finalErr = invalidf(errInvalidEncoding, params.Encoding)
+		return
+	}
+	if params.Encoding == FileReadEncodingBase64 && (params.StartLine > 0 || params.EndLine > 0) {
+		finalErr = invalidf(errEncodingWithLines, params.Encoding)
+		return
+	}
+	if params.Incremental && (params.StartLine > 0 || params.EndLine > 0 || params.HeadBytes > 0 || params.Encoding != FileReadEncodingText) {
+		finalErr = invalidf(errIncrementalOptions)
+		return
+	}
+	if params.MaxLines < 0 {
+		finalErr = invalidf(errInvalidMaxLines, params.MaxLines)
+		return
+	}
+	if params.MaxLines > 0 && (params.HeadBytes > 0 || params.Encoding != FileReadEncodingText) {
+		finalErr = invalidf(errMaxLinesOptions)
+		return
+	}
+
+	markers := params.StartMarker != "" || params.EndMarker != ""
+	if markers && (params.StartLine > 0 || params.EndLine > 0 || params.HeadBytes > 0 || params.Incremental || params.MaxLines > 0) {
+		finalErr = invalidf(errMarkersOptions)
+		return
+	}
+	switch params.OnMissingEndMarker {
+	case "", FileReadMissingEndMarkerError, FileReadMissingEndMarkerEOF:
+	default:
+		finalErr = invalidf(errInvalidMissingEnd, params.OnMissingEndMarker)
+		return
+	}
+	if params.ExpandTabs < 0 {
+		finalErr = invalidf(errInvalidExpandTabs, params.ExpandTabs)
+		return
+	}
+	if params.LinesPerChunk < 0 {
+		finalErr = invalidf(errInvalidLinesChunk, params.LinesPerChunk)
+		return
+	}
+	if params.LinesPerChunk > 0 && (markers || params.HeadBytes > 0 || params.Encoding != FileReadEncodingText) {
+		finalErr = invalidf(errLinesChunkOptions)
+		return
+	}
+	if params.OutputFormat != FileReadOutputText && params.OutputFormat != FileReadOutputLinesJSON {
+		finalErr = invalidf(errInvalidOutput, params.OutputFormat)
+		return
+	}
+	if params.OutputFormat != FileReadOutputText && (markers || params.HeadBytes > 0 || params.Encoding != FileReadEncodingText || params.LinesPerChunk > 0) {
+		finalErr = invalidf(errOutputOptions, params.OutputFormat)
+		return
+	}
+	if (params.TrimTrailingWhitespace || params.ExpandTabs > 0) && (markers || params.HeadBytes > 0 || params.Encoding != FileReadEncodingText) {
+		finalErr = invalidf(errWhitespaceOptions)
+		return
+	}
+	if params.AutoDetectEncoding && (params.StartByte > 0 || params.HeadBytes > 0 || params.Encoding != FileReadEncodingText || params.Incremental || markers) {
+		finalErr = invalidf(errAutoDetectOptions)
+		return
+	}
 
 	// Resolve the file path
-	absPath, err := fileutils.ResolveFilePath(cmd.Parameters.(FileReadParameters).FilePath, cmd.Parameters.(FileReadParameters).WorkingDirectory)
+	absPath, err := e.config.resolvePath(cmd.Parameters.(FileReadParameters).FilePath, cmd.Parameters.(FileReadParameters).WorkingDirectory)
 	if err != nil {
 		finalErr = fmt.Errorf("file path resolution failed: %w", err)
 		return
 	}
 
-	file, err := os.Open(absPath)
+	// Reject directories up front; opening one succeeds but reading it fails obscurely
+	if info, statErr := os.Stat(absPath); statErr == nil && info.IsDir() {
+		finalErr = fmt.Errorf(errPathIsDirectory, absPath)
+		return
+	}
+
+	file, stopInterrupt, err := openForReading(ctx, absPath)
 	if err != nil {
-		finalErr = fmt.Errorf(errFileOpenFailed, absPath, err)
+		finalErr = err
 		return
 	}
 	defer file.Close()
+	defer stopInterrupt()
+
+	// A file that shrank below the baseline was truncated or replaced, so none of it was seen yet
+	if params.Incremental && params.StartByte > 0 {
+		info, err := file.Stat()
+		if err != nil {
+			finalErr = fmt.Errorf(errFileOpenFailed, absPath, err)
+			return
+		}
+		if info.Size() < params.StartByte {
+			restarted = true
+			offset = 0
+		}
+	}
+
+	if offset > 0 {
+		if _, err := file.Seek(offset, io.SeekStart); err != nil {
+			finalErr = fmt.Errorf(errSeekFailed, offset, err)
+			return
+		}
+	}
+
+	if markers {
+		if err := e.streamBetweenMarkers(ctx, cmd, file, params, results, budget, &offset); err != nil {
+			finalErr = fmt.Errorf("file reading failed: %w", err)
+		}
+		return
+	}
 
-	if err := e.readAndStreamFile(ctx, cmd, file, results); err != nil {
+	if params.HeadBytes > 0 || params.Encoding == FileReadEncodingBase64 {
+		encode := params.Encoding == FileReadEncodingBase64
+		if err := e.streamBytes(ctx, cmd, file, params.HeadBytes, encode, results, budget, &offset); err != nil {
+			finalErr = fmt.Errorf("file reading failed: %w", err)
+		}
+		return
+	}
+
+	if params.AutoDetectEncoding {
+		// The charset is detected from the start of the content, which is then decoded as
+		// it is read. Decoded lines do not map onto file offsets, so the offset is left unreported
+		decoded, detected, err := detectCharset(file)
+		if err != nil {
+			finalErr = fmt.Errorf(errReadFailed, err)
+			return
+		}
+		charset = detected
+		var decodedOffset int64
+		if capped, err = e.readAndStreamFile(ctx, cmd, decoded, results, budget, &decodedOffset, lines); err != nil {
+			finalErr = fmt.Errorf("file reading failed: %w", err)
+		}
+		return
+	}
+
+	if capped, err = e.readAndStreamFile(ctx, cmd, file, results, budget, &offset, lines); err != nil {
 		finalErr = fmt.Errorf("file reading failed: %w", err)
 	}
 }
 
+// openForReading opens path for reading. Named pipes and other non-regular files may
+// block until a writer appears and then wait for data, so for those the open gives up
+// and reads fail once ctx is done, instead of ignoring the task's deadline.
+// The returned function stops interrupting reads and must be called once reading ends.
+func openForReading(ctx context.Context, path string) (*os.File, func(), error) {
+	if info, err := os.Stat(path); err != nil || info.Mode().IsRegular() {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf(errFileOpenFailed, path, err)
+		}
+		return file, func() {}, nil
+	}
+
+	type openResult struct {
+		file *os.File
+		err  error
+	}
+	opened := make(chan openResult, 1)
+	go func() {
+		file, err := os.Open(path)
+		opened <- openResult{file, err}
+	}()
+
+	var file *os.File
+	select {
+	case r := <-opened:
+		if r.err != nil {
+			return nil, nil, fmt.Errorf(errFileOpenFailed, path, r.err)
+		}
+		file = r.file
+	case <-ctx.Done():
+		// Briefly become the writer so the pending open of a named pipe can return
+		if writer, err := os.OpenFile(path, os.O_WRONLY|syscall.O_NONBLOCK, 0); err == nil {
+			writer.Close()
+		}
+		if r := <-opened; r.file != nil {
+			r.file.Close()
+		}
+		return nil, nil, fmt.Errorf(errNoWriter, path, ctx.Err())
+	}
+
+	// Interrupt a read that is waiting for data when ctx is done. Files that cannot be
+	// polled, such as most devices, do not support deadlines and are read as before.
+	stop := context.AfterFunc(ctx, func() { file.SetReadDeadline(time.Now()) })
+	return file, func() { stop() }, nil
+}
+
+// streamBytes streams the content of r without line processing, so it is delivered
+// exactly as stored, stopping after n bytes when n is greater than zero.
+// With encode set each chunk is base64-encoded; an encoded chunk cut short by the
+// output budget is trimmed to whole base64 quanta so the output stays decodable.
+// offset is advanced by the number of file bytes sent.
+func (e *FileReadExecutor) streamBytes(ctx context.Context, cmd *Task, r io.Reader, n int64, encode bool, results chan<- OutputResult, budget *outputBudget, offset *int64) error {
+	chunkSize := int64(headChunkSize)
+	if encode {
+		chunkSize = base64ChunkSize
+	}
+	if n > 0 {
+		r = io.LimitReader(r, n)
+		chunkSize = min(n, chunkSize)
+	}
+	buf := make([]byte, chunkSize)
+	for {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("context error during reading: %w", err)
+		}
+
+		// Full chunks keep every encoded chunk except the last free of padding
+		read, err := io.ReadFull(r, buf)
+		if read > 0 {
+			data := string(buf[:read])
+			if encode {
+				data = base64.StdEncoding.EncodeToString(buf[:read])
+			}
+			sent, ok := budget.take(data)
+			if encode && len(sent) < len(data) {
+				sent = sent[:len(sent)/4*4]
+			}
+			if !ok || sent == "" {
+				return nil
+			}
+			if !e.config.send(ctx, results, cmd.describe(OutputResult{
+				TaskID:     cmd.TaskId,
+				Status:     StatusRunning,
+				ResultData: sent,
+			})) {
+				return fmt.Errorf("context error during reading: %w", ctx.Err())
+			}
+			switch {
+			case len(sent) == len(data):
+				*offset += int64(read)
+			case encode:
+				*offset += int64(len(sent) / 4 * 3)
+			default:
+				*offset += int64(len(sent))
+			}
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf(errReadFailed, err)
+		}
+	}
+}
+
+// streamBetweenMarkers streams the content of r between params.StartMarker and
+// params.EndMarker, as streamBytes does. offset is advanced past the start marker
+// before any content is sent.
+func (e *FileReadExecutor) streamBetweenMarkers(ctx context.Context, cmd *Task, r io.Reader, params FileReadParameters, results chan<- OutputResult, budget *outputBudget, offset *int64) error {
+	br := bufio.NewReaderSize(r, headChunkSize)
+	if params.StartMarker != "" {
+		skipped, err := skipPastMarker(ctx, br, []byte(params.StartMarker))
+		*offset += skipped
+		if err != nil {
+			return err
+		}
+	}
+
+	var content io.Reader = br
+	if params.EndMarker != "" {
+		content = &markerLimitedReader{
+			r:          br,
+			marker:     []byte(params.EndMarker),
+			allowEOF:   params.OnMissingEndMarker == FileReadMissingEndMarkerEOF,
+			missingErr: fmt.Errorf(errEndMarkerMissing, params.EndMarker),
+		}
+	}
+	return e.streamBytes(ctx, cmd, content, 0, params.Encoding == FileReadEncodingBase64, results, budget, offset)
+}
+
+// skipPastMarker consumes r up to and including the first occurrence of marker
+// and returns the number of bytes consumed.
+func skipPastMarker(ctx context.Context, r *bufio.Reader, marker []byte) (int64, error) {
+	var consumed int64
+	matched := 0
+	for {
+		if matched == 0 {
+			if err := ctx.Err(); err != nil {
+				return consumed, fmt.Errorf("context error during reading: %w", err)
+			}
+		}
+		b, err := r.ReadByte()
+		if errors.Is(err, io.EOF) {
+			return consumed, fmt.Errorf(errStartMarkerMissing, marker)
+		}
+		if err != nil {
+			return consumed, fmt.Errorf(errReadFailed, err)
+		}
+		consumed++
+
+		// Fall back to the longest marker prefix that is still a suffix of the input
+		for matched > 0 && b != marker[matched] {
+			matched = markerFallback(marker, matched)
+		}
+		if b == marker[matched] {
+			matched++
+		}
+		if matched == len(marker) {
+			return consumed, nil
+		}
+	}
+}
+
+// markerFallback returns the length of the longest proper prefix of marker[:matched]
+// that is also a suffix of it.
+func markerFallback(marker []byte, matched int) int {
+	for n := matched - 1; n > 0; n-- {
+		if bytes.Equal(marker[:n], marker[matched-n:matched]) {
+			return n
+		}
+	}
+	return 0
+}
+
+// markerLimitedReader reads from r until the first occurrence of marker, which is
+// not returned. At the end of r without the marker it returns missingErr, or io.EOF
+// when allowEOF is set.
+type markerLimitedReader struct {
+	r          io.Reader
+	marker     []byte
+	allowEOF   bool
+	missingErr error
+
+	pending []byte // Read but not yet returned; may hold the start of the marker
+	done    bool
+	eof     bool
+}
+
+func (m *markerLimitedReader) Read(p []byte) (int, error) {
+	for {
+		if i := bytes.Index(m.pending, m.marker); i >= 0 {
+			m.pending = m.pending[:i]
+			m.done = true
+		}
+
+		// Everything except a possible partial marker at the end can be returned
+		ready := len(m.pending)
+		if !m.done && !m.eof {
+			ready = max(0, len(m.pending)-(len(m.marker)-1))
+		}
+		if ready > 0 {
+			n := copy(p, m.pending[:ready])
+			m.pending = m.pending[n:]
+			return n, nil
+		}
+
+		switch {
+		case m.done:
+			return 0, io.EOF
+		case m.eof && m.allowEOF:
+			return 0, io.EOF
+		case m.eof:
+			return 0, m.missingErr
+		}
+
+		chunk := make([]byte, headChunkSize)
+		n, err := m.r.Read(chunk)
+		m.pending = append(m.pending, chunk[:n]...)
+		if errors.Is(err, io.EOF) {
+			m.eof = true
+		} else if err != nil {
+			return 0, err
+		}
+	}
+}
+
 // validateLineNumbers checks if the line number parameters are valid.
 func validateLineNumbers(params FileReadParameters) error {
 	if params.StartLine < 0 {
-		return fmt.Errorf(errInvalidStartLine, params.StartLine)
+		return invalidf(errInvalidStartLine, params.StartLine)
 	}
 	if params.EndLine < 0 {
-		return fmt.Errorf(errInvalidEndLine, params.EndLine)
+		return invalidf(errInvalidEndLine, params.EndLine)
 	}
 	if params.StartLine > 0 && params.EndLine > 0 && params.StartLine > params.EndLine {
-		return fmt.Errorf(errInvalidLineRange, params.StartLine, params.EndLine)
+		return invalidf(errInvalidLineRange, params.StartLine, params.EndLine)
 	}
 	return nil
 }
 
-// readAndStreamFile reads the file and streams its content to the results channel.
-func (e *FileReadExecutor) readAndStreamFile(ctx context.Context, cmd *Task, file *os.File, results chan<- OutputResult) error {
+// lineCounter wraps bufio.ScanLines and records how many raw bytes the last token
+// consumed, including the line terminator that the scanner strips, and whether
+// that token was a final line without a newline.
+type lineCounter struct {
+	lastAdvance  int
+	unterminated bool
+}
+
+func (c *lineCounter) split(data []byte, atEOF bool) (int, []byte, error) {
+	advance, token, err := bufio.ScanLines(data, atEOF)
+	if token != nil {
+		c.lastAdvance = advance
+		c.unterminated = atEOF && advance == len(data) && !bytes.HasSuffix(data, []byte("\n"))
+	}
+	return advance, token, err
+}
+
+// readAndStreamFile reads the file, or its decoded content, and streams it to the results channel
+// in chunks of LinesPerChunk lines. Reading stops early once the output budget is exhausted or MaxLines lines were sent;
+// capped reports whether lines remained when the MaxLines limit stopped the read.
+// offset is advanced by the number of file bytes consumed, so that it always points
+// just past the last line that was skipped or sent. When lines is not nil the lines are
+// appended to it, without their terminators, instead of being sent.
+func (e *FileReadExecutor) readAndStreamFile(ctx context.Context, cmd *Task, file io.Reader, results chan<- OutputResult, budget *outputBudget, offset *int64, lines *[]string) (capped bool, err error) {
 	scanner := bufio.NewScanner(file)
+	// Let the buffer grow without limit so single huge lines (e.g. minified files) can be read
+	scanner.Buffer(make([]byte, 0, initialLineBufferSize), math.MaxInt)
+	counter := &lineCounter{}
+	scanner.Split(counter.split)
 	currentLine := 1
+	linesSent := 0
+	transform := whitespaceTransform(cmd.Parameters.(FileReadParameters))
+
+	// Lines are collected into a chunk and offset only moves past them once it was sent
+	linesPerChunk := max(cmd.Parameters.(FileReadParameters).LinesPerChunk, 1)
+	var chunk strings.Builder
+	var chunkLines int
+	var chunkAdvance int64
+	flush := func() bool {
+		if chunkLines == 0 {
+			return true
+		}
+		sent := true
+		if lines != nil {
+			// Lines hold no newlines, and only the last one of a chunk can lack its terminator
+			*lines = append(*lines, strings.Split(strings.TrimSuffix(chunk.String(), "\n"), "\n")...)
+		} else {
+			sent = e.config.send(ctx, results, cmd.describe(OutputResult{
+				TaskID:     cmd.TaskId,
+				Status:     StatusRunning,
+				ResultData: chunk.String(),
+			}))
+		}
+		if sent {
+			*offset += chunkAdvance
+		}
+		chunk.Reset()
+		chunkLines, chunkAdvance = 0, 0
+		return sent
+	}
 
 	// Skip to start line
 	for currentLine < cmd.Parameters.(FileReadParameters).StartLine && scanner.Scan() {
+		*offset += int64(counter.lastAdvance)
 		currentLine++
 	}
 
 	if currentLine < cmd.Parameters.(FileReadParameters).StartLine {
-		return fmt.Errorf(errFileTooShort, cmd.Parameters.(FileReadParameters).StartLine)
+		return false, fmt.Errorf(errFileTooShort, cmd.Parameters.(FileReadParameters).StartLine)
 	}
 
 	// Read and stream lines
 	for {
 		if err := ctx.Err(); err != nil {
-			return fmt.Errorf("context error during reading: %w", err)
+			return false, fmt.Errorf("context error during reading: %w", err)
 		}
 
 		if !scanner.Scan() {
 			break
 		}
 
-		line := scanner.Text() + "\n"
+		// An incomplete last line may still be being written; leave it for the next read
+		if cmd.Parameters.(FileReadParameters).Incremental && counter.unterminated {
+			break
+		}
+
+		line := scanner.Text()
+		if transform != nil {
+			line = transform(line)
+		}
+		line += "\n"
 
 		if cmd.Parameters.(FileReadParameters).EndLine > 0 && currentLine > cmd.Parameters.(FileReadParameters).EndLine {
 			break
 		}
+		if maxLines := cmd.Parameters.(FileReadParameters).MaxLines; maxLines > 0 && linesSent == maxLines {
+			if !flush() {
+				return false, ctx.Err()
+			}
+			return true, nil
+		}
 
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-			results <- OutputResult{
-				TaskID:     cmd.TaskId,
-				Status:     StatusRunning,
-				ResultData: line,
+		fullLength := len(line)
+		line, ok := budget.take(line)
+		if !ok || line == "" {
+			break
+		}
+
+		chunk.WriteString(line)
+		chunkLines++
+		if len(line) < fullLength {
+			// A transformed line does not map byte for byte onto the file, so a
+			// partially sent one is read again in full when the read is resumed
+			if transform == nil {
+				chunkAdvance += int64(len(line))
 			}
+		} else {
+			chunkAdvance += int64(counter.lastAdvance)
 		}
 
+		linesSent++
 		currentLine++
+
+		if chunkLines == linesPerChunk || len(line) < fullLength {
+			if err := ctx.Err(); err != nil {
+				return false, err
+			}
+			if !flush() {
+				return false, ctx.Err()
+			}
+		}
 	}
 
+	if !flush() {
+		return false, ctx.Err()
+	}
 	if err := scanner.Err(); err != nil {
-		return fmt.Errorf(errScanFailed, err)
+		return false, fmt.Errorf(errScanFailed, err)
 	}
 
-	return nil
+	return false, nil
 }
 
 // createFinalResult creates the final OutputResult with appropriate status and message.
-func (e *FileReadExecutor) createFinalResult(cmd *Task, startTime time.Time, finalErr error) OutputResult {
+// Once ctx is done, its error decides between cancellation and timeout, whatever
+// error the interrupted read returned.
+func (e *FileReadExecutor) createFinalResult(ctx context.Context, cmd *Task, startTime time.Time, finalErr error) OutputResult {
 	var status TaskStatus
 	var message string
 	var errMsg string
+	var failure FailureKind
 
 	if finalErr != nil {
+		cause := finalErr
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			if !errors.Is(finalErr, ctxErr) {
+				finalErr = fmt.Errorf("%w: %w", ctxErr, finalErr)
+			}
+			cause = ctxErr
+		}
+
 		status = StatusFailed
 		errMsg = finalErr.Error()
+		failure = failureKind(cause)
 		switch {
-		case errors.Is(finalErr, context.Canceled):
+		case errors.Is(cause, context.Canceled):
 			message = msgReadingCancelled
-		case errors.Is(finalErr, context.DeadlineExceeded):
+		case errors.Is(cause, context.DeadlineExceeded):
 			message = msgReadingTimedOut
 		default:
 			message = fmt.Sprintf(msgReadingFailed, finalErr)
 		}
 	} else {
 		status = StatusSucceeded
-		message = fmt.Sprintf(msgReadingSucceeded, time.Since(startTime).Round(time.Millisecond))
+		message = fmt.Sprintf(msgReadingSucceeded, e.config.since(startTime).Round(time.Millisecond))
 	}
 
 	return OutputResult{
-		TaskID:  cmd.TaskId,
-		Status:  status,
-		Message: message,
-		Error:   errMsg,
+		TaskID:      cmd.TaskId,
+		Status:      status,
+		Message:     message,
+		Error:       errMsg,
+		Err:         finalErr,
+		FailureKind: failure,
 	}
 }
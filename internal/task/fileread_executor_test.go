@@ -2,11 +2,16 @@ package task
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
+	"unicode/utf16"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -413,6 +418,74 @@ func TestFileReadExecutor_LineBasedReading(t *testing.T) {
 	}
 }
 
+// TestFileReadExecutor_Execute_DeadlineVersusCancel verifies that an interrupted read of a
+// large file reports a timeout and a cancellation as distinct failure kinds.
+func TestFileReadExecutor_Execute_DeadlineVersusCancel(t *testing.T) {
+	testCases := []struct {
+		name            string
+		config          ExecutorConfig
+		parentTimeout   time.Duration
+		cancelParent    bool
+		expectedKind    FailureKind
+		expectedErr     error
+		expectedMessage string
+	}{
+		{
+			name:            "Parent cancelled",
+			cancelParent:    true,
+			expectedKind:    FailureCancelled,
+			expectedErr:     context.Canceled,
+			expectedMessage: "File reading cancelled.",
+		},
+		{
+			name:            "Parent deadline",
+			parentTimeout:   50 * time.Millisecond,
+			expectedKind:    FailureTimedOut,
+			expectedErr:     context.DeadlineExceeded,
+			expectedMessage: "File reading timed out.",
+		},
+		{
+			name:            "Default timeout",
+			config:          ExecutorConfig{DefaultTimeout: 50 * time.Millisecond},
+			expectedKind:    FailureTimedOut,
+			expectedErr:     context.DeadlineExceeded,
+			expectedMessage: "File reading timed out.",
+		},
+	}
+
+	tempFilePath, _ := createLargeTempFile(t, 1024*1024)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			if tc.parentTimeout > 0 {
+				ctx, cancel = context.WithTimeout(context.Background(), tc.parentTimeout)
+			}
+			defer cancel()
+
+			cmd := NewFileReadTask("read-interrupted", "Interrupted read", FileReadParameters{FilePath: tempFilePath})
+			resultsChan, err := NewFileReadExecutorWithConfig(tc.config).Execute(ctx, cmd)
+			require.NoError(t, err)
+
+			// Stall the reader after the first line so the read is interrupted mid-file
+			first := <-resultsChan
+			require.Equal(t, StatusRunning, first.Status)
+			if tc.cancelParent {
+				cancel()
+			} else {
+				time.Sleep(150 * time.Millisecond)
+			}
+
+			finalResult, _, received := collectStreamingResults_FileRead(t, resultsChan, 5*time.Second)
+			require.True(t, received, "Did not receive final result")
+			assert.Equal(t, StatusFailed, finalResult.Status)
+			assert.Equal(t, tc.expectedKind, finalResult.FailureKind)
+			assert.ErrorIs(t, finalResult.Err, tc.expectedErr)
+			assert.Equal(t, tc.expectedMessage, finalResult.Message)
+			assert.Equal(t, tc.expectedKind, cmd.Output.FailureKind)
+		})
+	}
+}
+
 func TestFileReadExecutor_ContextCancellation_FinalStatus(t *testing.T) {
 	// Create a large test file to ensure reading takes some time
 	fileSize := 50 * 1024 // 50KB
@@ -585,3 +658,825 @@ func TestFileReadExecutor_Execute_TerminalTaskHandling(t *testing.T) {
 		})
 	}
 }
+
+func TestFileReadExecutor_ResumeFromOffsetAfterCancel(t *testing.T) {
+	var content strings.Builder
+	for i := 0; i < 5000; i++ {
+		fmt.Fprintf(&content, "line %d with some padding text\r\n", i)
+	}
+	filePath := createTempFile(t, content.String())
+	executor := NewFileReadExecutor()
+
+	// Start reading and cancel after a few chunks have been received
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cmd := NewFileReadTask("read-resume-1", "Interrupted read", FileReadParameters{FilePath: filePath})
+	resultsChan, err := executor.Execute(ctx, cmd)
+	require.NoError(t, err)
+
+	var firstPart strings.Builder
+	var finalResult OutputResult
+	received := 0
+	for result := range resultsChan {
+		if result.Status == StatusRunning {
+			firstPart.WriteString(result.ResultData)
+			received++
+			if received == 10 {
+				cancel()
+			}
+			continue
+		}
+		finalResult = result
+	}
+
+	require.Equal(t, StatusFailed, finalResult.Status, "Read should have been cancelled")
+	require.Greater(t, finalResult.OffsetReached, int64(0))
+	require.Less(t, finalResult.OffsetReached, int64(content.Len()), "Read should not have completed")
+	assert.Equal(t, strings.Count(firstPart.String(), "\n"), strings.Count(content.String()[:finalResult.OffsetReached], "\n"),
+		"Offset should point just past the last line received")
+
+	// Resume from the reported offset
+	resumeCmd := NewFileReadTask("read-resume-2", "Resumed read", FileReadParameters{
+		FilePath:  filePath,
+		StartByte: finalResult.OffsetReached,
+	})
+	resultsChan, err = executor.Execute(context.Background(), resumeCmd)
+	require.NoError(t, err)
+	resumeResult, secondPart, ok := collectStreamingResults_FileRead(t, resultsChan, 5*time.Second)
+	require.True(t, ok)
+	require.Equal(t, StatusSucceeded, resumeResult.Status, resumeResult.Error)
+	assert.Equal(t, int64(content.Len()), resumeResult.OffsetReached)
+
+	// The scanner normalizes CRLF to LF, so compare against the normalized file content
+	expected := strings.ReplaceAll(content.String(), "\r\n", "\n")
+	assert.Equal(t, expected, firstPart.String()+secondPart, "Resumed read should complete the file exactly")
+}
+
+func TestFileReadExecutor_InvalidStartByte(t *testing.T) {
+	filePath := createTempFile(t, "data\n")
+	cmd := NewFileReadTask("read-bad-offset", "Negative offset", FileReadParameters{FilePath: filePath, StartByte: -1})
+
+	resultsChan, err := NewFileReadExecutor().Execute(context.Background(), cmd)
+	require.NoError(t, err)
+	finalResult, _, ok := collectStreamingResults_FileRead(t, resultsChan, 5*time.Second)
+	require.True(t, ok)
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Contains(t, finalResult.Error, "invalid start byte")
+}
+
+func TestFileReadExecutor_DirectoryPath(t *testing.T) {
+	dirPath := t.TempDir()
+	cmd := NewFileReadTask("read-dir", "Read a directory", FileReadParameters{FilePath: dirPath})
+
+	resultsChan, err := NewFileReadExecutor().Execute(context.Background(), cmd)
+	require.NoError(t, err)
+	finalResult, _, ok := collectStreamingResults_FileRead(t, resultsChan, 5*time.Second)
+	require.True(t, ok)
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Contains(t, finalResult.Error, "is a directory, use LIST_DIRECTORY")
+	assert.Equal(t, StatusFailed, cmd.Status)
+}
+
+func TestFileReadExecutor_HeadBytes(t *testing.T) {
+	var content strings.Builder
+	for i := 0; content.Len() < 3*headChunkSize; i++ {
+		fmt.Fprintf(&content, "line %d of a large file\n", i)
+	}
+	filePath := createTempFile(t, content.String())
+
+	testCases := []struct {
+		name      string
+		headBytes int64
+		expected  string
+	}{
+		{name: "First100Bytes", headBytes: 100, expected: content.String()[:100]},
+		{name: "LargerThanFile", headBytes: int64(content.Len()) + 1000, expected: content.String()},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := NewFileReadTask("read-head-"+tc.name, "Read head", FileReadParameters{
+				FilePath:  filePath,
+				HeadBytes: tc.headBytes,
+			})
+
+			resultsChan, err := NewFileReadExecutor().Execute(context.Background(), cmd)
+			require.NoError(t, err)
+			finalResult, data, ok := collectStreamingResults_FileRead(t, resultsChan, 5*time.Second)
+			require.True(t, ok)
+			require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+			assert.Equal(t, tc.expected, data)
+			assert.Equal(t, int64(len(tc.expected)), finalResult.OffsetReached)
+		})
+	}
+}
+
+func TestFileReadExecutor_HeadBytesWithLineRange(t *testing.T) {
+	filePath := createTempFile(t, "a\nb\n")
+	cmd := NewFileReadTask("read-head-lines", "Head with lines", FileReadParameters{
+		FilePath:  filePath,
+		HeadBytes: 1,
+		StartLine: 2,
+	})
+
+	resultsChan, err := NewFileReadExecutor().Execute(context.Background(), cmd)
+	require.NoError(t, err)
+	finalResult, _, ok := collectStreamingResults_FileRead(t, resultsChan, 5*time.Second)
+	require.True(t, ok)
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Contains(t, finalResult.Error, "head_bytes cannot be combined")
+}
+
+func TestFileReadExecutor_Timestamps(t *testing.T) {
+	testCases := []struct {
+		name           string
+		filePath       string
+		expectedStatus TaskStatus
+	}{
+		{name: "Success", filePath: createTempFile(t, "content\n"), expectedStatus: StatusSucceeded},
+		{name: "Failure", filePath: filepath.Join(t.TempDir(), "missing.txt"), expectedStatus: StatusFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			before := time.Now()
+			cmd := NewFileReadTask("read-times-"+tc.name, "Timestamps", FileReadParameters{FilePath: tc.filePath})
+
+			resultsChan, err := NewFileReadExecutor().Execute(context.Background(), cmd)
+			require.NoError(t, err)
+			finalResult, _, ok := collectStreamingResults_FileRead(t, resultsChan, 5*time.Second)
+			require.True(t, ok)
+			after := time.Now()
+
+			assert.Equal(t, tc.expectedStatus, finalResult.Status)
+			require.False(t, finalResult.StartedAt.IsZero(), "StartedAt should be set")
+			require.False(t, finalResult.FinishedAt.IsZero(), "FinishedAt should be set")
+			assert.False(t, finalResult.StartedAt.Before(before), "StartedAt should not precede the call")
+			assert.False(t, finalResult.FinishedAt.Before(finalResult.StartedAt), "FinishedAt should not precede StartedAt")
+			assert.False(t, finalResult.FinishedAt.After(after), "FinishedAt should not follow the final result")
+			assert.Equal(t, finalResult.FinishedAt.Sub(finalResult.StartedAt), finalResult.Duration())
+			assert.Equal(t, finalResult.StartedAt, cmd.Output.StartedAt, "Task output should carry the timestamps")
+		})
+	}
+}
+
+func TestFileReadExecutor_VeryLongLine(t *testing.T) {
+	longLine := strings.Repeat("abcdefghij", 300*1024) // 3MB without a newline
+	filePath := createTempFile(t, "first\n"+longLine+"\nlast\n")
+
+	cmd := NewFileReadTask("read-long-line", "Read a huge line", FileReadParameters{FilePath: filePath, StartLine: 2, EndLine: 2})
+	resultsChan, err := NewFileReadExecutor().Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	finalResult, data, ok := collectStreamingResults_FileRead(t, resultsChan, 10*time.Second)
+	require.True(t, ok)
+	require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+	assert.Equal(t, len(longLine)+1, len(data))
+	assert.Equal(t, longLine+"\n", data)
+}
+
+func TestFileReadExecutor_Base64Encoding(t *testing.T) {
+	// Control characters, NUL bytes, invalid UTF-8 and CRLF line endings, spanning several chunks
+	var raw []byte
+	for i := 0; len(raw) < 2*base64ChunkSize+7; i++ {
+		raw = append(raw, byte(i), 0x00, 0x1b, 0xff, '\r', '\n')
+	}
+	filePath := filepath.Join(t.TempDir(), "binary.bin")
+	require.NoError(t, os.WriteFile(filePath, raw, 0644))
+
+	testCases := []struct {
+		name           string
+		params         FileReadParameters
+		maxOutputBytes int64
+		expected       []byte
+	}{
+		{name: "WholeFile", params: FileReadParameters{FilePath: filePath, Encoding: FileReadEncodingBase64}, expected: raw},
+		{name: "HeadBytes", params: FileReadParameters{FilePath: filePath, Encoding: FileReadEncodingBase64, HeadBytes: 10}, expected: raw[:10]},
+		{name: "OutputLimit", params: FileReadParameters{FilePath: filePath, Encoding: FileReadEncodingBase64}, maxOutputBytes: 102, expected: raw[:75]},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			executor := NewFileReadExecutorWithConfig(ExecutorConfig{MaxOutputBytes: tc.maxOutputBytes})
+			cmd := NewFileReadTask("read-base64-"+tc.name, "Read as base64", tc.params)
+
+			resultsChan, err := executor.Execute(context.Background(), cmd)
+			require.NoError(t, err)
+			finalResult, data, ok := collectStreamingResults_FileRead(t, resultsChan, 5*time.Second)
+			require.True(t, ok)
+			require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+
+			decoded, err := base64.StdEncoding.DecodeString(data)
+			require.NoError(t, err, "Streamed chunks should concatenate to valid base64")
+			assert.Equal(t, tc.expected, decoded)
+			assert.Equal(t, int64(len(tc.expected)), finalResult.OffsetReached)
+		})
+	}
+}
+
+func TestFileReadExecutor_InvalidEncoding(t *testing.T) {
+	filePath := createTempFile(t, "a\nb\n")
+
+	testCases := []struct {
+		name          string
+		params        FileReadParameters
+		errorContains string
+	}{
+		{name: "Unknown", params: FileReadParameters{FilePath: filePath, Encoding: "hex"}, errorContains: "unsupported encoding 'hex'"},
+		{name: "WithLineRange", params: FileReadParameters{FilePath: filePath, Encoding: FileReadEncodingBase64, StartLine: 2}, errorContains: "cannot be combined with start_line"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := NewFileReadTask("read-encoding-"+tc.name, "Invalid encoding", tc.params)
+			resultsChan, err := NewFileReadExecutor().Execute(context.Background(), cmd)
+			require.NoError(t, err)
+			finalResult, _, ok := collectStreamingResults_FileRead(t, resultsChan, 5*time.Second)
+			require.True(t, ok)
+			assert.Equal(t, StatusFailed, finalResult.Status)
+			assert.Contains(t, finalResult.Error, tc.errorContains)
+		})
+	}
+}
+
+func TestFileReadExecutor_Incremental(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "app.log")
+	require.NoError(t, os.WriteFile(filePath, []byte("first\nsecond\n"), 0644))
+
+	readSince := func(baseline int64) (OutputResult, string) {
+		t.Helper()
+		cmd := NewFileReadTask("read-incremental", "Read appended lines", FileReadParameters{
+			FilePath:    filePath,
+			StartByte:   baseline,
+			Incremental: true,
+		})
+		resultsChan, err := NewFileReadExecutor().Execute(context.Background(), cmd)
+		require.NoError(t, err)
+		finalResult, data, ok := collectStreamingResults_FileRead(t, resultsChan, 5*time.Second)
+		require.True(t, ok)
+		require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+		return finalResult, data
+	}
+
+	finalResult, data := readSince(0)
+	assert.Equal(t, "first\nsecond\n", data)
+	baseline := finalResult.OffsetReached
+	assert.Equal(t, int64(len("first\nsecond\n")), baseline)
+
+	// Nothing new since the baseline
+	finalResult, data = readSince(baseline)
+	assert.Empty(t, data)
+	assert.Equal(t, baseline, finalResult.OffsetReached)
+
+	// Only the appended delta is returned; the half-written line waits for the next read
+	f, err := os.OpenFile(filePath, os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = f.WriteString("third\nfour")
+	require.NoError(t, err)
+	finalResult, data = readSince(baseline)
+	assert.Equal(t, "third\n", data)
+	baseline = finalResult.OffsetReached
+
+	_, err = f.WriteString("th\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	finalResult, data = readSince(baseline)
+	assert.Equal(t, "fourth\n", data)
+	baseline = finalResult.OffsetReached
+
+	// A file that was truncated below the baseline is read again from the start
+	require.NoError(t, os.WriteFile(filePath, []byte("rotated\n"), 0644))
+	finalResult, data = readSince(baseline)
+	assert.Equal(t, "rotated\n", data)
+	assert.Equal(t, int64(len("rotated\n")), finalResult.OffsetReached)
+	assert.Contains(t, finalResult.Message, "read from the beginning")
+}
+
+func TestFileReadExecutor_IncrementalInvalidOptions(t *testing.T) {
+	filePath := createTempFile(t, "a\nb\n")
+	cmd := NewFileReadTask("read-incremental-invalid", "Incremental with line range", FileReadParameters{
+		FilePath:    filePath,
+		StartLine:   2,
+		Incremental: true,
+	})
+	resultsChan, err := NewFileReadExecutor().Execute(context.Background(), cmd)
+	require.NoError(t, err)
+	finalResult, _, ok := collectStreamingResults_FileRead(t, resultsChan, 5*time.Second)
+	require.True(t, ok)
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Contains(t, finalResult.Error, "incremental cannot be combined")
+}
+
+func TestFileReadExecutor_MaxLines(t *testing.T) {
+	filePath := createTempFile(t, "line1\nline2\nline3\nline4\nline5\n")
+
+	testCases := []struct {
+		name      string
+		params    FileReadParameters
+		expected  string
+		truncated bool
+	}{
+		{name: "CapsLargerFile", params: FileReadParameters{FilePath: filePath, MaxLines: 2}, expected: "line1\nline2\n", truncated: true},
+		{name: "SmallerFileNotTruncated", params: FileReadParameters{FilePath: filePath, MaxLines: 10}, expected: "line1\nline2\nline3\nline4\nline5\n"},
+		{name: "ExactFitNotTruncated", params: FileReadParameters{FilePath: filePath, MaxLines: 5}, expected: "line1\nline2\nline3\nline4\nline5\n"},
+		{name: "WithStartLine", params: FileReadParameters{FilePath: filePath, StartLine: 3, MaxLines: 2}, expected: "line3\nline4\n", truncated: true},
+		{name: "EndLineFirst", params: FileReadParameters{FilePath: filePath, StartLine: 2, EndLine: 3, MaxLines: 2}, expected: "line2\nline3\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := NewFileReadTask("read-max-lines-"+tc.name, "Read with a line cap", tc.params)
+			resultsChan, err := NewFileReadExecutor().Execute(context.Background(), cmd)
+			require.NoError(t, err)
+			finalResult, data, ok := collectStreamingResults_FileRead(t, resultsChan, 5*time.Second)
+			require.True(t, ok)
+			require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+
+			assert.Equal(t, tc.expected, data)
+			assert.Equal(t, tc.truncated, finalResult.Truncated)
+			if tc.truncated {
+				assert.Contains(t, finalResult.Message, fmt.Sprintf("Output capped at %d lines.", tc.params.MaxLines))
+				// The offset points at the first line that was not returned
+				assert.Equal(t, int64(strings.Index("line1\nline2\nline3\nline4\nline5\n", tc.expected)+len(tc.expected)), finalResult.OffsetReached)
+			}
+		})
+	}
+}
+
+func TestFileReadExecutor_MaxLinesInvalid(t *testing.T) {
+	filePath := createTempFile(t, "a\nb\n")
+
+	testCases := []struct {
+		name          string
+		params        FileReadParameters
+		errorContains string
+	}{
+		{name: "Negative", params: FileReadParameters{FilePath: filePath, MaxLines: -1}, errorContains: "invalid max lines: -1"},
+		{name: "WithHeadBytes", params: FileReadParameters{FilePath: filePath, MaxLines: 1, HeadBytes: 10}, errorContains: "max_lines cannot be combined"},
+		{name: "WithBase64", params: FileReadParameters{FilePath: filePath, MaxLines: 1, Encoding: FileReadEncodingBase64}, errorContains: "max_lines cannot be combined"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := NewFileReadTask("read-max-lines-"+tc.name, "Invalid line cap", tc.params)
+			resultsChan, err := NewFileReadExecutor().Execute(context.Background(), cmd)
+			require.NoError(t, err)
+			finalResult, _, ok := collectStreamingResults_FileRead(t, resultsChan, 5*time.Second)
+			require.True(t, ok)
+			assert.Equal(t, StatusFailed, finalResult.Status)
+			assert.Equal(t, FailureValidationError, finalResult.FailureKind)
+			assert.Contains(t, finalResult.Error, tc.errorContains)
+		})
+	}
+}
+
+func TestFileReadExecutor_Markers(t *testing.T) {
+	pem := "header text\n-----BEGIN KEY-----\nMIIBVgIBADANBgkqhkiG9w0BAQEFAASC\nAUAwggE8AgEAAkEAq7BFUpkGp3+LQmlQ\n-----END KEY-----\ntrailer\n"
+	filePath := createTempFile(t, pem)
+
+	// A marker that repeats its own prefix must still be found after a false start
+	tricky := createTempFile(t, "a-a-a-b payload a-a-b tail")
+
+	// The end marker straddles a read chunk boundary
+	body := strings.Repeat("x", headChunkSize-3)
+	large := createTempFile(t, "<<"+body+">>rest")
+
+	testCases := []struct {
+		name           string
+		params         FileReadParameters
+		expectedOutput string
+		expectedOffset int64
+	}{
+		{
+			name:           "PEM block",
+			params:         FileReadParameters{FilePath: filePath, StartMarker: "-----BEGIN KEY-----", EndMarker: "-----END KEY-----"},
+			expectedOutput: "\nMIIBVgIBADANBgkqhkiG9w0BAQEFAASC\nAUAwggE8AgEAAkEAq7BFUpkGp3+LQmlQ\n",
+			expectedOffset: int64(strings.Index(pem, "-----END KEY-----")),
+		},
+		{
+			name:           "Start marker only",
+			params:         FileReadParameters{FilePath: filePath, StartMarker: "-----END KEY-----\n"},
+			expectedOutput: "trailer\n",
+			expectedOffset: int64(len(pem)),
+		},
+		{
+			name:           "End marker only",
+			params:         FileReadParameters{FilePath: filePath, EndMarker: "\n-----BEGIN"},
+			expectedOutput: "header text",
+			expectedOffset: int64(len("header text")),
+		},
+		{
+			name:           "Missing end marker read to EOF",
+			params:         FileReadParameters{FilePath: filePath, StartMarker: "-----END KEY-----", EndMarker: "-----BEGIN", OnMissingEndMarker: FileReadMissingEndMarkerEOF},
+			expectedOutput: "\ntrailer\n",
+			expectedOffset: int64(len(pem)),
+		},
+		{
+			name:           "Overlapping marker prefix",
+			params:         FileReadParameters{FilePath: tricky, StartMarker: "a-a-b", EndMarker: "a-a-b"},
+			expectedOutput: " payload ",
+			expectedOffset: int64(len("a-a-a-b payload ")),
+		},
+		{
+			name:           "Marker across chunks",
+			params:         FileReadParameters{FilePath: large, StartMarker: "<<", EndMarker: ">>"},
+			expectedOutput: body,
+			expectedOffset: int64(2 + len(body)),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := NewFileReadTask("read-markers", "Read between markers", tc.params)
+			resultsChan, err := NewFileReadExecutor().Execute(context.Background(), cmd)
+			require.NoError(t, err)
+
+			finalResult, output, ok := collectStreamingResults_FileRead(t, resultsChan, 5*time.Second)
+			require.True(t, ok)
+			require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+			assert.Equal(t, tc.expectedOutput, output)
+			assert.Equal(t, tc.expectedOffset, finalResult.OffsetReached)
+		})
+	}
+}
+
+func TestFileReadExecutor_MarkersMissing(t *testing.T) {
+	filePath := createTempFile(t, "-----BEGIN KEY-----\nMIIBVgIBADANBgkq\n")
+
+	testCases := []struct {
+		name          string
+		params        FileReadParameters
+		expectedKind  FailureKind
+		errorContains string
+	}{
+		{
+			name:          "Missing end marker",
+			params:        FileReadParameters{FilePath: filePath, StartMarker: "-----BEGIN KEY-----", EndMarker: "-----END KEY-----"},
+			expectedKind:  FailureExecutionError,
+			errorContains: `end marker "-----END KEY-----" not found`,
+		},
+		{
+			name:          "Missing start marker",
+			params:        FileReadParameters{FilePath: filePath, StartMarker: "-----BEGIN CERT-----", EndMarker: "-----END CERT-----"},
+			expectedKind:  FailureExecutionError,
+			errorContains: `start marker "-----BEGIN CERT-----" not found`,
+		},
+		{
+			name:          "With line range",
+			params:        FileReadParameters{FilePath: filePath, StartMarker: "-----BEGIN KEY-----", StartLine: 2},
+			expectedKind:  FailureValidationError,
+			errorContains: "start_marker and end_marker cannot be combined",
+		},
+		{
+			name:          "Unknown missing end behavior",
+			params:        FileReadParameters{FilePath: filePath, EndMarker: "x", OnMissingEndMarker: "ignore"},
+			expectedKind:  FailureValidationError,
+			errorContains: "unsupported on_missing_end_marker 'ignore'",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := NewFileReadTask("read-markers-missing", "Missing marker", tc.params)
+			resultsChan, err := NewFileReadExecutor().Execute(context.Background(), cmd)
+			require.NoError(t, err)
+
+			finalResult, _, ok := collectStreamingResults_FileRead(t, resultsChan, 5*time.Second)
+			require.True(t, ok)
+			assert.Equal(t, StatusFailed, finalResult.Status)
+			assert.Equal(t, tc.expectedKind, finalResult.FailureKind)
+			assert.Contains(t, finalResult.Error, tc.errorContains)
+		})
+	}
+}
+
+func TestFileReadExecutor_Whitespace(t *testing.T) {
+	content := "func main() {  \n\tif x {\t\n\t\treturn\n\t}\n}\t \nab\tc\n  keep  leading\n"
+	filePath := createTempFile(t, content)
+
+	testCases := []struct {
+		name           string
+		params         FileReadParameters
+		expectedOutput string
+	}{
+		{
+			name:           "Unchanged by default",
+			params:         FileReadParameters{FilePath: filePath},
+			expectedOutput: content,
+		},
+		{
+			name:           "Trim trailing whitespace",
+			params:         FileReadParameters{FilePath: filePath, TrimTrailingWhitespace: true},
+			expectedOutput: "func main() {\n\tif x {\n\t\treturn\n\t}\n}\nab\tc\n  keep  leading\n",
+		},
+		{
+			name:           "Expand tabs to four columns",
+			params:         FileReadParameters{FilePath: filePath, ExpandTabs: 4},
+			expectedOutput: "func main() {  \n    if x {  \n        return\n    }\n}    \nab  c\n  keep  leading\n",
+		},
+		{
+			name:           "Expand tabs to two columns and trim",
+			params:         FileReadParameters{FilePath: filePath, ExpandTabs: 2, TrimTrailingWhitespace: true},
+			expectedOutput: "func main() {\n  if x {\n    return\n  }\n}\nab  c\n  keep  leading\n",
+		},
+		{
+			name:           "Applied within a line range",
+			params:         FileReadParameters{FilePath: filePath, StartLine: 2, EndLine: 3, ExpandTabs: 8, TrimTrailingWhitespace: true},
+			expectedOutput: "        if x {\n                return\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := NewFileReadTask("read-whitespace", "Normalize whitespace", tc.params)
+			resultsChan, err := NewFileReadExecutor().Execute(context.Background(), cmd)
+			require.NoError(t, err)
+
+			finalResult, output, ok := collectStreamingResults_FileRead(t, resultsChan, 5*time.Second)
+			require.True(t, ok)
+			require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+			assert.Equal(t, tc.expectedOutput, output)
+		})
+	}
+
+	invalid := []FileReadParameters{
+		{FilePath: filePath, ExpandTabs: -1},
+		{FilePath: filePath, TrimTrailingWhitespace: true, HeadBytes: 10},
+		{FilePath: filePath, ExpandTabs: 4, Encoding: FileReadEncodingBase64},
+		{FilePath: filePath, TrimTrailingWhitespace: true, StartMarker: "if"},
+	}
+	for _, params := range invalid {
+		cmd := NewFileReadTask("read-whitespace-invalid", "Invalid whitespace options", params)
+		resultsChan, err := NewFileReadExecutor().Execute(context.Background(), cmd)
+		require.NoError(t, err)
+
+		finalResult, _, ok := collectStreamingResults_FileRead(t, resultsChan, 5*time.Second)
+		require.True(t, ok)
+		assert.Equal(t, StatusFailed, finalResult.Status)
+		assert.Equal(t, FailureValidationError, finalResult.FailureKind, finalResult.Error)
+	}
+}
+
+func TestFileReadExecutor_NamedPipe(t *testing.T) {
+	readPipe := func(t *testing.T, fifoPath string, timeout time.Duration) (OutputResult, string, time.Duration) {
+		t.Helper()
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		cmd := NewFileReadTask("read-fifo", "Read a named pipe", FileReadParameters{FilePath: fifoPath})
+		start := time.Now()
+		resultsChan, err := NewFileReadExecutor().Execute(ctx, cmd)
+		require.NoError(t, err)
+		finalResult, output, received := collectStreamingResults_FileRead(t, resultsChan, 5*time.Second)
+		require.True(t, received, "Read of a named pipe should finish")
+		return finalResult, output, time.Since(start)
+	}
+
+	t.Run("NoWriter", func(t *testing.T) {
+		finalResult, _, elapsed := readPipe(t, makeFIFO(t), 100*time.Millisecond)
+		assert.Less(t, elapsed, 2*time.Second)
+		assert.Equal(t, StatusFailed, finalResult.Status)
+		assert.Equal(t, FailureTimedOut, finalResult.FailureKind)
+		assert.Equal(t, msgReadingTimedOut, finalResult.Message)
+		assert.Contains(t, finalResult.Error, "no writer opened")
+	})
+
+	t.Run("WriterNeverCloses", func(t *testing.T) {
+		fifoPath := makeFIFO(t)
+		release := make(chan struct{})
+		writerDone := make(chan struct{})
+		go func() {
+			defer close(writerDone)
+			writer, err := os.OpenFile(fifoPath, os.O_WRONLY, 0)
+			if err != nil {
+				return
+			}
+			defer writer.Close()
+			writer.WriteString("partial\n")
+			<-release
+		}()
+
+		finalResult, output, elapsed := readPipe(t, fifoPath, 200*time.Millisecond)
+		close(release)
+		assert.Less(t, elapsed, 2*time.Second, "The read should stop at the deadline while the writer is still open")
+		assert.Equal(t, StatusFailed, finalResult.Status)
+		assert.Equal(t, FailureTimedOut, finalResult.FailureKind)
+		assert.Equal(t, "partial\n", output)
+		<-writerDone
+	})
+
+	t.Run("WriterCloses", func(t *testing.T) {
+		fifoPath := makeFIFO(t)
+		go func() {
+			if writer, err := os.OpenFile(fifoPath, os.O_WRONLY, 0); err == nil {
+				writer.WriteString("one\ntwo\n")
+				writer.Close()
+			}
+		}()
+
+		finalResult, output, _ := readPipe(t, fifoPath, 5*time.Second)
+		require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+		assert.Equal(t, "one\ntwo\n", output)
+	})
+}
+
+func TestFileReadExecutor_AutoDetectEncoding(t *testing.T) {
+	utf16Of := func(s string, order binary.AppendByteOrder, bom bool) string {
+		var buf []byte
+		if bom {
+			buf = order.AppendUint16(buf, 0xFEFF)
+		}
+		for _, unit := range utf16.Encode([]rune(s)) {
+			buf = order.AppendUint16(buf, unit)
+		}
+		return string(buf)
+	}
+	const text = "héllo\nwörld\n"
+
+	testCases := []struct {
+		name            string
+		content         string
+		expectedCharset string
+		expectedOutput  string
+	}{
+		{name: "UTF-8", content: text, expectedCharset: "UTF-8", expectedOutput: text},
+		{name: "UTF-8 with BOM", content: "\xEF\xBB\xBF" + text, expectedCharset: "UTF-8", expectedOutput: text},
+		{name: "UTF-16LE with BOM", content: utf16Of(text, binary.LittleEndian, true), expectedCharset: "UTF-16LE", expectedOutput: text},
+		{name: "UTF-16BE without BOM", content: utf16Of(text, binary.BigEndian, false), expectedCharset: "UTF-16BE", expectedOutput: text},
+		{name: "Latin-1", content: "caf\xe9\nna\xefve\n", expectedCharset: "ISO-8859-1", expectedOutput: "café\nnaïve\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := createTempFile(t, tc.content)
+			cmd := NewFileReadTask("read-charset", "Read a file of unknown charset", FileReadParameters{FilePath: filePath, AutoDetectEncoding: true})
+			resultsChan, err := NewFileReadExecutor().Execute(context.Background(), cmd)
+			require.NoError(t, err)
+
+			finalResult, output, ok := collectStreamingResults_FileRead(t, resultsChan, 5*time.Second)
+			require.True(t, ok)
+			require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+			assert.Equal(t, tc.expectedOutput, output)
+			assert.Contains(t, finalResult.Message, "Detected charset: "+tc.expectedCharset+".")
+		})
+	}
+
+	t.Run("Lines of decoded content", func(t *testing.T) {
+		filePath := createTempFile(t, utf16Of("one\ntwo\nthree\n", binary.LittleEndian, true))
+		cmd := NewFileReadTask("read-charset-lines", "Read a line range", FileReadParameters{FilePath: filePath, StartLine: 2, EndLine: 2, AutoDetectEncoding: true})
+		resultsChan, err := NewFileReadExecutor().Execute(context.Background(), cmd)
+		require.NoError(t, err)
+
+		finalResult, output, ok := collectStreamingResults_FileRead(t, resultsChan, 5*time.Second)
+		require.True(t, ok)
+		require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+		assert.Equal(t, "two\n", output)
+	})
+
+	t.Run("Binary falls back to raw bytes", func(t *testing.T) {
+		content := "\x89PNG\x1a\x00\x00IHDR\x00\n"
+		filePath := createTempFile(t, content)
+		cmd := NewFileReadTask("read-charset-binary", "Read a binary file", FileReadParameters{FilePath: filePath, AutoDetectEncoding: true})
+		resultsChan, err := NewFileReadExecutor().Execute(context.Background(), cmd)
+		require.NoError(t, err)
+
+		finalResult, output, ok := collectStreamingResults_FileRead(t, resultsChan, 5*time.Second)
+		require.True(t, ok)
+		require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+		assert.Equal(t, content, output)
+		assert.Contains(t, finalResult.Message, "Charset could not be detected")
+	})
+
+	filePath := createTempFile(t, text)
+	invalid := []FileReadParameters{
+		{FilePath: filePath, AutoDetectEncoding: true, StartByte: 2},
+		{FilePath: filePath, AutoDetectEncoding: true, HeadBytes: 10},
+		{FilePath: filePath, AutoDetectEncoding: true, Encoding: FileReadEncodingBase64},
+		{FilePath: filePath, AutoDetectEncoding: true, Incremental: true},
+		{FilePath: filePath, AutoDetectEncoding: true, EndMarker: "w"},
+	}
+	for _, params := range invalid {
+		cmd := NewFileReadTask("read-charset-invalid", "Invalid charset options", params)
+		resultsChan, err := NewFileReadExecutor().Execute(context.Background(), cmd)
+		require.NoError(t, err)
+
+		finalResult, _, ok := collectStreamingResults_FileRead(t, resultsChan, 5*time.Second)
+		require.True(t, ok)
+		assert.Equal(t, StatusFailed, finalResult.Status)
+		assert.Equal(t, FailureValidationError, finalResult.FailureKind, finalResult.Error)
+	}
+}
+
+func TestFileReadExecutor_LinesPerChunk(t *testing.T) {
+	const content = "one\ntwo\nthree\nfour\nfive\n"
+	filePath := createTempFile(t, content)
+
+	readChunks := func(t *testing.T, params FileReadParameters) ([]string, OutputResult) {
+		t.Helper()
+		params.FilePath = filePath
+		resultsChan, err := NewFileReadExecutor().Execute(context.Background(), NewFileReadTask("read-chunks", "Read in chunks", params))
+		require.NoError(t, err)
+
+		var chunks []string
+		var finalResult OutputResult
+		for result := range resultsChan {
+			if result.Status == StatusRunning {
+				chunks = append(chunks, result.ResultData)
+			} else {
+				finalResult = result
+			}
+		}
+		require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+		return chunks, finalResult
+	}
+
+	t.Run("OneLinePerChunk", func(t *testing.T) {
+		chunks, _ := readChunks(t, FileReadParameters{LinesPerChunk: 1})
+		assert.Equal(t, []string{"one\n", "two\n", "three\n", "four\n", "five\n"}, chunks)
+		assert.Equal(t, content, strings.Join(chunks, ""))
+	})
+
+	t.Run("Default", func(t *testing.T) {
+		chunks, _ := readChunks(t, FileReadParameters{})
+		assert.Len(t, chunks, 5)
+	})
+
+	t.Run("SeveralLinesPerChunk", func(t *testing.T) {
+		chunks, _ := readChunks(t, FileReadParameters{LinesPerChunk: 2})
+		assert.Equal(t, []string{"one\ntwo\n", "three\nfour\n", "five\n"}, chunks)
+	})
+
+	t.Run("CappedByMaxLines", func(t *testing.T) {
+		chunks, finalResult := readChunks(t, FileReadParameters{LinesPerChunk: 2, MaxLines: 3})
+		assert.Equal(t, []string{"one\ntwo\n", "three\n"}, chunks)
+		assert.Equal(t, int64(len("one\ntwo\nthree\n")), finalResult.OffsetReached)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, params := range []FileReadParameters{
+			{FilePath: filePath, LinesPerChunk: -1},
+			{FilePath: filePath, LinesPerChunk: 2, HeadBytes: 10},
+		} {
+			resultsChan, err := NewFileReadExecutor().Execute(context.Background(), NewFileReadTask("read-chunks-invalid", "", params))
+			require.NoError(t, err)
+			finalResult, _, received := collectStreamingResults_FileRead(t, resultsChan, 5*time.Second)
+			require.True(t, received)
+			assert.Equal(t, StatusFailed, finalResult.Status)
+			assert.Equal(t, FailureValidationError, finalResult.FailureKind)
+		}
+	})
+}
+
+func TestFileReadExecutor_OutputFormatLinesJSON(t *testing.T) {
+	readLines := func(t *testing.T, params FileReadParameters) []string {
+		t.Helper()
+		resultsChan, err := NewFileReadExecutor().Execute(context.Background(), NewFileReadTask("read-lines-json", "Read as JSON lines", params))
+		require.NoError(t, err)
+
+		var finalResult OutputResult
+		for result := range resultsChan {
+			require.NotEqual(t, StatusRunning, result.Status, "lines-json should not stream")
+			finalResult = result
+		}
+		require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+
+		var lines []string
+		require.NoError(t, json.Unmarshal([]byte(finalResult.ResultData), &lines), finalResult.ResultData)
+		return lines
+	}
+
+	t.Run("MultiLine", func(t *testing.T) {
+		filePath := createTempFile(t, "one\n\n\"three\"\r\nfour")
+		lines := readLines(t, FileReadParameters{FilePath: filePath, OutputFormat: FileReadOutputLinesJSON})
+		assert.Len(t, lines, 4)
+		assert.Equal(t, []string{"one", "", "\"three\"", "four"}, lines)
+	})
+
+	t.Run("LineRange", func(t *testing.T) {
+		filePath := createTempFile(t, "one\ntwo\nthree\nfour\n")
+		lines := readLines(t, FileReadParameters{FilePath: filePath, StartLine: 2, EndLine: 3, OutputFormat: FileReadOutputLinesJSON})
+		assert.Equal(t, []string{"two", "three"}, lines)
+	})
+
+	t.Run("EmptyFile", func(t *testing.T) {
+		filePath := createTempFile(t, "")
+		lines := readLines(t, FileReadParameters{FilePath: filePath, OutputFormat: FileReadOutputLinesJSON})
+		assert.NotNil(t, lines)
+		assert.Empty(t, lines)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		filePath := createTempFile(t, "one\n")
+		for _, params := range []FileReadParameters{
+			{FilePath: filePath, OutputFormat: "yaml"},
+			{FilePath: filePath, OutputFormat: FileReadOutputLinesJSON, Encoding: FileReadEncodingBase64},
+			{FilePath: filePath, OutputFormat: FileReadOutputLinesJSON, LinesPerChunk: 2},
+		} {
+			resultsChan, err := NewFileReadExecutor().Execute(context.Background(), NewFileReadTask("read-lines-json-invalid", "", params))
+			require.NoError(t, err)
+			finalResult, _, received := collectStreamingResults_FileRead(t, resultsChan, 5*time.Second)
+			require.True(t, received)
+			assert.Equal(t, StatusFailed, finalResult.Status)
+			assert.Equal(t, FailureValidationError, finalResult.FailureKind)
+		}
+	})
+}
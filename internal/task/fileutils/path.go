@@ -2,7 +2,12 @@ package fileutils
 
 import (
 	"errors"
+	"fmt"
+	"io/fs"
+	"os"
 	"path/filepath"
+	"strings"
+	"syscall"
 )
 
 // ResolvePath takes a file path and working directory, and returns the absolute path.
@@ -29,3 +34,99 @@ func ResolveFilePath(filePath string, workingDir string) (string, error) {
 	}
 	return ResolvePath(filePath, workingDir), nil
 }
+
+// ErrPathOutsideRoot is returned when a resolved path escapes the configured root directory.
+var ErrPathOutsideRoot = errors.New("path is outside the root directory")
+
+// ResolvePathWithinRoot resolves a file path like ResolveFilePath and, when rootDir is
+// not empty, confines the result to rootDir.
+// Relative working directories and relative paths without a working directory are
+// resolved against rootDir. A path that resolves outside rootDir, either lexically or
+// by following the symbolic links along it, yields ErrPathOutsideRoot. The returned
+// path is not resolved, so a link can still be swapped after the check.
+func ResolvePathWithinRoot(filePath, workingDir, rootDir string) (string, error) {
+	if rootDir == "" {
+		return ResolveFilePath(filePath, workingDir)
+	}
+	if filePath == "" {
+		return "", errors.New("file path cannot be empty")
+	}
+
+	root, err := filepath.Abs(rootDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve root directory '%s': %w", rootDir, err)
+	}
+
+	baseDir := ResolvePath(workingDir, root)
+	if workingDir == "" {
+		baseDir = root
+	}
+	resolved := filepath.Clean(ResolvePath(filePath, baseDir))
+
+	if !within(root, resolved) {
+		return "", fmt.Errorf("%w: '%s' is not within '%s'", ErrPathOutsideRoot, resolved, root)
+	}
+
+	// A symbolic link inside the root may point outside it, so the check is repeated
+	// on the paths the links lead to
+	realRoot, err := realPath(root)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve root directory '%s': %w", rootDir, err)
+	}
+	realResolved, err := realPath(resolved)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve symbolic links in '%s': %w", resolved, err)
+	}
+	if !within(realRoot, realResolved) {
+		return "", fmt.Errorf("%w: '%s' leads to '%s', which is not within '%s'", ErrPathOutsideRoot, resolved, realResolved, root)
+	}
+	return resolved, nil
+}
+
+// within reports whether the clean absolute path is root or lies below it.
+func within(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// maxSymlinkHops bounds the number of dangling symbolic links realPath follows.
+const maxSymlinkHops = 40
+
+// realPath resolves the symbolic links in the longest existing prefix of the absolute
+// path and appends the components that do not exist yet. A dangling symbolic link is
+// followed to its target, as creating a file through it would.
+func realPath(path string) (string, error) {
+	var missing []string
+	hops := 0
+	for p := path; ; {
+		real, err := filepath.EvalSymlinks(p)
+		if err == nil {
+			return filepath.Join(append([]string{real}, missing...)...), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) && !errors.Is(err, syscall.ENOTDIR) {
+			return "", err
+		}
+
+		if info, statErr := os.Lstat(p); statErr == nil && info.Mode()&fs.ModeSymlink != 0 {
+			if hops++; hops > maxSymlinkHops {
+				return "", fmt.Errorf("too many levels of symbolic links at '%s'", p)
+			}
+			target, err := os.Readlink(p)
+			if err != nil {
+				return "", err
+			}
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(filepath.Dir(p), target)
+			}
+			p = filepath.Clean(target)
+			continue
+		}
+
+		parent := filepath.Dir(p)
+		if parent == p {
+			return path, nil
+		}
+		missing = append([]string{filepath.Base(p)}, missing...)
+		p = parent
+	}
+}
@@ -5,24 +5,35 @@ package task
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"hash"
+	"io"
 	"os"
+	"syscall"
 	"time"
-
-	"ai-agent-v3/internal/task/fileutils"
 )
 
 // Error constants for FileWriteExecutor
 const (
 	// Command validation errors
 	errFileWriteInvalidCommandType = "invalid command type for FileWriteExecutor"
+	errFileWriteInvalidChecksum    = "invalid checksum '%s': must be '%s' or '%s'"
+	errFileWriteAppendOverwrite    = "append and overwrite are mutually exclusive"
 
 	// File operation errors
 	errFileWriteResolveFilePath = "failed to resolve file path: %w"
+	errFileWriteIsDirectory     = "destination '%s' is a directory"
+	errFileWriteExists          = "file '%s' already exists and overwrite=false"
+	errFileWriteLockFailed      = "failed to lock file '%s': %w"
 	errFileWriteOpenFileFailed  = "failed to open/create file '%s': %w"
 	errFileWriteWriteFileFailed = "failed to write content to file '%s': %w"
 	errFileWriteIncompleteWrite = "incomplete write to file '%s': wrote %d bytes, expected %d"
+	errFileWriteCloseFailed     = "failed to close file '%s': %w"
+	errFileWriteVerifyFailed    = "failed to verify written file '%s': %w"
 
 	// Status messages
 	msgFileWriteCancelled = "File writing cancelled."
@@ -31,18 +42,38 @@ const (
 	msgFileWriteSucceeded = "File writing finished successfully to '%s' in %v."
 )
 
-// FileWriteResult represents the result of a file write operation
+// FileWriteResult represents the result of a file write operation. It is the
+// Payload of a FILE_WRITE result when a Checksum was requested.
 type FileWriteResult struct {
-	FilePath string
+	FilePath string `json:"file_path"`
+	// Checksum is the hex-encoded digest of the written content.
+	Checksum string `json:"checksum,omitempty"`
 }
 
 // FileWriteExecutor handles the execution of FileWriteCommand.
 // It manages file creation, writing content, and proper error handling.
-type FileWriteExecutor struct{}
+type FileWriteExecutor struct {
+	config ExecutorConfig
+	// fs is used to verify the size of the file after writing.
+	fs FileSystem
+}
+
+var (
+	_ TaskExecutor = (*FileWriteExecutor)(nil)
+	_ Compensator  = (*FileWriteExecutor)(nil)
+)
 
 // NewFileWriteExecutor creates a new FileWriteExecutor.
 func NewFileWriteExecutor() *FileWriteExecutor {
-	return &FileWriteExecutor{}
+	return &FileWriteExecutor{fs: &defaultFileSystem{}}
+}
+
+// NewFileWriteExecutorWithConfig creates a new FileWriteExecutor using the shared executor config.
+func NewFileWriteExecutorWithConfig(cfg ExecutorConfig) *FileWriteExecutor {
+	return &FileWriteExecutor{
+		config: cfg,
+		fs:     &defaultFileSystem{locks: cfg.fileLocks(), dirMode: cfg.dirMode()},
+	}
 }
 
 // Execute implements the Executor interface for FileWriteCommand.
@@ -60,53 +91,82 @@ func (e *FileWriteExecutor) Execute(ctx context.Context, fileWriteCmd *Task) (<-
 		return terminalChan, nil
 	}
 
+	checksum := fileWriteCmd.Parameters.(FileWriteParameters).Checksum
+	if _, err := newChecksumHash(checksum); err != nil {
+		return nil, err
+	}
+
 	// Create a channel for results
 	results := make(chan OutputResult, 1)
 	go func() {
 		defer close(results)
-		startTime := time.Now()
+		startTime := e.config.clock().Now()
+
+		ctx, cancel := e.config.withTimeout(ctx, fileWriteCmd)
+		defer cancel()
 
 		// Check context before starting
 		if err := ctx.Err(); err != nil {
-			finalResult := createFinalResult(fileWriteCmd.TaskId, "", err, time.Since(startTime))
+			finalResult := createFinalResult(fileWriteCmd.TaskId, "", err, e.config.since(startTime))
+			fileWriteCmd.Status = finalResult.Status
+			finalResult.setTimes(e.config.clock(), startTime)
+			fileWriteCmd.UpdateOutput(&finalResult)
+			e.config.send(ctx, results, finalResult)
+			return
+		}
+
+		if params := fileWriteCmd.Parameters.(FileWriteParameters); params.Append && params.Overwrite {
+			finalResult := createFinalResult(fileWriteCmd.TaskId, "", invalidf(errFileWriteAppendOverwrite), e.config.since(startTime))
 			fileWriteCmd.Status = finalResult.Status
+			finalResult.setTimes(e.config.clock(), startTime)
 			fileWriteCmd.UpdateOutput(&finalResult)
-			results <- finalResult
+			e.config.send(ctx, results, finalResult)
 			return
 		}
 
 		// Resolve the file path
-		resolvedPath, err := fileutils.ResolveFilePath(fileWriteCmd.Parameters.(FileWriteParameters).FilePath, fileWriteCmd.Parameters.(FileWriteParameters).WorkingDirectory)
+		resolvedPath, err := e.config.resolvePath(fileWriteCmd.Parameters.(FileWriteParameters).FilePath, fileWriteCmd.Parameters.(FileWriteParameters).WorkingDirectory)
 		if err != nil {
-			finalResult := createFinalResult(fileWriteCmd.TaskId, resolvedPath, fmt.Errorf(errFileWriteResolveFilePath, err), time.Since(startTime))
+			finalResult := createFinalResult(fileWriteCmd.TaskId, resolvedPath, fmt.Errorf(errFileWriteResolveFilePath, err), e.config.since(startTime))
 			fileWriteCmd.Status = finalResult.Status
+			finalResult.setTimes(e.config.clock(), startTime)
 			fileWriteCmd.UpdateOutput(&finalResult)
-			results <- finalResult
+			e.config.send(ctx, results, finalResult)
 			return
 		}
 
 		// Check context before writing file
 		if err := ctx.Err(); err != nil {
-			finalResult := createFinalResult(fileWriteCmd.TaskId, resolvedPath, err, time.Since(startTime))
+			finalResult := createFinalResult(fileWriteCmd.TaskId, resolvedPath, err, e.config.since(startTime))
 			fileWriteCmd.Status = finalResult.Status
+			finalResult.setTimes(e.config.clock(), startTime)
 			fileWriteCmd.UpdateOutput(&finalResult)
-			results <- finalResult
+			e.config.send(ctx, results, finalResult)
 			return
 		}
 
-		// Write the file
-		if err := writeFileContent(ctx, resolvedPath, fileWriteCmd.Parameters.(FileWriteParameters).Content); err != nil {
-			finalResult := createFinalResult(fileWriteCmd.TaskId, resolvedPath, err, time.Since(startTime))
+		// Write the file, hashing the content on the way when a checksum was requested
+		sum, _ := newChecksumHash(checksum)
+		params := fileWriteCmd.Parameters.(FileWriteParameters)
+		if err := e.writeFileContent(ctx, resolvedPath, params.Content, params.Append, params.Overwrite, sum); err != nil {
+			finalResult := createFinalResult(fileWriteCmd.TaskId, resolvedPath, err, e.config.since(startTime))
 			fileWriteCmd.Status = finalResult.Status
+			finalResult.setTimes(e.config.clock(), startTime)
 			fileWriteCmd.UpdateOutput(&finalResult)
-			results <- finalResult
+			e.config.send(ctx, results, finalResult)
 			return
 		}
 
-		finalResult := createFinalResult(fileWriteCmd.TaskId, resolvedPath, nil, time.Since(startTime))
+		finalResult := createFinalResult(fileWriteCmd.TaskId, resolvedPath, nil, e.config.since(startTime))
+		if sum != nil {
+			digest := hex.EncodeToString(sum.Sum(nil))
+			finalResult.ResultData = digest
+			finalResult.Payload = FileWriteResult{FilePath: resolvedPath, Checksum: digest}
+		}
 		fileWriteCmd.Status = finalResult.Status
+		finalResult.setTimes(e.config.clock(), startTime)
 		fileWriteCmd.UpdateOutput(&finalResult)
-		results <- finalResult
+		e.config.send(ctx, results, finalResult)
 	}()
 
 	return results, nil
@@ -118,10 +178,12 @@ func createFinalResult(cmdID, filePath string, err error, duration time.Duration
 	var status TaskStatus
 	var errMsg string
 	var message string
+	var failure FailureKind
 
 	if err != nil {
 		status = StatusFailed
 		errMsg = err.Error()
+		failure = failureKind(err)
 
 		// Create specific messages based on error type
 		if errors.Is(err, context.Canceled) {
@@ -139,26 +201,63 @@ func createFinalResult(cmdID, filePath string, err error, duration time.Duration
 	}
 
 	return OutputResult{
-		TaskID:  cmdID,
-		Status:  status,
-		Message: message,
-		Error:   errMsg,
+		TaskID:      cmdID,
+		Status:      status,
+		Message:     message,
+		Error:       errMsg,
+		FailureKind: failure,
 	}
 }
 
 // writeFileContent writes the given content to a file at the specified path.
-// It creates the file if it doesn't exist or truncates it if it does.
+// It creates the file if it doesn't exist. An existing file is truncated when
+// overwrite is set, content is added after its bytes when appendContent is set,
+// and otherwise the write fails without touching it.
 // The function checks the context before writing to handle cancellation properly.
-// Returns an error if the file cannot be opened, written to, or closed properly,
+// After closing the file, its size is compared against the expected length so that
+// short writes on unusual filesystems are reported instead of silently succeeding.
+// Named pipes and other non-regular files are handed to writeStreamContent instead.
+// A non-nil sum is fed every byte that is written.
+// The file's lock is held throughout, so a concurrent patch of the same path
+// sees either the old or the new content, never a partial write.
+// Returns an error if the file cannot be opened, written to, closed, or verified,
 // or if the context is cancelled during execution.
-func writeFileContent(ctx context.Context, filePath, content string) error {
+func (e *FileWriteExecutor) writeFileContent(ctx context.Context, filePath, content string, appendContent, overwrite bool, sum hash.Hash) error {
 	// Check context before opening file
 	if err := ctx.Err(); err != nil {
 		return err
 	}
 
-	// Open the file for writing (create if not exists, truncate if exists)
-	file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	unlock, err := e.fs.LockFile(filePath)
+	if err != nil {
+		return fmt.Errorf(errFileWriteLockFailed, filePath, err)
+	}
+	defer unlock()
+
+	// Appended content lands after the bytes already in the file
+	var existingSize int64
+	if info, err := e.fs.Stat(filePath); err == nil {
+		// Opening a directory for writing fails with an obscure OS error, so report it plainly
+		if info.IsDir() {
+			return fmt.Errorf(errFileWriteIsDirectory, filePath)
+		}
+		// Named pipes and devices can be neither truncated nor verified by size
+		if !info.Mode().IsRegular() {
+			return e.writeStreamContent(ctx, filePath, content, sum)
+		}
+		if appendContent {
+			existingSize = info.Size()
+		} else if !overwrite {
+			return fmt.Errorf(errFileWriteExists, filePath)
+		}
+	}
+
+	// Open the file for writing (create if not exists, truncate or append if exists)
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if appendContent {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+	file, err := os.OpenFile(filePath, flags, e.config.fileMode())
 	if err != nil {
 		return fmt.Errorf(errFileWriteOpenFileFailed, filePath, err)
 	}
@@ -173,7 +272,7 @@ func writeFileContent(ctx context.Context, filePath, content string) error {
 
 	// Write content to the file
 	contentBytes := []byte(content)
-	n, err := file.Write(contentBytes)
+	n, err := checksumWriter(file, sum).Write(contentBytes)
 	if err != nil {
 		return fmt.Errorf(errFileWriteWriteFileFailed, filePath, err)
 	}
@@ -183,5 +282,107 @@ func writeFileContent(ctx context.Context, filePath, content string) error {
 		return fmt.Errorf(errFileWriteIncompleteWrite, filePath, n, len(contentBytes))
 	}
 
+	// Close explicitly so that deferred write errors are reported
+	if err := file.Close(); err != nil {
+		return fmt.Errorf(errFileWriteCloseFailed, filePath, err)
+	}
+
+	// Verify the size on disk matches what we intended to write
+	info, err := e.fs.Stat(filePath)
+	if err != nil {
+		return fmt.Errorf(errFileWriteVerifyFailed, filePath, err)
+	}
+	if info.Size() != existingSize+int64(len(contentBytes)) {
+		return fmt.Errorf(errFileWriteIncompleteWrite, filePath, info.Size()-existingSize, len(contentBytes))
+	}
+
 	return nil
 }
+
+// writeStreamContent writes content to a named pipe or other non-regular file.
+// The file is opened without O_CREATE or O_TRUNC, and its size is not verified.
+// Opening a named pipe for writing blocks until a reader opens it; if ctx is done
+// first, the pipe is briefly opened for reading so the pending open can return.
+func (e *FileWriteExecutor) writeStreamContent(ctx context.Context, filePath, content string, sum hash.Hash) error {
+	type openResult struct {
+		file *os.File
+		err  error
+	}
+	opened := make(chan openResult, 1)
+	go func() {
+		file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_APPEND, 0)
+		opened <- openResult{file, err}
+	}()
+
+	var file *os.File
+	select {
+	case r := <-opened:
+		if r.err != nil {
+			return fmt.Errorf(errFileWriteOpenFileFailed, filePath, r.err)
+		}
+		file = r.file
+	case <-ctx.Done():
+		if reader, err := os.OpenFile(filePath, os.O_RDONLY|syscall.O_NONBLOCK, 0); err == nil {
+			reader.Close()
+		}
+		if r := <-opened; r.file != nil {
+			r.file.Close()
+		}
+		return ctx.Err()
+	}
+	defer file.Close()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(checksumWriter(file, sum), content); err != nil {
+		return fmt.Errorf(errFileWriteWriteFileFailed, filePath, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf(errFileWriteCloseFailed, filePath, err)
+	}
+	return nil
+}
+
+// newChecksumHash returns the hash named by a FILE_WRITE checksum, or nil if name is empty.
+func newChecksumHash(name string) (hash.Hash, error) {
+	switch name {
+	case "":
+		return nil, nil
+	case ChecksumSHA256:
+		return sha256.New(), nil
+	case ChecksumSHA512:
+		return sha512.New(), nil
+	default:
+		return nil, invalidf(errFileWriteInvalidChecksum, name, ChecksumSHA256, ChecksumSHA512)
+	}
+}
+
+// checksumWriter returns a writer to file that also feeds sum, if it is not nil.
+// Only the bytes the file accepted are hashed.
+func checksumWriter(file *os.File, sum hash.Hash) io.Writer {
+	if sum == nil {
+		return file
+	}
+	return writerFunc(func(p []byte) (int, error) {
+		n, err := file.Write(p)
+		sum.Write(p[:n])
+		return n, err
+	})
+}
+
+// writerFunc adapts a function to io.Writer.
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
+
+// PrepareCompensation implements Compensator by backing up the target file
+// so that a rollback restores its previous content or removes it if it was created.
+func (e *FileWriteExecutor) PrepareCompensation(ctx context.Context, fileWriteCmd *Task) (Compensation, error) {
+	params := fileWriteCmd.Parameters.(FileWriteParameters)
+	filePath, err := e.config.resolvePath(params.FilePath, params.WorkingDirectory)
+	if err != nil {
+		return nil, err
+	}
+	return fileBackupCompensation(filePath)
+}
@@ -2,8 +2,13 @@ package task
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -125,6 +130,87 @@ func TestFileWriteExecutor_Execute_Overwrite(t *testing.T) {
 	assert.Equal(t, newContent, actualContent, "File content was not overwritten")
 }
 
+func TestFileWriteExecutor_Execute_ExistingWithoutOverwrite(t *testing.T) {
+	executor := NewFileWriteExecutor()
+	tempDir := t.TempDir()
+	tempFilePath := filepath.Join(tempDir, "test_write_existing.txt")
+	initialContent := "Initial content."
+
+	// Create the initial file
+	err := os.WriteFile(tempFilePath, []byte(initialContent), 0644)
+	require.NoError(t, err, "Failed to create initial file")
+
+	cmd := NewFileWriteTask("test-write-existing-1", "Test File Write Without Overwrite", FileWriteParameters{
+		FilePath: tempFilePath,
+		Content:  "Replacement content.",
+	})
+
+	resultsChan, err := executor.Execute(context.Background(), cmd)
+	require.NoError(t, err, "Execute setup failed")
+
+	finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Contains(t, finalResult.Error, "already exists and overwrite=false")
+	assert.Equal(t, StatusFailed, cmd.Status)
+
+	// Verify the existing file was left alone
+	actualContent, readErr := readFileContent(t, tempFilePath)
+	require.NoError(t, readErr, "Failed to read back file content")
+	assert.Equal(t, initialContent, actualContent, "Existing file was modified")
+}
+
+func TestFileWriteExecutor_Execute_Append(t *testing.T) {
+	run := func(t *testing.T, params FileWriteParameters) OutputResult {
+		t.Helper()
+		resultsChan, err := NewFileWriteExecutor().Execute(context.Background(), NewFileWriteTask("test-write-append", "Append to a file", params))
+		require.NoError(t, err, "Execute setup failed")
+		finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+		require.True(t, received, "Did not receive final result")
+		return finalResult
+	}
+
+	t.Run("ExistingFile", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "log.txt")
+		require.NoError(t, os.WriteFile(filePath, []byte("first\n"), 0644))
+
+		finalResult := run(t, FileWriteParameters{FilePath: filePath, Content: "second\n", Append: true})
+		assert.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+		finalResult = run(t, FileWriteParameters{FilePath: filePath, Content: "third\n", Append: true})
+		assert.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+
+		content, err := readFileContent(t, filePath)
+		require.NoError(t, err)
+		assert.Equal(t, "first\nsecond\nthird\n", content)
+	})
+
+	t.Run("CreatesNewFile", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "new.txt")
+
+		finalResult := run(t, FileWriteParameters{FilePath: filePath, Content: "only\n", Append: true})
+		assert.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+
+		content, err := readFileContent(t, filePath)
+		require.NoError(t, err)
+		assert.Equal(t, "only\n", content)
+	})
+
+	t.Run("ConflictsWithOverwrite", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "conflict.txt")
+		require.NoError(t, os.WriteFile(filePath, []byte("unchanged\n"), 0644))
+
+		finalResult := run(t, FileWriteParameters{FilePath: filePath, Content: "new\n", Append: true, Overwrite: true})
+		assert.Equal(t, StatusFailed, finalResult.Status)
+		assert.Equal(t, FailureValidationError, finalResult.FailureKind)
+		assert.Contains(t, finalResult.Error, "mutually exclusive")
+
+		content, err := readFileContent(t, filePath)
+		require.NoError(t, err)
+		assert.Equal(t, "unchanged\n", content)
+	})
+}
+
 func TestFileWriteExecutor_Execute_DirectoryNotFound(t *testing.T) {
 	executor := NewFileWriteExecutor()
 	tempDir := t.TempDir()
@@ -295,3 +381,243 @@ func TestFileWriteExecutor_Execute_TerminalTaskHandling(t *testing.T) {
 		})
 	}
 }
+
+// shortWriteFileSystem reports one byte less than the real file size to simulate a short write.
+type shortWriteFileSystem struct {
+	defaultFileSystem
+}
+
+type shortFileInfo struct {
+	os.FileInfo
+}
+
+func (i shortFileInfo) Size() int64 {
+	return i.FileInfo.Size() - 1
+}
+
+func (fs *shortWriteFileSystem) Stat(name string) (os.FileInfo, error) {
+	info, err := os.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	return shortFileInfo{info}, nil
+}
+
+func TestFileWriteExecutor_Execute_ShortWriteDetected(t *testing.T) {
+	executor := NewFileWriteExecutor()
+	executor.fs = &shortWriteFileSystem{}
+	tempFilePath := filepath.Join(t.TempDir(), "test_short_write.txt")
+
+	cmd := NewFileWriteTask("test-write-short-1", "Test File Write Short Write", FileWriteParameters{
+		FilePath: tempFilePath,
+		Content:  "content that was not fully persisted",
+	})
+
+	resultsChan, err := executor.Execute(context.Background(), cmd)
+	require.NoError(t, err, "Execute setup failed")
+
+	finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Contains(t, finalResult.Error, "incomplete write")
+	assert.Equal(t, StatusFailed, cmd.Status)
+}
+
+func TestFileWriteExecutor_Execute_ContentWrittenExactly(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+	}{
+		{name: "No trailing newline", content: "line 1\nline 2"},
+		{name: "Trailing newline", content: "line 1\nline 2\n"},
+		{name: "Multiple trailing newlines", content: "line 1\n\n\n"},
+		{name: "CRLF line endings", content: "line 1\r\nline 2\r\n"},
+		{name: "Single newline", content: "\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			executor := NewFileWriteExecutor()
+			tempFilePath := filepath.Join(t.TempDir(), "exact.txt")
+
+			cmd := NewFileWriteTask("test-write-exact", "Test exact content", FileWriteParameters{
+				FilePath: tempFilePath,
+				Content:  tc.content,
+			})
+
+			resultsChan, err := executor.Execute(context.Background(), cmd)
+			require.NoError(t, err, "Execute setup failed")
+
+			finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+			require.True(t, received, "Did not receive final result")
+			require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+
+			actual, err := os.ReadFile(tempFilePath)
+			require.NoError(t, err)
+			assert.Equal(t, []byte(tc.content), actual, "Content must be written byte-for-byte")
+		})
+	}
+}
+
+func TestFileWriteExecutor_Execute_ConfiguredFileMode(t *testing.T) {
+	executor := NewFileWriteExecutorWithConfig(ExecutorConfig{FileMode: 0600})
+	tempFilePath := filepath.Join(t.TempDir(), "private.txt")
+
+	cmd := NewFileWriteTask("test-write-mode", "Test configured file mode", FileWriteParameters{
+		FilePath: tempFilePath,
+		Content:  "secret",
+	})
+
+	resultsChan, err := executor.Execute(context.Background(), cmd)
+	require.NoError(t, err, "Execute setup failed")
+
+	finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+	require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+
+	info, err := os.Stat(tempFilePath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestFileWriteExecutor_Execute_DestinationIsDirectory(t *testing.T) {
+	executor := NewFileWriteExecutor()
+	dirPath := t.TempDir()
+
+	cmd := NewFileWriteTask("test-write-dir", "Test writing to a directory", FileWriteParameters{
+		FilePath: dirPath,
+		Content:  "content",
+	})
+
+	resultsChan, err := executor.Execute(context.Background(), cmd)
+	require.NoError(t, err, "Execute setup failed")
+
+	finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Equal(t, "destination '"+dirPath+"' is a directory", finalResult.Error)
+
+	info, err := os.Stat(dirPath)
+	require.NoError(t, err)
+	assert.True(t, info.IsDir(), "Directory must be left untouched")
+}
+
+// makeFIFO creates a named pipe in a temporary directory, skipping the test where mkfifo is unavailable.
+func makeFIFO(t *testing.T) string {
+	t.Helper()
+	fifoPath := filepath.Join(t.TempDir(), "pipe")
+	if err := exec.Command("mkfifo", fifoPath).Run(); err != nil {
+		t.Skipf("mkfifo unavailable: %v", err)
+	}
+	return fifoPath
+}
+
+func TestFileWriteExecutor_Execute_NamedPipe(t *testing.T) {
+	fifoPath := makeFIFO(t)
+
+	received := make(chan string, 1)
+	go func() {
+		content, err := os.ReadFile(fifoPath)
+		if err != nil {
+			received <- "read error: " + err.Error()
+			return
+		}
+		received <- string(content)
+	}()
+
+	cmd := NewFileWriteTask("test-write-fifo", "Write to a named pipe", FileWriteParameters{
+		FilePath: fifoPath,
+		Content:  "streamed through a pipe\n",
+	})
+	resultsChan, err := NewFileWriteExecutor().Execute(context.Background(), cmd)
+	require.NoError(t, err, "Execute setup failed")
+
+	finalResult, ok := readFinalResult(t, resultsChan, 5*time.Second)
+	require.True(t, ok, "Did not receive final result")
+	require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+
+	select {
+	case content := <-received:
+		assert.Equal(t, "streamed through a pipe\n", content)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Reader did not receive the content")
+	}
+
+	info, err := os.Stat(fifoPath)
+	require.NoError(t, err)
+	assert.NotZero(t, info.Mode()&os.ModeNamedPipe, "The pipe must not be replaced by a regular file")
+}
+
+func TestFileWriteExecutor_Execute_NamedPipeWithoutReader(t *testing.T) {
+	fifoPath := makeFIFO(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	cmd := NewFileWriteTask("test-write-fifo-noreader", "Write to a pipe nobody reads", FileWriteParameters{
+		FilePath: fifoPath,
+		Content:  "lost",
+	})
+	resultsChan, err := NewFileWriteExecutor().Execute(ctx, cmd)
+	require.NoError(t, err, "Execute setup failed")
+
+	finalResult, ok := readFinalResult(t, resultsChan, 5*time.Second)
+	require.True(t, ok, "Write to a pipe without a reader should give up when the context is done")
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Equal(t, FailureTimedOut, finalResult.FailureKind)
+}
+
+func TestFileWriteExecutor_Execute_Checksum(t *testing.T) {
+	content := strings.Repeat("generated line\n", 10000)
+	hashFile := func(t *testing.T, path string, algorithm string) string {
+		t.Helper()
+		written, err := os.ReadFile(path)
+		require.NoError(t, err)
+		if algorithm == ChecksumSHA512 {
+			sum := sha512.Sum512(written)
+			return hex.EncodeToString(sum[:])
+		}
+		sum := sha256.Sum256(written)
+		return hex.EncodeToString(sum[:])
+	}
+
+	for _, algorithm := range []string{ChecksumSHA256, ChecksumSHA512} {
+		t.Run(algorithm, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "generated.txt")
+			cmd := NewFileWriteTask("write-checksum", "Write with checksum", FileWriteParameters{
+				FilePath: filePath,
+				Content:  content,
+				Checksum: algorithm,
+			})
+			resultsChan, err := NewFileWriteExecutor().Execute(context.Background(), cmd)
+			require.NoError(t, err)
+
+			finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+			require.True(t, received, "Did not receive final result")
+			require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+
+			expected := hashFile(t, filePath, algorithm)
+			assert.Equal(t, expected, finalResult.ResultData)
+			assert.Equal(t, FileWriteResult{FilePath: filePath, Checksum: expected}, finalResult.Payload)
+		})
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		cmd := NewFileWriteTask("write-no-checksum", "Write without checksum", FileWriteParameters{
+			FilePath: filepath.Join(t.TempDir(), "plain.txt"),
+			Content:  content,
+		})
+		resultsChan, err := NewFileWriteExecutor().Execute(context.Background(), cmd)
+		require.NoError(t, err)
+		finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+		require.True(t, received, "Did not receive final result")
+		assert.Empty(t, finalResult.ResultData)
+		assert.Nil(t, finalResult.Payload)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		cmd := NewFileWriteTask("write-bad-checksum", "Unknown hash", FileWriteParameters{FilePath: "x", Checksum: "md5"})
+		_, err := NewFileWriteExecutor().Execute(context.Background(), cmd)
+		assert.ErrorContains(t, err, "invalid checksum 'md5'")
+	})
+}
@@ -2,28 +2,121 @@ package task
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 )
 
+// Error constants for GroupExecutor
+const (
+	errGroupTooDeep        = "group task %s is nested %d levels deep, exceeding the maximum of %d"
+	errGroupNegativeBudget = "max_runtime_ms cannot be negative, got %d"
+	errGroupNegativeLimit  = "max_concurrency cannot be negative, got %d"
+	errGroupForwardDep     = "child task %s depends on %s, which does not run before it in the group"
+	msgGroupParallel       = "Starting parallel execution of group task with %d children, at most %d at a time"
+	msgGroupBudgetExceeded = "Group task exceeded its runtime budget of %v after completing %d/%d child tasks"
+	msgGroupNoChildren     = "Group task has no children, nothing to run"
+	msgGroupCanceled       = "Group task execution canceled after completing %d/%d child tasks"
+
+	// DefaultMaxGroupDepth is the default limit on how deeply GROUP tasks may be nested
+	DefaultMaxGroupDepth = 10
+)
+
+// ErrChildAlreadyFailed is the error recorded for a child that was already FAILED
+// when its group ran.
+var ErrChildAlreadyFailed = errors.New("already in FAILED state")
+
+// ChildTaskError is the failure of a single child task of a group.
+type ChildTaskError struct {
+	// TaskID identifies the child task.
+	TaskID string
+	// Err is the child's error. For a nested group it is that group's *GroupError.
+	Err error
+}
+
+func (e *ChildTaskError) Error() string {
+	if e.Err == ErrChildAlreadyFailed {
+		return fmt.Sprintf("Task %s %v", e.TaskID, e.Err)
+	}
+	return fmt.Sprintf("Task %s failed: %v", e.TaskID, e.Err)
+}
+
+func (e *ChildTaskError) Unwrap() error {
+	return e.Err
+}
+
+// GroupError aggregates the child failures of a group task. It is set as the Err
+// of the group's final OutputResult, whose Error holds the same messages joined by newlines.
+type GroupError struct {
+	// Errors holds a *ChildTaskError for each failed child, in execution order.
+	Errors []error
+}
+
+func (e *GroupError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+func (e *GroupError) Unwrap() []error {
+	return e.Errors
+}
+
+// ChildStatus is the state of one child task when its group was canceled.
+// The final result of a canceled group carries a []ChildStatus as its Payload,
+// listing every child in order.
+type ChildStatus struct {
+	TaskID string     `json:"task_id"`
+	Status TaskStatus `json:"status"`
+}
+
+// childError returns the structured error behind a failed child result.
+func childError(result OutputResult) error {
+	if result.Err != nil {
+		return result.Err
+	}
+	return errors.New(result.Error)
+}
+
 // GroupExecutor handles the execution of GroupTask.
 // It manages executing a collection of child tasks, tracking their results,
 // and determining the overall outcome.
 type GroupExecutor struct {
 	registry TaskRegistry
+	config   ExecutorConfig
 }
 
+var _ TaskExecutor = (*GroupExecutor)(nil)
+
 // NewGroupExecutor creates a new GroupExecutor.
 func NewGroupExecutor(registry TaskRegistry) *GroupExecutor {
+	return NewGroupExecutorWithConfig(registry, ExecutorConfig{})
+}
+
+// NewGroupExecutorWithConfig creates a new GroupExecutor using the shared executor config.
+func NewGroupExecutorWithConfig(registry TaskRegistry, cfg ExecutorConfig) *GroupExecutor {
 	return &GroupExecutor{
 		registry: registry,
+		config:   cfg,
 	}
 }
 
+// groupDepthKey is the context key holding the nesting depth of the running group.
+type groupDepthKey struct{}
+
+// groupDepth returns the nesting depth of the group running in ctx, or 0 outside a group.
+func groupDepth(ctx context.Context) int {
+	depth, _ := ctx.Value(groupDepthKey{}).(int)
+	return depth
+}
+
 // Execute implements the TaskExecutor interface for GroupTask.
-// It processes each child task sequentially, tracking their results.
-// The GROUP task fails if any child task fails.
+// It processes each child task sequentially, tracking their results, or concurrently
+// when the group's parameters set Parallel.
+// The GROUP task fails if any child task fails. A group without children succeeds immediately.
 func (e *GroupExecutor) Execute(ctx context.Context, v *Task) (<-chan OutputResult, error) {
 	var children []*Task
 	var taskId string
@@ -45,121 +138,299 @@ func (e *GroupExecutor) Execute(ctx context.Context, v *Task) (<-chan OutputResu
 		return terminalChan, err
 	}
 
-	if len(children) == 0 {
-		return nil, fmt.Errorf("group task has no children")
+	if params := groupParameters(v); params.MaxRuntimeMs < 0 {
+		return nil, fmt.Errorf(errGroupNegativeBudget, params.MaxRuntimeMs)
+	} else if params.MaxConcurrency < 0 {
+		return nil, fmt.Errorf(errGroupNegativeLimit, params.MaxConcurrency)
+	} else if params.Parallel {
+		if err := checkParallelDependencies(children); err != nil {
+			return nil, err
+		}
+	}
+
+	// Nested groups run through this method recursively, so bound the depth to
+	// guard against deep or self-referential structures
+	depth := groupDepth(ctx) + 1
+	if maxDepth := e.config.maxGroupDepth(); depth > maxDepth {
+		return nil, fmt.Errorf(errGroupTooDeep, taskId, depth, maxDepth)
+	}
+
+	// A group run directly has not been given a span by a caller, but needs one
+	// for its children to record as their parent
+	s, ok := executionSpan(ctx, v)
+	if !ok {
+		ctx, s = startSpan(ctx, v)
 	}
 
 	results := make(chan OutputResult, 2) // Buffer for at least the running and final states
 
-	go e.executeGroupTask(ctx, taskId, children, results)
+	go func() {
+		groupCtx := ContextWithSpan(context.WithValue(ctx, groupDepthKey{}, depth), s.id)
+		ctx, cancel := e.config.withDeadline(groupCtx, v)
+		defer cancel()
+		e.executeGroupTask(ctx, v, groupParameters(v), children, results)
+	}()
 	return results, nil
 }
 
 // executeGroupTask handles the execution of all child tasks in a separate goroutine.
-func (e *GroupExecutor) executeGroupTask(ctx context.Context, taskId string, children []*Task, results chan<- OutputResult) {
+func (e *GroupExecutor) executeGroupTask(ctx context.Context, group *Task, params GroupParameters, children []*Task, results chan<- OutputResult) {
 	defer close(results)
+	taskId := group.TaskId
+
+	// A group whose children were all filtered out has nothing to do
+	if len(children) == 0 {
+		startTime := e.config.clock().Now()
+		finalResult := group.describeIn(ctx, OutputResult{
+			TaskID:  taskId,
+			Status:  StatusSucceeded,
+			Message: msgGroupNoChildren,
+		})
+		finalResult.setTimes(e.config.clock(), startTime)
+		e.config.send(ctx, results, finalResult)
+		return
+	}
+
+	if params.Parallel {
+		e.executeParallel(ctx, group, params, children, results)
+		return
+	}
 
 	// Send initial running status
-	results <- OutputResult{
+	e.config.send(ctx, results, group.describeIn(ctx, OutputResult{
 		TaskID:  taskId,
 		Status:  StatusRunning,
 		Message: fmt.Sprintf("Starting execution of group task with %d children", len(children)),
-	}
+	}))
 
-	startTime := time.Now()
+	startTime := e.config.clock().Now()
 	var allResults []string
-	var allErrors []string
+	var allErrors []error
+	var warnings []string
 	var failedTasks int
+	// Kind of the first required child failure, reported as the group's own
+	var failure FailureKind
 	var processedTasks int
 
-	// Create a child context that can be canceled if needed
-	childCtx, cancel := context.WithCancel(ctx)
+	// Combined output of each child that succeeded, keyed by task ID,
+	// used to resolve result references in later children
+	outputs := newResultStore()
+
+	// Create a child context that can be canceled if needed. It also carries the
+	// group's runtime budget, so a parent cancellation is told apart by checking ctx.
+	var childCtx context.Context
+	var cancel context.CancelFunc
+	if budget := params.maxRuntime(); budget > 0 {
+		childCtx, cancel = withClockDeadline(ctx, e.config.clock(), startTime.Add(budget))
+	} else {
+		childCtx, cancel = context.WithCancel(ctx)
+	}
 	defer cancel()
+	budgetExceeded := func() bool {
+		return ctx.Err() == nil && childCtx.Err() != nil
+	}
+	// Index of the first child the budget interrupted, or -1
+	interrupted := -1
 
 	// Process each child task
 	for i, childTask := range children {
 		// Check if the parent context is already done
 		if ctx.Err() != nil {
-			results <- OutputResult{
-				TaskID:  taskId,
-				Status:  StatusFailed,
-				Message: fmt.Sprintf("Group task execution canceled after completing %d/%d child tasks", processedTasks, len(children)),
-				Error:   ctx.Err().Error(),
-			}
+			e.sendCanceled(ctx, group, children, processedTasks, allResults, startTime, results)
 			return
 		}
+		if budgetExceeded() {
+			interrupted = i
+			break
+		}
 
 		// Skip tasks that are already in a terminal state
 		if childTask.Status.IsTerminal() {
 			// If a task is already in a terminal state, count it appropriately
 			if childTask.Status == StatusFailed {
-				failedTasks++
-				allErrors = append(allErrors, fmt.Sprintf("Task %s already in FAILED state", childTask.TaskId))
+				if childTask.Optional {
+					warnings = append(warnings, fmt.Sprintf("Optional task %s already in FAILED state", childTask.TaskId))
+				} else {
+					failedTasks++
+					allErrors = append(allErrors, &ChildTaskError{TaskID: childTask.TaskId, Err: ErrChildAlreadyFailed})
+					if failure == "" {
+						failure = childTask.Output.FailureKind
+					}
+				}
+			}
+			if childTask.Status == StatusSucceeded {
+				outputs.set(childTask.TaskId, childTask.Output.ResultData)
 			}
 			processedTasks++
 			continue
 		}
 
-		// Process the child task
-		childResult := e.processChildTask(childCtx, childTask, results, taskId, i, len(children))
+		// Process the child task once its dependencies are satisfied
+		var childResult OutputResult
+		if err := resolveDependencies(childTask, outputs); err != nil {
+			childResult = childTask.describe(OutputResult{
+				TaskID:      childTask.TaskId,
+				Status:      StatusFailed,
+				Message:     "Failed to resolve child task dependencies",
+				Error:       err.Error(),
+				FailureKind: FailureValidationError,
+			})
+			childTask.Status = childResult.Status
+			childTask.Output = childResult
+		} else {
+			childResult = e.processChildTask(childCtx, childTask, results, group, params, i, len(children))
+		}
+		// A child cut short by the parent's cancellation did not fail on its own
+		if childResult.Status != StatusSucceeded && ctx.Err() != nil {
+			e.sendCanceled(ctx, group, children, processedTasks, allResults, startTime, results)
+			return
+		}
+		if childResult.Status != StatusSucceeded && budgetExceeded() {
+			interrupted = i
+			break
+		}
 		processedTasks++
 
 		// Collect the result
+		if childResult.Error != "" && childTask.Optional {
+			// Optional failures are reported but do not stop or fail the group
+			warnings = append(warnings, fmt.Sprintf("Optional task %s failed: %s", childResult.TaskID, childResult.Error))
+			e.config.send(ctx, results, group.describeIn(ctx, OutputResult{
+				TaskID:  taskId,
+				Status:  StatusRunning,
+				Message: fmt.Sprintf("Optional child task %d/%d failed (%s), continuing", i+1, len(children), childResult.Status),
+			}))
+			continue
+		}
 		if childResult.Error != "" {
 			failedTasks++
-			allErrors = append(allErrors, fmt.Sprintf("Task %s failed: %s", childResult.TaskID, childResult.Error))
+			allErrors = append(allErrors, &ChildTaskError{TaskID: childResult.TaskID, Err: childError(childResult)})
+			if failure == "" {
+				failure = childResult.FailureKind
+			}
 
 			// Report progress for the failed task
-			results <- OutputResult{
+			e.config.send(ctx, results, group.describeIn(ctx, OutputResult{
 				TaskID:  taskId,
 				Status:  StatusRunning,
 				Message: fmt.Sprintf("Child task %d/%d failed (%s)", i+1, len(children), childResult.Status),
-			}
+			}))
 
 			// Stop processing remaining tasks once one fails
 			break
 		}
 
+		outputs.set(childTask.TaskId, childResult.ResultData)
 		if childResult.ResultData != "" {
 			allResults = append(allResults, childResult.ResultData)
 		}
 
 		// Report progress
-		results <- OutputResult{
+		e.config.send(ctx, results, group.describeIn(ctx, OutputResult{
 			TaskID:  taskId,
 			Status:  StatusRunning,
 			Message: fmt.Sprintf("Completed child task %d/%d (%s)", i+1, len(children), childResult.Status),
-		}
+		}))
+	}
+
+	outcome := groupOutcome{
+		results:   allResults,
+		errors:    allErrors,
+		warnings:  warnings,
+		failed:    failedTasks,
+		processed: processedTasks,
+		failure:   failure,
+	}
+	if interrupted >= 0 {
+		outcome.budgetErr = newBudgetExceededError(params.maxRuntime(), children, interrupted)
 	}
+	e.sendGroupResult(ctx, group, params, children, startTime, outcome, results)
+}
+
+// groupOutcome collects what the children of a group produced, for its final result.
+type groupOutcome struct {
+	// results holds the non-empty output of the children that succeeded, in child order
+	results []string
+	// errors holds a *ChildTaskError for each required child that failed
+	errors   []error
+	warnings []string
+	// failed counts the required children that failed and processed those that finished
+	failed    int
+	processed int
+	// failure is the kind of the first required child failure
+	failure FailureKind
+	// budgetErr is set when the group ran out of its runtime budget
+	budgetErr *BudgetExceededError
+}
 
-	// Determine final status
+// sendGroupResult sends the final result of a group from the outcome of its children.
+func (e *GroupExecutor) sendGroupResult(ctx context.Context, group *Task, params GroupParameters, children []*Task, startTime time.Time, outcome groupOutcome, results chan<- OutputResult) {
 	finalStatus := StatusSucceeded
 	var finalMessage string
-	var finalError string
+	var finalErr error
+	failure := outcome.failure
 
-	if failedTasks > 0 {
+	if outcome.budgetErr != nil {
+		finalStatus = StatusFailed
+		finalMessage = fmt.Sprintf(msgGroupBudgetExceeded, params.maxRuntime(), outcome.processed, len(children))
+		finalErr = outcome.budgetErr
+		failure = FailureTimedOut
+	} else if outcome.failed > 0 {
 		finalStatus = StatusFailed
-		finalMessage = fmt.Sprintf("Group task completed with %d/%d failed tasks in %v", failedTasks, processedTasks, time.Since(startTime).Round(time.Millisecond))
-		finalError = strings.Join(allErrors, "\n")
+		finalMessage = fmt.Sprintf("Group task completed with %d/%d failed tasks in %v", outcome.failed, outcome.processed, e.config.since(startTime).Round(time.Millisecond))
+		finalErr = &GroupError{Errors: outcome.errors}
+		if failure == "" {
+			failure = FailureExecutionError
+		}
 	} else {
-		finalMessage = fmt.Sprintf("Group task completed successfully with %d child tasks in %v", processedTasks, time.Since(startTime).Round(time.Millisecond))
+		finalMessage = fmt.Sprintf("Group task completed successfully with %d child tasks in %v", outcome.processed, e.config.since(startTime).Round(time.Millisecond))
+	}
+	if len(outcome.warnings) > 0 {
+		finalMessage += fmt.Sprintf(". %d optional tasks failed:\n%s", len(outcome.warnings), strings.Join(outcome.warnings, "\n"))
 	}
 
 	// Send final result
-	finalResult := OutputResult{
-		TaskID:     taskId,
-		Status:     finalStatus,
-		Message:    finalMessage,
-		Error:      finalError,
-		ResultData: strings.Join(allResults, "\n"),
+	finalResult := group.describeIn(ctx, OutputResult{
+		TaskID:      group.TaskId,
+		Status:      finalStatus,
+		Message:     finalMessage,
+		ResultData:  strings.Join(outcome.results, "\n"),
+		Err:         finalErr,
+		FailureKind: failure,
+	})
+	if finalErr != nil {
+		finalResult.Error = finalErr.Error()
 	}
+	finalResult.setTimes(e.config.clock(), startTime)
 
-	results <- finalResult
+	e.config.send(ctx, results, finalResult)
+}
+
+// sendCanceled sends the final result of a group whose context was canceled before
+// all children ran. It keeps the output of the children that completed and lists
+// the status of every child in its Payload.
+func (e *GroupExecutor) sendCanceled(ctx context.Context, group *Task, children []*Task, processed int, completedResults []string, startTime time.Time, results chan<- OutputResult) {
+	statuses := make([]ChildStatus, len(children))
+	for i, child := range children {
+		statuses[i] = ChildStatus{TaskID: child.TaskId, Status: child.Status}
+	}
+	canceledResult := group.describeIn(ctx, OutputResult{
+		TaskID:      group.TaskId,
+		Status:      StatusFailed,
+		Message:     fmt.Sprintf(msgGroupCanceled, processed, len(children)),
+		Error:       ctx.Err().Error(),
+		ResultData:  strings.Join(completedResults, "\n"),
+		FailureKind: failureKind(ctx.Err()),
+		Payload:     statuses,
+	})
+	canceledResult.setTimes(e.config.clock(), startTime)
+	e.config.send(ctx, results, canceledResult)
 }
 
 // processChildTask handles the execution of a single child task and returns its final result.
 // It also forwards task execution updates to the parent's result channel.
-func (e *GroupExecutor) processChildTask(ctx context.Context, childTask *Task, parentResults chan<- OutputResult, taskId string, childIndex, totalChildren int) OutputResult {
+func (e *GroupExecutor) processChildTask(ctx context.Context, childTask *Task, parentResults chan<- OutputResult, group *Task, params GroupParameters, childIndex, totalChildren int) OutputResult {
+	taskId := group.TaskId
 	// Set the task status to running if it's pending
 	if childTask.Status.IsPending() {
 		childTask.Status = StatusRunning
@@ -168,27 +439,30 @@ func (e *GroupExecutor) processChildTask(ctx context.Context, childTask *Task, p
 	// Get the appropriate executor for this task type
 	executor, err := e.registry.GetExecutor(childTask.Type)
 	if err != nil {
-		finalResult := OutputResult{
-			TaskID:  childTask.TaskId,
-			Status:  StatusFailed,
-			Message: "Failed to get executor for child task",
-			Error:   err.Error(),
-		}
+		finalResult := childTask.describe(OutputResult{
+			TaskID:      childTask.TaskId,
+			Status:      StatusFailed,
+			Message:     "Failed to get executor for child task",
+			Error:       err.Error(),
+			FailureKind: FailureValidationError,
+		})
 		// Update child task status and output
 		childTask.Status = finalResult.Status
 		childTask.Output = finalResult
 		return finalResult
 	}
 
-	// Execute the child task directly
-	childResultsChan, err := executor.Execute(ctx, childTask)
+	// Execute the child task directly, as a span within the group's
+	spanCtx, s := startSpan(ctx, childTask)
+	childResultsChan, err := executor.Execute(spanCtx, childTask)
 	if err != nil {
-		finalResult := OutputResult{
-			TaskID:  childTask.TaskId,
-			Status:  StatusFailed,
-			Message: "Failed to execute child task",
-			Error:   err.Error(),
-		}
+		finalResult := childTask.describeIn(spanCtx, OutputResult{
+			TaskID:      childTask.TaskId,
+			Status:      StatusFailed,
+			Message:     "Failed to execute child task",
+			Error:       err.Error(),
+			FailureKind: FailureValidationError,
+		})
 		// Update child task status and output
 		childTask.Status = finalResult.Status
 		childTask.Output = finalResult
@@ -199,12 +473,36 @@ func (e *GroupExecutor) processChildTask(ctx context.Context, childTask *Task, p
 	var lastResult OutputResult
 	var resultData strings.Builder
 
-	// Read all results from the channel and forward intermediate results
+	// Read all results from the channel and forward intermediate results.
+	// Once the parent's consumer stops receiving, the child is still drained so it can finish.
+	forwarding := true
 	for result := range childResultsChan {
+		result = s.stamp(result)
+		// Only the child's own chunks make up its output; results forwarded by a
+		// nested group are already included in that group's final ResultData
+		if result.TaskID == childTask.TaskId && result.ResultData != "" {
+			resultData.WriteString(result.ResultData)
+		}
+		lastResult = result
+
+		if !forwarding {
+			continue
+		}
+		if params.ForwardChildOutput && result.Status == StatusRunning && result.ResultData != "" {
+			forwarding = e.config.send(ctx, parentResults, group.describeIn(ctx, OutputResult{
+				TaskID:     taskId,
+				Status:     StatusRunning,
+				ResultData: prefixLines(result.ResultData, "["+result.TaskID+"] "),
+			}))
+			continue
+		}
+
 		// First, forward the original message with the original child task ID
 		// but only if it has meaningful content
 		if result.Message != "" || result.ResultData != "" {
-			parentResults <- result
+			if forwarding = e.config.send(ctx, parentResults, result); !forwarding {
+				continue
+			}
 		}
 
 		// Then, also send a summary message with the group task ID
@@ -215,21 +513,17 @@ func (e *GroupExecutor) processChildTask(ctx context.Context, childTask *Task, p
 			message = fmt.Sprintf("Child task %d/%d [%s] output: %s", childIndex+1, totalChildren, childTask.TaskId, strings.TrimSpace(result.ResultData))
 		}
 
-		parentResults <- OutputResult{
+		forwarding = e.config.send(ctx, parentResults, group.describeIn(ctx, OutputResult{
 			TaskID:  taskId,
 			Status:  StatusRunning,
 			Message: message,
-		}
-
-		lastResult = result
-		if result.ResultData != "" {
-			resultData.WriteString(result.ResultData)
-		}
+		}))
 	}
 
-	// Create the final child result
+	// Create the final child result. Executors either stream their output or
+	// return it in the final result, never both.
 	finalResult := lastResult
-	if resultData.Len() > 0 {
+	if finalResult.ResultData == "" && resultData.Len() > 0 {
 		finalResult.ResultData = resultData.String()
 	}
 
@@ -239,3 +533,38 @@ func (e *GroupExecutor) processChildTask(ctx context.Context, childTask *Task, p
 
 	return finalResult
 }
+
+// groupParameters returns the parameters of a group task, which may be omitted.
+func groupParameters(t *Task) GroupParameters {
+	params, _ := t.Parameters.(GroupParameters)
+	return params
+}
+
+// prefixLines inserts prefix at the start of every line in data.
+func prefixLines(data, prefix string) string {
+	var b strings.Builder
+	for line := range strings.SplitAfterSeq(data, "\n") {
+		if line != "" {
+			b.WriteString(prefix)
+			b.WriteString(line)
+		}
+	}
+	return b.String()
+}
+
+// resolveDependencies verifies that every task the child depends on has succeeded
+// and substitutes result references in the child's parameters with their outputs.
+func resolveDependencies(childTask *Task, outputs *resultStore) error {
+	for _, dep := range childTask.DependsOn {
+		if _, ok := outputs.get(dep); !ok {
+			return fmt.Errorf("dependency %s of task %s has not completed successfully", dep, childTask.TaskId)
+		}
+	}
+
+	params, err := substituteResultReferences(childTask.Parameters, childTask.DependsOn, outputs)
+	if err != nil {
+		return err
+	}
+	childTask.Parameters = params
+	return nil
+}
@@ -3,6 +3,8 @@ package task_test
 import (
 	"ai-agent-v3/internal/task"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -546,3 +548,465 @@ func TestGroupExecutor_WithFileReadTask(t *testing.T) {
 	// Verify the file was created
 	verifyFileContent(t, filepath.Join(tempDir, "output.txt"), "Output from file write task")
 }
+
+func TestGroupExecutor_DependsOn_InjectsResult(t *testing.T) {
+	registry := task.NewMapRegistry()
+	tempDir := t.TempDir()
+	targetPath := filepath.Join(tempDir, "target.txt")
+	require.NoError(t, os.WriteFile(targetPath, []byte("injected content\n"), 0644))
+
+	childA := task.NewBashExecTask("child-a", "Print the target path", task.BashExecParameters{
+		Command: fmt.Sprintf("echo %s", targetPath),
+	})
+	childB := task.NewFileReadTask("child-b", "Read the printed path", task.FileReadParameters{
+		FilePath: "${child-a.result:1}",
+	})
+	childB.DependsOn = []string{"child-a"}
+
+	groupTask := task.NewGroupTask("group-deps", "Group with dependency", []*task.Task{childA, childB})
+	executor, err := registry.GetExecutor(task.TaskGroup)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	resultsChan, err := executor.Execute(ctx, groupTask)
+	require.NoError(t, err)
+
+	var lastResult task.OutputResult
+	for result := range resultsChan {
+		lastResult = result
+	}
+
+	require.Equal(t, task.StatusSucceeded, lastResult.Status, lastResult.Error)
+	assert.Equal(t, task.StatusSucceeded, childB.Status)
+	assert.Equal(t, targetPath, childB.Parameters.(task.FileReadParameters).FilePath)
+	assert.Contains(t, childB.Output.ResultData, "injected content")
+}
+
+func TestGroupExecutor_DependsOn_UnsatisfiedDependency(t *testing.T) {
+	registry := task.NewMapRegistry()
+	tempDir := t.TempDir()
+
+	child := task.NewFileWriteTask("child-b", "Depends on a missing sibling", task.FileWriteParameters{
+		FilePath: filepath.Join(tempDir, "out.txt"),
+		Content:  "${child-a.result}",
+	})
+	child.DependsOn = []string{"child-a"}
+
+	groupTask := task.NewGroupTask("group-missing-dep", "Group with unsatisfied dependency", []*task.Task{child})
+	executor, err := registry.GetExecutor(task.TaskGroup)
+	require.NoError(t, err)
+
+	resultsChan, err := executor.Execute(context.Background(), groupTask)
+	require.NoError(t, err)
+
+	var lastResult task.OutputResult
+	for result := range resultsChan {
+		lastResult = result
+	}
+
+	assert.Equal(t, task.StatusFailed, lastResult.Status)
+	assert.Contains(t, lastResult.Error, "dependency child-a")
+	assert.Equal(t, task.StatusFailed, child.Status)
+	_, statErr := os.Stat(filepath.Join(tempDir, "out.txt"))
+	assert.True(t, os.IsNotExist(statErr), "Child with unsatisfied dependency must not run")
+}
+
+func TestGroupExecutor_OptionalChildren(t *testing.T) {
+	testCases := []struct {
+		name             string
+		requiredFails    bool
+		expectedStatus   task.TaskStatus
+		expectLastRun    bool
+		expectedInMsg    string
+		expectedInErrMsg string
+	}{
+		{
+			name:           "OptionalFailureOnly",
+			requiredFails:  false,
+			expectedStatus: task.StatusSucceeded,
+			expectLastRun:  true,
+			expectedInMsg:  "1 optional tasks failed",
+		},
+		{
+			name:             "OptionalAndRequiredFailure",
+			requiredFails:    true,
+			expectedStatus:   task.StatusFailed,
+			expectLastRun:    false,
+			expectedInMsg:    "1 optional tasks failed",
+			expectedInErrMsg: "Task required failed",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			registry := task.NewMapRegistry()
+			tempDir := t.TempDir()
+
+			optional := task.NewBashExecTask("cleanup", "Optional failing cleanup", task.BashExecParameters{Command: "exit 1"})
+			optional.Optional = true
+
+			requiredCommand := "echo ok"
+			if tc.requiredFails {
+				requiredCommand = "exit 2"
+			}
+			required := task.NewBashExecTask("required", "Required step", task.BashExecParameters{Command: requiredCommand})
+
+			lastPath := filepath.Join(tempDir, "last.txt")
+			last := task.NewFileWriteTask("last", "Runs after the others", task.FileWriteParameters{
+				FilePath: lastPath,
+				Content:  "done",
+			})
+
+			groupTask := task.NewGroupTask("group-optional", "Group with optional child", []*task.Task{optional, required, last})
+			executor, err := registry.GetExecutor(task.TaskGroup)
+			require.NoError(t, err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			resultsChan, err := executor.Execute(ctx, groupTask)
+			require.NoError(t, err)
+
+			var lastResult task.OutputResult
+			for result := range resultsChan {
+				lastResult = result
+			}
+
+			assert.Equal(t, tc.expectedStatus, lastResult.Status, lastResult.Error)
+			assert.Equal(t, task.StatusFailed, optional.Status, "Optional child itself should still be FAILED")
+			assert.Contains(t, lastResult.Message, tc.expectedInMsg)
+			assert.Contains(t, lastResult.Message, "Optional task cleanup failed")
+			if tc.expectedInErrMsg != "" {
+				assert.Contains(t, lastResult.Error, tc.expectedInErrMsg)
+			} else {
+				assert.Empty(t, lastResult.Error)
+			}
+
+			_, statErr := os.Stat(lastPath)
+			assert.Equal(t, tc.expectLastRun, statErr == nil, "Unexpected execution state of the last child")
+		})
+	}
+}
+
+func TestGroupExecutor_AggregateError(t *testing.T) {
+	registry := task.NewMapRegistry()
+
+	alreadyFailed := task.NewBashExecTask("already-failed", "Failed in a previous run", task.BashExecParameters{Command: "true"})
+	alreadyFailed.Status = task.StatusFailed
+	innerFailing := task.NewBashExecTask("inner-failing", "Failing nested child", task.BashExecParameters{Command: "exit 3"})
+	inner := task.NewGroupTask("inner-group", "Nested group", []*task.Task{innerFailing})
+
+	groupTask := task.NewGroupTask("outer-group", "Group with several failures", []*task.Task{alreadyFailed, inner})
+	executor, err := registry.GetExecutor(task.TaskGroup)
+	require.NoError(t, err)
+
+	resultsChan, err := executor.Execute(context.Background(), groupTask)
+	require.NoError(t, err)
+
+	var lastResult task.OutputResult
+	for result := range resultsChan {
+		lastResult = result
+	}
+	require.Equal(t, task.StatusFailed, lastResult.Status)
+
+	var groupErr *task.GroupError
+	require.True(t, errors.As(lastResult.Err, &groupErr), "Final result should carry a *GroupError, got %T", lastResult.Err)
+	require.Len(t, groupErr.Errors, 2)
+	assert.Equal(t, lastResult.Error, groupErr.Error(), "Serialized error should be the joined child errors")
+	assert.Equal(t, "Task already-failed already in FAILED state", strings.Split(lastResult.Error, "\n")[0])
+
+	var ids []string
+	for _, err := range groupErr.Errors {
+		var childErr *task.ChildTaskError
+		require.True(t, errors.As(err, &childErr), "Each aggregated error should be a *ChildTaskError, got %T", err)
+		ids = append(ids, childErr.TaskID)
+	}
+	assert.Equal(t, []string{"already-failed", "inner-group"}, ids)
+	assert.True(t, errors.Is(lastResult.Err, task.ErrChildAlreadyFailed))
+
+	// The nested group's failure is reachable through the outer aggregate
+	var innerChildErr *task.ChildTaskError
+	require.True(t, errors.As(groupErr.Errors[1].(*task.ChildTaskError).Err, &innerChildErr))
+	assert.Equal(t, "inner-failing", innerChildErr.TaskID)
+	assert.Contains(t, innerChildErr.Error(), "Task inner-failing failed:")
+
+	// Err is not part of the serialized result
+	data, err := json.Marshal(lastResult)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "Err\"")
+}
+
+func TestGroupExecutor_ForwardChildOutput(t *testing.T) {
+	registry := task.NewMapRegistry()
+
+	leaf := task.NewBashExecTask("leaf", "Nested command with output", task.BashExecParameters{Command: "echo first; echo second"})
+	inner := task.NewGroupTask("inner", "Nested group", []*task.Task{leaf})
+	inner.Parameters = task.GroupParameters{ForwardChildOutput: true}
+	outer := task.NewGroupTask("outer", "Outer group", []*task.Task{inner})
+	outer.Parameters = task.GroupParameters{ForwardChildOutput: true}
+
+	executor, err := registry.GetExecutor(task.TaskGroup)
+	require.NoError(t, err)
+	resultsChan, err := executor.Execute(context.Background(), outer)
+	require.NoError(t, err)
+
+	var forwarded strings.Builder
+	var lastResult task.OutputResult
+	for result := range resultsChan {
+		if result.TaskID == "outer" && result.Status == task.StatusRunning {
+			forwarded.WriteString(result.ResultData)
+		}
+		lastResult = result
+	}
+	require.Equal(t, task.StatusSucceeded, lastResult.Status, lastResult.Error)
+
+	// The leaf's chunks surface on the outer group's stream, tagged with their path
+	assert.Contains(t, forwarded.String(), "[inner] [leaf] first\n")
+	assert.Contains(t, forwarded.String(), "[inner] [leaf] second\n")
+
+	// Forwarding does not change the output recorded for each task
+	assert.True(t, strings.HasPrefix(inner.Output.ResultData, "first\nsecond\n"), inner.Output.ResultData)
+	assert.Equal(t, inner.Output.ResultData, lastResult.ResultData)
+}
+
+func TestGroupParameters_JSONRoundTrip(t *testing.T) {
+	group := task.NewGroupTask("g", "Group with parameters", []*task.Task{
+		task.NewBashExecTask("c", "Child", task.BashExecParameters{Command: "true"}),
+	})
+	group.Parameters = task.GroupParameters{ForwardChildOutput: true}
+
+	data, err := json.Marshal(group)
+	require.NoError(t, err)
+	var decoded task.Task
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, task.GroupParameters{ForwardChildOutput: true}, decoded.Parameters)
+}
+
+// nestedGroups wraps a single BASH_EXEC leaf in depth nested groups and returns the outermost one.
+func nestedGroups(depth int) *task.Task {
+	current := task.NewBashExecTask("leaf", "Innermost command", task.BashExecParameters{Command: "echo deep"})
+	for i := depth; i >= 1; i-- {
+		current = task.NewGroupTask(fmt.Sprintf("group-%d", i), "Nested group", []*task.Task{current})
+	}
+	return current
+}
+
+func TestGroupExecutor_MaxDepth(t *testing.T) {
+	registry := task.NewMapRegistryWithConfig(task.ExecutorConfig{MaxGroupDepth: 3})
+	executor, err := registry.GetExecutor(task.TaskGroup)
+	require.NoError(t, err)
+
+	t.Run("within limit", func(t *testing.T) {
+		resultsChan, err := executor.Execute(context.Background(), nestedGroups(3))
+		require.NoError(t, err)
+
+		var lastResult task.OutputResult
+		for result := range resultsChan {
+			lastResult = result
+		}
+		assert.Equal(t, task.StatusSucceeded, lastResult.Status, lastResult.Error)
+		assert.Contains(t, lastResult.ResultData, "deep")
+	})
+
+	t.Run("beyond limit", func(t *testing.T) {
+		outer := nestedGroups(4)
+		resultsChan, err := executor.Execute(context.Background(), outer)
+		require.NoError(t, err)
+
+		var lastResult task.OutputResult
+		for result := range resultsChan {
+			lastResult = result
+		}
+		require.Equal(t, task.StatusFailed, lastResult.Status)
+		assert.Contains(t, lastResult.Error, "group task group-4 is nested 4 levels deep, exceeding the maximum of 3")
+
+		// The leaf is never reached
+		leaf := outer.Children[0].Children[0].Children[0].Children[0]
+		assert.Equal(t, task.StatusPending, leaf.Status)
+	})
+}
+
+func TestGroupExecutor_SelfReferentialGroup(t *testing.T) {
+	registry := task.NewMapRegistry()
+	executor, err := registry.GetExecutor(task.TaskGroup)
+	require.NoError(t, err)
+
+	group := task.NewGroupTask("loop", "Group containing itself", nil)
+	group.Children = []*task.Task{group}
+
+	resultsChan, err := executor.Execute(context.Background(), group)
+	require.NoError(t, err)
+
+	var lastResult task.OutputResult
+	for result := range resultsChan {
+		lastResult = result
+	}
+	assert.Equal(t, task.StatusFailed, lastResult.Status)
+	assert.Contains(t, lastResult.Error, fmt.Sprintf("exceeding the maximum of %d", task.DefaultMaxGroupDepth))
+}
+
+func TestGroupExecutor_MaxRuntime(t *testing.T) {
+	registry := task.NewMapRegistry()
+	executor, err := registry.GetExecutor(task.TaskGroup)
+	require.NoError(t, err)
+
+	first := task.NewBashExecTask("slow-1", "Finishes within the budget", task.BashExecParameters{Command: "sleep 0.1; echo one"})
+	second := task.NewBashExecTask("slow-2", "Interrupted by the budget", task.BashExecParameters{Command: "sleep 5"})
+	third := task.NewBashExecTask("slow-3", "Never started", task.BashExecParameters{Command: "sleep 5"})
+	group := task.NewGroupTask("budgeted", "Group with a runtime budget", []*task.Task{first, second, third})
+	group.Parameters = task.GroupParameters{MaxRuntimeMs: 1000}
+
+	start := time.Now()
+	resultsChan, err := executor.Execute(context.Background(), group)
+	require.NoError(t, err)
+
+	var lastResult task.OutputResult
+	for result := range resultsChan {
+		lastResult = result
+	}
+	assert.Less(t, time.Since(start), 4*time.Second, "The budget should cut the group short")
+
+	require.Equal(t, task.StatusFailed, lastResult.Status)
+	assert.Equal(t, task.FailureTimedOut, lastResult.FailureKind)
+	assert.Contains(t, lastResult.Message, "exceeded its runtime budget of 1s after completing 1/3 child tasks")
+
+	var budgetErr *task.BudgetExceededError
+	require.True(t, errors.As(lastResult.Err, &budgetErr), "Final result should carry a *BudgetExceededError, got %T", lastResult.Err)
+	assert.Equal(t, time.Second, budgetErr.Budget)
+	assert.Equal(t, []string{"slow-1"}, budgetErr.Completed)
+	assert.Equal(t, []string{"slow-2", "slow-3"}, budgetErr.Cancelled)
+	assert.True(t, errors.Is(lastResult.Err, context.DeadlineExceeded))
+	assert.Equal(t, lastResult.Error, budgetErr.Error())
+
+	assert.Equal(t, task.StatusSucceeded, first.Status)
+	assert.Equal(t, task.StatusFailed, second.Status)
+	assert.Equal(t, task.StatusPending, third.Status)
+
+	negative := task.NewGroupTask("negative", "Negative budget", []*task.Task{
+		task.NewBashExecTask("c", "Child", task.BashExecParameters{Command: "true"}),
+	})
+	negative.Parameters = task.GroupParameters{MaxRuntimeMs: -1}
+	_, err = executor.Execute(context.Background(), negative)
+	assert.ErrorContains(t, err, "max_runtime_ms cannot be negative")
+}
+
+func TestGroupExecutor_CanceledKeepsCompletedOutput(t *testing.T) {
+	registry := task.NewMapRegistry()
+	executor, err := registry.GetExecutor(task.TaskGroup)
+	require.NoError(t, err)
+
+	first := task.NewBashExecTask("fast-1", "Completes before the cancel", task.BashExecParameters{Command: "echo one"})
+	second := task.NewBashExecTask("slow-2", "Interrupted by the cancel", task.BashExecParameters{Command: "sleep 5"})
+	third := task.NewBashExecTask("slow-3", "Never started", task.BashExecParameters{Command: "echo three"})
+	group := task.NewGroupTask("canceled", "Group canceled mid-child", []*task.Task{first, second, third})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	start := time.Now()
+	resultsChan, err := executor.Execute(ctx, group)
+	require.NoError(t, err)
+
+	var lastResult task.OutputResult
+	for result := range resultsChan {
+		if result.TaskID == group.TaskId && strings.HasPrefix(result.Message, "Completed child task 1/3") {
+			cancel()
+		}
+		lastResult = result
+	}
+	assert.Less(t, time.Since(start), 4*time.Second, "The cancel should cut the group short")
+
+	require.Equal(t, task.StatusFailed, lastResult.Status)
+	assert.Equal(t, task.FailureCancelled, lastResult.FailureKind)
+	assert.Contains(t, lastResult.Message, "canceled after completing 1/3 child tasks")
+	assert.Contains(t, lastResult.ResultData, "one\n")
+	assert.NotContains(t, lastResult.ResultData, "three")
+	assert.Equal(t, []task.ChildStatus{
+		{TaskID: "fast-1", Status: task.StatusSucceeded},
+		{TaskID: "slow-2", Status: task.StatusFailed},
+		{TaskID: "slow-3", Status: task.StatusPending},
+	}, lastResult.Payload)
+}
+
+func TestGroupExecutor_EmptyGroupSucceeds(t *testing.T) {
+	registry := task.NewMapRegistry()
+	executor, err := registry.GetExecutor(task.TaskGroup)
+	require.NoError(t, err)
+
+	run := func(t *testing.T, group *task.Task) []task.OutputResult {
+		t.Helper()
+		resultsChan, err := executor.Execute(context.Background(), group)
+		require.NoError(t, err)
+		var results []task.OutputResult
+		for result := range resultsChan {
+			results = append(results, result)
+		}
+		require.NotEmpty(t, results)
+		return results
+	}
+
+	t.Run("no children", func(t *testing.T) {
+		results := run(t, task.NewGroupTask("empty", "Empty group", nil))
+		require.Len(t, results, 1, "An empty group should report only its final result")
+		assert.Equal(t, task.StatusSucceeded, results[0].Status)
+		assert.Equal(t, "Group task has no children, nothing to run", results[0].Message)
+		assert.Equal(t, task.TaskGroup, results[0].TaskType)
+		assert.Empty(t, results[0].Error)
+	})
+
+	t.Run("children filtered out of a decoded plan", func(t *testing.T) {
+		var group task.Task
+		require.NoError(t, json.Unmarshal([]byte(`{"task_id": "filtered", "type": "GROUP", "children": []}`), &group))
+		results := run(t, &group)
+		assert.Equal(t, task.StatusSucceeded, results[len(results)-1].Status)
+	})
+
+	t.Run("nested empty group", func(t *testing.T) {
+		inner := task.NewGroupTask("inner", "Emptied group", []*task.Task{})
+		outer := task.NewGroupTask("outer", "Outer group", []*task.Task{
+			inner,
+			task.NewBashExecTask("after", "Runs after the empty group", task.BashExecParameters{Command: "echo after"}),
+		})
+		results := run(t, outer)
+		last := results[len(results)-1]
+		require.Equal(t, task.StatusSucceeded, last.Status, last.Error)
+		assert.Equal(t, task.StatusSucceeded, inner.Status)
+		assert.Contains(t, last.ResultData, "after")
+	})
+}
+
+func TestGroupExecutor_Execute_SpanIDs(t *testing.T) {
+	registry := task.NewMapRegistry()
+	inner := task.NewGroupTask("inner", "Nested group", []*task.Task{
+		task.NewBashExecTask("inner-child", "Nested child", task.BashExecParameters{Command: "echo nested"}),
+	})
+	group := task.NewGroupTask("outer", "Traced group", []*task.Task{
+		task.NewBashExecTask("first", "First child", task.BashExecParameters{Command: "echo one"}),
+		task.NewBashExecTask("second", "Second child", task.BashExecParameters{Command: "echo two"}),
+		inner,
+	})
+
+	executor, err := registry.GetExecutor(task.TaskGroup)
+	require.NoError(t, err)
+	resultsChan, err := executor.Execute(task.ContextWithSpan(context.Background(), "caller-span"), group)
+	require.NoError(t, err)
+
+	spans := make(map[string]task.OutputResult)
+	for result := range resultsChan {
+		require.NotEmpty(t, result.SpanID, "Result of %s has no span", result.TaskID)
+		if previous, ok := spans[result.TaskID]; ok {
+			assert.Equal(t, previous.SpanID, result.SpanID, "Results of %s carry different spans", result.TaskID)
+		}
+		spans[result.TaskID] = result
+	}
+	require.Equal(t, task.StatusSucceeded, spans["outer"].Status, spans["outer"].Error)
+
+	groupSpan := spans["outer"].SpanID
+	assert.Equal(t, "caller-span", spans["outer"].ParentSpanID)
+	for _, id := range []string{"first", "second", "inner"} {
+		assert.Equal(t, groupSpan, spans[id].ParentSpanID, "%s should be a child of the group's span", id)
+		assert.NotEqual(t, groupSpan, spans[id].SpanID)
+	}
+	assert.NotEqual(t, spans["first"].SpanID, spans["second"].SpanID)
+	assert.Equal(t, spans["inner"].SpanID, spans["inner-child"].ParentSpanID)
+	assert.Equal(t, groupSpan, group.Children[0].Output.ParentSpanID, "The child's recorded output carries its span too")
+}
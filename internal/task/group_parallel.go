@@ -0,0 +1,218 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// maxConcurrency returns the number of children a parallel group runs at once.
+func (p GroupParameters) maxConcurrency() int {
+	if p.MaxConcurrency > 0 {
+		return p.MaxConcurrency
+	}
+	return runtime.NumCPU()
+}
+
+// checkParallelDependencies rejects a child that depends on itself or on a later
+// sibling. Those dependencies can never be met when the group runs sequentially, and
+// in a parallel group whether they were met would depend on scheduling.
+func checkParallelDependencies(children []*Task) error {
+	siblings := make(map[string]int, len(children))
+	for i := len(children) - 1; i >= 0; i-- {
+		siblings[children[i].TaskId] = i
+	}
+	for i, child := range children {
+		for _, dep := range child.DependsOn {
+			if j, ok := siblings[dep]; ok && j >= i {
+				return invalidf(errGroupForwardDep, child.TaskId, dep)
+			}
+		}
+	}
+	return nil
+}
+
+// executeParallel runs the children of a parallel group concurrently, never more than
+// params.MaxConcurrency at a time, and sends the group's final result once all have
+// finished. A failed child does not stop its siblings. A child waits for the earlier
+// siblings it depends on before taking a worker slot, so dependency chains still run in order.
+func (e *GroupExecutor) executeParallel(ctx context.Context, group *Task, params GroupParameters, children []*Task, results chan<- OutputResult) {
+	limit := params.maxConcurrency()
+	e.config.send(ctx, results, group.describeIn(ctx, OutputResult{
+		TaskID:  group.TaskId,
+		Status:  StatusRunning,
+		Message: fmt.Sprintf(msgGroupParallel, len(children), limit),
+	}))
+
+	startTime := e.config.clock().Now()
+	outputs := newResultStore()
+
+	// The child context carries the group's runtime budget, as for sequential groups
+	var childCtx context.Context
+	var cancel context.CancelFunc
+	if budget := params.maxRuntime(); budget > 0 {
+		childCtx, cancel = withClockDeadline(ctx, e.config.clock(), startTime.Add(budget))
+	} else {
+		childCtx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	// Children already in a terminal state are not run again, but their outputs are
+	// available to their dependents
+	siblings := make(map[string]int, len(children))
+	done := make([]chan struct{}, len(children))
+	for i, child := range children {
+		if _, ok := siblings[child.TaskId]; !ok {
+			siblings[child.TaskId] = i
+		}
+		done[i] = make(chan struct{})
+		if child.Status.IsTerminal() {
+			if child.Status == StatusSucceeded {
+				outputs.set(child.TaskId, child.Output.ResultData)
+			}
+			close(done[i])
+		}
+	}
+
+	childResults := make([]OutputResult, len(children))
+	started := make([]bool, len(children))
+	slots := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i, child := range children {
+		if child.Status.IsTerminal() {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(done[i])
+
+			for _, dep := range child.DependsOn {
+				if j, ok := siblings[dep]; ok && j < i {
+					select {
+					case <-done[j]:
+					case <-childCtx.Done():
+						return
+					}
+				}
+			}
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-childCtx.Done():
+				return
+			}
+			if childCtx.Err() != nil {
+				return
+			}
+
+			started[i] = true
+			childResults[i] = e.runParallelChild(childCtx, ctx, child, outputs, results, group, params, i, len(children))
+		}()
+	}
+	wg.Wait()
+
+	// A child cut short by the parent's cancellation did not fail on its own
+	if ctx.Err() != nil {
+		processed := 0
+		var completedResults []string
+		for i, child := range children {
+			if started[i] && childResults[i].Status == StatusSucceeded {
+				processed++
+				if childResults[i].ResultData != "" {
+					completedResults = append(completedResults, childResults[i].ResultData)
+				}
+			} else if !started[i] && child.Status.IsTerminal() {
+				processed++
+			}
+		}
+		e.sendCanceled(ctx, group, children, processed, completedResults, startTime, results)
+		return
+	}
+
+	var outcome groupOutcome
+	budgetExceeded := childCtx.Err() != nil
+	var budgetErr *BudgetExceededError
+	if budgetExceeded {
+		budgetErr = &BudgetExceededError{Budget: params.maxRuntime()}
+	}
+	for i, child := range children {
+		result := child.Output
+		if started[i] {
+			result = childResults[i]
+		}
+		switch {
+		case !started[i] && !child.Status.IsTerminal(), budgetExceeded && result.Status != StatusSucceeded && isInterruption(result.FailureKind):
+			// Never started, or interrupted by the budget
+			if budgetErr != nil {
+				budgetErr.Cancelled = append(budgetErr.Cancelled, child.TaskId)
+			}
+			continue
+		case result.Status == StatusFailed && child.Optional && !started[i]:
+			outcome.warnings = append(outcome.warnings, fmt.Sprintf("Optional task %s already in FAILED state", child.TaskId))
+		case result.Status == StatusFailed && child.Optional:
+			outcome.warnings = append(outcome.warnings, fmt.Sprintf("Optional task %s failed: %s", child.TaskId, result.Error))
+		case result.Status == StatusFailed:
+			outcome.failed++
+			if started[i] {
+				outcome.errors = append(outcome.errors, &ChildTaskError{TaskID: child.TaskId, Err: childError(result)})
+			} else {
+				outcome.errors = append(outcome.errors, &ChildTaskError{TaskID: child.TaskId, Err: ErrChildAlreadyFailed})
+			}
+			if outcome.failure == "" {
+				outcome.failure = result.FailureKind
+			}
+		case started[i] && result.ResultData != "":
+			outcome.results = append(outcome.results, result.ResultData)
+		}
+		outcome.processed++
+		if budgetErr != nil {
+			budgetErr.Completed = append(budgetErr.Completed, child.TaskId)
+		}
+	}
+	if budgetErr != nil && len(budgetErr.Cancelled) > 0 {
+		outcome.budgetErr = budgetErr
+	}
+	e.sendGroupResult(ctx, group, params, children, startTime, outcome, results)
+}
+
+// runParallelChild runs one child of a parallel group once its dependencies are
+// resolved, records its output for its dependents and reports its completion.
+func (e *GroupExecutor) runParallelChild(childCtx, ctx context.Context, child *Task, outputs *resultStore, results chan<- OutputResult, group *Task, params GroupParameters, index, total int) OutputResult {
+	var result OutputResult
+	if err := resolveDependencies(child, outputs); err != nil {
+		result = child.describe(OutputResult{
+			TaskID:      child.TaskId,
+			Status:      StatusFailed,
+			Message:     "Failed to resolve child task dependencies",
+			Error:       err.Error(),
+			FailureKind: FailureValidationError,
+		})
+		child.Status = result.Status
+		child.Output = result
+	} else {
+		result = e.processChildTask(childCtx, child, results, group, params, index, total)
+	}
+
+	message := fmt.Sprintf("Completed child task %d/%d (%s)", index+1, total, result.Status)
+	switch {
+	case result.Status == StatusSucceeded:
+		outputs.set(child.TaskId, result.ResultData)
+	case child.Optional:
+		message = fmt.Sprintf("Optional child task %d/%d failed (%s), continuing", index+1, total, result.Status)
+	default:
+		message = fmt.Sprintf("Child task %d/%d failed (%s)", index+1, total, result.Status)
+	}
+	e.config.send(ctx, results, group.describeIn(ctx, OutputResult{
+		TaskID:  group.TaskId,
+		Status:  StatusRunning,
+		Message: message,
+	}))
+	return result
+}
+
+// isInterruption reports whether a child failure kind can be caused by cancelling the child.
+func isInterruption(kind FailureKind) bool {
+	return kind == FailureCancelled || kind == FailureTimedOut
+}
@@ -0,0 +1,207 @@
+package task_test
+
+import (
+	"ai-agent-v3/internal/task"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runGroup executes group and returns every result it streamed, the final one last.
+func runGroup(t *testing.T, group *task.Task) []task.OutputResult {
+	t.Helper()
+	executor, err := task.NewMapRegistry().GetExecutor(task.TaskGroup)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	resultsChan, err := executor.Execute(ctx, group)
+	require.NoError(t, err)
+
+	var results []task.OutputResult
+	for result := range resultsChan {
+		results = append(results, result)
+	}
+	require.NotEmpty(t, results)
+	return results
+}
+
+func TestGroupExecutor_Parallel_RunsChildrenConcurrently(t *testing.T) {
+	children := []*task.Task{
+		task.NewBashExecTask("p-1", "First", task.BashExecParameters{Command: "sleep 0.5; echo one"}),
+		task.NewBashExecTask("p-2", "Second", task.BashExecParameters{Command: "sleep 0.5; echo two"}),
+		task.NewBashExecTask("p-3", "Third", task.BashExecParameters{Command: "sleep 0.5; echo three"}),
+	}
+	group := task.NewGroupTask("parallel", "Parallel group", children)
+	group.Parameters = task.GroupParameters{Parallel: true, MaxConcurrency: 3}
+
+	start := time.Now()
+	results := runGroup(t, group)
+	elapsed := time.Since(start)
+
+	lastResult := results[len(results)-1]
+	require.Equal(t, task.StatusSucceeded, lastResult.Status, lastResult.Error)
+	assert.Less(t, elapsed, 1400*time.Millisecond, "Children should overlap rather than run one after another")
+	assert.Contains(t, lastResult.Message, "completed successfully with 3 child tasks")
+
+	// Outputs are combined in child order, whatever order the children finished in
+	one := strings.Index(lastResult.ResultData, "one\n")
+	two := strings.Index(lastResult.ResultData, "two\n")
+	three := strings.Index(lastResult.ResultData, "three\n")
+	require.True(t, one >= 0 && two >= 0 && three >= 0, lastResult.ResultData)
+	assert.True(t, one < two && two < three, lastResult.ResultData)
+
+	for _, child := range children {
+		assert.Equal(t, task.StatusSucceeded, child.Status)
+	}
+	for _, result := range results[:len(results)-1] {
+		if result.TaskID == group.TaskId {
+			assert.Equal(t, task.StatusRunning, result.Status, "Only the group's last result may be final")
+		}
+	}
+}
+
+func TestGroupExecutor_Parallel_MaxConcurrency(t *testing.T) {
+	running := t.TempDir()
+	var children []*task.Task
+	for i := 1; i <= 4; i++ {
+		marker := filepath.Join(running, strconv.Itoa(i))
+		children = append(children, task.NewBashExecTask(fmt.Sprintf("w-%d", i), "Worker", task.BashExecParameters{
+			// Each child reports how many children are running while it runs
+			Command: fmt.Sprintf("touch '%s'; ls '%s' | wc -l; sleep 0.3; rm '%s'", marker, running, marker),
+		}))
+	}
+	group := task.NewGroupTask("bounded", "Bounded parallel group", children)
+	group.Parameters = task.GroupParameters{Parallel: true, MaxConcurrency: 2}
+
+	start := time.Now()
+	results := runGroup(t, group)
+	elapsed := time.Since(start)
+
+	lastResult := results[len(results)-1]
+	require.Equal(t, task.StatusSucceeded, lastResult.Status, lastResult.Error)
+	assert.GreaterOrEqual(t, elapsed, 600*time.Millisecond, "Four children two at a time take two rounds")
+	assert.Contains(t, results[0].Message, "at most 2 at a time")
+	for _, child := range children {
+		count, err := strconv.Atoi(strings.TrimSpace(strings.SplitN(child.Output.ResultData, "\n", 2)[0]))
+		require.NoError(t, err, child.Output.ResultData)
+		assert.LessOrEqual(t, count, 2, "Child %s ran alongside too many others", child.TaskId)
+	}
+}
+
+func TestGroupExecutor_Parallel_FailureDoesNotStopSiblings(t *testing.T) {
+	failing := task.NewBashExecTask("fails", "Fails at once", task.BashExecParameters{Command: "exit 4"})
+	optional := task.NewBashExecTask("optional", "Optional failure", task.BashExecParameters{Command: "exit 5"})
+	optional.Optional = true
+	slow := task.NewBashExecTask("slow", "Finishes after the failure", task.BashExecParameters{Command: "sleep 0.3; echo done"})
+	group := task.NewGroupTask("parallel-fail", "Parallel group with a failure", []*task.Task{failing, optional, slow})
+	group.Parameters = task.GroupParameters{Parallel: true}
+
+	results := runGroup(t, group)
+	lastResult := results[len(results)-1]
+
+	require.Equal(t, task.StatusFailed, lastResult.Status)
+	assert.Equal(t, task.FailureExecutionError, lastResult.FailureKind)
+	assert.Contains(t, lastResult.Message, "completed with 1/3 failed tasks")
+	assert.Contains(t, lastResult.Message, "Optional task optional failed")
+	assert.Equal(t, task.StatusSucceeded, slow.Status)
+	assert.Contains(t, lastResult.ResultData, "done\n")
+
+	var groupErr *task.GroupError
+	require.True(t, errors.As(lastResult.Err, &groupErr), "Final result should carry a *GroupError, got %T", lastResult.Err)
+	require.Len(t, groupErr.Errors, 1)
+	assert.Equal(t, "fails", groupErr.Errors[0].(*task.ChildTaskError).TaskID)
+}
+
+func TestGroupExecutor_Parallel_DependsOn(t *testing.T) {
+	producer := task.NewBashExecTask("producer", "Slow producer", task.BashExecParameters{Command: "sleep 0.3; echo payload"})
+	consumer := task.NewBashExecTask("consumer", "Uses the producer's output", task.BashExecParameters{Command: "echo got ${producer.result:1}"})
+	consumer.DependsOn = []string{"producer"}
+	independent := task.NewBashExecTask("independent", "Runs right away", task.BashExecParameters{Command: "echo free"})
+	group := task.NewGroupTask("parallel-deps", "Parallel group with a dependency", []*task.Task{producer, consumer, independent})
+	group.Parameters = task.GroupParameters{Parallel: true, MaxConcurrency: 2}
+
+	results := runGroup(t, group)
+	lastResult := results[len(results)-1]
+
+	require.Equal(t, task.StatusSucceeded, lastResult.Status, lastResult.Error)
+	assert.Contains(t, consumer.Output.ResultData, "got payload\n")
+	assert.True(t, consumer.Output.StartedAt.After(producer.Output.FinishedAt) || consumer.Output.StartedAt.Equal(producer.Output.FinishedAt),
+		"The consumer must start after its dependency finished")
+}
+
+func TestGroupExecutor_Parallel_SharedGroupsAreIndependent(t *testing.T) {
+	// Several parallel groups running at once share the registry's executors
+	executor, err := task.NewMapRegistry().GetExecutor(task.TaskGroup)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 3; g++ {
+		group := task.NewGroupTask(fmt.Sprintf("group-%d", g), "Parallel group", []*task.Task{
+			task.NewBashExecTask(fmt.Sprintf("group-%d-a", g), "A", task.BashExecParameters{Command: "echo a"}),
+			task.NewBashExecTask(fmt.Sprintf("group-%d-b", g), "B", task.BashExecParameters{Command: "echo b"}),
+		})
+		group.Parameters = task.GroupParameters{Parallel: true}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resultsChan, err := executor.Execute(context.Background(), group)
+			if !assert.NoError(t, err) {
+				return
+			}
+			var lastResult task.OutputResult
+			for result := range resultsChan {
+				lastResult = result
+			}
+			assert.Equal(t, task.StatusSucceeded, lastResult.Status, lastResult.Error)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestGroupExecutor_Parallel_Parameters(t *testing.T) {
+	group := task.NewGroupTask("g", "Parallel group", []*task.Task{
+		task.NewBashExecTask("c", "Child", task.BashExecParameters{Command: "true"}),
+	})
+	group.Parameters = task.GroupParameters{Parallel: true, MaxConcurrency: 4}
+
+	data, err := json.Marshal(group)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"parallel":true`)
+	assert.Contains(t, string(data), `"max_concurrency":4`)
+	var decoded task.Task
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, task.GroupParameters{Parallel: true, MaxConcurrency: 4}, decoded.Parameters)
+
+	executor, err := task.NewMapRegistry().GetExecutor(task.TaskGroup)
+	require.NoError(t, err)
+	group.Parameters = task.GroupParameters{Parallel: true, MaxConcurrency: -1}
+	_, err = executor.Execute(context.Background(), group)
+	assert.ErrorContains(t, err, "max_concurrency cannot be negative")
+}
+
+func TestGroupExecutor_Parallel_ForwardDependency(t *testing.T) {
+	consumer := task.NewBashExecTask("consumer", "Uses a later sibling's output", task.BashExecParameters{Command: "echo got ${producer.result:1}"})
+	consumer.DependsOn = []string{"producer"}
+	producer := task.NewBashExecTask("producer", "Listed after its dependent", task.BashExecParameters{Command: "echo payload"})
+	group := task.NewGroupTask("parallel-forward", "Parallel group with a forward dependency", []*task.Task{consumer, producer})
+	group.Parameters = task.GroupParameters{Parallel: true}
+
+	executor, err := task.NewMapRegistry().GetExecutor(task.TaskGroup)
+	require.NoError(t, err)
+	_, err = executor.Execute(context.Background(), group)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "child task consumer depends on producer, which does not run before it")
+	assert.Equal(t, task.StatusPending, consumer.Status, "No child should run")
+	assert.Equal(t, task.StatusPending, producer.Status, "No child should run")
+}
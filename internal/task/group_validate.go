@@ -0,0 +1,133 @@
+package task
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// WarningKind classifies a problem found by ValidateGroup.
+type WarningKind string
+
+const (
+	// WarningMissingDependency means a child depends on a task that is not its sibling.
+	WarningMissingDependency WarningKind = "MISSING_DEPENDENCY"
+	// WarningSelfDependency means a child depends on itself.
+	WarningSelfDependency WarningKind = "SELF_DEPENDENCY"
+	// WarningLaterDependency means a child depends on a sibling that runs after it.
+	WarningLaterDependency WarningKind = "LATER_DEPENDENCY"
+	// WarningUnreachableDependency means a child depends on a sibling that can never run.
+	WarningUnreachableDependency WarningKind = "UNREACHABLE_DEPENDENCY"
+	// WarningDuplicateDependency means a child lists the same dependency more than once.
+	WarningDuplicateDependency WarningKind = "DUPLICATE_DEPENDENCY"
+	// WarningDuplicateTaskID means two siblings share a task ID, so the result of the
+	// later one shadows the earlier one for the tasks that depend on it.
+	WarningDuplicateTaskID WarningKind = "DUPLICATE_TASK_ID"
+	// WarningUndeclaredReference means a parameter references the result of a task
+	// that is not listed in depends_on.
+	WarningUndeclaredReference WarningKind = "UNDECLARED_REFERENCE"
+)
+
+// Warning messages reported by ValidateGroup
+const (
+	msgWarnMissingDependency     = "task %s depends on %s, which is not a task in group %s"
+	msgWarnSelfDependency        = "task %s depends on itself"
+	msgWarnLaterDependency       = "task %s depends on %s, which runs after it"
+	msgWarnUnreachableDependency = "task %s depends on %s, which can never run"
+	msgWarnDuplicateDependency   = "task %s lists dependency %s more than once"
+	msgWarnDuplicateTaskID       = "task ID %s is used by more than one task in group %s"
+	msgWarnUndeclaredReference   = "task %s references the result of %s, which is not listed in depends_on"
+)
+
+// Warning describes a problem in a group that ValidateGroup found without running it.
+type Warning struct {
+	// TaskID is the ID of the child the warning is about.
+	TaskID string `json:"task_id"`
+	// GroupID is the ID of the group that contains the child.
+	GroupID string      `json:"group_id"`
+	Kind    WarningKind `json:"kind"`
+	Message string      `json:"message"`
+}
+
+// ValidateGroup statically checks the children of a group task, and of the groups
+// nested in it, for dependencies that can never be satisfied and for IDs that shadow
+// each other. A child that can never run is reported along with the cause. Nothing is
+// executed; a task that is not a group has no warnings.
+func (t *Task) ValidateGroup() []Warning {
+	if t.Type != TaskGroup {
+		return nil
+	}
+
+	var warnings []Warning
+	warn := func(taskID string, kind WarningKind, format string, args ...any) {
+		warnings = append(warnings, Warning{TaskID: taskID, GroupID: t.TaskId, Kind: kind, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// Children run in order, so a dependency is only available if it comes earlier
+	position := make(map[string]int, len(t.Children))
+	for i, child := range t.Children {
+		if _, ok := position[child.TaskId]; ok {
+			warn(child.TaskId, WarningDuplicateTaskID, msgWarnDuplicateTaskID, child.TaskId, t.TaskId)
+		}
+		position[child.TaskId] = i
+	}
+
+	unreachable := make(map[string]bool)
+	for i, child := range t.Children {
+		var seen []string
+		for _, dep := range child.DependsOn {
+			if slices.Contains(seen, dep) {
+				warn(child.TaskId, WarningDuplicateDependency, msgWarnDuplicateDependency, child.TaskId, dep)
+				continue
+			}
+			seen = append(seen, dep)
+
+			depPos, ok := position[dep]
+			switch {
+			case dep == child.TaskId:
+				warn(child.TaskId, WarningSelfDependency, msgWarnSelfDependency, child.TaskId)
+			case !ok:
+				warn(child.TaskId, WarningMissingDependency, msgWarnMissingDependency, child.TaskId, dep, t.TaskId)
+			case depPos > i:
+				warn(child.TaskId, WarningLaterDependency, msgWarnLaterDependency, child.TaskId, dep)
+			case unreachable[dep]:
+				warn(child.TaskId, WarningUnreachableDependency, msgWarnUnreachableDependency, child.TaskId, dep)
+			default:
+				continue
+			}
+			unreachable[child.TaskId] = true
+		}
+
+		for _, ref := range resultReferences(child.Parameters) {
+			if !slices.Contains(child.DependsOn, ref) {
+				warn(child.TaskId, WarningUndeclaredReference, msgWarnUndeclaredReference, child.TaskId, ref)
+			}
+		}
+
+		warnings = append(warnings, child.ValidateGroup()...)
+	}
+	return warnings
+}
+
+// resultReferences returns the task IDs named by result references in the string
+// fields of params, in the order they first appear.
+func resultReferences(params any) []string {
+	if params == nil {
+		return nil
+	}
+	var ids []string
+	collect := func(s string) (string, error) {
+		for _, match := range resultReferencePattern.FindAllStringSubmatch(s, -1) {
+			if !slices.Contains(ids, match[1]) {
+				ids = append(ids, match[1])
+			}
+		}
+		return s, nil
+	}
+
+	// substituteValue works on a copy, so params is left untouched
+	copied := reflect.New(reflect.TypeOf(params)).Elem()
+	copied.Set(reflect.ValueOf(params))
+	substituteValue(copied, collect)
+	return ids
+}
@@ -0,0 +1,91 @@
+package task
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func whichTask(id string, dependsOn ...string) *Task {
+	t := NewWhichTask(id, "Locate go", WhichParameters{Name: "go"})
+	t.DependsOn = dependsOn
+	return t
+}
+
+func warningKinds(warnings []Warning) map[string][]WarningKind {
+	kinds := make(map[string][]WarningKind)
+	for _, w := range warnings {
+		kinds[w.TaskID] = append(kinds[w.TaskID], w.Kind)
+	}
+	return kinds
+}
+
+func TestValidateGroup_MissingDependency(t *testing.T) {
+	group := NewGroupTask("group", "Missing dependency", []*Task{
+		whichTask("first"),
+		whichTask("second", "first", "compile"),
+		whichTask("third", "second"),
+		whichTask("fourth", "first"),
+	})
+
+	warnings := group.ValidateGroup()
+	assert.Equal(t, map[string][]WarningKind{
+		"second": {WarningMissingDependency},
+		// third can never run because second never does
+		"third": {WarningUnreachableDependency},
+	}, warningKinds(warnings))
+	assert.Equal(t, Warning{
+		TaskID:  "second",
+		GroupID: "group",
+		Kind:    WarningMissingDependency,
+		Message: "task second depends on compile, which is not a task in group group",
+	}, warnings[0])
+}
+
+func TestValidateGroup_DuplicateDependency(t *testing.T) {
+	group := NewGroupTask("group", "Duplicate dependency", []*Task{
+		whichTask("first"),
+		whichTask("second", "first", "first"),
+	})
+
+	warnings := group.ValidateGroup()
+	assert.Equal(t, map[string][]WarningKind{"second": {WarningDuplicateDependency}}, warningKinds(warnings))
+	assert.Equal(t, "task second lists dependency first more than once", warnings[0].Message)
+}
+
+func TestValidateGroup_OrderingAndShadowing(t *testing.T) {
+	reader := NewFileReadTask("reader", "Read the located binary", FileReadParameters{FilePath: "${locate.result}"})
+	nested := NewGroupTask("nested", "Nested group", []*Task{whichTask("inner", "outer")})
+
+	group := NewGroupTask("group", "Ordering", []*Task{
+		whichTask("early", "late"),
+		whichTask("self", "self"),
+		whichTask("late"),
+		whichTask("late"),
+		reader,
+		nested,
+	})
+
+	warnings := group.ValidateGroup()
+	assert.Equal(t, map[string][]WarningKind{
+		"early":  {WarningLaterDependency},
+		"self":   {WarningSelfDependency},
+		"late":   {WarningDuplicateTaskID},
+		"reader": {WarningUndeclaredReference},
+		"inner":  {WarningMissingDependency},
+	}, warningKinds(warnings))
+	assert.Equal(t, "nested", warnings[len(warnings)-1].GroupID)
+
+	// Validation does not run anything or change the tasks
+	assert.True(t, reader.Status.IsPending())
+	assert.Equal(t, "${locate.result}", reader.Parameters.(FileReadParameters).FilePath)
+}
+
+func TestValidateGroup_Valid(t *testing.T) {
+	reader := NewFileReadTask("reader", "Read the located binary", FileReadParameters{FilePath: "${locate.result}"})
+	reader.DependsOn = []string{"locate"}
+	group := NewGroupTask("group", "Valid", []*Task{whichTask("locate"), reader})
+
+	assert.Empty(t, group.ValidateGroup())
+	assert.Nil(t, whichTask("single").ValidateGroup())
+}
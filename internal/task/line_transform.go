@@ -0,0 +1,84 @@
+package task
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Line transform names accepted in BashExecParameters.LineTransform.
+const (
+	// LineTransformNone forwards output lines unchanged.
+	LineTransformNone = ""
+	// LineTransformStripANSI removes ANSI escape sequences such as color codes.
+	LineTransformStripANSI = "strip-ansi"
+
+	errUnknownLineTransform = "unknown line transform: %q"
+)
+
+// ansiEscapePattern matches CSI sequences (colors, cursor movement) and OSC sequences (titles, hyperlinks).
+var ansiEscapePattern = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)`)
+
+// lineTransforms holds the built-in line transforms by name.
+var lineTransforms = map[string]func(string) string{
+	LineTransformStripANSI: stripANSI,
+}
+
+// lookupLineTransform returns the transform registered under name.
+// A nil function is returned for LineTransformNone.
+func lookupLineTransform(name string) (func(string) string, error) {
+	if name == LineTransformNone {
+		return nil, nil
+	}
+	transform, ok := lineTransforms[name]
+	if !ok {
+		return nil, fmt.Errorf(errUnknownLineTransform, name)
+	}
+	return transform, nil
+}
+
+// stripANSI removes ANSI escape sequences from line.
+func stripANSI(line string) string {
+	return ansiEscapePattern.ReplaceAllString(line, "")
+}
+
+// whitespaceTransform returns the line transform selected by the whitespace options
+// of a FILE_READ task, or nil if it changes nothing.
+func whitespaceTransform(params FileReadParameters) func(string) string {
+	width := params.ExpandTabs
+	switch {
+	case params.TrimTrailingWhitespace && width > 0:
+		return func(line string) string { return trimTrailingWhitespace(expandTabs(line, width)) }
+	case params.TrimTrailingWhitespace:
+		return trimTrailingWhitespace
+	case width > 0:
+		return func(line string) string { return expandTabs(line, width) }
+	}
+	return nil
+}
+
+// trimTrailingWhitespace removes spaces and tabs from the end of line.
+func trimTrailingWhitespace(line string) string {
+	return strings.TrimRight(line, " \t")
+}
+
+// expandTabs replaces each tab in line with spaces up to the next tab stop,
+// placed every width columns. Columns are counted in runes.
+func expandTabs(line string, width int) string {
+	if !strings.Contains(line, "\t") {
+		return line
+	}
+	var b strings.Builder
+	column := 0
+	for _, r := range line {
+		if r == '\t' {
+			spaces := width - column%width
+			b.WriteString(strings.Repeat(" ", spaces))
+			column += spaces
+			continue
+		}
+		b.WriteRune(r)
+		column++
+	}
+	return b.String()
+}
@@ -1,23 +1,84 @@
 package task
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 )
 
+const errListInvalidSortBy = "invalid sort_by '%s': must be one of %s"
+
+// listSortOrders holds the comparison of each SortBy order. Ties are broken by name
+// ascending so that every order is deterministic.
+var listSortOrders = map[string]func(a, b listedEntry) int{
+	ListSortNameAsc:     func(a, b listedEntry) int { return 0 },
+	ListSortNameDesc:    func(a, b listedEntry) int { return strings.Compare(b.name, a.name) },
+	ListSortSizeAsc:     func(a, b listedEntry) int { return cmp.Compare(a.size(), b.size()) },
+	ListSortSizeDesc:    func(a, b listedEntry) int { return cmp.Compare(b.size(), a.size()) },
+	ListSortModTimeAsc:  func(a, b listedEntry) int { return a.modTime().Compare(b.modTime()) },
+	ListSortModTimeDesc: func(a, b listedEntry) int { return b.modTime().Compare(a.modTime()) },
+}
+
+// listedEntry is a directory entry with the file info used to format and sort it.
+type listedEntry struct {
+	name  string
+	isDir bool
+	info  fs.FileInfo
+	err   error
+}
+
+func (l listedEntry) size() int64 {
+	if l.info == nil {
+		return 0
+	}
+	return l.info.Size()
+}
+
+func (l listedEntry) modTime() time.Time {
+	if l.info == nil {
+		return time.Time{}
+	}
+	return l.info.ModTime()
+}
+
+// sortEntries orders entries by the named SortBy order, which must be valid.
+func sortEntries(entries []listedEntry, sortBy string) {
+	if sortBy == "" {
+		sortBy = ListSortNameAsc
+	}
+	compare := listSortOrders[sortBy]
+	slices.SortFunc(entries, func(a, b listedEntry) int {
+		if c := compare(a, b); c != 0 {
+			return c
+		}
+		return strings.Compare(a.name, b.name)
+	})
+}
+
 // ListDirectoryExecutor handles the execution of ListDirectoryCommand.
-type ListDirectoryExecutor struct{}
+type ListDirectoryExecutor struct {
+	config ExecutorConfig
+}
+
+var _ TaskExecutor = (*ListDirectoryExecutor)(nil)
 
 // NewListDirectoryExecutor creates a new ListDirectoryExecutor.
 func NewListDirectoryExecutor() *ListDirectoryExecutor {
 	return &ListDirectoryExecutor{}
 }
 
+// NewListDirectoryExecutorWithConfig creates a new ListDirectoryExecutor using the shared executor config.
+func NewListDirectoryExecutorWithConfig(cfg ExecutorConfig) *ListDirectoryExecutor {
+	return &ListDirectoryExecutor{config: cfg}
+}
+
 // Execute lists the contents of the directory specified in the ListDirectoryCommand.
 // It expects the cmd argument to be of type ListDirectoryCommand.
 // Returns a channel for results and an error if the command type is wrong or execution setup fails.
@@ -35,22 +96,32 @@ func (e *ListDirectoryExecutor) Execute(ctx context.Context, listCmd *Task) (<-c
 		return terminalChan, nil
 	}
 
+	if sortBy := listCmd.Parameters.(ListDirectoryParameters).SortBy; sortBy != "" && listSortOrders[sortBy] == nil {
+		return nil, invalidf(errListInvalidSortBy, sortBy, strings.Join([]string{
+			ListSortNameAsc, ListSortNameDesc, ListSortSizeAsc, ListSortSizeDesc, ListSortModTimeAsc, ListSortModTimeDesc,
+		}, ", "))
+	}
+
 	results := make(chan OutputResult, 1) // Buffered channel for the single final result
 
 	go func() {
-		startTime := time.Now()
+		startTime := e.config.clock().Now()
 		var finalErr error
 		var directoryListing string
 
 		// Defer closing the channel *after* the status send defer runs
 		defer close(results)
 
+		ctx, cancel := e.config.withTimeout(ctx, listCmd)
+		defer cancel()
+
 		// Defer sending the final status message
 		defer func() {
-			duration := time.Since(startTime)
+			duration := e.config.since(startTime)
 			var finalStatus TaskStatus
 			var errMsg string
 			var message string
+			var failure FailureKind
 			effectiveErr := finalErr
 
 			// Final context check, prioritizing context error if no primary error occurred
@@ -67,6 +138,7 @@ func (e *ListDirectoryExecutor) Execute(ctx context.Context, listCmd *Task) (<-c
 			if effectiveErr != nil {
 				finalStatus = StatusFailed
 				errMsg = effectiveErr.Error()
+				failure = failureKind(effectiveErr)
 				if errors.Is(effectiveErr, context.Canceled) {
 					message = "Directory listing cancelled."
 				} else if errors.Is(effectiveErr, context.DeadlineExceeded) {
@@ -81,13 +153,16 @@ func (e *ListDirectoryExecutor) Execute(ctx context.Context, listCmd *Task) (<-c
 			}
 
 			// Send final result
-			results <- OutputResult{
-				TaskID:     listCmd.TaskId,
-				Status:     finalStatus,
-				Message:    message,
-				Error:      errMsg,
-				ResultData: directoryListing, // Include listing data on success
-			}
+			finalResult := listCmd.describe(OutputResult{
+				TaskID:      listCmd.TaskId,
+				Status:      finalStatus,
+				Message:     message,
+				Error:       errMsg,
+				FailureKind: failure,
+				ResultData:  directoryListing, // Include listing data on success
+			})
+			finalResult.setTimes(e.config.clock(), startTime)
+			e.config.send(ctx, results, finalResult)
 		}()
 
 		// Check for immediate cancellation before starting work
@@ -99,8 +174,17 @@ func (e *ListDirectoryExecutor) Execute(ctx context.Context, listCmd *Task) (<-c
 			// Continue processing
 		}
 
-		// Get absolute path
-		absPath, err := filepath.Abs(listCmd.Parameters.(ListDirectoryParameters).Path)
+		// Resolve the directory against the working directory and root, then make it absolute
+		dirPath := listCmd.Parameters.(ListDirectoryParameters).Path
+		if dirPath == "" {
+			dirPath = "."
+		}
+		resolvedPath, err := e.config.resolvePath(dirPath, listCmd.Parameters.(ListDirectoryParameters).WorkingDirectory)
+		if err != nil {
+			finalErr = err
+			return
+		}
+		absPath, err := filepath.Abs(resolvedPath)
 		if err != nil {
 			finalErr = fmt.Errorf("failed to get absolute path for '%s': %w", listCmd.Parameters.(ListDirectoryParameters).Path, err)
 			return
@@ -122,21 +206,29 @@ func (e *ListDirectoryExecutor) Execute(ctx context.Context, listCmd *Task) (<-c
 			return
 		}
 
+		// Order the entries explicitly rather than relying on the order they were read in
+		listed := make([]listedEntry, 0, len(entries))
+		for _, entry := range entries {
+			info, err := entry.Info()
+			listed = append(listed, listedEntry{name: entry.Name(), isDir: entry.IsDir(), info: info, err: err})
+		}
+		sortEntries(listed, listCmd.Parameters.(ListDirectoryParameters).SortBy)
+
 		// Format the listing
 		var builder strings.Builder
 		builder.WriteString(fmt.Sprintf("Listing for %s:\n", absPath))
 		var detailErrors []string // Collect errors getting file info
-		for _, entry := range entries {
-			info, err := entry.Info()
-			if err != nil {
-				detailErr := fmt.Sprintf("  [ERROR] %s: %v\n", entry.Name(), err)
+		for _, entry := range listed {
+			if entry.err != nil {
+				detailErr := fmt.Sprintf("  [ERROR] %s: %v\n", entry.name, entry.err)
 				builder.WriteString(detailErr)
 				detailErrors = append(detailErrors, detailErr)
 				continue // Skip processing this entry further
 			}
+			info := entry.info
 
 			entryType := "FILE"
-			if entry.IsDir() {
+			if entry.isDir {
 				entryType = "DIR " // Add space for alignment
 			}
 
@@ -147,7 +239,7 @@ func (e *ListDirectoryExecutor) Execute(ctx context.Context, listCmd *Task) (<-c
 				info.Mode().String(), // Permissions (e.g., -rw-r--r--)
 				modTimeStr,
 				info.Size(), // Size in bytes
-				entry.Name(),
+				entry.name,
 			))
 		}
 		directoryListing = builder.String()
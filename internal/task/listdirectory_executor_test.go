@@ -261,3 +261,60 @@ func TestListDirectoryExecutor_Execute_TerminalTaskHandling(t *testing.T) {
 		})
 	}
 }
+
+func TestListDirectoryExecutor_Execute_SortBy(t *testing.T) {
+	tempDir := t.TempDir()
+	now := time.Now()
+	files := []struct {
+		name string
+		size int
+		age  time.Duration
+	}{
+		{name: "alpha.txt", size: 30, age: time.Hour},
+		{name: "bravo.txt", size: 10, age: 3 * time.Hour},
+		{name: "charlie.txt", size: 20, age: 2 * time.Hour},
+	}
+	for _, f := range files {
+		path := filepath.Join(tempDir, f.name)
+		require.NoError(t, os.WriteFile(path, []byte(strings.Repeat("x", f.size)), 0644))
+		require.NoError(t, os.Chtimes(path, now.Add(-f.age), now.Add(-f.age)))
+	}
+
+	testCases := []struct {
+		sortBy   string
+		expected []string
+	}{
+		{sortBy: "", expected: []string{"alpha.txt", "bravo.txt", "charlie.txt"}},
+		{sortBy: ListSortNameAsc, expected: []string{"alpha.txt", "bravo.txt", "charlie.txt"}},
+		{sortBy: ListSortNameDesc, expected: []string{"charlie.txt", "bravo.txt", "alpha.txt"}},
+		{sortBy: ListSortSizeAsc, expected: []string{"bravo.txt", "charlie.txt", "alpha.txt"}},
+		{sortBy: ListSortSizeDesc, expected: []string{"alpha.txt", "charlie.txt", "bravo.txt"}},
+		{sortBy: ListSortModTimeAsc, expected: []string{"bravo.txt", "charlie.txt", "alpha.txt"}},
+		{sortBy: ListSortModTimeDesc, expected: []string{"alpha.txt", "charlie.txt", "bravo.txt"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run("SortBy_"+tc.sortBy, func(t *testing.T) {
+			cmd := NewListDirectoryTask("test-list-sort", "Sorted listing", ListDirectoryParameters{Path: tempDir, SortBy: tc.sortBy})
+			resultsChan, err := NewListDirectoryExecutor().Execute(context.Background(), cmd)
+			require.NoError(t, err)
+
+			finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+			require.True(t, received, "Did not receive final result")
+			require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+
+			var names []string
+			for _, line := range strings.Split(strings.TrimSpace(finalResult.ResultData), "\n")[1:] {
+				fields := strings.Fields(line)
+				names = append(names, fields[len(fields)-1])
+			}
+			assert.Equal(t, tc.expected, names)
+		})
+	}
+
+	t.Run("Invalid", func(t *testing.T) {
+		cmd := NewListDirectoryTask("test-list-sort-invalid", "Invalid order", ListDirectoryParameters{Path: tempDir, SortBy: "size"})
+		_, err := NewListDirectoryExecutor().Execute(context.Background(), cmd)
+		assert.ErrorContains(t, err, "invalid sort_by 'size'")
+	})
+}
@@ -0,0 +1,269 @@
+package task
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Error constants for ManifestExecutor
+const (
+	// Command validation errors
+	errManifestInvalidCommandType = "invalid command type for ManifestExecutor: %T"
+	errManifestEmptyOutputPath    = "output_path cannot be empty"
+	errManifestInvalidFormat      = "invalid format '%s': must be '%s' or '%s'"
+
+	// File operation errors
+	errManifestResolvePath  = "failed to resolve path: %w"
+	errManifestNotDir       = "path '%s' is not a directory"
+	errManifestWalkFailed   = "failed to walk '%s': %w"
+	errManifestHashFailed   = "failed to hash '%s': %w"
+	errManifestWriteFailed  = "failed to write manifest '%s': %w"
+	errManifestEncodeFailed = "failed to encode manifest: %w"
+
+	// Status messages
+	msgManifestCancelled = "Manifest generation cancelled."
+	msgManifestTimedOut  = "Manifest generation timed out."
+	msgManifestFailed    = "Manifest generation failed: %v"
+	msgManifestSucceeded = "Wrote manifest of %d files under '%s' to '%s'."
+)
+
+// ManifestEntry is the digest of a single file listed in a manifest.
+type ManifestEntry struct {
+	// Path is relative to the manifest's root and uses forward slashes.
+	Path string `json:"path"`
+	// Digest is the hex-encoded SHA-256 of the file's content.
+	Digest string `json:"digest"`
+}
+
+// ManifestExecutor handles the execution of ManifestTask.
+// It hashes every regular file below a directory and writes the digests to a manifest file.
+type ManifestExecutor struct {
+	config ExecutorConfig
+}
+
+var (
+	_ TaskExecutor = (*ManifestExecutor)(nil)
+	_ Compensator  = (*ManifestExecutor)(nil)
+)
+
+// NewManifestExecutor creates a new ManifestExecutor.
+func NewManifestExecutor() *ManifestExecutor {
+	return &ManifestExecutor{}
+}
+
+// NewManifestExecutorWithConfig creates a new ManifestExecutor using the shared executor config.
+func NewManifestExecutorWithConfig(cfg ExecutorConfig) *ManifestExecutor {
+	return &ManifestExecutor{config: cfg}
+}
+
+// Execute implements the TaskExecutor interface for ManifestTask.
+// The final result's ResultData holds the manifest as written and Payload holds
+// the []ManifestEntry, sorted by path.
+func (e *ManifestExecutor) Execute(ctx context.Context, manifestCmd *Task) (<-chan OutputResult, error) {
+	if manifestCmd.Type != TaskManifest {
+		return nil, fmt.Errorf(errManifestInvalidCommandType, manifestCmd)
+	}
+
+	// Check if task is already in a terminal state
+	terminalChan, err := HandleTerminalTask(manifestCmd.TaskId, manifestCmd.Status, manifestCmd.Output)
+	if err != nil || terminalChan != nil {
+		return terminalChan, err
+	}
+
+	params := manifestCmd.Parameters.(ManifestParameters)
+	if params.OutputPath == "" {
+		return nil, errors.New(errManifestEmptyOutputPath)
+	}
+	if params.Format == "" {
+		params.Format = ManifestFormatSHA256Sums
+	}
+	if params.Format != ManifestFormatSHA256Sums && params.Format != ManifestFormatJSON {
+		return nil, fmt.Errorf(errManifestInvalidFormat, params.Format, ManifestFormatSHA256Sums, ManifestFormatJSON)
+	}
+
+	results := make(chan OutputResult, 1)
+	go func() {
+		defer close(results)
+
+		ctx, cancel := e.config.withTimeout(ctx, manifestCmd)
+		defer cancel()
+
+		startedAt := e.config.clock().Now()
+		manifestCmd.Status = StatusRunning
+		finalResult := e.writeManifest(ctx, manifestCmd.TaskId, params)
+
+		manifestCmd.Status = finalResult.Status
+		finalResult.setTimes(e.config.clock(), startedAt)
+		manifestCmd.UpdateOutput(&finalResult)
+		e.config.send(ctx, results, finalResult)
+	}()
+
+	return results, nil
+}
+
+// writeManifest hashes the tree, writes the manifest and returns the final result.
+func (e *ManifestExecutor) writeManifest(ctx context.Context, taskID string, params ManifestParameters) OutputResult {
+	path := params.Path
+	if path == "" {
+		path = "."
+	}
+	root, err := e.config.resolvePath(path, params.WorkingDirectory)
+	if err != nil {
+		return createManifestErrorResult(taskID, fmt.Errorf(errManifestResolvePath, err))
+	}
+	outputPath, err := e.config.resolvePath(params.OutputPath, params.WorkingDirectory)
+	if err != nil {
+		return createManifestErrorResult(taskID, fmt.Errorf(errManifestResolvePath, err))
+	}
+
+	entries, err := hashTree(ctx, root, outputPath)
+	if err != nil {
+		return createManifestErrorResult(taskID, err)
+	}
+	content, err := formatManifest(entries, params.Format)
+	if err != nil {
+		return createManifestErrorResult(taskID, err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return createManifestErrorResult(taskID, err)
+	}
+	// Stage and rename so that a reader never sees a partial manifest
+	unlock := e.config.fileLocks().Lock(outputPath)
+	defer unlock()
+	tempPath, err := stageFile(outputPath, content, e.config.fileMode())
+	if err != nil {
+		return createManifestErrorResult(taskID, err)
+	}
+	if err := os.Rename(tempPath, outputPath); err != nil {
+		os.Remove(tempPath)
+		return createManifestErrorResult(taskID, fmt.Errorf(errManifestWriteFailed, outputPath, err))
+	}
+
+	return OutputResult{
+		TaskID:     taskID,
+		Status:     StatusSucceeded,
+		Message:    fmt.Sprintf(msgManifestSucceeded, len(entries), root, outputPath),
+		ResultData: content,
+		Payload:    entries,
+	}
+}
+
+// hashTree returns the digest of every regular file below root, in lexical order.
+// Symlinks and other special files are skipped, as is the file at exclude.
+func hashTree(ctx context.Context, root, exclude string) ([]ManifestEntry, error) {
+	var entries []ManifestEntry
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if p == root {
+			if !d.IsDir() {
+				return fmt.Errorf(errManifestNotDir, root)
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || p == exclude {
+			return nil
+		}
+
+		digest, err := hashFile(p)
+		if err != nil {
+			return fmt.Errorf(errManifestHashFailed, p, err)
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, ManifestEntry{Path: filepath.ToSlash(rel), Digest: digest})
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return nil, err
+		}
+		return nil, fmt.Errorf(errManifestWalkFailed, root, err)
+	}
+	return entries, nil
+}
+
+// hashFile returns the hex-encoded SHA-256 of the file at path.
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// formatManifest renders entries in the given format.
+func formatManifest(entries []ManifestEntry, format string) (string, error) {
+	if format == ManifestFormatJSON {
+		digests := make(map[string]string, len(entries))
+		for _, entry := range entries {
+			digests[entry.Path] = entry.Digest
+		}
+		data, err := json.MarshalIndent(digests, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf(errManifestEncodeFailed, err)
+		}
+		return string(data) + "\n", nil
+	}
+
+	var b strings.Builder
+	for _, entry := range entries {
+		// Two spaces separate the digest from the path, as sha256sum writes it
+		fmt.Fprintf(&b, "%s  %s\n", entry.Digest, entry.Path)
+	}
+	return b.String(), nil
+}
+
+// createManifestErrorResult constructs the final OutputResult for a failed ManifestTask.
+func createManifestErrorResult(taskID string, err error) OutputResult {
+	var message string
+	switch {
+	case errors.Is(err, context.Canceled):
+		message = msgManifestCancelled
+	case errors.Is(err, context.DeadlineExceeded):
+		message = msgManifestTimedOut
+	default:
+		message = fmt.Sprintf(msgManifestFailed, err)
+	}
+	return OutputResult{
+		TaskID:      taskID,
+		Status:      StatusFailed,
+		Message:     message,
+		Error:       err.Error(),
+		Err:         err,
+		FailureKind: failureKind(err),
+	}
+}
+
+// PrepareCompensation implements Compensator by backing up the manifest file
+// so that a rollback restores its previous content or removes it if it was created.
+func (e *ManifestExecutor) PrepareCompensation(ctx context.Context, manifestCmd *Task) (Compensation, error) {
+	params := manifestCmd.Parameters.(ManifestParameters)
+	outputPath, err := e.config.resolvePath(params.OutputPath, params.WorkingDirectory)
+	if err != nil {
+		return nil, err
+	}
+	return fileBackupCompensation(outputPath)
+}
@@ -0,0 +1,134 @@
+package task
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runManifest(t *testing.T, ctx context.Context, params ManifestParameters) OutputResult {
+	t.Helper()
+	cmd := NewManifestTask("manifest-test", "Write manifest", params)
+	resultsChan, err := NewManifestExecutor().Execute(ctx, cmd)
+	require.NoError(t, err)
+
+	finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+	assert.Equal(t, finalResult.Status, cmd.Status)
+	return finalResult
+}
+
+func sha256Hex(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// newManifestTree creates a small release tree and returns its root.
+func newManifestTree(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "bin"), 0755))
+	require.NoError(t, os.Mkdir(filepath.Join(root, "empty"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "README"), []byte("release notes\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "bin", "tool"), []byte("binary"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "bin", "empty.dat"), nil, 0644))
+	require.NoError(t, os.Symlink("README", filepath.Join(root, "link")))
+	return root
+}
+
+func TestManifestExecutor_Execute_SHA256Sums(t *testing.T) {
+	root := newManifestTree(t)
+	outputPath := filepath.Join(root, "SHA256SUMS")
+
+	finalResult := runManifest(t, context.Background(), ManifestParameters{Path: root, OutputPath: outputPath})
+	require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+	assert.Contains(t, finalResult.Message, "Wrote manifest of 3 files")
+
+	// Entries are sorted by path; the symlink, directories and the manifest itself are skipped
+	expected := []ManifestEntry{
+		{Path: "README", Digest: sha256Hex("release notes\n")},
+		{Path: "bin/empty.dat", Digest: sha256Hex("")},
+		{Path: "bin/tool", Digest: sha256Hex("binary")},
+	}
+	assert.Equal(t, expected, finalResult.Payload)
+
+	content, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Equal(t, sha256Hex("release notes\n")+"  README\n"+
+		sha256Hex("")+"  bin/empty.dat\n"+
+		sha256Hex("binary")+"  bin/tool\n", string(content))
+	assert.Equal(t, string(content), finalResult.ResultData)
+}
+
+func TestManifestExecutor_Execute_JSON(t *testing.T) {
+	root := newManifestTree(t)
+	outputPath := filepath.Join(t.TempDir(), "manifest.json")
+
+	finalResult := runManifest(t, context.Background(), ManifestParameters{
+		BaseParameters: BaseParameters{WorkingDirectory: root},
+		OutputPath:     outputPath,
+		Format:         ManifestFormatJSON,
+	})
+	require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+
+	content, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	var digests map[string]string
+	require.NoError(t, json.Unmarshal(content, &digests))
+	assert.Equal(t, map[string]string{
+		"README":        sha256Hex("release notes\n"),
+		"bin/empty.dat": sha256Hex(""),
+		"bin/tool":      sha256Hex("binary"),
+	}, digests)
+}
+
+func TestManifestExecutor_Execute_ConfiguredFileMode(t *testing.T) {
+	root := newManifestTree(t)
+	outputPath := filepath.Join(t.TempDir(), "SHA256SUMS")
+
+	cmd := NewManifestTask("manifest-mode", "Write manifest with configured mode", ManifestParameters{Path: root, OutputPath: outputPath})
+	resultsChan, err := NewManifestExecutorWithConfig(ExecutorConfig{FileMode: 0600}).Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+	require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+
+	info, err := os.Stat(outputPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestManifestExecutor_Execute_Failures(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "file.txt")
+	require.NoError(t, os.WriteFile(filePath, []byte("x"), 0644))
+
+	finalResult := runManifest(t, context.Background(), ManifestParameters{Path: filePath, OutputPath: filePath + ".sums"})
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Contains(t, finalResult.Error, "is not a directory")
+	assert.NoFileExists(t, filePath+".sums")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	outputPath := filepath.Join(t.TempDir(), "SHA256SUMS")
+	finalResult = runManifest(t, ctx, ManifestParameters{Path: newManifestTree(t), OutputPath: outputPath})
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Equal(t, FailureCancelled, finalResult.FailureKind)
+	assert.Equal(t, msgManifestCancelled, finalResult.Message)
+	assert.NoFileExists(t, outputPath)
+
+	executor := NewManifestExecutor()
+	_, err := executor.Execute(context.Background(), NewManifestTask("no-output", "No output", ManifestParameters{Path: "."}))
+	assert.ErrorContains(t, err, "output_path cannot be empty")
+
+	_, err = executor.Execute(context.Background(), NewManifestTask("bad-format", "Bad format", ManifestParameters{OutputPath: "x", Format: "md5"}))
+	assert.ErrorContains(t, err, "invalid format 'md5'")
+}
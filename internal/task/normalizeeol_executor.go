@@ -0,0 +1,208 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Error constants for NormalizeEOLExecutor
+const (
+	// Command validation errors
+	errNormalizeEOLInvalidCommandType = "invalid command type for NormalizeEOLExecutor: %T"
+	errNormalizeEOLEmptyFilePath      = "file path cannot be empty"
+	errNormalizeEOLInvalidTarget      = "unsupported line ending target '%s' (supported: lf, crlf)"
+
+	// File operation errors
+	errNormalizeEOLResolveFilePath = "failed to resolve file path: %w"
+	errNormalizeEOLLockFailed      = "failed to lock file '%s': %w"
+	errNormalizeEOLReadFailed      = "failed to read file '%s': %w"
+	errNormalizeEOLWriteFailed     = "failed to replace file '%s': %w"
+
+	// Status messages
+	msgNormalizeEOLCancelled = "Line ending normalization cancelled."
+	msgNormalizeEOLTimedOut  = "Line ending normalization timed out."
+	msgNormalizeEOLFailed    = "Line ending normalization failed: %v"
+	msgNormalizeEOLSucceeded = "Converted %d line endings in '%s' to %s."
+	msgNormalizeEOLUnchanged = "Line endings in '%s' are already %s."
+)
+
+// NormalizeEOLExecutor handles the execution of NormalizeEOLTask.
+// It rewrites every line ending of a file as either LF or CRLF.
+type NormalizeEOLExecutor struct {
+	fs     FileSystem
+	config ExecutorConfig
+}
+
+var _ TaskExecutor = (*NormalizeEOLExecutor)(nil)
+
+// NewNormalizeEOLExecutor creates a new NormalizeEOLExecutor.
+func NewNormalizeEOLExecutor() *NormalizeEOLExecutor {
+	return NewNormalizeEOLExecutorWithConfig(ExecutorConfig{})
+}
+
+// NewNormalizeEOLExecutorWithConfig creates a new NormalizeEOLExecutor using the shared executor config.
+func NewNormalizeEOLExecutorWithConfig(cfg ExecutorConfig) *NormalizeEOLExecutor {
+	return &NormalizeEOLExecutor{
+		fs:     &defaultFileSystem{locks: cfg.fileLocks(), dirMode: cfg.dirMode()},
+		config: cfg,
+	}
+}
+
+// Execute implements the TaskExecutor interface for NormalizeEOLTask.
+// On success ResultData holds the number of line endings that were converted.
+func (e *NormalizeEOLExecutor) Execute(ctx context.Context, eolCmd *Task) (<-chan OutputResult, error) {
+	if eolCmd.Type != TaskNormalizeEOL {
+		return nil, fmt.Errorf(errNormalizeEOLInvalidCommandType, eolCmd)
+	}
+
+	// Check if task is already in a terminal state
+	terminalChan, err := HandleTerminalTask(eolCmd.TaskId, eolCmd.Status, eolCmd.Output)
+	if err != nil || terminalChan != nil {
+		return terminalChan, err
+	}
+
+	params := eolCmd.Parameters.(NormalizeEOLParameters)
+	if params.FilePath == "" {
+		return nil, errors.New(errNormalizeEOLEmptyFilePath)
+	}
+	if params.Target != EOLTargetLF && params.Target != EOLTargetCRLF {
+		return nil, fmt.Errorf(errNormalizeEOLInvalidTarget, params.Target)
+	}
+
+	results := make(chan OutputResult, 1)
+	go func() {
+		defer close(results)
+
+		ctx, cancel := e.config.withTimeout(ctx, eolCmd)
+		defer cancel()
+
+		startedAt := e.config.clock().Now()
+		eolCmd.Status = StatusRunning
+		filePath, changed, err := e.normalize(ctx, params)
+
+		finalResult := createNormalizeEOLResult(eolCmd.TaskId, filePath, params.Target, changed, err)
+		eolCmd.Status = finalResult.Status
+		finalResult.setTimes(e.config.clock(), startedAt)
+		eolCmd.UpdateOutput(&finalResult)
+		e.config.send(ctx, results, finalResult)
+	}()
+
+	return results, nil
+}
+
+// normalize rewrites the file described by params and returns its resolved path and
+// the number of line endings that were converted. A file that needs no changes is not rewritten.
+func (e *NormalizeEOLExecutor) normalize(ctx context.Context, params NormalizeEOLParameters) (string, int, error) {
+	if err := ctx.Err(); err != nil {
+		return "", 0, err
+	}
+
+	filePath, err := e.config.resolvePath(params.FilePath, params.WorkingDirectory)
+	if err != nil {
+		return "", 0, fmt.Errorf(errNormalizeEOLResolveFilePath, err)
+	}
+
+	unlock, err := e.fs.LockFile(filePath)
+	if err != nil {
+		return filePath, 0, fmt.Errorf(errNormalizeEOLLockFailed, filePath, err)
+	}
+	defer unlock()
+
+	info, err := e.fs.Stat(filePath)
+	if err != nil {
+		return filePath, 0, fmt.Errorf(errNormalizeEOLReadFailed, filePath, err)
+	}
+	content, err := e.fs.ReadFile(filePath)
+	if err != nil {
+		return filePath, 0, fmt.Errorf(errNormalizeEOLReadFailed, filePath, err)
+	}
+
+	normalized, changed := normalizeLineEndings(content, params.Target)
+	if changed == 0 {
+		return filePath, 0, nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return filePath, 0, err
+	}
+
+	// Stage and rename so the file is never left half converted
+	tempPath, err := stageFile(filePath, string(normalized), info.Mode().Perm())
+	if err != nil {
+		return filePath, 0, fmt.Errorf(errNormalizeEOLWriteFailed, filePath, err)
+	}
+	if err := os.Rename(tempPath, filePath); err != nil {
+		os.Remove(tempPath)
+		return filePath, 0, fmt.Errorf(errNormalizeEOLWriteFailed, filePath, err)
+	}
+	return filePath, changed, nil
+}
+
+// normalizeLineEndings converts every line ending in content to target and returns the
+// result together with the number of line endings that had to change.
+// A lone carriage return is not treated as a line ending and is left as is.
+func normalizeLineEndings(content []byte, target string) ([]byte, int) {
+	var out bytes.Buffer
+	out.Grow(len(content))
+	changed := 0
+	for i := 0; i < len(content); i++ {
+		b := content[i]
+		switch {
+		case b == '\r' && i+1 < len(content) && content[i+1] == '\n':
+			if target == EOLTargetLF {
+				changed++
+				out.WriteByte('\n')
+			} else {
+				out.WriteString("\r\n")
+			}
+			i++
+		case b == '\n':
+			if target == EOLTargetCRLF {
+				changed++
+				out.WriteString("\r\n")
+			} else {
+				out.WriteByte('\n')
+			}
+		default:
+			out.WriteByte(b)
+		}
+	}
+	return out.Bytes(), changed
+}
+
+// createNormalizeEOLResult constructs the final OutputResult for a NormalizeEOLTask.
+func createNormalizeEOLResult(taskID, filePath, target string, changed int, err error) OutputResult {
+	if err == nil {
+		message := fmt.Sprintf(msgNormalizeEOLSucceeded, changed, filePath, target)
+		if changed == 0 {
+			message = fmt.Sprintf(msgNormalizeEOLUnchanged, filePath, target)
+		}
+		return OutputResult{
+			TaskID:     taskID,
+			Status:     StatusSucceeded,
+			Message:    message,
+			ResultData: strconv.Itoa(changed),
+		}
+	}
+
+	var message string
+	switch {
+	case errors.Is(err, context.Canceled):
+		message = msgNormalizeEOLCancelled
+	case errors.Is(err, context.DeadlineExceeded):
+		message = msgNormalizeEOLTimedOut
+	default:
+		message = fmt.Sprintf(msgNormalizeEOLFailed, err)
+	}
+	return OutputResult{
+		TaskID:      taskID,
+		Status:      StatusFailed,
+		Message:     message,
+		Error:       err.Error(),
+		FailureKind: failureKind(err),
+	}
+}
@@ -0,0 +1,105 @@
+package task
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runNormalizeEOL(t *testing.T, filePath, target string) OutputResult {
+	t.Helper()
+	cmd := NewNormalizeEOLTask("normalize-eol", "Normalize line endings", NormalizeEOLParameters{
+		FilePath: filePath,
+		Target:   target,
+	})
+	resultsChan, err := NewNormalizeEOLExecutor().Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+	require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+	return finalResult
+}
+
+func TestNormalizeEOLExecutor_Execute(t *testing.T) {
+	mixed := "one\r\ntwo\nthree\r\nfour\n\rfive"
+
+	testCases := []struct {
+		target   string
+		expected string
+		changed  string
+	}{
+		{target: EOLTargetLF, expected: "one\ntwo\nthree\nfour\n\rfive", changed: "2"},
+		{target: EOLTargetCRLF, expected: "one\r\ntwo\r\nthree\r\nfour\r\n\rfive", changed: "2"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.target, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "mixed.txt")
+			require.NoError(t, os.WriteFile(filePath, []byte(mixed), 0600))
+
+			finalResult := runNormalizeEOL(t, filePath, tc.target)
+			assert.Equal(t, tc.changed, finalResult.ResultData)
+			assert.Contains(t, finalResult.Message, "Converted "+tc.changed+" line endings")
+
+			content, err := os.ReadFile(filePath)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, string(content))
+			info, err := os.Stat(filePath)
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "Mode should be preserved")
+
+			// A second run finds nothing to convert and leaves the file alone
+			before := info.ModTime()
+			require.NoError(t, os.Chtimes(filePath, before.Add(-time.Hour), before.Add(-time.Hour)))
+			finalResult = runNormalizeEOL(t, filePath, tc.target)
+			assert.Equal(t, "0", finalResult.ResultData)
+			assert.Contains(t, finalResult.Message, "already "+tc.target)
+
+			content, err = os.ReadFile(filePath)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, string(content))
+			info, err = os.Stat(filePath)
+			require.NoError(t, err)
+			assert.True(t, info.ModTime().Equal(before.Add(-time.Hour)), "File should not be rewritten")
+		})
+	}
+}
+
+func TestNormalizeEOLExecutor_Execute_InvalidParameters(t *testing.T) {
+	executor := NewNormalizeEOLExecutor()
+
+	_, err := executor.Execute(context.Background(), NewNormalizeEOLTask("eol-target", "Bad target", NormalizeEOLParameters{
+		FilePath: "file.txt",
+		Target:   "cr",
+	}))
+	assert.ErrorContains(t, err, "unsupported line ending target 'cr'")
+
+	_, err = executor.Execute(context.Background(), NewNormalizeEOLTask("eol-path", "No path", NormalizeEOLParameters{
+		Target: EOLTargetLF,
+	}))
+	assert.ErrorContains(t, err, "file path cannot be empty")
+
+	_, err = executor.Execute(context.Background(), NewTouchTask("eol-type", "Wrong type", TouchParameters{FilePath: "x"}))
+	assert.Error(t, err)
+}
+
+func TestNormalizeEOLExecutor_Execute_MissingFile(t *testing.T) {
+	cmd := NewNormalizeEOLTask("eol-missing", "Missing file", NormalizeEOLParameters{
+		FilePath: filepath.Join(t.TempDir(), "missing.txt"),
+		Target:   EOLTargetLF,
+	})
+	resultsChan, err := NewNormalizeEOLExecutor().Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Contains(t, finalResult.Error, "failed to read file")
+	assert.Equal(t, StatusFailed, cmd.Status)
+}
@@ -1,11 +1,18 @@
 package task
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
+	"unicode/utf8"
 )
 
+// resultDataChunkSize is the number of ResultData bytes escaped at a time by WriteJSON.
+const resultDataChunkSize = 32 * 1024
+
 // CombineOutputResults reads all OutputResult messages from the provided channel
 // until it closes or the provided context is cancelled.
 // It returns a single OutputResult summarizing the execution.
@@ -23,6 +30,11 @@ import (
 //
 // This function blocks until the resultsChan is closed or the context is cancelled.
 func CombineOutputResults(ctx context.Context, resultsChan <-chan OutputResult) OutputResult {
+	return combineOutputResults(ctx, resultsChan, nil)
+}
+
+// combineOutputResults is CombineOutputResults, also passing each received result to observe, if set.
+func combineOutputResults(ctx context.Context, resultsChan <-chan OutputResult, observe func(OutputResult)) OutputResult {
 	var concatenatedData strings.Builder
 	var lastMsg OutputResult
 	lastMsg = OutputResult{} // Initialize for empty channel case
@@ -37,6 +49,9 @@ func CombineOutputResults(ctx context.Context, resultsChan <-chan OutputResult)
 				return summaryResult
 			}
 			// Process received message
+			if observe != nil {
+				observe(result)
+			}
 			if result.ResultData != "" {
 				concatenatedData.WriteString(result.ResultData)
 			}
@@ -45,12 +60,65 @@ func CombineOutputResults(ctx context.Context, resultsChan <-chan OutputResult)
 		case <-ctx.Done():
 			// Context cancelled
 			return OutputResult{
-				TaskID:     lastMsg.TaskID, // Use ID from last message seen, if any
-				Status:     StatusFailed,
-				Message:    fmt.Sprintf("Result collection cancelled for command %s.", lastMsg.TaskID),
-				Error:      ctx.Err().Error(),
-				ResultData: concatenatedData.String(), // Include data collected so far
+				TaskID:      lastMsg.TaskID, // Use ID from last message seen, if any
+				Status:      StatusFailed,
+				Message:     fmt.Sprintf("Result collection cancelled for command %s.", lastMsg.TaskID),
+				Error:       ctx.Err().Error(),
+				FailureKind: failureKind(ctx.Err()),
+				ResultData:  concatenatedData.String(), // Include data collected so far
 			}
 		}
 	}
 }
+
+// WriteJSON writes the JSON encoding of r to w, followed by a newline.
+// The output decodes to the same value as json.Marshal would produce, but
+// ResultData is escaped and written in chunks so that a large result does not
+// have to be buffered in full a second time.
+func (r OutputResult) WriteJSON(w io.Writer) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+
+	// Encode everything except ResultData, which is appended below
+	meta := r
+	meta.ResultData = ""
+	if err := enc.Encode(meta); err != nil {
+		return err
+	}
+	if r.ResultData == "" {
+		_, err := w.Write(buf.Bytes())
+		return err
+	}
+
+	// Drop the closing brace and newline so the object can be extended
+	buf.Truncate(buf.Len() - 2)
+	buf.WriteString(`,"resultData":"`)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return err
+	}
+
+	data := r.ResultData
+	for len(data) > 0 {
+		end := min(resultDataChunkSize, len(data))
+		// Never split a multi-byte character across chunks
+		for end < len(data) && end > 0 && !utf8.RuneStart(data[end]) {
+			end--
+		}
+		if end == 0 {
+			end = min(resultDataChunkSize, len(data))
+		}
+
+		buf.Reset()
+		if err := enc.Encode(data[:end]); err != nil {
+			return err
+		}
+		// Strip the surrounding quotes and trailing newline added by the encoder
+		if _, err := w.Write(buf.Bytes()[1 : buf.Len()-2]); err != nil {
+			return err
+		}
+		data = data[end:]
+	}
+
+	_, err := io.WriteString(w, "\"}\n")
+	return err
+}
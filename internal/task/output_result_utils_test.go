@@ -1,7 +1,9 @@
 package task
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"sync"
@@ -266,10 +268,11 @@ func TestCombineOutputResults(t *testing.T) {
 		actualResult := CombineOutputResults(ctx, resultsChan)
 
 		expectedResult := OutputResult{
-			Status:     StatusFailed,
-			Error:      context.Canceled.Error(),
-			Message:    "Result collection cancelled for command .", // CommandID is empty
-			ResultData: "",
+			Status:      StatusFailed,
+			Error:       context.Canceled.Error(),
+			FailureKind: FailureCancelled,
+			Message:     "Result collection cancelled for command .", // CommandID is empty
+			ResultData:  "",
 		}
 		if diff := cmp.Diff(expectedResult, actualResult); diff != "" {
 			t.Errorf("CombineOutputResults mismatch (-want +got):\n%s", diff)
@@ -297,11 +300,12 @@ func TestCombineOutputResults(t *testing.T) {
 		wg.Wait() // Ensure goroutine finishes before assertion
 
 		expectedResult := OutputResult{
-			TaskID:     "cancel-mid-1", // Should have ID from last message read
-			Status:     StatusFailed,
-			Error:      context.Canceled.Error(),
-			Message:    "Result collection cancelled for command cancel-mid-1.",
-			ResultData: "Part 1.Part 2.", // Data collected before cancel
+			TaskID:      "cancel-mid-1", // Should have ID from last message read
+			Status:      StatusFailed,
+			Error:       context.Canceled.Error(),
+			FailureKind: FailureCancelled,
+			Message:     "Result collection cancelled for command cancel-mid-1.",
+			ResultData:  "Part 1.Part 2.", // Data collected before cancel
 		}
 		if diff := cmp.Diff(expectedResult, actualResult); diff != "" {
 			t.Errorf("CombineOutputResults mismatch (-want +got):\n%s", diff)
@@ -309,3 +313,74 @@ func TestCombineOutputResults(t *testing.T) {
 	})
 
 }
+
+// maxWriteRecorder records the size of the largest single Write call.
+type maxWriteRecorder struct {
+	bytes.Buffer
+	maxWrite int
+}
+
+func (w *maxWriteRecorder) Write(p []byte) (int, error) {
+	w.maxWrite = max(w.maxWrite, len(p))
+	return w.Buffer.Write(p)
+}
+
+func TestOutputResult_WriteJSON(t *testing.T) {
+	var large strings.Builder
+	for i := 0; large.Len() < 4*resultDataChunkSize; i++ {
+		fmt.Fprintf(&large, "line %d: \"quoted\" <tag> & tab\t héllo 世界\n", i)
+	}
+
+	testCases := []struct {
+		name   string
+		result OutputResult
+	}{
+		{
+			name:   "Empty ResultData",
+			result: OutputResult{TaskID: "empty", Status: StatusSucceeded, Message: "Done"},
+		},
+		{
+			name: "Small ResultData",
+			result: OutputResult{TaskID: "small", Status: StatusFailed, Message: "Failed", Error: "boom",
+				ResultData: "partial output\n", OffsetReached: 15},
+		},
+		{
+			name:   "Large ResultData",
+			result: OutputResult{TaskID: "large", Status: StatusSucceeded, Message: "Read file", ResultData: large.String()},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var w maxWriteRecorder
+			if err := tc.result.WriteJSON(&w); err != nil {
+				t.Fatalf("WriteJSON failed: %v", err)
+			}
+
+			var decoded OutputResult
+			if err := json.Unmarshal(w.Bytes(), &decoded); err != nil {
+				t.Fatalf("Streamed JSON does not parse: %v\n%s", err, w.String())
+			}
+			if diff := cmp.Diff(tc.result, decoded); diff != "" {
+				t.Errorf("Decoded result mismatch (-want +got):\n%s", diff)
+			}
+
+			// The streamed form must decode identically to the standard encoding
+			standard, err := json.Marshal(tc.result)
+			if err != nil {
+				t.Fatalf("json.Marshal failed: %v", err)
+			}
+			var fromStandard OutputResult
+			if err := json.Unmarshal(standard, &fromStandard); err != nil {
+				t.Fatalf("Standard JSON does not parse: %v", err)
+			}
+			if diff := cmp.Diff(fromStandard, decoded); diff != "" {
+				t.Errorf("Streamed and standard encodings differ (-standard +streamed):\n%s", diff)
+			}
+
+			if len(tc.result.ResultData) > 2*resultDataChunkSize && w.maxWrite >= len(tc.result.ResultData) {
+				t.Errorf("Expected ResultData to be written in chunks, largest write was %d bytes", w.maxWrite)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sourcegraph/go-diff/diff"
+)
+
+// Error constants for patches read from PatchReader
+const (
+	errPatchReaderTree   = "patch_reader is not supported with base_directory"
+	errPatchReaderFailed = "failed to read patch: %w"
+)
+
+// fileDiffPatcher is implemented by Patchers that can apply a patch that was
+// already parsed. Patches read from PatchReader are parsed as they arrive, so with
+// such a Patcher their text is never held in memory as a whole.
+type fileDiffPatcher interface {
+	applyFileDiffs(ctx context.Context, originalContent []byte, fileDiffs []*diff.FileDiff, opts PatchOptions) ([]byte, error)
+}
+
+// loadedPatch is the patch of a PATCH_FILE task, either as text or, when it was
+// read from PatchReader, as the file diffs parsed from it.
+type loadedPatch struct {
+	content   []byte
+	fileDiffs []*diff.FileDiff
+	parsed    bool
+	// parseErr is reported when the patch is applied, as it is for a patch given as text
+	parseErr error
+}
+
+// loadTaskPatch returns the patch of a single-file PATCH_FILE task. PatchReader is
+// only used when neither Patch nor PatchPath is set.
+func (e *PatchFileExecutor) loadTaskPatch(params PatchFileParameters) (loadedPatch, error) {
+	if params.Patch != "" || params.PatchPath != "" || params.PatchReader == nil {
+		content, err := e.loadPatch(params)
+		return loadedPatch{content: content}, err
+	}
+	return readPatch(params.PatchReader)
+}
+
+// readPatch parses a patch incrementally from r.
+func readPatch(r io.Reader) (loadedPatch, error) {
+	tracked := &trackingReader{r: r, blank: true}
+	fileDiffs, err := diff.NewMultiFileDiffReader(tracked).ReadAllFiles()
+	if tracked.err != nil {
+		return loadedPatch{}, fmt.Errorf(errPatchReaderFailed, tracked.err)
+	}
+	if tracked.blank {
+		// Like an empty Patch, a blank patch changes nothing
+		return loadedPatch{}, nil
+	}
+	if err != nil {
+		return loadedPatch{parsed: true, parseErr: fmt.Errorf("failed to parse patch: %v", err)}, nil
+	}
+	return loadedPatch{fileDiffs: fileDiffs, parsed: true}, nil
+}
+
+// mode returns the file mode set by the patch's extended headers, if any.
+func (p loadedPatch) mode() (os.FileMode, bool, error) {
+	if !p.parsed {
+		return patchFileMode(p.content)
+	}
+	if len(p.fileDiffs) != 1 {
+		return 0, false, nil
+	}
+	return fileDiffMode(p.fileDiffs[0])
+}
+
+// applyLoadedPatch applies patch to the original content with the executor's Patcher.
+func (e *PatchFileExecutor) applyLoadedPatch(ctx context.Context, originalContent []byte, patch loadedPatch, opts PatchOptions) ([]byte, error) {
+	if !patch.parsed {
+		return e.applyPatch(ctx, originalContent, patch.content, opts)
+	}
+	if patch.parseErr != nil {
+		return nil, e.mapPatchError(patch.parseErr, string(originalContent))
+	}
+	if patcher, ok := e.patcher.(fileDiffPatcher); ok {
+		patchedContent, err := patcher.applyFileDiffs(ctx, originalContent, patch.fileDiffs, opts)
+		if err != nil {
+			return nil, e.mapPatchError(err, string(originalContent))
+		}
+		return patchedContent, nil
+	}
+
+	// Other Patchers only accept the patch as text
+	content, err := diff.PrintMultiFileDiff(patch.fileDiffs)
+	if err != nil {
+		return nil, e.mapPatchError(fmt.Errorf("%w: %v", errParseFailed, err), string(originalContent))
+	}
+	return e.applyPatch(ctx, originalContent, content, opts)
+}
+
+// trackingReader records whether anything but whitespace was read from r,
+// and the first read error other than io.EOF.
+type trackingReader struct {
+	r     io.Reader
+	blank bool
+	err   error
+}
+
+func (t *trackingReader) Read(p []byte) (int, error) {
+	n, err := t.r.Read(p)
+	if t.blank && len(bytes.TrimSpace(p[:n])) > 0 {
+		t.blank = false
+	}
+	if err != nil && err != io.EOF && t.err == nil {
+		t.err = err
+	}
+	return n, err
+}
@@ -0,0 +1,153 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// largePatchFixture returns a file of numHunks*10 lines and a patch changing one line in every ten.
+func largePatchFixture(numHunks int) (string, string) {
+	var original strings.Builder
+	var patch strings.Builder
+	patch.WriteString("--- a/big.txt\n+++ b/big.txt\n")
+	for i := 0; i < numHunks; i++ {
+		base := i * 10
+		for j := 0; j < 10; j++ {
+			fmt.Fprintf(&original, "line %d\n", base+j)
+		}
+		fmt.Fprintf(&patch, "@@ -%d,2 +%d,2 @@\n-line %d\n+changed %d\n line %d\n", base+1, base+1, base, base, base+1)
+	}
+	return original.String(), patch.String()
+}
+
+// chunkedReader delivers patch through a pipe in small writes, as a generator would.
+func chunkedReader(patch string, chunkSize int) io.Reader {
+	pr, pw := io.Pipe()
+	go func() {
+		for len(patch) > 0 {
+			n := min(chunkSize, len(patch))
+			if _, err := pw.Write([]byte(patch[:n])); err != nil {
+				return
+			}
+			patch = patch[n:]
+		}
+		pw.Close()
+	}()
+	return pr
+}
+
+func runPatchFile(t *testing.T, executor *PatchFileExecutor, params PatchFileParameters) OutputResult {
+	t.Helper()
+	cmd := NewPatchFileTask("patch-reader", "Apply streamed patch", params)
+	resultsChan, err := executor.Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	results := collectPatchTestResults(t, resultsChan, 10*time.Second)
+	require.NotEmpty(t, results)
+	return results[len(results)-1]
+}
+
+func TestPatchFileExecutor_Execute_PatchReaderMatchesString(t *testing.T) {
+	original, patch := largePatchFixture(2000)
+	stringPath := createPatchTestTempFile(t, t.TempDir(), "big.txt", original)
+	readerPath := createPatchTestTempFile(t, t.TempDir(), "big.txt", original)
+
+	fromString := runPatchFile(t, NewPatchFileExecutor(), PatchFileParameters{FilePath: stringPath, Patch: patch})
+	fromReader := runPatchFile(t, NewPatchFileExecutor(), PatchFileParameters{
+		FilePath:    readerPath,
+		PatchReader: chunkedReader(patch, 4096),
+	})
+	require.Equal(t, StatusSucceeded, fromString.Status, fromString.Error)
+	require.Equal(t, StatusSucceeded, fromReader.Status, fromReader.Error)
+
+	patched := readPatchTestFileContent(t, stringPath)
+	assert.Contains(t, patched, "changed 19990\nline 19991\n")
+	assert.Equal(t, patched, readPatchTestFileContent(t, readerPath))
+	assert.Equal(t, strings.Replace(fromString.Message, stringPath, readerPath, 1), fromReader.Message)
+}
+
+func TestPatchFileExecutor_Execute_PatchReaderFailuresMatchString(t *testing.T) {
+	testCases := []struct {
+		name  string
+		patch string
+	}{
+		{name: "Context mismatch", patch: "--- a/f.txt\n+++ b/f.txt\n@@ -1,2 +1,2 @@\n one\n-three\n+3\n"},
+		{name: "Malformed hunk header", patch: "--- a/f.txt\n+++ b/f.txt\n@@ -1,2 @@\n one\n"},
+		{name: "No file diffs", patch: "not a patch\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stringPath := createPatchTestTempFile(t, t.TempDir(), "f.txt", "one\ntwo\n")
+			readerPath := createPatchTestTempFile(t, t.TempDir(), "f.txt", "one\ntwo\n")
+
+			fromString := runPatchFile(t, NewPatchFileExecutor(), PatchFileParameters{FilePath: stringPath, Patch: tc.patch})
+			fromReader := runPatchFile(t, NewPatchFileExecutor(), PatchFileParameters{FilePath: readerPath, PatchReader: strings.NewReader(tc.patch)})
+			require.Equal(t, StatusFailed, fromString.Status)
+			assert.Equal(t, fromString.Status, fromReader.Status)
+			assert.Equal(t, fromString.Error, fromReader.Error)
+			assert.Equal(t, fromString.FailureKind, fromReader.FailureKind)
+			assert.Equal(t, "one\ntwo\n", readPatchTestFileContent(t, readerPath))
+		})
+	}
+}
+
+func TestPatchFileExecutor_Execute_PatchReaderEdgeCases(t *testing.T) {
+	t.Run("Blank patch changes nothing", func(t *testing.T) {
+		filePath := createPatchTestTempFile(t, t.TempDir(), "f.txt", "keep\n")
+		finalResult := runPatchFile(t, NewPatchFileExecutor(), PatchFileParameters{FilePath: filePath, PatchReader: strings.NewReader(" \n\n")})
+		require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+		assert.Equal(t, "keep\n", readPatchTestFileContent(t, filePath))
+	})
+
+	t.Run("Read error fails the task", func(t *testing.T) {
+		filePath := createPatchTestTempFile(t, t.TempDir(), "f.txt", "keep\n")
+		reader := io.MultiReader(strings.NewReader("--- a/f.txt\n"), iotest.ErrReader(errors.New("connection reset")))
+		finalResult := runPatchFile(t, NewPatchFileExecutor(), PatchFileParameters{FilePath: filePath, PatchReader: reader})
+		assert.Equal(t, StatusFailed, finalResult.Status)
+		assert.Contains(t, finalResult.Error, "failed to read patch: connection reset")
+		assert.Equal(t, "keep\n", readPatchTestFileContent(t, filePath))
+	})
+
+	t.Run("Inline patch takes precedence", func(t *testing.T) {
+		filePath := createPatchTestTempFile(t, t.TempDir(), "f.txt", "a\n")
+		finalResult := runPatchFile(t, NewPatchFileExecutor(), PatchFileParameters{
+			FilePath:    filePath,
+			Patch:       "--- a/f.txt\n+++ b/f.txt\n@@ -1 +1 @@\n-a\n+inline\n",
+			PatchReader: iotest.ErrReader(errors.New("must not be read")),
+		})
+		require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+		assert.Equal(t, "inline\n", readPatchTestFileContent(t, filePath))
+	})
+
+	t.Run("Custom patcher receives the patch as text", func(t *testing.T) {
+		filePath := createPatchTestTempFile(t, t.TempDir(), "f.txt", "a\n")
+		patcher := &recordingPatcher{content: []byte("canned\n")}
+		patch := "--- a/f.txt\n+++ b/f.txt\n@@ -1,1 +1,1 @@\n-a\n+b\n"
+
+		finalResult := runPatchFile(t, NewPatchFileExecutorWithConfig(ExecutorConfig{Patcher: patcher}), PatchFileParameters{
+			FilePath:    filePath,
+			PatchReader: strings.NewReader(patch),
+		})
+		require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+		assert.Equal(t, []string{"a\n|" + patch}, patcher.calls)
+		assert.Equal(t, "canned\n", readPatchTestFileContent(t, filePath))
+	})
+
+	t.Run("Tree mode is rejected", func(t *testing.T) {
+		_, err := NewPatchFileExecutor().Execute(context.Background(), NewPatchFileTask("tree", "Tree", PatchFileParameters{
+			BaseDirectory: t.TempDir(),
+			PatchReader:   strings.NewReader(repoRootedPatch),
+		}))
+		assert.ErrorContains(t, err, "patch_reader is not supported with base_directory")
+	})
+}
@@ -0,0 +1,319 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sourcegraph/go-diff/diff"
+)
+
+// Error constants for applying a patch to a directory tree
+const (
+	errTreeFilePathConflict = "file_path and base_directory cannot both be set for PATCH_FILE"
+	errTreeExpectedResult   = "expected_result is not supported with base_directory"
+	errTreeExpectedSHA      = "expected_sha is not supported with base_directory"
+	errTreeBestEffort       = "best_effort is not supported with base_directory"
+	errTreeNegativeStrip    = "strip cannot be negative, got %d"
+	errTreeResolveBaseDir   = "failed to resolve base directory: %w"
+	errTreeNoFiles          = "patch does not contain any file diffs"
+	errTreeStripTooLong     = "cannot strip %d path component(s) from '%s'"
+	errTreeOutsideBaseDir   = "patch path '%s' is not within base directory '%s'"
+	errTreeDuplicateFile    = "patch modifies '%s' more than once"
+	errTreePatchFailed      = "failed to patch %s: %w"
+	errTreeRemoveFailed     = "failed to remove file %s: %w"
+	errTreeDeleteNotEmpty   = "file has content the deletion does not remove"
+
+	msgTreeSuccess = "Successfully patched %d file(s) under %s"
+)
+
+// treePatchTarget is a single file of a patch applied to a directory tree.
+type treePatchTarget struct {
+	relPath  string // Path relative to the base directory after stripping
+	path     string // Resolved path of the file
+	fileDiff *diff.FileDiff
+	original []byte
+	existed  bool
+	patched  []byte
+	remove   bool // The patch deletes the file
+	mode     os.FileMode
+	setMode  bool
+}
+
+// executeTree runs a PATCH_FILE task that has a BaseDirectory.
+// Every file diff is applied in memory before anything is written, so a patch that
+// does not apply leaves the tree untouched. If writing fails part way, the files
+// already written are restored.
+func (e *PatchFileExecutor) executeTree(ctx context.Context, patchCmd *Task, params PatchFileParameters) (<-chan OutputResult, error) {
+	if params.FilePath != "" {
+		return nil, errors.New(errTreeFilePathConflict)
+	}
+	if params.ExpectedResult != "" {
+		return nil, errors.New(errTreeExpectedResult)
+	}
+	if len(params.ExpectedSHA) > 0 {
+		return nil, errors.New(errTreeExpectedSHA)
+	}
+	if params.BestEffort {
+		return nil, errors.New(errTreeBestEffort)
+	}
+	if params.PatchReader != nil && params.Patch == "" && params.PatchPath == "" {
+		return nil, errors.New(errPatchReaderTree)
+	}
+	if params.Strip < 0 {
+		return nil, fmt.Errorf(errTreeNegativeStrip, params.Strip)
+	}
+
+	results := make(chan OutputResult, 1)
+	go func() {
+		defer close(results)
+		startedAt := e.config.clock().Now()
+
+		ctx, cancel := e.config.withTimeout(ctx, patchCmd)
+		defer cancel()
+
+		finalResult := e.patchTree(ctx, patchCmd, params)
+		patchCmd.Status = finalResult.Status
+		finalResult.setTimes(e.config.clock(), startedAt)
+		patchCmd.UpdateOutput(&finalResult)
+		e.config.send(ctx, results, finalResult)
+	}()
+
+	return results, nil
+}
+
+// patchTree applies the patch and returns the final result.
+func (e *PatchFileExecutor) patchTree(ctx context.Context, patchCmd *Task, params PatchFileParameters) OutputResult {
+	if err := ctx.Err(); err != nil {
+		return formatResult(patchCmd, StatusFailed, "File patching cancelled.", err)
+	}
+
+	baseDir, targets, err := e.planTree(params)
+	if err != nil {
+		return formatResult(patchCmd, StatusFailed, fmt.Sprintf("Failed to apply patch: %v", err), err)
+	}
+
+	// Lock in path order so that concurrent tree patches cannot deadlock
+	locked := make([]*treePatchTarget, len(targets))
+	copy(locked, targets)
+	sort.Slice(locked, func(i, j int) bool { return locked[i].path < locked[j].path })
+	for _, target := range locked {
+		unlock, err := e.fs.LockFile(target.path)
+		if err != nil {
+			return formatResult(patchCmd, StatusFailed, fmt.Sprintf("Failed to lock file: %v", err), err)
+		}
+		defer unlock()
+	}
+
+	opts := PatchOptions{IgnoreTrailingWhitespace: params.IgnoreTrailingWhitespace}
+	for _, target := range targets {
+		if err := e.applyTreeTarget(ctx, patchCmd.TaskId, target, opts); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return formatResult(patchCmd, StatusFailed, "File patching cancelled before writing to file.", ctxErr)
+			}
+			err = fmt.Errorf(errTreePatchFailed, target.relPath, err)
+			return formatResult(patchCmd, StatusFailed, fmt.Sprintf("Failed to apply patch: %v", err), err)
+		}
+		if !target.remove && len(target.patched) == 0 && !params.allowsEmptyResult() {
+			err := invalidf(errEmptyResult, target.path)
+			return formatResult(patchCmd, StatusFailed, fmt.Sprintf("Failed to apply patch: %v", err), err)
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return formatResult(patchCmd, StatusFailed, "File patching cancelled before writing to file.", err)
+	}
+
+	if err := e.writeTree(targets); err != nil {
+		return formatResult(patchCmd, StatusFailed, fmt.Sprintf("Failed to write patched files: %v", err), err)
+	}
+
+	finalResult := formatResult(patchCmd, StatusSucceeded, fmt.Sprintf(msgTreeSuccess, len(targets), baseDir), nil)
+	if params.IncludeDiff {
+		var combined strings.Builder
+		for _, target := range targets {
+			combined.WriteString(unifiedDiff(filepath.ToSlash(target.relPath), target.original, target.patched))
+		}
+		finalResult.ResultData = combined.String()
+	}
+	return finalResult
+}
+
+// planTree loads and parses the patch and resolves the file each diff applies to.
+func (e *PatchFileExecutor) planTree(params PatchFileParameters) (string, []*treePatchTarget, error) {
+	baseDir, err := e.config.resolvePath(params.BaseDirectory, params.WorkingDirectory)
+	if err != nil {
+		return "", nil, fmt.Errorf(errTreeResolveBaseDir, err)
+	}
+
+	patchContent, err := e.loadPatch(params)
+	if err != nil {
+		return baseDir, nil, err
+	}
+	fileDiffs, err := diff.ParseMultiFileDiff(patchContent)
+	if err != nil {
+		return baseDir, nil, fmt.Errorf("%w: %v", errParseFailed, err)
+	}
+	if len(fileDiffs) == 0 {
+		return baseDir, nil, invalidf(errTreeNoFiles)
+	}
+
+	targets := make([]*treePatchTarget, 0, len(fileDiffs))
+	seen := make(map[string]bool, len(fileDiffs))
+	for _, fileDiff := range fileDiffs {
+		name := fileDiff.NewName
+		if name == "/dev/null" {
+			name = fileDiff.OrigName
+		}
+		relPath, err := stripPathComponents(name, params.Strip)
+		if err != nil {
+			return baseDir, nil, err
+		}
+		path, err := e.config.resolvePath(relPath, baseDir)
+		if err != nil {
+			return baseDir, nil, err
+		}
+		if rel, err := filepath.Rel(baseDir, path); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return baseDir, nil, invalidf(errTreeOutsideBaseDir, name, baseDir)
+		}
+		if seen[path] {
+			return baseDir, nil, invalidf(errTreeDuplicateFile, relPath)
+		}
+		seen[path] = true
+
+		targets = append(targets, &treePatchTarget{
+			relPath:  relPath,
+			path:     path,
+			fileDiff: fileDiff,
+			remove:   fileDiff.NewName == "/dev/null",
+		})
+	}
+	return baseDir, targets, nil
+}
+
+// applyTreeTarget reads the target's current content and computes its patched content.
+func (e *PatchFileExecutor) applyTreeTarget(ctx context.Context, taskID string, target *treePatchTarget, opts PatchOptions) error {
+	existed, _, err := e.fileExists(target.path)
+	if err != nil {
+		return err
+	}
+	target.existed = existed
+	if target.original, err = e.readOriginalFile(target.path); err != nil {
+		return err
+	}
+	e.config.logf("patch task %s: original content of %s: %s", taskID, target.path, e.config.loggedContent(target.original))
+
+	// A deletion is applied as an ordinary diff so that its hunks are checked
+	// against the file instead of removing whatever the file holds
+	fileDiff := *target.fileDiff
+	if target.remove {
+		fileDiff.NewName = fileDiff.OrigName
+	}
+	filePatch, err := diff.PrintFileDiff(&fileDiff)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errParseFailed, err)
+	}
+	if target.patched, err = e.applyPatch(ctx, target.original, filePatch, opts); err != nil {
+		return err
+	}
+	if target.remove && len(target.patched) > 0 {
+		return fmt.Errorf("%w: %s", errHunkMismatch, errTreeDeleteNotEmpty)
+	}
+	if target.mode, target.setMode, err = patchFileMode(filePatch); err != nil {
+		return err
+	}
+
+	e.config.logf("patch task %s: patched content of %s: %s", taskID, target.path, e.config.loggedContent(target.patched))
+	return nil
+}
+
+// writeTree writes, removes or changes the mode of every target in order.
+// If one of them fails, the targets already written are restored.
+func (e *PatchFileExecutor) writeTree(targets []*treePatchTarget) error {
+	for i, target := range targets {
+		if err := e.writeTreeTarget(target); err != nil {
+			var rollbackErrs []error
+			for j := i; j >= 0; j-- {
+				if rollbackErr := e.restoreTreeTarget(targets[j]); rollbackErr != nil {
+					rollbackErrs = append(rollbackErrs, rollbackErr)
+				}
+			}
+			return errors.Join(append([]error{err}, rollbackErrs...)...)
+		}
+	}
+	return nil
+}
+
+// writeTreeTarget makes the change described by a single target.
+func (e *PatchFileExecutor) writeTreeTarget(target *treePatchTarget) error {
+	if target.remove {
+		if err := e.fs.Remove(target.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf(errTreeRemoveFailed, target.path, err)
+		}
+		return nil
+	}
+
+	if err := e.writePatchedFile(target.path, target.patched); err != nil {
+		return err
+	}
+	if target.setMode {
+		if err := e.fs.Chmod(target.path, target.mode); err != nil {
+			return fmt.Errorf(errChmodFailed, target.path, target.mode, err)
+		}
+	}
+	return nil
+}
+
+// restoreTreeTarget puts back the content a target had before the patch.
+func (e *PatchFileExecutor) restoreTreeTarget(target *treePatchTarget) error {
+	if !target.existed {
+		if err := e.fs.Remove(target.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf(errRollbackFailed, target.path, err)
+		}
+		return nil
+	}
+	if err := e.writePatchedFile(target.path, target.original); err != nil {
+		return fmt.Errorf(errRollbackFailed, target.path, err)
+	}
+	return nil
+}
+
+// prepareTreeCompensation backs up every file the patch touches.
+func (e *PatchFileExecutor) prepareTreeCompensation(params PatchFileParameters) (Compensation, error) {
+	_, targets, err := e.planTree(params)
+	if err != nil {
+		return nil, err
+	}
+	compensations := make([]Compensation, 0, len(targets))
+	for _, target := range targets {
+		compensation, err := fileBackupCompensation(target.path)
+		if err != nil {
+			return nil, err
+		}
+		if compensation != nil {
+			compensations = append(compensations, compensation)
+		}
+	}
+	return combineCompensations(compensations), nil
+}
+
+// stripPathComponents removes the first n slash-separated components of a file name
+// taken from a patch header, like patch -p.
+func stripPathComponents(name string, n int) (string, error) {
+	stripped := name
+	for i := 0; i < n; i++ {
+		slash := strings.IndexByte(stripped, '/')
+		if slash < 0 {
+			return "", invalidf(errTreeStripTooLong, n, name)
+		}
+		stripped = strings.TrimLeft(stripped[slash+1:], "/")
+	}
+	if stripped == "" {
+		return "", invalidf(errTreeStripTooLong, n, name)
+	}
+	return filepath.FromSlash(stripped), nil
+}
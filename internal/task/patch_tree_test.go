@@ -0,0 +1,208 @@
+package task
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// repoRootedPatch is a git diff taken at a repository root, modifying one file,
+// creating another and deleting a third.
+const repoRootedPatch = `diff --git a/src/main.go b/src/main.go
+index 1111111..2222222 100644
+--- a/src/main.go
++++ b/src/main.go
+@@ -1,2 +1,2 @@
+ package main
+-const version = "1.0"
++const version = "1.1"
+diff --git a/docs/CHANGES.md b/docs/CHANGES.md
+new file mode 100644
+index 0000000..3333333
+--- /dev/null
++++ b/docs/CHANGES.md
+@@ -0,0 +1,2 @@
++# Changes
++- Bump version
+diff --git a/old.txt b/old.txt
+deleted file mode 100644
+index 4444444..0000000
+--- a/old.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-obsolete
+`
+
+// newPatchTree creates the tree repoRootedPatch applies to.
+func newPatchTree(t *testing.T) string {
+	t.Helper()
+	baseDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(baseDir, "src"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(baseDir, "src", "main.go"), []byte("package main\nconst version = \"1.0\"\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(baseDir, "old.txt"), []byte("obsolete\n"), 0644))
+	return baseDir
+}
+
+func runPatchTree(t *testing.T, params PatchFileParameters) OutputResult {
+	t.Helper()
+	cmd := NewPatchFileTask("patch-tree", "Apply repository diff", params)
+	resultsChan, err := NewPatchFileExecutor().Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+	assert.Equal(t, finalResult.Status, cmd.Status)
+	return finalResult
+}
+
+func TestPatchFileExecutor_Execute_Tree(t *testing.T) {
+	baseDir := newPatchTree(t)
+
+	finalResult := runPatchTree(t, PatchFileParameters{
+		BaseDirectory: baseDir,
+		Strip:         1,
+		Patch:         repoRootedPatch,
+		IncludeDiff:   true,
+	})
+	require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+	assert.Contains(t, finalResult.Message, "Successfully patched 3 file(s)")
+
+	content, err := os.ReadFile(filepath.Join(baseDir, "src", "main.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "package main\nconst version = \"1.1\"\n", string(content))
+
+	content, err = os.ReadFile(filepath.Join(baseDir, "docs", "CHANGES.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# Changes\n- Bump version\n", string(content))
+
+	assert.NoFileExists(t, filepath.Join(baseDir, "old.txt"))
+
+	assert.Contains(t, finalResult.ResultData, "--- a/src/main.go\n+++ b/src/main.go\n")
+	assert.Contains(t, finalResult.ResultData, "+++ b/docs/CHANGES.md\n")
+	assert.Contains(t, finalResult.ResultData, "-obsolete\n")
+}
+
+func TestPatchFileExecutor_Execute_TreeWithoutStrip(t *testing.T) {
+	baseDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(baseDir, "notes.txt"), []byte("one\n"), 0644))
+
+	finalResult := runPatchTree(t, PatchFileParameters{
+		BaseDirectory: baseDir,
+		Patch:         "--- notes.txt\n+++ notes.txt\n@@ -1 +1 @@\n-one\n+two\n",
+	})
+	require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+
+	content, err := os.ReadFile(filepath.Join(baseDir, "notes.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "two\n", string(content))
+}
+
+func TestPatchFileExecutor_Execute_TreeMismatchLeavesTreeUntouched(t *testing.T) {
+	baseDir := newPatchTree(t)
+	// The deletion no longer applies, so nothing may be written
+	require.NoError(t, os.WriteFile(filepath.Join(baseDir, "old.txt"), []byte("changed\n"), 0644))
+
+	finalResult := runPatchTree(t, PatchFileParameters{BaseDirectory: baseDir, Strip: 1, Patch: repoRootedPatch})
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Contains(t, finalResult.Error, "failed to patch old.txt")
+
+	content, err := os.ReadFile(filepath.Join(baseDir, "src", "main.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"1.0"`)
+	assert.NoDirExists(t, filepath.Join(baseDir, "docs"))
+	assert.FileExists(t, filepath.Join(baseDir, "old.txt"))
+}
+
+func TestPatchFileExecutor_Execute_TreeInvalidPaths(t *testing.T) {
+	testCases := []struct {
+		name        string
+		strip       int
+		patch       string
+		errContains string
+	}{
+		{
+			name:        "Strip more components than the path has",
+			strip:       3,
+			patch:       "--- a/x.txt\n+++ b/x.txt\n@@ -0,0 +1 @@\n+x\n",
+			errContains: "cannot strip 3 path component(s) from 'b/x.txt'",
+		},
+		{
+			name:        "Path escaping the base directory",
+			strip:       1,
+			patch:       "--- a/../escape.txt\n+++ b/../escape.txt\n@@ -0,0 +1 @@\n+x\n",
+			errContains: "is not within base directory",
+		},
+		{
+			name:        "Same file twice",
+			strip:       1,
+			patch:       "--- a/x.txt\n+++ b/x.txt\n@@ -0,0 +1 @@\n+x\n--- a/x.txt\n+++ b/x.txt\n@@ -0,0 +1 @@\n+y\n",
+			errContains: "patch modifies 'x.txt' more than once",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			baseDir := filepath.Join(t.TempDir(), "base")
+			require.NoError(t, os.Mkdir(baseDir, 0755))
+
+			finalResult := runPatchTree(t, PatchFileParameters{BaseDirectory: baseDir, Strip: tc.strip, Patch: tc.patch})
+			assert.Equal(t, StatusFailed, finalResult.Status)
+			assert.Equal(t, FailureValidationError, finalResult.FailureKind)
+			assert.Contains(t, finalResult.Error, tc.errContains)
+			assert.NoFileExists(t, filepath.Join(baseDir, "x.txt"))
+			assert.NoFileExists(t, filepath.Join(filepath.Dir(baseDir), "escape.txt"))
+		})
+	}
+
+	executor := NewPatchFileExecutor()
+	_, err := executor.Execute(context.Background(), NewPatchFileTask("both", "Both paths", PatchFileParameters{
+		FilePath: "x.txt", BaseDirectory: t.TempDir(), Patch: repoRootedPatch,
+	}))
+	assert.ErrorContains(t, err, "cannot both be set")
+
+	_, err = executor.Execute(context.Background(), NewPatchFileTask("hash", "Expected result", PatchFileParameters{
+		BaseDirectory: t.TempDir(), Patch: repoRootedPatch, ExpectedResult: "abc",
+	}))
+	assert.ErrorContains(t, err, "expected_result is not supported")
+}
+
+func TestPatchFileExecutor_PrepareCompensation_Tree(t *testing.T) {
+	baseDir := newPatchTree(t)
+	executor := NewPatchFileExecutor()
+	params := PatchFileParameters{BaseDirectory: baseDir, Strip: 1, Patch: repoRootedPatch}
+
+	compensation, err := executor.PrepareCompensation(context.Background(), NewPatchFileTask("patch-tree", "Apply", params))
+	require.NoError(t, err)
+	finalResult := runPatchTree(t, params)
+	require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+
+	require.NoError(t, compensation(context.Background()))
+	content, err := os.ReadFile(filepath.Join(baseDir, "src", "main.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"1.0"`)
+	assert.FileExists(t, filepath.Join(baseDir, "old.txt"))
+	assert.NoFileExists(t, filepath.Join(baseDir, "docs", "CHANGES.md"))
+}
+
+func TestPatchFileExecutor_Execute_TreeRefusesEmptyResult(t *testing.T) {
+	baseDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(baseDir, "notes.txt"), []byte("one\n"), 0644))
+	refuse := false
+
+	finalResult := runPatchTree(t, PatchFileParameters{
+		BaseDirectory:    baseDir,
+		Patch:            "--- notes.txt\n+++ notes.txt\n@@ -1 +0,0 @@\n-one\n",
+		AllowEmptyResult: &refuse,
+	})
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Contains(t, finalResult.Error, "allow_empty_result is false")
+
+	content, err := os.ReadFile(filepath.Join(baseDir, "notes.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "one\n", string(content))
+}
@@ -7,10 +7,14 @@ package task
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/sourcegraph/go-diff/diff"
@@ -23,9 +27,18 @@ const (
 	errEmptyFilePath = "file path cannot be empty for PATCH_FILE"
 
 	// File operation errors
-	errReadFileFailed  = "failed to read original file %s"
-	errStatFileFailed  = "failed to stat original file %s before writing patch"
-	errWriteFileFailed = "failed to write patched content to file %s"
+	errReadFileFailed     = "failed to read original file %s"
+	errStatFileFailed     = "failed to stat original file %s before writing patch"
+	errWriteFileFailed    = "failed to write patched content to file %s"
+	errReadPatchFailed    = "failed to read patch file %s: %w"
+	errResultMismatch     = "patched content of %s has SHA-256 %s, expected %s"
+	errOriginalMismatch   = "content of %s has SHA-256 %s, expected one of %s"
+	errInvalidExpectedSHA = "invalid expected_sha '%s': must be a hex-encoded SHA-256"
+	errEmptyResult        = "patch would leave %s empty and allow_empty_result is false"
+	errVerifyFailed       = "failed to verify written file %s: %w"
+	errRollbackFailed     = "failed to roll back file %s after verification failure: %w"
+	errInvalidFileMode    = "invalid file mode '%s' in patch header"
+	errChmodFailed        = "failed to change mode of file %s to %o: %w"
 
 	// Status messages
 	msgEmptyPatch       = "Empty patch provided. No changes applied to file: %s"
@@ -35,9 +48,12 @@ const (
 	msgFailedParse      = "Failed to parse patch content for file %s"
 	msgFailedContext    = "Patch context mismatch for file %s"
 	msgFailedMultiFile  = "Patch contained multiple file diffs (unsupported) for %s"
+	msgSkippedHunks     = ", skipping %d hunk(s) that did not match"
 
 	// DefaultFilePermissions is the default file mode for new files (rw-r--r--)
 	DefaultFilePermissions = 0644
+	// DefaultDirPermissions is the default mode for new directories (rwxr-xr-x)
+	DefaultDirPermissions = 0755
 )
 
 // --- Error Types ---
@@ -84,9 +100,33 @@ var (
 
 // --- Patching Logic ---
 
+// PatchOptions controls how strictly a patch is matched against the original content.
+// The zero value requires exact matches.
+type PatchOptions struct {
+	// IgnoreTrailingWhitespace compares context and deletion lines without trailing
+	// spaces and tabs, for patch sources that mangle trailing whitespace.
+	IgnoreTrailingWhitespace bool
+	// BestEffort skips hunks that do not match instead of failing the whole patch.
+	// The lines a skipped hunk covers are kept as they are.
+	BestEffort bool
+	// OnSkippedHunk, if set, is called for each hunk BestEffort skips.
+	OnSkippedHunk func(SkippedHunk)
+}
+
+// SkippedHunk describes a hunk that a best-effort patch did not apply.
+type SkippedHunk struct {
+	// Hunk is the position of the hunk in its file diff, starting at 1.
+	Hunk int `json:"hunk"`
+	// OrigStartLine is the line the hunk starts at in the original file.
+	OrigStartLine int `json:"orig_start_line"`
+	// Reason is why the hunk did not match.
+	Reason string `json:"reason"`
+}
+
 // applyPatch applies a unified diff patch to the original content.
 // It assumes the patch applies to a single file and uses github.com/sourcegraph/go-diff.
-func applyPatch(originalContent []byte, patchContent []byte) ([]byte, error) {
+// The context is checked between hunks so that large patches can be interrupted.
+func applyPatch(ctx context.Context, originalContent []byte, patchContent []byte, opts PatchOptions) ([]byte, error) {
 	// Handle empty patch edge case upfront
 	if len(bytes.TrimSpace(patchContent)) == 0 {
 		return originalContent, nil // Applying empty patch is a no-op
@@ -97,7 +137,11 @@ func applyPatch(originalContent []byte, patchContent []byte) ([]byte, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse patch: %v", err)
 	}
+	return applyFileDiffs(ctx, originalContent, fileDiffs, opts)
+}
 
+// applyFileDiffs applies an already parsed single-file patch to the original content.
+func applyFileDiffs(ctx context.Context, originalContent []byte, fileDiffs []*diff.FileDiff, opts PatchOptions) ([]byte, error) {
 	if len(fileDiffs) == 0 {
 		return nil, errNoFilePatch
 	}
@@ -110,7 +154,7 @@ func applyPatch(originalContent []byte, patchContent []byte) ([]byte, error) {
 
 	// Special handling for file creation patch (/dev/null source)
 	if fileDiff.OrigName == "/dev/null" {
-		return handleFileCreation(fileDiff)
+		return handleFileCreation(ctx, fileDiff)
 	}
 
 	// Special handling for file deletion patch (/dev/null destination)
@@ -122,13 +166,16 @@ func applyPatch(originalContent []byte, patchContent []byte) ([]byte, error) {
 	originalLines := prepareOriginalLines(originalContent)
 
 	// Apply the patch to the original content
-	return applyFileDiff(fileDiff, originalLines, bytes.HasSuffix(originalContent, []byte("\n")))
+	return applyFileDiff(ctx, fileDiff, originalLines, bytes.HasSuffix(originalContent, []byte("\n")), opts)
 }
 
 // handleFileCreation processes a file creation diff (/dev/null source)
-func handleFileCreation(fileDiff *diff.FileDiff) ([]byte, error) {
+func handleFileCreation(ctx context.Context, fileDiff *diff.FileDiff) ([]byte, error) {
 	var result [][]byte
 	for _, hunk := range fileDiff.Hunks {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		hunkLines := bytes.Split(hunk.Body, []byte("\n"))
 		for _, line := range hunkLines {
 			if len(line) > 0 && line[0] == '+' {
@@ -144,6 +191,44 @@ func handleFileCreation(fileDiff *diff.FileDiff) ([]byte, error) {
 	return []byte{}, nil
 }
 
+// patchFileMode returns the file mode set by a git-style "new mode" or
+// "new file mode" extended header line. The second return value is false
+// when the patch does not change the mode.
+func patchFileMode(patchContent []byte) (os.FileMode, bool, error) {
+	if len(bytes.TrimSpace(patchContent)) == 0 {
+		return 0, false, nil
+	}
+
+	fileDiffs, err := diff.ParseMultiFileDiff(patchContent)
+	if err != nil || len(fileDiffs) != 1 {
+		// Parse problems are reported when the patch itself is applied
+		return 0, false, nil
+	}
+	return fileDiffMode(fileDiffs[0])
+}
+
+// fileDiffMode returns the file mode set by the extended headers of a parsed file diff.
+func fileDiffMode(fileDiff *diff.FileDiff) (os.FileMode, bool, error) {
+	for _, line := range fileDiff.Extended {
+		var value string
+		switch {
+		case strings.HasPrefix(line, "new file mode "):
+			value = strings.TrimPrefix(line, "new file mode ")
+		case strings.HasPrefix(line, "new mode "):
+			value = strings.TrimPrefix(line, "new mode ")
+		default:
+			continue
+		}
+		mode, err := strconv.ParseUint(strings.TrimSpace(value), 8, 32)
+		if err != nil {
+			return 0, false, fmt.Errorf(errInvalidFileMode, value)
+		}
+		// Git modes carry the object type in the upper bits; only the permissions apply
+		return os.FileMode(mode).Perm(), true, nil
+	}
+	return 0, false, nil
+}
+
 // prepareOriginalLines splits the original content into lines, using buffer pooling
 func prepareOriginalLines(originalContent []byte) [][]byte {
 	if len(originalContent) == 0 {
@@ -190,11 +275,15 @@ func prepareOriginalLines(originalContent []byte) [][]byte {
 }
 
 // applyFileDiff applies a file diff to original lines and returns the patched content
-func applyFileDiff(fileDiff *diff.FileDiff, originalLines [][]byte, preserveTrailingNewline bool) ([]byte, error) {
+func applyFileDiff(ctx context.Context, fileDiff *diff.FileDiff, originalLines [][]byte, preserveTrailingNewline bool, opts PatchOptions) ([]byte, error) {
 	var result [][]byte
 	currentLine := 0
 
-	for _, hunk := range fileDiff.Hunks {
+	for i, hunk := range fileDiff.Hunks {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		// Add lines before the hunk
 		for ; currentLine < int(hunk.OrigStartLine-1); currentLine++ {
 			if currentLine < len(originalLines) {
@@ -203,36 +292,19 @@ func applyFileDiff(fileDiff *diff.FileDiff, originalLines [][]byte, preserveTrai
 		}
 
 		// Process the hunk
-		hunkLines := bytes.Split(hunk.Body, []byte("\n"))
-		for lineIdx, line := range hunkLines {
-			// Skip empty line at end of hunk (trailing newline)
-			if len(line) == 0 && lineIdx == len(hunkLines)-1 {
-				continue
+		hunkResult, nextLine, err := applyHunk(hunk, originalLines, currentLine, opts)
+		if err != nil {
+			if !opts.BestEffort {
+				return nil, err
 			}
-
-			// Empty line in middle of hunk
-			if len(line) == 0 {
-				result = append(result, []byte{})
-				continue
-			}
-
-			// Process line based on prefix
-			switch line[0] {
-			case ' ': // Context line
-				if err := verifyContextLine(line, originalLines, currentLine); err != nil {
-					return nil, err
-				}
-				result = append(result, originalLines[currentLine])
-				currentLine++
-			case '-': // Deletion line
-				if err := verifyDeletionLine(line, originalLines, currentLine); err != nil {
-					return nil, err
-				}
-				currentLine++
-			case '+': // Addition line
-				result = append(result, line[1:])
+			// Leave the lines the hunk covers to be copied unchanged
+			if opts.OnSkippedHunk != nil {
+				opts.OnSkippedHunk(SkippedHunk{Hunk: i + 1, OrigStartLine: int(hunk.OrigStartLine), Reason: err.Error()})
 			}
+			continue
 		}
+		result = append(result, hunkResult...)
+		currentLine = nextLine
 	}
 
 	// Add remaining lines after last hunk
@@ -242,8 +314,45 @@ func applyFileDiff(fileDiff *diff.FileDiff, originalLines [][]byte, preserveTrai
 	return formatFinalOutput(result, fileDiff, preserveTrailingNewline)
 }
 
+// applyHunk applies a single hunk whose original lines start at currentLine.
+// It returns the lines the hunk produces and the original line following it.
+func applyHunk(hunk *diff.Hunk, originalLines [][]byte, currentLine int, opts PatchOptions) ([][]byte, int, error) {
+	var result [][]byte
+	hunkLines := bytes.Split(hunk.Body, []byte("\n"))
+	for lineIdx, line := range hunkLines {
+		// Skip empty line at end of hunk (trailing newline)
+		if len(line) == 0 && lineIdx == len(hunkLines)-1 {
+			continue
+		}
+
+		// Empty line in middle of hunk
+		if len(line) == 0 {
+			result = append(result, []byte{})
+			continue
+		}
+
+		// Process line based on prefix
+		switch line[0] {
+		case ' ': // Context line
+			if err := verifyContextLine(line, originalLines, currentLine, opts); err != nil {
+				return nil, 0, err
+			}
+			result = append(result, originalLines[currentLine])
+			currentLine++
+		case '-': // Deletion line
+			if err := verifyDeletionLine(line, originalLines, currentLine, opts); err != nil {
+				return nil, 0, err
+			}
+			currentLine++
+		case '+': // Addition line
+			result = append(result, line[1:])
+		}
+	}
+	return result, currentLine, nil
+}
+
 // verifyContextLine checks if a context line in the patch matches the original content
-func verifyContextLine(line []byte, originalLines [][]byte, currentLine int) error {
+func verifyContextLine(line []byte, originalLines [][]byte, currentLine int, opts PatchOptions) error {
 	if currentLine >= len(originalLines) {
 		return fmt.Errorf("context mismatch: expected '%s', got end of file at line %d",
 			string(line[1:]), currentLine+1)
@@ -252,7 +361,7 @@ func verifyContextLine(line []byte, originalLines [][]byte, currentLine int) err
 	originalLine := bytes.TrimRight(originalLines[currentLine], "\n\r")
 	patchLine := bytes.TrimRight(line[1:], "\n\r")
 
-	if !bytes.Equal(originalLine, patchLine) {
+	if !linesMatch(originalLine, patchLine, opts) {
 		return fmt.Errorf("context mismatch: expected '%s', got '%s' at original line %d",
 			string(patchLine), string(originalLine), currentLine+1)
 	}
@@ -261,7 +370,7 @@ func verifyContextLine(line []byte, originalLines [][]byte, currentLine int) err
 }
 
 // verifyDeletionLine checks if a deletion line in the patch matches the original content
-func verifyDeletionLine(line []byte, originalLines [][]byte, currentLine int) error {
+func verifyDeletionLine(line []byte, originalLines [][]byte, currentLine int, opts PatchOptions) error {
 	if currentLine >= len(originalLines) {
 		return fmt.Errorf("context mismatch: expected removal of '%s', got end of file at line %d",
 			string(line[1:]), currentLine+1)
@@ -270,7 +379,7 @@ func verifyDeletionLine(line []byte, originalLines [][]byte, currentLine int) er
 	originalLine := bytes.TrimRight(originalLines[currentLine], "\n\r")
 	patchLine := bytes.TrimRight(line[1:], "\n\r")
 
-	if !bytes.Equal(originalLine, patchLine) {
+	if !linesMatch(originalLine, patchLine, opts) {
 		return fmt.Errorf("context mismatch: expected removal of '%s', got '%s' at original line %d",
 			string(patchLine), string(originalLine), currentLine+1)
 	}
@@ -278,6 +387,14 @@ func verifyDeletionLine(line []byte, originalLines [][]byte, currentLine int) er
 	return nil
 }
 
+// linesMatch compares an original line with a patch line, both without line terminators.
+func linesMatch(originalLine, patchLine []byte, opts PatchOptions) bool {
+	if opts.IgnoreTrailingWhitespace {
+		return bytes.Equal(bytes.TrimRight(originalLine, " \t"), bytes.TrimRight(patchLine, " \t"))
+	}
+	return bytes.Equal(originalLine, patchLine)
+}
+
 // addRemainingLines adds any lines from the original content that come after the last hunk
 func addRemainingLines(result *[][]byte, originalLines [][]byte, currentLine int) {
 	for ; currentLine < len(originalLines)-1 ||
@@ -337,20 +454,26 @@ type FileSystem interface {
 	ReadFile(name string) ([]byte, error)
 	WriteFile(name string, data []byte, perm os.FileMode) error
 	Stat(name string) (os.FileInfo, error)
+	Chmod(name string, mode os.FileMode) error
+	Remove(name string) error
 	LockFile(name string) (func(), error)
 }
 
 // Patcher defines the interface for applying patches.
-// This allows for easier testing and dependency injection.
+// A custom implementation, such as one backed by git apply or a fuzzier matcher,
+// can be supplied through ExecutorConfig.Patcher.
 type Patcher interface {
-	ApplyPatch(originalContent []byte, patchContent []byte) ([]byte, error)
+	// ApplyPatch returns originalContent with patchContent applied.
+	// Implementations should stop and return ctx.Err() once ctx is done.
+	ApplyPatch(ctx context.Context, originalContent []byte, patchContent []byte, opts PatchOptions) ([]byte, error)
 }
 
 // --- Default Implementations ---
 
 // defaultFileSystem implements FileSystem using the standard os package.
 type defaultFileSystem struct {
-	fileLocks sync.Map // Map of file paths to mutexes
+	locks   *FileLocks  // Locks taken by LockFile; the process-wide default if nil
+	dirMode os.FileMode // Mode for directories created by WriteFile; DefaultDirPermissions if zero
 }
 
 func (fs *defaultFileSystem) ReadFile(name string) ([]byte, error) {
@@ -360,7 +483,11 @@ func (fs *defaultFileSystem) ReadFile(name string) ([]byte, error) {
 func (fs *defaultFileSystem) WriteFile(name string, data []byte, perm os.FileMode) error {
 	// Ensure the directory exists before writing the file
 	dir := filepath.Dir(name)
-	if err := os.MkdirAll(dir, 0755); err != nil {
+	dirMode := fs.dirMode
+	if dirMode == 0 {
+		dirMode = DefaultDirPermissions
+	}
+	if err := os.MkdirAll(dir, dirMode); err != nil {
 		return fmt.Errorf("failed to create directory %s: %w", dir, err)
 	}
 	return os.WriteFile(name, data, perm)
@@ -370,26 +497,31 @@ func (fs *defaultFileSystem) Stat(name string) (os.FileInfo, error) {
 	return os.Stat(name)
 }
 
-func (fs *defaultFileSystem) LockFile(name string) (func(), error) {
-	// Get or create a mutex for this file
-	lockKey := filepath.Clean(name)
-	lockValue, _ := fs.fileLocks.LoadOrStore(lockKey, &sync.Mutex{})
-	mutex := lockValue.(*sync.Mutex)
+func (fs *defaultFileSystem) Chmod(name string, mode os.FileMode) error {
+	return os.Chmod(name, mode)
+}
 
-	// Lock the mutex
-	mutex.Lock()
+func (fs *defaultFileSystem) Remove(name string) error {
+	return os.Remove(name)
+}
 
-	// Return an unlock function
-	return func() {
-		mutex.Unlock()
-	}, nil
+func (fs *defaultFileSystem) LockFile(name string) (func(), error) {
+	locks := fs.locks
+	if locks == nil {
+		locks = defaultFileLocks
+	}
+	return locks.Lock(name), nil
 }
 
 // defaultPatcher implements Patcher using the internal applyPatch function.
 type defaultPatcher struct{}
 
-func (p *defaultPatcher) ApplyPatch(originalContent []byte, patchContent []byte) ([]byte, error) {
-	return applyPatch(originalContent, patchContent)
+func (p *defaultPatcher) ApplyPatch(ctx context.Context, originalContent []byte, patchContent []byte, opts PatchOptions) ([]byte, error) {
+	return applyPatch(ctx, originalContent, patchContent, opts)
+}
+
+func (p *defaultPatcher) applyFileDiffs(ctx context.Context, originalContent []byte, fileDiffs []*diff.FileDiff, opts PatchOptions) ([]byte, error) {
+	return applyFileDiffs(ctx, originalContent, fileDiffs, opts)
 }
 
 // --- Executor Implementation ---
@@ -398,8 +530,14 @@ func (p *defaultPatcher) ApplyPatch(originalContent []byte, patchContent []byte)
 type PatchFileExecutor struct {
 	fs      FileSystem
 	patcher Patcher
+	config  ExecutorConfig
 }
 
+var (
+	_ TaskExecutor = (*PatchFileExecutor)(nil)
+	_ Compensator  = (*PatchFileExecutor)(nil)
+)
+
 // NewPatchFileExecutor creates a new PatchFileExecutor instance.
 func NewPatchFileExecutor() *PatchFileExecutor {
 	return &PatchFileExecutor{
@@ -408,20 +546,33 @@ func NewPatchFileExecutor() *PatchFileExecutor {
 	}
 }
 
+// NewPatchFileExecutorWithConfig creates a new PatchFileExecutor using the shared executor config.
+// Set cfg.Patcher to replace the built-in patch backend.
+func NewPatchFileExecutorWithConfig(cfg ExecutorConfig) *PatchFileExecutor {
+	return &PatchFileExecutor{
+		fs:      &defaultFileSystem{locks: cfg.fileLocks(), dirMode: cfg.dirMode()},
+		patcher: cfg.patcher(),
+		config:  cfg,
+	}
+}
+
 // --- Helper Functions ---
 
 // formatResult creates an OutputResult with the given parameters.
 func formatResult(cmd *Task, status TaskStatus, message string, err error) OutputResult {
 	var errMsg string
+	var failure FailureKind
 	if err != nil {
 		errMsg = err.Error()
+		failure = failureKind(err)
 	}
 
 	return OutputResult{
-		TaskID:  cmd.TaskId,
-		Status:  status,
-		Message: message,
-		Error:   errMsg,
+		TaskID:      cmd.TaskId,
+		Status:      status,
+		Message:     message,
+		Error:       errMsg,
+		FailureKind: failure,
 	}
 }
 
@@ -446,42 +597,93 @@ func (e *PatchFileExecutor) Execute(ctx context.Context, patchCmd *Task) (<-chan
 		return terminalChan, nil
 	}
 
+	// A base directory selects the mode that patches a whole tree
+	if params := patchCmd.Parameters.(PatchFileParameters); params.BaseDirectory != "" {
+		return e.executeTree(ctx, patchCmd, params)
+	}
+
 	// Validate file path
 	if patchCmd.Parameters.(PatchFileParameters).FilePath == "" {
 		return nil, errors.New(errEmptyFilePath)
 	}
+	for _, expected := range patchCmd.Parameters.(PatchFileParameters).ExpectedSHA {
+		if decoded, err := hex.DecodeString(strings.TrimSpace(expected)); err != nil || len(decoded) != sha256.Size {
+			return nil, invalidf(errInvalidExpectedSHA, expected)
+		}
+	}
 
 	// Run the execution in a goroutine
 	go func() {
 		defer close(results)
+		startedAt := e.config.clock().Now()
+
+		ctx, cancel := e.config.withTimeout(ctx, patchCmd)
+		defer cancel()
 
 		// Check context before each operation
 		if err := ctx.Err(); err != nil {
 			finalResult := formatResult(patchCmd, StatusFailed, "File patching cancelled.", err)
 			patchCmd.Status = finalResult.Status
+			finalResult.setTimes(e.config.clock(), startedAt)
+			patchCmd.UpdateOutput(&finalResult)
+			e.config.send(ctx, results, finalResult)
+			return
+		}
+
+		// Resolve the file path against the working directory and root
+		filePath, err := e.config.resolvePath(patchCmd.Parameters.(PatchFileParameters).FilePath, patchCmd.Parameters.(PatchFileParameters).WorkingDirectory)
+		if err != nil {
+			finalResult := formatResult(patchCmd, StatusFailed, fmt.Sprintf("Failed to resolve file path: %v", err), err)
+			patchCmd.Status = finalResult.Status
+			finalResult.setTimes(e.config.clock(), startedAt)
+			patchCmd.UpdateOutput(&finalResult)
+			e.config.send(ctx, results, finalResult)
+			return
+		}
+
+		// Load the patch, from PatchPath or PatchReader if it was not given inline
+		patch, err := e.loadTaskPatch(patchCmd.Parameters.(PatchFileParameters))
+		if err != nil {
+			finalResult := formatResult(patchCmd, StatusFailed, fmt.Sprintf("Failed to read patch: %v", err), err)
+			patchCmd.Status = finalResult.Status
+			finalResult.setTimes(e.config.clock(), startedAt)
 			patchCmd.UpdateOutput(&finalResult)
-			results <- finalResult
+			e.config.send(ctx, results, finalResult)
 			return
 		}
 
 		// Lock the file for exclusive access
-		unlock, err := e.fs.LockFile(patchCmd.Parameters.(PatchFileParameters).FilePath)
+		unlock, err := e.fs.LockFile(filePath)
 		if err != nil {
 			finalResult := formatResult(patchCmd, StatusFailed, fmt.Sprintf("Failed to lock file: %v", err), err)
 			patchCmd.Status = finalResult.Status
+			finalResult.setTimes(e.config.clock(), startedAt)
 			patchCmd.UpdateOutput(&finalResult)
-			results <- finalResult
+			e.config.send(ctx, results, finalResult)
 			return
 		}
 		defer unlock()
 
 		// Read original file
-		originalContent, err := e.readOriginalFile(patchCmd.Parameters.(PatchFileParameters).FilePath)
+		originalContent, err := e.readOriginalFile(filePath)
 		if err != nil {
 			finalResult := formatResult(patchCmd, StatusFailed, fmt.Sprintf("Failed to read original file: %v", err), err)
 			patchCmd.Status = finalResult.Status
+			finalResult.setTimes(e.config.clock(), startedAt)
+			patchCmd.UpdateOutput(&finalResult)
+			e.config.send(ctx, results, finalResult)
+			return
+		}
+
+		e.config.logf("patch task %s: original content of %s: %s", patchCmd.TaskId, filePath, e.config.loggedContent(originalContent))
+
+		// Only patch a version of the file the patch was written for
+		if err := checkExpectedOriginal(filePath, originalContent, patchCmd.Parameters.(PatchFileParameters).ExpectedSHA); err != nil {
+			finalResult := formatResult(patchCmd, StatusFailed, fmt.Sprintf("Original content verification failed: %v", err), err)
+			patchCmd.Status = finalResult.Status
+			finalResult.setTimes(e.config.clock(), startedAt)
 			patchCmd.UpdateOutput(&finalResult)
-			results <- finalResult
+			e.config.send(ctx, results, finalResult)
 			return
 		}
 
@@ -489,18 +691,69 @@ func (e *PatchFileExecutor) Execute(ctx context.Context, patchCmd *Task) (<-chan
 		if err := ctx.Err(); err != nil {
 			finalResult := formatResult(patchCmd, StatusFailed, "File patching cancelled before applying patch.", err)
 			patchCmd.Status = finalResult.Status
+			finalResult.setTimes(e.config.clock(), startedAt)
 			patchCmd.UpdateOutput(&finalResult)
-			results <- finalResult
+			e.config.send(ctx, results, finalResult)
 			return
 		}
 
 		// Apply patch
-		patchedContent, err := e.applyPatch(originalContent, []byte(patchCmd.Parameters.(PatchFileParameters).Patch))
+		var skipped []SkippedHunk
+		patchedContent, err := e.applyLoadedPatch(ctx, originalContent, patch, PatchOptions{
+			IgnoreTrailingWhitespace: patchCmd.Parameters.(PatchFileParameters).IgnoreTrailingWhitespace,
+			BestEffort:               patchCmd.Parameters.(PatchFileParameters).BestEffort,
+			OnSkippedHunk:            func(hunk SkippedHunk) { skipped = append(skipped, hunk) },
+		})
 		if err != nil {
 			finalResult := formatResult(patchCmd, StatusFailed, fmt.Sprintf("Failed to apply patch: %v", err), err)
 			patchCmd.Status = finalResult.Status
+			finalResult.setTimes(e.config.clock(), startedAt)
+			patchCmd.UpdateOutput(&finalResult)
+			e.config.send(ctx, results, finalResult)
+			return
+		}
+
+		e.config.logf("patch task %s: patched content of %s: %s", patchCmd.TaskId, filePath, e.config.loggedContent(patchedContent))
+
+		// Pick up a mode change carried in git-style extended headers
+		newMode, hasModeChange, err := patch.mode()
+		if err != nil {
+			finalResult := formatResult(patchCmd, StatusFailed, fmt.Sprintf("Failed to apply patch: %v", err), err)
+			patchCmd.Status = finalResult.Status
+			finalResult.setTimes(e.config.clock(), startedAt)
+			patchCmd.UpdateOutput(&finalResult)
+			e.config.send(ctx, results, finalResult)
+			return
+		}
+
+		// Refuse to empty the file unless that is allowed
+		if len(patchedContent) == 0 && !patchCmd.Parameters.(PatchFileParameters).allowsEmptyResult() {
+			err := invalidf(errEmptyResult, filePath)
+			finalResult := formatResult(patchCmd, StatusFailed, fmt.Sprintf("Failed to apply patch: %v", err), err)
+			patchCmd.Status = finalResult.Status
+			finalResult.setTimes(e.config.clock(), startedAt)
 			patchCmd.UpdateOutput(&finalResult)
-			results <- finalResult
+			e.config.send(ctx, results, finalResult)
+			return
+		}
+
+		// Refuse to write content that does not match the expected result
+		expectedResult := patchCmd.Parameters.(PatchFileParameters).ExpectedResult
+		if err := checkExpectedResult(filePath, patchedContent, expectedResult); err != nil {
+			finalResult := formatResult(patchCmd, StatusFailed, fmt.Sprintf("Patched content verification failed: %v", err), err)
+			patchCmd.Status = finalResult.Status
+			finalResult.setTimes(e.config.clock(), startedAt)
+			patchCmd.UpdateOutput(&finalResult)
+			e.config.send(ctx, results, finalResult)
+			return
+		}
+		existed, _, err := e.fileExists(filePath)
+		if err != nil {
+			finalResult := formatResult(patchCmd, StatusFailed, fmt.Sprintf("Failed to read original file: %v", err), err)
+			patchCmd.Status = finalResult.Status
+			finalResult.setTimes(e.config.clock(), startedAt)
+			patchCmd.UpdateOutput(&finalResult)
+			e.config.send(ctx, results, finalResult)
 			return
 		}
 
@@ -508,32 +761,101 @@ func (e *PatchFileExecutor) Execute(ctx context.Context, patchCmd *Task) (<-chan
 		if err := ctx.Err(); err != nil {
 			finalResult := formatResult(patchCmd, StatusFailed, "File patching cancelled before writing to file.", err)
 			patchCmd.Status = finalResult.Status
+			finalResult.setTimes(e.config.clock(), startedAt)
 			patchCmd.UpdateOutput(&finalResult)
-			results <- finalResult
+			e.config.send(ctx, results, finalResult)
 			return
 		}
 
 		// Write patched file
-		if err := e.writePatchedFile(patchCmd.Parameters.(PatchFileParameters).FilePath, patchedContent); err != nil {
+		if err := e.writePatchedFile(filePath, patchedContent); err != nil {
 			finalResult := formatResult(patchCmd, StatusFailed, fmt.Sprintf("Failed to write patched file: %v", err), err)
 			patchCmd.Status = finalResult.Status
+			finalResult.setTimes(e.config.clock(), startedAt)
 			patchCmd.UpdateOutput(&finalResult)
-			results <- finalResult
+			e.config.send(ctx, results, finalResult)
 			return
 		}
 
+		// Read the written file back and roll it back if it is not what was expected
+		if expectedResult != "" {
+			if err := e.verifyWrittenFile(filePath, expectedResult, originalContent, existed); err != nil {
+				finalResult := formatResult(patchCmd, StatusFailed, fmt.Sprintf("Written file verification failed: %v", err), err)
+				patchCmd.Status = finalResult.Status
+				finalResult.setTimes(e.config.clock(), startedAt)
+				patchCmd.UpdateOutput(&finalResult)
+				e.config.send(ctx, results, finalResult)
+				return
+			}
+		}
+
+		// Apply the mode change after the content is in place
+		if hasModeChange {
+			if err := e.fs.Chmod(filePath, newMode); err != nil {
+				err = fmt.Errorf(errChmodFailed, filePath, newMode, err)
+				finalResult := formatResult(patchCmd, StatusFailed, fmt.Sprintf("Failed to change file mode: %v", err), err)
+				patchCmd.Status = finalResult.Status
+				finalResult.setTimes(e.config.clock(), startedAt)
+				patchCmd.UpdateOutput(&finalResult)
+				e.config.send(ctx, results, finalResult)
+				return
+			}
+		}
+
 		// Send success result
-		finalResult := formatResult(patchCmd, StatusSucceeded, fmt.Sprintf("Successfully patched file %s", patchCmd.Parameters.(PatchFileParameters).FilePath), nil)
+		message := fmt.Sprintf("Successfully patched file %s", filePath)
+		if len(skipped) > 0 {
+			message += fmt.Sprintf(msgSkippedHunks, len(skipped))
+		}
+		finalResult := formatResult(patchCmd, StatusSucceeded, message, nil)
+		if len(skipped) > 0 {
+			finalResult.Payload = skipped
+		}
+		if patchCmd.Parameters.(PatchFileParameters).IncludeDiff {
+			finalResult.ResultData = unifiedDiff(filepath.Base(filePath), originalContent, patchedContent)
+		}
 		patchCmd.Status = finalResult.Status
+		finalResult.setTimes(e.config.clock(), startedAt)
 		patchCmd.UpdateOutput(&finalResult)
-		results <- finalResult
+		e.config.send(ctx, results, finalResult)
 	}()
 
 	return results, nil
 }
 
+// PrepareCompensation implements Compensator by backing up the file to be patched
+// so that a rollback restores its previous content and mode.
+func (e *PatchFileExecutor) PrepareCompensation(ctx context.Context, patchCmd *Task) (Compensation, error) {
+	params := patchCmd.Parameters.(PatchFileParameters)
+	if params.BaseDirectory != "" {
+		return e.prepareTreeCompensation(params)
+	}
+	filePath, err := e.config.resolvePath(params.FilePath, params.WorkingDirectory)
+	if err != nil {
+		return nil, err
+	}
+	return fileBackupCompensation(filePath)
+}
+
 // --- File Operations ---
 
+// loadPatch returns the patch content, reading it from PatchPath when Patch is empty.
+func (e *PatchFileExecutor) loadPatch(params PatchFileParameters) ([]byte, error) {
+	if params.Patch != "" || params.PatchPath == "" {
+		return []byte(params.Patch), nil
+	}
+
+	patchPath, err := e.config.resolvePath(params.PatchPath, params.WorkingDirectory)
+	if err != nil {
+		return nil, fmt.Errorf(errReadPatchFailed, params.PatchPath, err)
+	}
+	content, err := e.fs.ReadFile(patchPath)
+	if err != nil {
+		return nil, fmt.Errorf(errReadPatchFailed, patchPath, err)
+	}
+	return content, nil
+}
+
 // fileExists checks if a file exists and returns its size if it does.
 // Returns (exists bool, size int64, err error)
 func (e *PatchFileExecutor) fileExists(filePath string) (bool, int64, error) {
@@ -582,15 +904,69 @@ func (e *PatchFileExecutor) writePatchedFile(filePath string, patchedContent []b
 	return nil
 }
 
+// verifyWrittenFile reads filePath back and compares its SHA-256 to expected.
+// On mismatch the file is restored to originalContent, or removed if it did not exist before.
+func (e *PatchFileExecutor) verifyWrittenFile(filePath, expected string, originalContent []byte, existed bool) error {
+	written, err := e.fs.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf(errVerifyFailed, filePath, err)
+	}
+	verifyErr := checkExpectedResult(filePath, written, expected)
+	if verifyErr == nil {
+		return nil
+	}
+
+	var rollbackErr error
+	if existed {
+		rollbackErr = e.writePatchedFile(filePath, originalContent)
+	} else {
+		rollbackErr = e.fs.Remove(filePath)
+	}
+	if rollbackErr != nil {
+		return errors.Join(verifyErr, fmt.Errorf(errRollbackFailed, filePath, rollbackErr))
+	}
+	return verifyErr
+}
+
+// checkExpectedResult compares the SHA-256 of content with the hex-encoded expected hash.
+// An empty expected hash disables the check.
+func checkExpectedResult(filePath string, content []byte, expected string) error {
+	if expected == "" {
+		return nil
+	}
+	sum := sha256.Sum256(content)
+	actual := hex.EncodeToString(sum[:])
+	if !strings.EqualFold(actual, strings.TrimSpace(expected)) {
+		return fmt.Errorf(errResultMismatch, filePath, actual, expected)
+	}
+	return nil
+}
+
+// checkExpectedOriginal reports an error unless the SHA-256 of content matches one of
+// the hex-encoded expected hashes. An empty list disables the check.
+func checkExpectedOriginal(filePath string, content []byte, expected []string) error {
+	if len(expected) == 0 {
+		return nil
+	}
+	sum := sha256.Sum256(content)
+	actual := hex.EncodeToString(sum[:])
+	for _, hash := range expected {
+		if strings.EqualFold(actual, strings.TrimSpace(hash)) {
+			return nil
+		}
+	}
+	return fmt.Errorf(errOriginalMismatch, filePath, actual, strings.Join(expected, ", "))
+}
+
 // getFilePermissions retrieves the file permissions for the given path.
 // If the file exists, it returns the current permissions.
-// If the file doesn't exist, it returns DefaultFilePermissions.
+// If the file doesn't exist, it returns the configured file mode.
 // Returns an error if there was a problem accessing the file.
 func (e *PatchFileExecutor) getFilePermissions(filePath string) (os.FileMode, error) {
 	fileInfo, err := e.fs.Stat(filePath)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
-			return DefaultFilePermissions, nil
+			return e.config.fileMode(), nil
 		}
 		return 0, fmt.Errorf("failed to get file permissions for %s: %w", filePath, err)
 	}
@@ -600,8 +976,8 @@ func (e *PatchFileExecutor) getFilePermissions(filePath string) (os.FileMode, er
 // --- Patch Operations ---
 
 // applyPatch applies the patch to the original content.
-func (e *PatchFileExecutor) applyPatch(originalContent []byte, patchContent []byte) ([]byte, error) {
-	patchedContent, err := e.patcher.ApplyPatch(originalContent, patchContent)
+func (e *PatchFileExecutor) applyPatch(ctx context.Context, originalContent []byte, patchContent []byte, opts PatchOptions) ([]byte, error) {
+	patchedContent, err := e.patcher.ApplyPatch(ctx, originalContent, patchContent, opts)
 	if err != nil {
 		return nil, e.mapPatchError(err, string(originalContent))
 	}
@@ -3,6 +3,8 @@ package task
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"os"
@@ -549,7 +551,7 @@ func TestApplyPatch_EdgeCases(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := applyPatch([]byte(tt.original), []byte(tt.patch))
+			result, err := applyPatch(context.Background(), []byte(tt.original), []byte(tt.patch), PatchOptions{})
 			if tt.expectError {
 				require.Error(t, err)
 				assert.Contains(t, err.Error(), tt.errorContains)
@@ -673,9 +675,9 @@ func TestLineVerification(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			var err error
 			if tc.functionToTest == "context" {
-				err = verifyContextLine(tc.line, tc.originalLines, tc.currentLine)
+				err = verifyContextLine(tc.line, tc.originalLines, tc.currentLine, PatchOptions{})
 			} else {
-				err = verifyDeletionLine(tc.line, tc.originalLines, tc.currentLine)
+				err = verifyDeletionLine(tc.line, tc.originalLines, tc.currentLine, PatchOptions{})
 			}
 
 			if tc.expectError {
@@ -774,7 +776,7 @@ func BenchmarkPatchProcessing(b *testing.B) {
 			b.ReportAllocs()
 			b.SetBytes(int64(len(original)))
 			for i := 0; i < b.N; i++ {
-				output, _ := executor.patcher.ApplyPatch(original, patch)
+				output, _ := executor.patcher.ApplyPatch(context.Background(), original, patch, PatchOptions{})
 				runtime.KeepAlive(output)
 			}
 		})
@@ -925,3 +927,595 @@ func TestConcurrentPatchOps(t *testing.T) {
 		t.Logf("Success count: %d out of %d attempts", successCount, numPatches)
 	})
 }
+
+func TestPatchFileExecutor_Execute_IncludeDiff(t *testing.T) {
+	tempDir := t.TempDir()
+	original := "line 1\nline 2\nline 3\nline 4\n"
+	filePath := createPatchTestTempFile(t, tempDir, "diff.txt", original)
+
+	cmd := NewPatchFileTask("patch-diff", "Patch with effective diff", PatchFileParameters{
+		FilePath:    filePath,
+		Patch:       "--- a/diff.txt\n+++ b/diff.txt\n@@ -2,2 +2,3 @@\n-line 2\n+line two\n line 3\n+line 3.5\n",
+		IncludeDiff: true,
+	})
+
+	executor := NewPatchFileExecutor()
+	resultsChan, err := executor.Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	results := collectPatchTestResults(t, resultsChan, 5*time.Second)
+	require.NotEmpty(t, results)
+	finalResult := results[len(results)-1]
+	require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+	require.NotEmpty(t, finalResult.ResultData, "Expected the effective diff in ResultData")
+
+	patched := readPatchTestFileContent(t, filePath)
+	reapplied, err := applyPatch(context.Background(), []byte(original), []byte(finalResult.ResultData), PatchOptions{})
+	require.NoError(t, err, "Returned diff should apply to the original content")
+	assert.Equal(t, patched, string(reapplied))
+}
+
+func TestPatchFileExecutor_Execute_NoDiffByDefault(t *testing.T) {
+	tempDir := t.TempDir()
+	filePath := createPatchTestTempFile(t, tempDir, "nodiff.txt", "a\nb\n")
+
+	cmd := NewPatchFileTask("patch-nodiff", "Patch without diff", PatchFileParameters{
+		FilePath: filePath,
+		Patch:    "--- a/nodiff.txt\n+++ b/nodiff.txt\n@@ -1,2 +1,2 @@\n a\n-b\n+c\n",
+	})
+
+	resultsChan, err := NewPatchFileExecutor().Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	results := collectPatchTestResults(t, resultsChan, 5*time.Second)
+	require.NotEmpty(t, results)
+	finalResult := results[len(results)-1]
+	assert.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+	assert.Empty(t, finalResult.ResultData)
+}
+
+func TestPatchFileExecutor_Execute_ConfiguredModes(t *testing.T) {
+	tempDir := t.TempDir()
+	newDir := filepath.Join(tempDir, "nested", "dir")
+	filePath := filepath.Join(newDir, "created.txt")
+
+	cmd := NewPatchFileTask("patch-modes", "Create file with configured modes", PatchFileParameters{
+		FilePath: filePath,
+		Patch:    "--- /dev/null\n+++ b/created.txt\n@@ -0,0 +1,1 @@\n+hello\n",
+	})
+
+	executor := NewPatchFileExecutorWithConfig(ExecutorConfig{FileMode: 0600, DirMode: 0700})
+	resultsChan, err := executor.Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	results := collectPatchTestResults(t, resultsChan, 5*time.Second)
+	require.NotEmpty(t, results)
+	require.Equal(t, StatusSucceeded, results[len(results)-1].Status, results[len(results)-1].Error)
+
+	fileInfo, err := os.Stat(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), fileInfo.Mode().Perm())
+
+	for _, dir := range []string{filepath.Join(tempDir, "nested"), newDir} {
+		dirInfo, err := os.Stat(dir)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0700), dirInfo.Mode().Perm(), "Unexpected mode for %s", dir)
+	}
+}
+
+func TestPatchFileExecutor_Execute_ModeChange(t *testing.T) {
+	testCases := []struct {
+		name         string
+		patch        string
+		expectedBody string
+		expectedMode os.FileMode
+	}{
+		{
+			name:         "ModeAndContent",
+			patch:        "diff --git a/script.sh b/script.sh\nold mode 100644\nnew mode 100755\n--- a/script.sh\n+++ b/script.sh\n@@ -1 +1 @@\n-echo old\n+echo new\n",
+			expectedBody: "echo new\n",
+			expectedMode: 0755,
+		},
+		{
+			name:         "ModeOnly",
+			patch:        "diff --git a/script.sh b/script.sh\nold mode 100644\nnew mode 100755\n",
+			expectedBody: "echo old\n",
+			expectedMode: 0755,
+		},
+		{
+			name:         "PlainPatch",
+			patch:        "--- a/script.sh\n+++ b/script.sh\n@@ -1 +1 @@\n-echo old\n+echo new\n",
+			expectedBody: "echo new\n",
+			expectedMode: 0644,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := createPatchTestTempFile(t, t.TempDir(), "script.sh", "echo old\n")
+			require.NoError(t, os.Chmod(filePath, 0644))
+
+			cmd := NewPatchFileTask("patch-mode-"+tc.name, "Patch with mode change", PatchFileParameters{
+				FilePath: filePath,
+				Patch:    tc.patch,
+			})
+			resultsChan, err := NewPatchFileExecutor().Execute(context.Background(), cmd)
+			require.NoError(t, err)
+
+			results := collectPatchTestResults(t, resultsChan, 5*time.Second)
+			require.NotEmpty(t, results)
+			require.Equal(t, StatusSucceeded, results[len(results)-1].Status, results[len(results)-1].Error)
+
+			assert.Equal(t, tc.expectedBody, readPatchTestFileContent(t, filePath))
+			fileInfo, err := os.Stat(filePath)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedMode, fileInfo.Mode().Perm())
+		})
+	}
+}
+
+func TestPatchFileMode_InvalidMode(t *testing.T) {
+	_, _, err := patchFileMode([]byte("diff --git a/x b/x\nold mode 100644\nnew mode 10zz55\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid file mode")
+}
+
+// recordingPatcher records each ApplyPatch call and returns a canned result.
+type recordingPatcher struct {
+	mu      sync.Mutex
+	calls   []string
+	content []byte
+}
+
+func (p *recordingPatcher) ApplyPatch(ctx context.Context, originalContent []byte, patchContent []byte, opts PatchOptions) ([]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls = append(p.calls, string(originalContent)+"|"+string(patchContent))
+	return p.content, nil
+}
+
+func TestPatchFileExecutor_Execute_CustomPatcher(t *testing.T) {
+	filePath := createPatchTestTempFile(t, t.TempDir(), "custom.txt", "original\n")
+	patcher := &recordingPatcher{content: []byte("canned\n")}
+
+	registry := NewMapRegistryWithConfig(ExecutorConfig{Patcher: patcher})
+	executor, err := registry.GetExecutor(TaskPatchFile)
+	require.NoError(t, err)
+
+	cmd := NewPatchFileTask("patch-custom", "Patch with custom backend", PatchFileParameters{
+		FilePath: filePath,
+		Patch:    "not a unified diff",
+	})
+	resultsChan, err := executor.Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	results := collectPatchTestResults(t, resultsChan, 5*time.Second)
+	require.NotEmpty(t, results)
+	require.Equal(t, StatusSucceeded, results[len(results)-1].Status, results[len(results)-1].Error)
+
+	assert.Equal(t, []string{"original\n|not a unified diff"}, patcher.calls)
+	assert.Equal(t, "canned\n", readPatchTestFileContent(t, filePath))
+}
+
+func TestApplyPatch_CancelledBetweenHunks(t *testing.T) {
+	const numHunks = 200
+	var original strings.Builder
+	var patch strings.Builder
+	patch.WriteString("--- a/big.txt\n+++ b/big.txt\n")
+	for i := 0; i < numHunks; i++ {
+		// Ten lines per hunk keep the hunks far enough apart to stay separate
+		base := i * 10
+		for j := 0; j < 10; j++ {
+			fmt.Fprintf(&original, "line %d\n", base+j)
+		}
+		fmt.Fprintf(&patch, "@@ -%d,1 +%d,1 @@\n-line %d\n+changed %d\n", base+1, base+1, base, base)
+	}
+
+	// Sanity check: the patch applies when not cancelled
+	_, err := applyPatch(context.Background(), []byte(original.String()), []byte(patch.String()), PatchOptions{})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err := applyPatch(ctx, []byte(original.String()), []byte(patch.String()), PatchOptions{})
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, result)
+
+	// The cancellation remains detectable after the executor maps the error
+	_, err = NewPatchFileExecutor().applyPatch(ctx, []byte(original.String()), []byte(patch.String()), PatchOptions{})
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestPatchFileExecutor_Execute_PatchPath(t *testing.T) {
+	tempDir := t.TempDir()
+	filePath := createPatchTestTempFile(t, tempDir, "target.txt", "line1\nline2\n")
+	createPatchTestTempFile(t, tempDir, "change.patch", "--- a/target.txt\n+++ b/target.txt\n@@ -1,2 +1,2 @@\n line1\n-line2\n+line2 patched\n")
+
+	cmd := NewPatchFileTask("patch-from-file", "Patch read from disk", PatchFileParameters{
+		BaseParameters: BaseParameters{WorkingDirectory: tempDir},
+		FilePath:       "target.txt",
+		PatchPath:      "change.patch",
+	})
+	resultsChan, err := NewPatchFileExecutor().Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	results := collectPatchTestResults(t, resultsChan, 5*time.Second)
+	require.NotEmpty(t, results)
+	require.Equal(t, StatusSucceeded, results[len(results)-1].Status, results[len(results)-1].Error)
+	assert.Equal(t, "line1\nline2 patched\n", readPatchTestFileContent(t, filePath))
+}
+
+func TestPatchFileExecutor_Execute_MissingPatchPath(t *testing.T) {
+	tempDir := t.TempDir()
+	filePath := createPatchTestTempFile(t, tempDir, "target.txt", "unchanged\n")
+
+	cmd := NewPatchFileTask("patch-missing-file", "Patch file does not exist", PatchFileParameters{
+		FilePath:  filePath,
+		PatchPath: filepath.Join(tempDir, "missing.patch"),
+	})
+	resultsChan, err := NewPatchFileExecutor().Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	results := collectPatchTestResults(t, resultsChan, 5*time.Second)
+	require.NotEmpty(t, results)
+	finalResult := results[len(results)-1]
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Contains(t, finalResult.Error, "failed to read patch file")
+	assert.Equal(t, "unchanged\n", readPatchTestFileContent(t, filePath))
+}
+
+// corruptingFileSystem appends a byte to the first file it writes to simulate a faulty write.
+type corruptingFileSystem struct {
+	defaultFileSystem
+	writes int
+}
+
+func (fs *corruptingFileSystem) WriteFile(name string, data []byte, perm os.FileMode) error {
+	fs.writes++
+	if fs.writes == 1 {
+		data = append(append([]byte{}, data...), '!')
+	}
+	return fs.defaultFileSystem.WriteFile(name, data, perm)
+}
+
+func TestPatchFileExecutor_Execute_ExpectedResult(t *testing.T) {
+	const original = "alpha\nbeta\n"
+	const patch = "--- a/file.txt\n+++ b/file.txt\n@@ -1,2 +1,2 @@\n alpha\n-beta\n+gamma\n"
+	sum := sha256.Sum256([]byte("alpha\ngamma\n"))
+	expectedHash := hex.EncodeToString(sum[:])
+
+	testCases := []struct {
+		name            string
+		expectedResult  string
+		corruptWrite    bool
+		expectedStatus  TaskStatus
+		expectedContent string
+		expectedError   string
+	}{
+		{
+			name:            "MatchingHash",
+			expectedResult:  strings.ToUpper(expectedHash),
+			expectedStatus:  StatusSucceeded,
+			expectedContent: "alpha\ngamma\n",
+		},
+		{
+			name:            "WrongHash",
+			expectedResult:  strings.Repeat("0", 64),
+			expectedStatus:  StatusFailed,
+			expectedContent: original,
+			expectedError:   "expected " + strings.Repeat("0", 64),
+		},
+		{
+			name:            "CorruptedWriteRolledBack",
+			expectedResult:  expectedHash,
+			corruptWrite:    true,
+			expectedStatus:  StatusFailed,
+			expectedContent: original,
+			expectedError:   "expected " + expectedHash,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := createPatchTestTempFile(t, t.TempDir(), "file.txt", original)
+			executor := NewPatchFileExecutor()
+			if tc.corruptWrite {
+				executor.fs = &corruptingFileSystem{}
+			}
+
+			cmd := NewPatchFileTask("patch-expected-"+tc.name, "Patch with expected result", PatchFileParameters{
+				FilePath:       filePath,
+				Patch:          patch,
+				ExpectedResult: tc.expectedResult,
+			})
+			resultsChan, err := executor.Execute(context.Background(), cmd)
+			require.NoError(t, err)
+
+			results := collectPatchTestResults(t, resultsChan, 5*time.Second)
+			require.NotEmpty(t, results)
+			finalResult := results[len(results)-1]
+			assert.Equal(t, tc.expectedStatus, finalResult.Status, finalResult.Error)
+			if tc.expectedError != "" {
+				assert.Contains(t, finalResult.Error, tc.expectedError)
+			}
+			assert.Equal(t, tc.expectedContent, readPatchTestFileContent(t, filePath))
+		})
+	}
+}
+
+func TestPatchFileExecutor_Execute_ExpectedResultRemovesCreatedFile(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "created.txt")
+	sum := sha256.Sum256([]byte("hello\n"))
+
+	executor := NewPatchFileExecutor()
+	executor.fs = &corruptingFileSystem{}
+	cmd := NewPatchFileTask("patch-expected-created", "Created file fails verification", PatchFileParameters{
+		FilePath:       filePath,
+		Patch:          "--- /dev/null\n+++ b/created.txt\n@@ -0,0 +1,1 @@\n+hello\n",
+		ExpectedResult: hex.EncodeToString(sum[:]),
+	})
+	resultsChan, err := executor.Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	results := collectPatchTestResults(t, resultsChan, 5*time.Second)
+	require.NotEmpty(t, results)
+	assert.Equal(t, StatusFailed, results[len(results)-1].Status)
+	_, statErr := os.Stat(filePath)
+	assert.True(t, os.IsNotExist(statErr), "File created by the failed patch should be removed")
+}
+
+func TestPatchFileExecutor_Execute_ExpectedSHA(t *testing.T) {
+	const original = "alpha\nbeta\n"
+	const patch = "--- a/file.txt\n+++ b/file.txt\n@@ -1,2 +1,2 @@\n alpha\n-beta\n+gamma\n"
+	hashOf := func(content string) string {
+		sum := sha256.Sum256([]byte(content))
+		return hex.EncodeToString(sum[:])
+	}
+
+	testCases := []struct {
+		name            string
+		expectedSHA     []string
+		expectedStatus  TaskStatus
+		expectedContent string
+	}{
+		{
+			name:            "MatchesOneOfSeveral",
+			expectedSHA:     []string{hashOf("alpha\nbeta\nv1\n"), strings.ToUpper(hashOf(original)), hashOf("alpha\n")},
+			expectedStatus:  StatusSucceeded,
+			expectedContent: "alpha\ngamma\n",
+		},
+		{
+			name:            "MatchesNone",
+			expectedSHA:     []string{hashOf("alpha\nbeta\nv1\n"), hashOf("alpha\n")},
+			expectedStatus:  StatusFailed,
+			expectedContent: original,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := createPatchTestTempFile(t, t.TempDir(), "file.txt", original)
+			cmd := NewPatchFileTask("patch-sha-"+tc.name, "Patch a known version", PatchFileParameters{
+				FilePath:    filePath,
+				Patch:       patch,
+				ExpectedSHA: tc.expectedSHA,
+			})
+			resultsChan, err := NewPatchFileExecutor().Execute(context.Background(), cmd)
+			require.NoError(t, err)
+
+			results := collectPatchTestResults(t, resultsChan, 5*time.Second)
+			require.NotEmpty(t, results)
+			finalResult := results[len(results)-1]
+			assert.Equal(t, tc.expectedStatus, finalResult.Status, finalResult.Error)
+			if tc.expectedStatus == StatusFailed {
+				assert.Contains(t, finalResult.Error, "has SHA-256 "+hashOf(original)+", expected one of")
+			}
+			assert.Equal(t, tc.expectedContent, readPatchTestFileContent(t, filePath))
+		})
+	}
+
+	t.Run("InvalidHash", func(t *testing.T) {
+		_, err := NewPatchFileExecutor().Execute(context.Background(), NewPatchFileTask("patch-sha-invalid", "Invalid hash", PatchFileParameters{
+			FilePath:    "file.txt",
+			Patch:       patch,
+			ExpectedSHA: []string{"abc"},
+		}))
+		assert.ErrorContains(t, err, "invalid expected_sha 'abc'")
+	})
+}
+
+func TestPatchFileExecutor_Execute_AllowEmptyResult(t *testing.T) {
+	const original = "one\ntwo\n"
+	const patch = "--- a/file.txt\n+++ b/file.txt\n@@ -1,2 +0,0 @@\n-one\n-two\n"
+	allow, refuse := true, false
+
+	testCases := []struct {
+		name             string
+		allowEmptyResult *bool
+		expectedStatus   TaskStatus
+		expectedContent  string
+	}{
+		{name: "Default", allowEmptyResult: nil, expectedStatus: StatusSucceeded, expectedContent: ""},
+		{name: "Allowed", allowEmptyResult: &allow, expectedStatus: StatusSucceeded, expectedContent: ""},
+		{name: "Refused", allowEmptyResult: &refuse, expectedStatus: StatusFailed, expectedContent: original},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := createPatchTestTempFile(t, t.TempDir(), "file.txt", original)
+			cmd := NewPatchFileTask("patch-empty-"+tc.name, "Delete every line", PatchFileParameters{
+				FilePath:         filePath,
+				Patch:            patch,
+				AllowEmptyResult: tc.allowEmptyResult,
+			})
+			resultsChan, err := NewPatchFileExecutor().Execute(context.Background(), cmd)
+			require.NoError(t, err)
+
+			results := collectPatchTestResults(t, resultsChan, 5*time.Second)
+			require.NotEmpty(t, results)
+			finalResult := results[len(results)-1]
+			assert.Equal(t, tc.expectedStatus, finalResult.Status, finalResult.Error)
+			if tc.expectedStatus == StatusFailed {
+				assert.Equal(t, FailureValidationError, finalResult.FailureKind)
+				assert.Contains(t, finalResult.Error, "allow_empty_result is false")
+			}
+			assert.Equal(t, tc.expectedContent, readPatchTestFileContent(t, filePath))
+		})
+	}
+}
+
+func TestPatchFileExecutor_Execute_BestEffort(t *testing.T) {
+	// The first hunk is already applied, the second is not
+	const original = "a\nB\nc\nd\ne\nf\ng\nh\n"
+	const patch = "--- a/file.txt\n+++ b/file.txt\n" +
+		"@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n" +
+		"@@ -6,3 +6,3 @@\n f\n-g\n+G\n h\n"
+
+	t.Run("SkipsAppliedHunk", func(t *testing.T) {
+		filePath := createPatchTestTempFile(t, t.TempDir(), "file.txt", original)
+		cmd := NewPatchFileTask("patch-best-effort", "Apply what still applies", PatchFileParameters{
+			FilePath:   filePath,
+			Patch:      patch,
+			BestEffort: true,
+		})
+		resultsChan, err := NewPatchFileExecutor().Execute(context.Background(), cmd)
+		require.NoError(t, err)
+
+		results := collectPatchTestResults(t, resultsChan, 5*time.Second)
+		require.NotEmpty(t, results)
+		finalResult := results[len(results)-1]
+		assert.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+		assert.Contains(t, finalResult.Message, "skipping 1 hunk(s)")
+		assert.Equal(t, "a\nB\nc\nd\ne\nf\nG\nh\n", readPatchTestFileContent(t, filePath))
+
+		skipped, ok := finalResult.Payload.([]SkippedHunk)
+		require.True(t, ok, "Payload should be []SkippedHunk, got %T", finalResult.Payload)
+		require.Len(t, skipped, 1)
+		assert.Equal(t, 1, skipped[0].Hunk)
+		assert.Equal(t, 1, skipped[0].OrigStartLine)
+		assert.Contains(t, skipped[0].Reason, "expected removal of 'b'")
+	})
+
+	t.Run("StrictFails", func(t *testing.T) {
+		filePath := createPatchTestTempFile(t, t.TempDir(), "file.txt", original)
+		cmd := NewPatchFileTask("patch-strict", "Apply all or nothing", PatchFileParameters{
+			FilePath: filePath,
+			Patch:    patch,
+		})
+		resultsChan, err := NewPatchFileExecutor().Execute(context.Background(), cmd)
+		require.NoError(t, err)
+
+		results := collectPatchTestResults(t, resultsChan, 5*time.Second)
+		require.NotEmpty(t, results)
+		finalResult := results[len(results)-1]
+		assert.Equal(t, StatusFailed, finalResult.Status)
+		assert.Nil(t, finalResult.Payload)
+		assert.Equal(t, original, readPatchTestFileContent(t, filePath))
+	})
+
+	t.Run("NotWithBaseDirectory", func(t *testing.T) {
+		_, err := NewPatchFileExecutor().Execute(context.Background(), NewPatchFileTask("patch-tree-best-effort", "", PatchFileParameters{
+			BaseDirectory: t.TempDir(),
+			Patch:         patch,
+			BestEffort:    true,
+		}))
+		assert.ErrorContains(t, err, "best_effort is not supported with base_directory")
+	})
+}
+
+func TestApplyPatch_IgnoreTrailingWhitespace(t *testing.T) {
+	// The original has trailing spaces and a tab that the patch lost
+	original := "keep  \nold\t\nlast\n"
+	patch := "--- a/f.txt\n+++ b/f.txt\n@@ -1,3 +1,3 @@\n keep\n-old\n+new\n last\n"
+
+	_, err := applyPatch(context.Background(), []byte(original), []byte(patch), PatchOptions{})
+	require.Error(t, err, "Strict matching should reject trailing whitespace differences")
+	assert.Contains(t, err.Error(), "context mismatch")
+
+	result, err := applyPatch(context.Background(), []byte(original), []byte(patch), PatchOptions{IgnoreTrailingWhitespace: true})
+	require.NoError(t, err)
+	assert.Equal(t, "keep  \nnew\nlast\n", string(result), "Context lines should keep their original whitespace")
+
+	// Leading whitespace is still significant
+	_, err = applyPatch(context.Background(), []byte("  keep\nold\n"), []byte("--- a/f.txt\n+++ b/f.txt\n@@ -1,2 +1,2 @@\n keep\n-old\n+new\n"), PatchOptions{IgnoreTrailingWhitespace: true})
+	assert.Error(t, err)
+}
+
+func TestPatchFileExecutor_Execute_IgnoreTrailingWhitespace(t *testing.T) {
+	tempDir := t.TempDir()
+	patch := "--- a/target.txt\n+++ b/target.txt\n@@ -1,2 +1,2 @@\n line1\n-line2\n+line2 patched\n"
+
+	for _, lenient := range []bool{false, true} {
+		filePath := createPatchTestTempFile(t, tempDir, fmt.Sprintf("target-%t.txt", lenient), "line1 \nline2\t\n")
+		cmd := NewPatchFileTask("patch-whitespace", "Patch with trailing whitespace", PatchFileParameters{
+			FilePath:                 filePath,
+			Patch:                    patch,
+			IgnoreTrailingWhitespace: lenient,
+		})
+		resultsChan, err := NewPatchFileExecutor().Execute(context.Background(), cmd)
+		require.NoError(t, err)
+
+		results := collectPatchTestResults(t, resultsChan, 5*time.Second)
+		require.NotEmpty(t, results)
+		finalResult := results[len(results)-1]
+		if lenient {
+			assert.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+			assert.Equal(t, "line1 \nline2 patched\n", readPatchTestFileContent(t, filePath))
+		} else {
+			assert.Equal(t, StatusFailed, finalResult.Status)
+			assert.Equal(t, "line1 \nline2\t\n", readPatchTestFileContent(t, filePath))
+		}
+	}
+}
+
+// capturingLogger records every diagnostic message written through it.
+type capturingLogger struct {
+	mu  sync.Mutex
+	buf strings.Builder
+}
+
+func (l *capturingLogger) Printf(format string, v ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fmt.Fprintf(&l.buf, format+"\n", v...)
+}
+
+func (l *capturingLogger) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.buf.String()
+}
+
+func TestPatchFileExecutor_Execute_RedactFileContent(t *testing.T) {
+	original := "api_key=original-secret\n"
+	patched := "api_key=patched-secret\n"
+	patch := "--- a/secrets.env\n+++ b/secrets.env\n@@ -1 +1 @@\n-api_key=original-secret\n+api_key=patched-secret\n"
+
+	for _, redact := range []bool{false, true} {
+		t.Run(fmt.Sprintf("redact=%t", redact), func(t *testing.T) {
+			logger := &capturingLogger{}
+			executor := NewPatchFileExecutorWithConfig(ExecutorConfig{Logger: logger, RedactFileContent: redact})
+			filePath := createPatchTestTempFile(t, t.TempDir(), "secrets.env", original)
+
+			cmd := NewPatchFileTask("patch-redact", "Patch a secrets file", PatchFileParameters{FilePath: filePath, Patch: patch})
+			resultsChan, err := executor.Execute(context.Background(), cmd)
+			require.NoError(t, err)
+			results := collectPatchTestResults(t, resultsChan, 5*time.Second)
+			require.NotEmpty(t, results)
+			require.Equal(t, StatusSucceeded, results[len(results)-1].Status, results[len(results)-1].Error)
+
+			logged := logger.String()
+			if !redact {
+				assert.Contains(t, logged, "original-secret")
+				assert.Contains(t, logged, "patched-secret")
+				return
+			}
+			assert.NotContains(t, logged, "original-secret")
+			assert.NotContains(t, logged, "patched-secret")
+			originalSum := sha256.Sum256([]byte(original))
+			patchedSum := sha256.Sum256([]byte(patched))
+			assert.Contains(t, logged, fmt.Sprintf("<redacted %d bytes, sha256 %s>", len(original), hex.EncodeToString(originalSum[:])))
+			assert.Contains(t, logged, fmt.Sprintf("<redacted %d bytes, sha256 %s>", len(patched), hex.EncodeToString(patchedSum[:])))
+		})
+	}
+}
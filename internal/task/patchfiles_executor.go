@@ -0,0 +1,386 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/sourcegraph/go-diff/diff"
+)
+
+// Error constants for PatchFilesExecutor
+const (
+	// Command validation errors
+	errPatchFilesInvalidCommandType = "invalid command type for PatchFilesExecutor: %T"
+	errPatchFilesNoFiles            = "no files provided for PATCH_FILES"
+	errPatchFilesEmptyPatch         = "patch cannot be empty for PATCH_FILES"
+	errPatchFilesTemplate           = "patch must contain exactly one file diff, got %d"
+	errPatchFilesDuplicate          = "file '%s' is listed more than once"
+
+	// File operation errors
+	errPatchFilesResolvePath   = "failed to resolve path for entry %d: %w"
+	errPatchFilesSomeFailed    = "%d of %d files failed to patch"
+	errPatchFilesTransactional = "transactional patch aborted, no files were written: %w"
+	errPatchFilesCommitFailed  = "failed to commit file '%s': %w"
+
+	// Status messages
+	msgPatchFilesCancelled   = "Batch patching cancelled."
+	msgPatchFilesTimedOut    = "Batch patching timed out."
+	msgPatchFilesFailed      = "Batch patching failed: %v"
+	msgPatchFilesSucceeded   = "Patched %d files in %v."
+	msgPatchFilesEntryOK     = "OK %s\n"
+	msgPatchFilesEntryFailed = "FAILED %s: %v\n"
+)
+
+// PatchFilesExecutor handles the execution of PatchFilesTask.
+// It applies one patch template to several files, optionally as an all-or-nothing transaction.
+type PatchFilesExecutor struct {
+	config  ExecutorConfig
+	patcher *PatchFileExecutor
+}
+
+var (
+	_ TaskExecutor = (*PatchFilesExecutor)(nil)
+	_ Compensator  = (*PatchFilesExecutor)(nil)
+)
+
+// NewPatchFilesExecutor creates a new PatchFilesExecutor.
+func NewPatchFilesExecutor() *PatchFilesExecutor {
+	return NewPatchFilesExecutorWithConfig(ExecutorConfig{})
+}
+
+// NewPatchFilesExecutorWithConfig creates a new PatchFilesExecutor using the shared executor config.
+// Patches are applied with cfg.Patcher like PATCH_FILE.
+func NewPatchFilesExecutorWithConfig(cfg ExecutorConfig) *PatchFilesExecutor {
+	return &PatchFilesExecutor{
+		config:  cfg,
+		patcher: NewPatchFileExecutorWithConfig(cfg),
+	}
+}
+
+// patchFilesTarget is a single file a PatchFilesTask applies the template to.
+type patchFilesTarget struct {
+	path     string
+	patch    []byte
+	original []byte
+	patched  []byte
+	// remove marks a target the template deletes (+++ /dev/null)
+	remove bool
+}
+
+// Execute implements the TaskExecutor interface for PatchFilesTask.
+// Each file outcome is streamed as a RUNNING result whose ResultData is a single
+// "OK <path>" or "FAILED <path>: <error>" line.
+func (e *PatchFilesExecutor) Execute(ctx context.Context, patchCmd *Task) (<-chan OutputResult, error) {
+	if patchCmd.Type != TaskPatchFiles {
+		return nil, fmt.Errorf(errPatchFilesInvalidCommandType, patchCmd)
+	}
+
+	// Check if task is already in a terminal state
+	terminalChan, err := HandleTerminalTask(patchCmd.TaskId, patchCmd.Status, patchCmd.Output)
+	if err != nil || terminalChan != nil {
+		return terminalChan, err
+	}
+
+	params := patchCmd.Parameters.(PatchFilesParameters)
+	if len(params.Files) == 0 {
+		return nil, errors.New(errPatchFilesNoFiles)
+	}
+	template, err := parsePatchTemplate(params.Patch)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(chan OutputResult, 1)
+	go func() {
+		defer close(results)
+
+		ctx, cancel := e.config.withTimeout(ctx, patchCmd)
+		defer cancel()
+
+		patchCmd.Status = StatusRunning
+		startTime := e.config.clock().Now()
+
+		var patched int
+		targets, err := e.planTargets(params, template)
+		if err == nil {
+			opts := PatchOptions{IgnoreTrailingWhitespace: params.IgnoreTrailingWhitespace}
+			if params.Transactional {
+				patched, err = e.patchTransactional(ctx, patchCmd, targets, opts, results)
+			} else {
+				patched, err = e.patchEach(ctx, patchCmd, targets, opts, results)
+			}
+		}
+
+		finalResult := createPatchFilesResult(patchCmd.TaskId, patched, err, e.config.since(startTime))
+		patchCmd.Status = finalResult.Status
+		finalResult.setTimes(e.config.clock(), startTime)
+		patchCmd.UpdateOutput(&finalResult)
+		e.config.send(ctx, results, finalResult)
+	}()
+
+	return results, nil
+}
+
+// parsePatchTemplate parses the single-file diff applied to every file.
+func parsePatchTemplate(patch string) (*diff.FileDiff, error) {
+	if patch == "" {
+		return nil, errors.New(errPatchFilesEmptyPatch)
+	}
+	fileDiffs, err := diff.ParseMultiFileDiff([]byte(patch))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errParseFailed, err)
+	}
+	if len(fileDiffs) != 1 {
+		return nil, invalidf(errPatchFilesTemplate, len(fileDiffs))
+	}
+	return fileDiffs[0], nil
+}
+
+// planTargets resolves every file and renders the template with the file's name in its headers.
+func (e *PatchFilesExecutor) planTargets(params PatchFilesParameters, template *diff.FileDiff) ([]*patchFilesTarget, error) {
+	targets := make([]*patchFilesTarget, 0, len(params.Files))
+	seen := make(map[string]bool, len(params.Files))
+	for i, file := range params.Files {
+		path, err := e.config.resolvePath(file, params.WorkingDirectory)
+		if err != nil {
+			return nil, fmt.Errorf(errPatchFilesResolvePath, i, err)
+		}
+		if seen[path] {
+			return nil, invalidf(errPatchFilesDuplicate, file)
+		}
+		seen[path] = true
+
+		// A deletion is applied as an ordinary diff so that its hunks are checked
+		// against the file before it is removed
+		fileDiff := *template
+		name := filepath.ToSlash(file)
+		if fileDiff.OrigName != "/dev/null" {
+			fileDiff.OrigName = name
+		}
+		fileDiff.NewName = name
+		patch, err := diff.PrintFileDiff(&fileDiff)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errParseFailed, err)
+		}
+		targets = append(targets, &patchFilesTarget{path: path, patch: patch, remove: template.NewName == "/dev/null"})
+	}
+	return targets, nil
+}
+
+// applyTarget reads the target's current content and computes its patched content.
+func (e *PatchFilesExecutor) applyTarget(ctx context.Context, target *patchFilesTarget, opts PatchOptions) error {
+	original, err := e.patcher.readOriginalFile(target.path)
+	if err != nil {
+		return err
+	}
+	patched, err := e.patcher.applyPatch(ctx, original, target.patch, opts)
+	if err != nil {
+		return err
+	}
+	if target.remove && len(patched) > 0 {
+		return fmt.Errorf("%w: %s", errHunkMismatch, errTreeDeleteNotEmpty)
+	}
+	target.original, target.patched = original, patched
+	return nil
+}
+
+// patchEach patches every file independently, continuing past individual failures.
+func (e *PatchFilesExecutor) patchEach(ctx context.Context, patchCmd *Task, targets []*patchFilesTarget, opts PatchOptions, results chan<- OutputResult) (int, error) {
+	patched := 0
+	for _, target := range targets {
+		if err := ctx.Err(); err != nil {
+			return patched, err
+		}
+
+		err := e.patchOne(ctx, target, opts)
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return patched, err
+		}
+		e.sendPatchFilesEntry(ctx, results, patchCmd, target.path, err)
+		if err == nil {
+			patched++
+		}
+	}
+
+	if patched != len(targets) {
+		return patched, fmt.Errorf(errPatchFilesSomeFailed, len(targets)-patched, len(targets))
+	}
+	return patched, nil
+}
+
+// patchOne patches and writes a single file while holding its lock.
+func (e *PatchFilesExecutor) patchOne(ctx context.Context, target *patchFilesTarget, opts PatchOptions) error {
+	unlock, err := e.patcher.fs.LockFile(target.path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := e.applyTarget(ctx, target, opts); err != nil {
+		return err
+	}
+	if target.remove {
+		return e.removeTarget(target)
+	}
+	return e.patcher.writePatchedFile(target.path, target.patched)
+}
+
+// removeTarget deletes a target the template removes.
+func (e *PatchFilesExecutor) removeTarget(target *patchFilesTarget) error {
+	if err := e.patcher.fs.Remove(target.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf(errTreeRemoveFailed, target.path, err)
+	}
+	return nil
+}
+
+// patchTransactional patches every file in memory and stages the results next to
+// their destinations, and only renames them into place once all of them succeeded.
+// On any failure the staged files are removed and no file is modified.
+func (e *PatchFilesExecutor) patchTransactional(ctx context.Context, patchCmd *Task, targets []*patchFilesTarget, opts PatchOptions, results chan<- OutputResult) (int, error) {
+	// Lock in path order so that concurrent batches cannot deadlock
+	locked := make([]*patchFilesTarget, len(targets))
+	copy(locked, targets)
+	sort.Slice(locked, func(i, j int) bool { return locked[i].path < locked[j].path })
+	for _, target := range locked {
+		unlock, err := e.patcher.fs.LockFile(target.path)
+		if err != nil {
+			return 0, fmt.Errorf(errPatchFilesTransactional, err)
+		}
+		defer unlock()
+	}
+
+	for _, target := range targets {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		if err := e.applyTarget(ctx, target, opts); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return 0, ctxErr
+			}
+			e.sendPatchFilesEntry(ctx, results, patchCmd, target.path, err)
+			return 0, fmt.Errorf(errPatchFilesTransactional, err)
+		}
+	}
+
+	// Deleted targets have nothing to stage and are removed when the batch is committed
+	staged := make([]string, 0, len(targets))
+	cleanup := func() {
+		for _, tempPath := range staged {
+			if tempPath != "" {
+				os.Remove(tempPath)
+			}
+		}
+	}
+	for _, target := range targets {
+		if target.remove {
+			staged = append(staged, "")
+			continue
+		}
+		tempPath, err := e.stageTarget(target)
+		if err != nil {
+			cleanup()
+			e.sendPatchFilesEntry(ctx, results, patchCmd, target.path, err)
+			return 0, fmt.Errorf(errPatchFilesTransactional, err)
+		}
+		staged = append(staged, tempPath)
+	}
+
+	if err := ctx.Err(); err != nil {
+		cleanup()
+		return 0, err
+	}
+
+	for i, target := range targets {
+		var err error
+		if target.remove {
+			err = e.removeTarget(target)
+		} else {
+			err = os.Rename(staged[i], target.path)
+		}
+		if err != nil {
+			// Files committed so far cannot be restored; report how far we got
+			staged = staged[i:]
+			cleanup()
+			err = fmt.Errorf(errPatchFilesCommitFailed, target.path, err)
+			e.sendPatchFilesEntry(ctx, results, patchCmd, target.path, err)
+			return i, err
+		}
+		e.sendPatchFilesEntry(ctx, results, patchCmd, target.path, nil)
+	}
+	return len(targets), nil
+}
+
+// stageTarget writes the patched content to a temporary file next to the target,
+// keeping the target's permissions.
+func (e *PatchFilesExecutor) stageTarget(target *patchFilesTarget) (string, error) {
+	perm, err := e.patcher.getFilePermissions(target.path)
+	if err != nil {
+		return "", err
+	}
+	return stageFile(target.path, string(target.patched), perm)
+}
+
+// PrepareCompensation implements Compensator by backing up every target file
+// so that a rollback restores the state before the batch was patched.
+func (e *PatchFilesExecutor) PrepareCompensation(ctx context.Context, patchCmd *Task) (Compensation, error) {
+	params := patchCmd.Parameters.(PatchFilesParameters)
+	compensations := make([]Compensation, 0, len(params.Files))
+	for i, file := range params.Files {
+		filePath, err := e.config.resolvePath(file, params.WorkingDirectory)
+		if err != nil {
+			return nil, fmt.Errorf(errPatchFilesResolvePath, i, err)
+		}
+		compensation, err := fileBackupCompensation(filePath)
+		if err != nil {
+			return nil, err
+		}
+		compensations = append(compensations, compensation)
+	}
+	return combineCompensations(compensations), nil
+}
+
+// sendPatchFilesEntry streams the outcome of patching a single file.
+func (e *PatchFilesExecutor) sendPatchFilesEntry(ctx context.Context, results chan<- OutputResult, patchCmd *Task, path string, err error) {
+	data := fmt.Sprintf(msgPatchFilesEntryOK, path)
+	if err != nil {
+		data = fmt.Sprintf(msgPatchFilesEntryFailed, path, err)
+	}
+	e.config.send(ctx, results, patchCmd.describe(OutputResult{
+		TaskID:     patchCmd.TaskId,
+		Status:     StatusRunning,
+		ResultData: data,
+	}))
+}
+
+// createPatchFilesResult constructs the final OutputResult for a PatchFilesTask.
+func createPatchFilesResult(taskID string, patched int, err error, duration time.Duration) OutputResult {
+	if err == nil {
+		return OutputResult{
+			TaskID:  taskID,
+			Status:  StatusSucceeded,
+			Message: fmt.Sprintf(msgPatchFilesSucceeded, patched, duration.Round(time.Millisecond)),
+		}
+	}
+
+	var message string
+	switch {
+	case errors.Is(err, context.Canceled):
+		message = msgPatchFilesCancelled
+	case errors.Is(err, context.DeadlineExceeded):
+		message = msgPatchFilesTimedOut
+	default:
+		message = fmt.Sprintf(msgPatchFilesFailed, err)
+	}
+	return OutputResult{
+		TaskID:      taskID,
+		Status:      StatusFailed,
+		Message:     message,
+		Error:       err.Error(),
+		FailureKind: failureKind(err),
+	}
+}
@@ -0,0 +1,228 @@
+package task
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// headerPatch replaces the license header shared by the files in these tests.
+const headerPatch = "--- a/template.go\n+++ b/template.go\n@@ -1,2 +1,2 @@\n-// Copyright 2023 Example\n+// Copyright 2024 Example\n package main\n"
+
+func writeHeaderFiles(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		content := "// Copyright 2023 Example\npackage main\n\n// " + name + "\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+}
+
+func TestPatchFilesExecutor_Execute_AllSucceed(t *testing.T) {
+	tempDir := t.TempDir()
+	writeHeaderFiles(t, tempDir, "a.go", "b.go", "c.go")
+
+	cmd := NewPatchFilesTask("patch-files-ok", "Update the license header", PatchFilesParameters{
+		BaseParameters: BaseParameters{WorkingDirectory: tempDir},
+		Files:          []string{"a.go", "b.go", "c.go"},
+		Patch:          headerPatch,
+	})
+	resultsChan, err := NewPatchFilesExecutor().Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	finalResult, output, received := collectStreamingResults(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+	assert.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+	assert.Contains(t, finalResult.Message, "Patched 3 files")
+	assert.Equal(t, 3, strings.Count(output, "OK "), "Expected one OK line per file, got %q", output)
+
+	for _, name := range []string{"a.go", "b.go", "c.go"} {
+		content, err := os.ReadFile(filepath.Join(tempDir, name))
+		require.NoError(t, err)
+		assert.Equal(t, "// Copyright 2024 Example\npackage main\n\n// "+name+"\n", string(content))
+	}
+}
+
+func TestPatchFilesExecutor_Execute_PartialFailure(t *testing.T) {
+	tempDir := t.TempDir()
+	writeHeaderFiles(t, tempDir, "a.go", "c.go")
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "b.go"), []byte("package main\n"), 0644))
+
+	cmd := NewPatchFilesTask("patch-files-partial", "Update the license header", PatchFilesParameters{
+		BaseParameters: BaseParameters{WorkingDirectory: tempDir},
+		Files:          []string{"a.go", "b.go", "c.go"},
+		Patch:          headerPatch,
+	})
+	resultsChan, err := NewPatchFilesExecutor().Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	finalResult, output, received := collectStreamingResults(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Contains(t, finalResult.Error, "1 of 3 files failed")
+	assert.Contains(t, output, "OK "+filepath.Join(tempDir, "a.go"))
+	assert.Contains(t, output, "FAILED "+filepath.Join(tempDir, "b.go"))
+	assert.Contains(t, output, "OK "+filepath.Join(tempDir, "c.go"))
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "c.go"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "// Copyright 2024"), "Non-transactional batch should keep successful patches")
+}
+
+func TestPatchFilesExecutor_Execute_TransactionalRollback(t *testing.T) {
+	tempDir := t.TempDir()
+	writeHeaderFiles(t, tempDir, "a.go", "c.go")
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "b.go"), []byte("package main\n"), 0644))
+
+	cmd := NewPatchFilesTask("patch-files-tx", "Update the license header", PatchFilesParameters{
+		BaseParameters: BaseParameters{WorkingDirectory: tempDir},
+		Files:          []string{"a.go", "b.go", "c.go"},
+		Patch:          headerPatch,
+		Transactional:  true,
+	})
+	resultsChan, err := NewPatchFilesExecutor().Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	finalResult, output, received := collectStreamingResults(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Contains(t, finalResult.Error, "no files were written")
+	assert.Contains(t, output, "FAILED "+filepath.Join(tempDir, "b.go"))
+	assert.NotContains(t, output, "OK ")
+
+	for _, name := range []string{"a.go", "c.go"} {
+		content, err := os.ReadFile(filepath.Join(tempDir, name))
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(content), "// Copyright 2023"), "%s should be unchanged", name)
+	}
+	entries, err := os.ReadDir(tempDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 3, "No staged files should remain")
+}
+
+func TestPatchFilesExecutor_Execute_TransactionalSuccess(t *testing.T) {
+	tempDir := t.TempDir()
+	writeHeaderFiles(t, tempDir, "a.go", "b.go")
+	require.NoError(t, os.Chmod(filepath.Join(tempDir, "b.go"), 0600))
+
+	cmd := NewPatchFilesTask("patch-files-tx-ok", "Update the license header", PatchFilesParameters{
+		Files:         []string{filepath.Join(tempDir, "a.go"), filepath.Join(tempDir, "b.go")},
+		Patch:         headerPatch,
+		Transactional: true,
+	})
+	resultsChan, err := NewPatchFilesExecutor().Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	finalResult, _, received := collectStreamingResults(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+	assert.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "b.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "// Copyright 2024 Example\npackage main\n\n// b.go\n", string(content))
+	info, err := os.Stat(filepath.Join(tempDir, "b.go"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "Staged file should keep the original mode")
+}
+
+func TestPatchFilesExecutor_Execute_InvalidParameters(t *testing.T) {
+	testCases := []struct {
+		name        string
+		params      PatchFilesParameters
+		expectedErr string
+	}{
+		{name: "NoFiles", params: PatchFilesParameters{Patch: headerPatch}, expectedErr: "no files provided"},
+		{name: "EmptyPatch", params: PatchFilesParameters{Files: []string{"a.go"}}, expectedErr: "patch cannot be empty"},
+		{
+			name:        "SeveralFileDiffs",
+			params:      PatchFilesParameters{Files: []string{"a.go"}, Patch: headerPatch + headerPatch},
+			expectedErr: "exactly one file diff, got 2",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewPatchFilesExecutor().Execute(context.Background(), NewPatchFilesTask("patch-files-invalid", "", tc.params))
+			assert.ErrorContains(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestPatchFilesExecutor_Execute_DuplicateFile(t *testing.T) {
+	tempDir := t.TempDir()
+	writeHeaderFiles(t, tempDir, "a.go")
+
+	cmd := NewPatchFilesTask("patch-files-dup", "Patch a file twice", PatchFilesParameters{
+		BaseParameters: BaseParameters{WorkingDirectory: tempDir},
+		Files:          []string{"a.go", filepath.Join(tempDir, "a.go")},
+		Patch:          headerPatch,
+	})
+	resultsChan, err := NewPatchFilesExecutor().Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	finalResult, _, received := collectStreamingResults(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Equal(t, FailureValidationError, finalResult.FailureKind)
+	assert.Contains(t, finalResult.Error, "listed more than once")
+}
+
+func TestPatchFilesExecutor_Execute_DeletionTemplate(t *testing.T) {
+	const deletePatch = "--- a/x\n+++ /dev/null\n@@ -1 +0,0 @@\n-hello\n"
+
+	for _, tc := range []struct {
+		name          string
+		transactional bool
+	}{
+		{name: "Each file", transactional: false},
+		{name: "Transactional", transactional: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(tempDir, "d.txt"), []byte("hello\n"), 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(tempDir, "e.txt"), []byte("hello\n"), 0644))
+
+			cmd := NewPatchFilesTask("patch-files-delete", "Delete files", PatchFilesParameters{
+				BaseParameters: BaseParameters{WorkingDirectory: tempDir},
+				Files:          []string{"d.txt", "e.txt"},
+				Patch:          deletePatch,
+				Transactional:  tc.transactional,
+			})
+			resultsChan, err := NewPatchFilesExecutor().Execute(context.Background(), cmd)
+			require.NoError(t, err)
+
+			finalResult, _, received := collectStreamingResults(t, resultsChan, 5*time.Second)
+			require.True(t, received, "Did not receive final result")
+			require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+			assert.NoFileExists(t, filepath.Join(tempDir, "d.txt"))
+			assert.NoFileExists(t, filepath.Join(tempDir, "e.txt"))
+		})
+	}
+
+	t.Run("Content left over", func(t *testing.T) {
+		tempDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "d.txt"), []byte("hello\n"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "e.txt"), []byte("hello\nworld\n"), 0644))
+
+		cmd := NewPatchFilesTask("patch-files-delete-mismatch", "Delete files", PatchFilesParameters{
+			BaseParameters: BaseParameters{WorkingDirectory: tempDir},
+			Files:          []string{"d.txt", "e.txt"},
+			Patch:          deletePatch,
+			Transactional:  true,
+		})
+		resultsChan, err := NewPatchFilesExecutor().Execute(context.Background(), cmd)
+		require.NoError(t, err)
+
+		finalResult, _, received := collectStreamingResults(t, resultsChan, 5*time.Second)
+		require.True(t, received, "Did not receive final result")
+		assert.Equal(t, StatusFailed, finalResult.Status)
+		assert.Contains(t, finalResult.Error, "deletion does not remove")
+		assert.FileExists(t, filepath.Join(tempDir, "d.txt"), "A failed transactional batch must not delete any file")
+		assert.FileExists(t, filepath.Join(tempDir, "e.txt"))
+	})
+}
@@ -0,0 +1,277 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"reflect"
+	"time"
+)
+
+// Error constants for Plan
+const (
+	errPlanTaskFailed          = "plan %s: %w"
+	errPlanPrepareCompensation = "plan %s: failed to prepare compensation for task %s: %w"
+	errPlanRollbackFailed      = "plan %s: rollback of task %s failed: %w"
+	errPlanWorkingDirectory    = "plan %s: failed to resolve working directory '%s': %w"
+	errPlanNegativeBudget      = "plan %s: max_runtime_ms cannot be negative, got %d"
+	errPlanBudgetExceeded      = "plan %s: %w"
+	errPlanUnknownTask         = "plan %s: no task with ID '%s'"
+)
+
+// Plan is an ordered sequence of tasks that are executed one after another.
+type Plan struct {
+	// PlanId is a unique identifier for this plan.
+	PlanId string `json:"plan_id"`
+	// Description provides a human-readable explanation of the plan's purpose.
+	Description string `json:"description"`
+	// Tasks are executed in order.
+	Tasks []*Task `json:"tasks"`
+	// Compensations maps a task ID to a task that undoes it during rollback.
+	// An entry takes precedence over the compensation provided by the task's executor.
+	Compensations map[string]*Task `json:"compensations,omitempty"`
+	// WorkingDirectory is inherited by every task, including group children and
+	// compensations, that does not set its own; a relative task working directory
+	// is resolved against it. It is made absolute once when the plan starts.
+	WorkingDirectory string `json:"working_directory,omitempty"`
+	// Env is added to the Env of every task. A task's own variables take precedence.
+	Env map[string]string `json:"env,omitempty"`
+	// MaxRuntimeMs caps the wall-clock time, in milliseconds, of the whole plan,
+	// in addition to any per-task timeouts. Zero means no budget.
+	MaxRuntimeMs int64 `json:"max_runtime_ms,omitempty"`
+}
+
+// NewPlan creates a new Plan with the given tasks.
+func NewPlan(planId, description string, tasks []*Task) *Plan {
+	return &Plan{
+		PlanId:      planId,
+		Description: description,
+		Tasks:       tasks,
+	}
+}
+
+// RunWithRollback executes the plan's tasks in order using executors from registry.
+// If a task fails, the compensations of every task that completed before it are run
+// in reverse order and an error describing the failure is returned. Rollback errors
+// are joined to the returned error; a failing compensation does not stop the others.
+//
+// A task is compensated by its entry in Compensations if present, otherwise by its
+// executor if that implements Compensator. Tasks with neither are not undone.
+// Each task's Status and Output are updated as it runs, and its parameters are
+// updated with the WorkingDirectory and Env it inherits from the plan.
+//
+// If MaxRuntimeMs is exceeded, the running task is cancelled, the remaining tasks are
+// not started and the completed ones are rolled back. The returned error then wraps
+// a *BudgetExceededError listing the tasks that completed and those that were cancelled.
+func (p *Plan) RunWithRollback(ctx context.Context, registry TaskRegistry) error {
+	return p.run(ctx, registry, p.Tasks, nil)
+}
+
+// RunSelected runs only the tasks with the given IDs, such as the steps that failed
+// in an earlier run, with the same rollback and budget as RunWithRollback. The tasks
+// named in their DependsOn, transitively, are run first unless they already
+// succeeded. Tasks run in plan order, and a selected task that already finished is
+// reset so that it runs again.
+func (p *Plan) RunSelected(ctx context.Context, registry TaskRegistry, ids ...string) error {
+	selected, err := p.selectTasks(ids)
+	if err != nil {
+		return err
+	}
+	return p.run(ctx, registry, selected, nil)
+}
+
+// selectTasks returns, in plan order, the tasks named by ids and the dependencies
+// they still need. Selected tasks are reset to pending.
+func (p *Plan) selectTasks(ids []string) ([]*Task, error) {
+	byID := make(map[string]*Task, len(p.Tasks))
+	for _, t := range p.Tasks {
+		byID[t.TaskId] = t
+	}
+
+	include := make(map[string]bool)
+	var visit func(id string, requested bool) error
+	visit = func(id string, requested bool) error {
+		t, ok := byID[id]
+		if !ok {
+			return fmt.Errorf(errPlanUnknownTask, p.PlanId, id)
+		}
+		// A dependency that already succeeded has nothing left to provide
+		if include[id] || (!requested && t.Status == StatusSucceeded) {
+			return nil
+		}
+		include[id] = true
+		for _, dep := range t.DependsOn {
+			if err := visit(dep, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, id := range ids {
+		if err := visit(id, true); err != nil {
+			return nil, err
+		}
+	}
+
+	var selected []*Task
+	for _, t := range p.Tasks {
+		if include[t.TaskId] {
+			t.Status = StatusPending
+			t.Output = OutputResult{}
+			selected = append(selected, t)
+		}
+	}
+	return selected, nil
+}
+
+// run executes tasks in order, rolling back the completed ones if one fails.
+// If observe is set, it is called with each result a task streams, including
+// those its group children stream, along with the task.
+func (p *Plan) run(ctx context.Context, registry TaskRegistry, tasks []*Task, observe func(*Task, OutputResult)) error {
+	type completedTask struct {
+		taskId       string
+		compensation Compensation
+	}
+	var completed []completedTask
+
+	rollback := func(cause error) error {
+		// Compensations must run even when the plan failed because ctx was cancelled
+		rollbackCtx := context.WithoutCancel(ctx)
+		errs := []error{cause}
+		for i := len(completed) - 1; i >= 0; i-- {
+			if completed[i].compensation == nil {
+				continue
+			}
+			if err := completed[i].compensation(rollbackCtx); err != nil {
+				errs = append(errs, fmt.Errorf(errPlanRollbackFailed, p.PlanId, completed[i].taskId, err))
+			}
+		}
+		return errors.Join(errs...)
+	}
+
+	base, err := p.baseParameters()
+	if err != nil {
+		return err
+	}
+	if p.MaxRuntimeMs < 0 {
+		return fmt.Errorf(errPlanNegativeBudget, p.PlanId, p.MaxRuntimeMs)
+	}
+
+	// The budget is kept off ctx so that a cancellation by the caller is told apart
+	budget := time.Duration(p.MaxRuntimeMs) * time.Millisecond
+	var runCtx context.Context
+	var cancel context.CancelFunc
+	if budget > 0 {
+		runCtx, cancel = context.WithTimeout(ctx, budget)
+	} else {
+		runCtx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+	budgetExceeded := func(i int) error {
+		if ctx.Err() != nil || runCtx.Err() == nil {
+			return nil
+		}
+		return fmt.Errorf(errPlanBudgetExceeded, p.PlanId, newBudgetExceededError(budget, tasks, i))
+	}
+
+	for i, t := range tasks {
+		if err := budgetExceeded(i); err != nil {
+			return rollback(err)
+		}
+		inheritBaseParameters(t, base)
+		compensation, err := p.prepareCompensation(runCtx, registry, t, base)
+		if err != nil {
+			return rollback(fmt.Errorf(errPlanPrepareCompensation, p.PlanId, t.TaskId, err))
+		}
+
+		var observeTask func(OutputResult)
+		if observe != nil {
+			observeTask = func(result OutputResult) { observe(t, result) }
+		}
+		if _, _, err := runAndCapture(runCtx, registry, t, observeTask); err != nil {
+			if budgetErr := budgetExceeded(i); budgetErr != nil {
+				return rollback(budgetErr)
+			}
+			return rollback(fmt.Errorf(errPlanTaskFailed, p.PlanId, err))
+		}
+		completed = append(completed, completedTask{taskId: t.TaskId, compensation: compensation})
+	}
+	return nil
+}
+
+// prepareCompensation returns the Compensation for t, or nil if t cannot be undone.
+func (p *Plan) prepareCompensation(ctx context.Context, registry TaskRegistry, t *Task, base BaseParameters) (Compensation, error) {
+	if undo, ok := p.Compensations[t.TaskId]; ok {
+		inheritBaseParameters(undo, base)
+		return func(ctx context.Context) error {
+			_, _, err := RunAndCapture(ctx, registry, undo)
+			return err
+		}, nil
+	}
+
+	// Tasks that already finished have nothing left to capture
+	if t.Status.IsTerminal() {
+		return nil, nil
+	}
+
+	executor, err := registry.GetExecutor(t.Type)
+	if err != nil {
+		return nil, err
+	}
+	if compensator, ok := executor.(Compensator); ok {
+		return compensator.PrepareCompensation(ctx, t)
+	}
+	return nil, nil
+}
+
+// baseParameters returns the plan's WorkingDirectory, made absolute, and Env.
+func (p *Plan) baseParameters() (BaseParameters, error) {
+	base := BaseParameters{Env: p.Env}
+	if p.WorkingDirectory != "" {
+		dir, err := filepath.Abs(p.WorkingDirectory)
+		if err != nil {
+			return BaseParameters{}, fmt.Errorf(errPlanWorkingDirectory, p.PlanId, p.WorkingDirectory, err)
+		}
+		base.WorkingDirectory = dir
+	}
+	return base, nil
+}
+
+// inheritBaseParameters applies base to the BaseParameters embedded in the
+// parameters of t and of its children. Values already set on a task win.
+func inheritBaseParameters(t *Task, base BaseParameters) {
+	if base.WorkingDirectory == "" && len(base.Env) == 0 {
+		return
+	}
+	for _, child := range t.Children {
+		inheritBaseParameters(child, base)
+	}
+
+	params := reflect.ValueOf(t.Parameters)
+	if !params.IsValid() || params.Kind() != reflect.Struct {
+		return
+	}
+	field, ok := params.Type().FieldByName("BaseParameters")
+	if !ok || !field.Anonymous || field.Type != reflect.TypeOf(BaseParameters{}) {
+		return
+	}
+
+	// Parameters are stored by value, so the change is made on a copy
+	copied := reflect.New(params.Type()).Elem()
+	copied.Set(params)
+	own := copied.FieldByIndex(field.Index).Addr().Interface().(*BaseParameters)
+	switch {
+	case own.WorkingDirectory == "":
+		own.WorkingDirectory = base.WorkingDirectory
+	case base.WorkingDirectory != "" && !filepath.IsAbs(own.WorkingDirectory):
+		own.WorkingDirectory = filepath.Join(base.WorkingDirectory, own.WorkingDirectory)
+	}
+	if len(base.Env) > 0 {
+		env := maps.Clone(base.Env)
+		maps.Copy(env, own.Env)
+		own.Env = env
+	}
+	t.Parameters = copied.Interface()
+}
@@ -0,0 +1,169 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Status messages for Plan.RunWithProgress
+const (
+	msgPlanProgress  = "%d/%d tasks complete (%d%%), current task %s."
+	msgPlanSucceeded = "Plan %s completed: %d/%d tasks (%d%%) in %v."
+	msgPlanFailed    = "Plan %s failed after %d/%d tasks (%d%%)."
+)
+
+// PlanProgress is the Payload of every result streamed by Plan.RunWithProgress.
+// The tasks of a GROUP are counted individually, so Total is the number of
+// non-group tasks in the plan.
+type PlanProgress struct {
+	// Completed is the number of tasks that finished, whether or not they succeeded.
+	Completed int `json:"completed"`
+	// Total is the number of tasks in the plan.
+	Total int `json:"total"`
+	// CurrentTask is the ID of the task the latest result came from.
+	CurrentTask string `json:"current_task,omitempty"`
+}
+
+// Percent returns Completed as a whole percentage of Total. A plan without tasks is complete.
+func (p PlanProgress) Percent() int {
+	if p.Total == 0 {
+		return 100
+	}
+	return p.Completed * 100 / p.Total
+}
+
+// RunWithProgress runs the plan like RunWithRollback, streaming one overall progress
+// stream instead of per-task results. Every result a task or one of its group children
+// streams produces a RUNNING result for the plan whose Payload is a PlanProgress and
+// whose ResultData is the streamed output, if any. Completed never decreases.
+//
+// The channel is closed after a final result that is SUCCEEDED, with every task
+// complete, or FAILED with the error RunWithRollback would have returned. Callers
+// must drain the channel; once ctx is done, results the consumer does not receive
+// are dropped.
+func (p *Plan) RunWithProgress(ctx context.Context, registry TaskRegistry) <-chan OutputResult {
+	results := make(chan OutputResult, 1)
+	go func() {
+		defer close(results)
+		clock := realClock{}
+		startedAt := clock.Now()
+		tracker := newProgressTracker(p.Tasks)
+
+		forwarding := true
+		err := p.run(ctx, registry, p.Tasks, func(t *Task, result OutputResult) {
+			progress := tracker.observe(t, result)
+			if !forwarding {
+				return
+			}
+			update := OutputResult{
+				TaskID:  p.PlanId,
+				Status:  StatusRunning,
+				Message: fmt.Sprintf(msgPlanProgress, progress.Completed, progress.Total, progress.Percent(), progress.CurrentTask),
+				Payload: progress,
+			}
+			// Final results repeat output that was already streamed, such as a group's
+			if result.Status == StatusRunning {
+				update.ResultData = result.ResultData
+			}
+			forwarding = sendResult(ctx, results, update, clock, DefaultResultSendTimeout)
+		})
+
+		progress := tracker.progress()
+		if err == nil {
+			progress.Completed = progress.Total
+		}
+		finalResult := OutputResult{TaskID: p.PlanId, Payload: progress}
+		if err != nil {
+			finalResult.Status = StatusFailed
+			finalResult.Message = fmt.Sprintf(msgPlanFailed, p.PlanId, progress.Completed, progress.Total, progress.Percent())
+			finalResult.Error = err.Error()
+			finalResult.Err = err
+			finalResult.FailureKind = failureKind(err)
+		} else {
+			finalResult.Status = StatusSucceeded
+			finalResult.Message = fmt.Sprintf(msgPlanSucceeded, p.PlanId, progress.Completed, progress.Total, progress.Percent(),
+				clock.Now().Sub(startedAt).Round(time.Millisecond))
+		}
+		finalResult.setTimes(clock, startedAt)
+		sendResult(ctx, results, finalResult, clock, DefaultResultSendTimeout)
+	}()
+	return results
+}
+
+// progressTracker counts the finished tasks of a plan from the results its tasks stream.
+type progressTracker struct {
+	total int
+	// finished counts the tasks below the plan's tasks that already finished
+	finished int
+	// running is the plan task whose results are being observed
+	running *Task
+	// leaves holds the IDs of the tasks below running, and done those that finished
+	leaves map[string]bool
+	done   map[string]bool
+	// current is the ID of the task the latest result came from
+	current string
+}
+
+func newProgressTracker(tasks []*Task) *progressTracker {
+	tracker := &progressTracker{}
+	for _, t := range tasks {
+		tracker.total += countLeafTasks(t)
+	}
+	return tracker
+}
+
+// observe records result, streamed while the plan task t runs, and returns the progress.
+func (p *progressTracker) observe(t *Task, result OutputResult) PlanProgress {
+	if p.running != t {
+		p.running = t
+		p.leaves = make(map[string]bool)
+		p.done = make(map[string]bool)
+		collectLeafTaskIDs(t, p.leaves)
+	}
+	p.current = result.TaskID
+
+	switch {
+	case !result.Status.IsTerminal():
+	case result.TaskID == t.TaskId:
+		// Everything below a finished task finished, including children that were skipped
+		p.finished += countLeafTasks(t)
+		p.running, p.leaves, p.done = nil, nil, nil
+	case p.leaves[result.TaskID]:
+		p.done[result.TaskID] = true
+	}
+	return p.progress()
+}
+
+// progress returns the progress observed so far.
+func (p *progressTracker) progress() PlanProgress {
+	return PlanProgress{
+		Completed:   p.finished + len(p.done),
+		Total:       p.total,
+		CurrentTask: p.current,
+	}
+}
+
+// countLeafTasks returns the number of tasks in the tree rooted at t that have no
+// children. A task without children counts as one.
+func countLeafTasks(t *Task) int {
+	if len(t.Children) == 0 {
+		return 1
+	}
+	count := 0
+	for _, child := range t.Children {
+		count += countLeafTasks(child)
+	}
+	return count
+}
+
+// collectLeafTaskIDs adds the IDs of the tasks below t that have no children to ids.
+func collectLeafTaskIDs(t *Task, ids map[string]bool) {
+	for _, child := range t.Children {
+		if len(child.Children) == 0 {
+			ids[child.TaskId] = true
+		} else {
+			collectLeafTaskIDs(child, ids)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package task
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// collectPlanProgress drains the results of Plan.RunWithProgress and returns the
+// RUNNING results' progress, their merged ResultData and the final result.
+func collectPlanProgress(t *testing.T, results <-chan OutputResult) ([]PlanProgress, string, OutputResult) {
+	t.Helper()
+	var updates []PlanProgress
+	var output strings.Builder
+	var finalResult OutputResult
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case result, ok := <-results:
+			if !ok {
+				return updates, output.String(), finalResult
+			}
+			require.IsType(t, PlanProgress{}, result.Payload)
+			if result.Status == StatusRunning {
+				updates = append(updates, result.Payload.(PlanProgress))
+				output.WriteString(result.ResultData)
+			} else {
+				finalResult = result
+			}
+		case <-timeout:
+			t.Fatal("Timed out waiting for plan progress")
+		}
+	}
+}
+
+func TestPlan_RunWithProgress(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.txt")
+
+	plan := NewPlan("plan-progress", "Progress test", []*Task{
+		NewFileWriteTask("write", "Write config", FileWriteParameters{FilePath: configPath, Content: "config\n"}),
+		NewBashExecTask("greet", "Greet", BashExecParameters{Command: "echo hello from greet"}),
+		NewGroupTask("checks", "Run checks", []*Task{
+			NewFileReadTask("read", "Read config", FileReadParameters{FilePath: configPath}),
+			NewBashExecTask("list", "List files", BashExecParameters{Command: "ls " + shellQuote(tempDir)}),
+			NewGroupTask("nested", "Nested checks", []*Task{
+				NewBashExecTask("true", "Succeed", BashExecParameters{Command: "true"}),
+			}),
+		}),
+	})
+
+	updates, output, finalResult := collectPlanProgress(t, plan.RunWithProgress(context.Background(), NewMapRegistry()))
+
+	require.NotEmpty(t, updates)
+	seen := make(map[int]bool)
+	for i, progress := range updates {
+		assert.Equal(t, 5, progress.Total)
+		if i > 0 {
+			assert.GreaterOrEqual(t, progress.Completed, updates[i-1].Completed, "Completion count decreased at update %d", i)
+		}
+		seen[progress.Completed] = true
+	}
+	for completed := 1; completed <= 5; completed++ {
+		assert.True(t, seen[completed], "No update reported %d/5 tasks complete", completed)
+	}
+	assert.Contains(t, output, "hello from greet\n")
+	assert.Contains(t, output, "config\n")
+
+	assert.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+	assert.Equal(t, "plan-progress", finalResult.TaskID)
+	progress := finalResult.Payload.(PlanProgress)
+	assert.Equal(t, 5, progress.Completed)
+	assert.Equal(t, 100, progress.Percent())
+	assert.Contains(t, finalResult.Message, "5/5 tasks (100%)")
+}
+
+func TestPlan_RunWithProgress_Failure(t *testing.T) {
+	tempDir := t.TempDir()
+	plan := NewPlan("plan-progress-fail", "Progress failure test", []*Task{
+		NewBashExecTask("first", "Succeed", BashExecParameters{Command: "true"}),
+		NewFileReadTask("missing", "Read missing file", FileReadParameters{FilePath: filepath.Join(tempDir, "missing.txt")}),
+		NewBashExecTask("never", "Never runs", BashExecParameters{Command: "true"}),
+	})
+
+	updates, _, finalResult := collectPlanProgress(t, plan.RunWithProgress(context.Background(), NewMapRegistry()))
+
+	require.NotEmpty(t, updates)
+	assert.Equal(t, "missing", updates[len(updates)-1].CurrentTask)
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Contains(t, finalResult.Error, "task missing failed")
+	progress := finalResult.Payload.(PlanProgress)
+	assert.Equal(t, PlanProgress{Completed: 2, Total: 3, CurrentTask: "missing"}, progress)
+	assert.Equal(t, 66, progress.Percent())
+	assert.Equal(t, StatusPending, plan.Tasks[2].Status)
+}
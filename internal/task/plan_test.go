@@ -0,0 +1,250 @@
+package task
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlan_RunWithRollback_RestoresCompletedSteps(t *testing.T) {
+	tempDir := t.TempDir()
+	existingPath := filepath.Join(tempDir, "existing.txt")
+	createdPath := filepath.Join(tempDir, "created.txt")
+	markerPath := filepath.Join(tempDir, "marker.txt")
+	require.NoError(t, os.WriteFile(existingPath, []byte("original\n"), 0640))
+
+	overwrite := NewFileWriteTask("step-1", "Overwrite existing file", FileWriteParameters{
+		FilePath:  existingPath,
+		Content:   "overwritten\n",
+		Overwrite: true,
+	})
+	create := NewFileWriteTask("step-2", "Create new file", FileWriteParameters{
+		FilePath: createdPath,
+		Content:  "new\n",
+	})
+	mark := NewBashExecTask("step-3", "Create marker", BashExecParameters{
+		Command: "touch " + shellQuote(markerPath),
+	})
+	fail := NewFileReadTask("step-4", "Read missing file", FileReadParameters{
+		FilePath: filepath.Join(tempDir, "missing.txt"),
+	})
+
+	plan := NewPlan("plan-rollback", "Rollback test", []*Task{overwrite, create, mark, fail})
+	plan.Compensations = map[string]*Task{
+		"step-3": NewBashExecTask("undo-step-3", "Remove marker", BashExecParameters{
+			Command: "rm " + shellQuote(markerPath),
+		}),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err := plan.RunWithRollback(ctx, NewMapRegistry())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "task step-4 failed")
+
+	assert.Equal(t, StatusSucceeded, overwrite.Status)
+	assert.Equal(t, StatusSucceeded, create.Status)
+	assert.Equal(t, StatusFailed, fail.Status)
+
+	content, err := os.ReadFile(existingPath)
+	require.NoError(t, err)
+	assert.Equal(t, "original\n", string(content), "Overwritten file should be restored")
+	info, err := os.Stat(existingPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+
+	_, statErr := os.Stat(createdPath)
+	assert.True(t, os.IsNotExist(statErr), "Created file should be removed")
+	_, statErr = os.Stat(markerPath)
+	assert.True(t, os.IsNotExist(statErr), "Explicit compensation should remove the marker")
+}
+
+func TestPlan_RunWithRollback_Success(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "kept.txt")
+	plan := NewPlan("plan-success", "No rollback", []*Task{
+		NewFileWriteTask("write", "Write file", FileWriteParameters{FilePath: filePath, Content: "kept\n"}),
+	})
+
+	require.NoError(t, plan.RunWithRollback(context.Background(), NewMapRegistry()))
+
+	content, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, "kept\n", string(content))
+}
+
+func TestPlan_RunWithRollback_ReportsRollbackErrors(t *testing.T) {
+	tempDir := t.TempDir()
+	plan := NewPlan("plan-rollback-error", "Failing compensation", []*Task{
+		NewBashExecTask("step-1", "No-op", BashExecParameters{Command: "true"}),
+		NewFileReadTask("step-2", "Read missing file", FileReadParameters{FilePath: filepath.Join(tempDir, "missing.txt")}),
+	})
+	plan.Compensations = map[string]*Task{
+		"step-1": NewBashExecTask("undo-step-1", "Failing undo", BashExecParameters{Command: "exit 1"}),
+	}
+
+	err := plan.RunWithRollback(context.Background(), NewMapRegistry())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "task step-2 failed")
+	assert.Contains(t, err.Error(), "rollback of task step-1 failed")
+}
+
+func TestPlan_RunWithRollback_InheritsWorkingDirectoryAndEnv(t *testing.T) {
+	root := t.TempDir()
+	otherDir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, "sub"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "input.txt"), []byte("from root\n"), 0644))
+
+	read := NewFileReadTask("read", "Read relative file", FileReadParameters{FilePath: "input.txt"})
+	plan := NewPlan("plan-inherit", "Inherit root", []*Task{
+		NewFileWriteTask("write", "Write relative file", FileWriteParameters{FilePath: "out.txt", Content: "root\n"}),
+		read,
+		NewBashExecTask("env", "Record inherited environment", BashExecParameters{
+			Command:        `printf '%s %s\n' "$STAGE" "$REGION" > env.txt`,
+			BaseParameters: BaseParameters{Env: map[string]string{"STAGE": "task"}},
+		}),
+		NewFileWriteTask("relative-override", "Write below the plan root", FileWriteParameters{
+			FilePath:       "out.txt",
+			Content:        "sub\n",
+			BaseParameters: BaseParameters{WorkingDirectory: "sub"},
+		}),
+		NewFileWriteTask("absolute-override", "Write elsewhere", FileWriteParameters{
+			FilePath:       "out.txt",
+			Content:        "other\n",
+			BaseParameters: BaseParameters{WorkingDirectory: otherDir},
+		}),
+		NewGroupTask("group", "Grouped write", []*Task{
+			NewFileWriteTask("group-write", "Write from a group", FileWriteParameters{FilePath: "group.txt", Content: "group\n"}),
+		}),
+	})
+	plan.WorkingDirectory = root
+	plan.Env = map[string]string{"STAGE": "plan", "REGION": "eu"}
+
+	require.NoError(t, plan.RunWithRollback(context.Background(), NewMapRegistry()))
+
+	expected := map[string]string{
+		filepath.Join(root, "out.txt"):        "root\n",
+		filepath.Join(root, "env.txt"):        "task eu\n",
+		filepath.Join(root, "sub", "out.txt"): "sub\n",
+		filepath.Join(otherDir, "out.txt"):    "other\n",
+		filepath.Join(root, "group.txt"):      "group\n",
+	}
+	for path, want := range expected {
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, want, string(content), path)
+	}
+	assert.Equal(t, StatusSucceeded, read.Status)
+	assert.Equal(t, root, read.Parameters.(FileReadParameters).WorkingDirectory)
+}
+
+func TestPlan_RunWithRollback_CompensationInheritsWorkingDirectory(t *testing.T) {
+	root := t.TempDir()
+	plan := NewPlan("plan-inherit-rollback", "Relative compensation", []*Task{
+		NewBashExecTask("mark", "Create marker", BashExecParameters{Command: "touch marker"}),
+		NewFileReadTask("fail", "Read missing file", FileReadParameters{FilePath: "missing.txt"}),
+	})
+	plan.WorkingDirectory = root
+	plan.Compensations = map[string]*Task{
+		"mark": NewBashExecTask("undo-mark", "Remove marker", BashExecParameters{Command: "rm marker"}),
+	}
+
+	err := plan.RunWithRollback(context.Background(), NewMapRegistry())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "task fail failed")
+	assert.NotContains(t, err.Error(), "rollback of task")
+	assert.NoFileExists(t, filepath.Join(root, "marker"))
+}
+
+func TestPlan_RunWithRollback_MaxRuntime(t *testing.T) {
+	tempDir := t.TempDir()
+	createdPath := filepath.Join(tempDir, "created.txt")
+
+	create := NewFileWriteTask("step-1", "Create file", FileWriteParameters{FilePath: createdPath, Content: "new\n"})
+	slow := NewBashExecTask("step-2", "Interrupted by the budget", BashExecParameters{Command: "sleep 5"})
+	never := NewBashExecTask("step-3", "Never started", BashExecParameters{Command: "sleep 5"})
+
+	plan := NewPlan("plan-budget", "Budget test", []*Task{create, slow, never})
+	plan.MaxRuntimeMs = 500
+
+	start := time.Now()
+	err := plan.RunWithRollback(context.Background(), NewMapRegistry())
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 4*time.Second, "The budget should cut the plan short")
+
+	var budgetErr *BudgetExceededError
+	require.ErrorAs(t, err, &budgetErr)
+	assert.Equal(t, []string{"step-1"}, budgetErr.Completed)
+	assert.Equal(t, []string{"step-2", "step-3"}, budgetErr.Cancelled)
+	assert.Contains(t, err.Error(), "plan plan-budget: runtime budget of 500ms exceeded")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	assert.Equal(t, StatusSucceeded, create.Status)
+	assert.Equal(t, StatusPending, never.Status)
+	assert.NoFileExists(t, createdPath, "Completed tasks should be rolled back")
+
+	plan = NewPlan("plan-negative", "Negative budget", nil)
+	plan.MaxRuntimeMs = -1
+	assert.ErrorContains(t, plan.RunWithRollback(context.Background(), NewMapRegistry()), "max_runtime_ms cannot be negative")
+}
+
+func TestPlan_RunSelected(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "run.log")
+	step := func(id string, dependsOn ...string) *Task {
+		t := NewBashExecTask(id, "Record "+id, BashExecParameters{Command: "echo " + id + " >> " + shellQuote(logPath)})
+		t.DependsOn = dependsOn
+		return t
+	}
+	readLog := func(t *testing.T) string {
+		t.Helper()
+		content, err := os.ReadFile(logPath)
+		require.NoError(t, err)
+		require.NoError(t, os.Remove(logPath))
+		return string(content)
+	}
+
+	prepare := step("prepare")
+	build := step("build", "prepare")
+	lint := step("lint")
+	test := step("test", "build")
+	deploy := step("deploy")
+	plan := NewPlan("plan-selected", "Selected steps", []*Task{prepare, build, lint, test, deploy})
+
+	// Dependencies are run first, in plan order, even when selected after their dependents
+	require.NoError(t, plan.RunSelected(context.Background(), NewMapRegistry(), "deploy", "test"))
+	assert.Equal(t, "prepare\nbuild\ntest\ndeploy\n", readLog(t))
+	assert.True(t, lint.Status.IsPending(), "Unselected task should not run")
+
+	// Dependencies that already succeeded are not run again, but selected tasks are
+	require.NoError(t, plan.RunSelected(context.Background(), NewMapRegistry(), "test"))
+	assert.Equal(t, "test\n", readLog(t))
+	assert.Equal(t, StatusSucceeded, test.Status)
+
+	err := plan.RunSelected(context.Background(), NewMapRegistry(), "lint", "publish")
+	assert.ErrorContains(t, err, "no task with ID 'publish'")
+	assert.NoFileExists(t, logPath)
+}
+
+func TestPlan_RunSelected_RollsBackSelection(t *testing.T) {
+	tempDir := t.TempDir()
+	writtenPath := filepath.Join(tempDir, "written.txt")
+	skippedPath := filepath.Join(tempDir, "skipped.txt")
+
+	write := NewFileWriteTask("write", "Write file", FileWriteParameters{FilePath: writtenPath, Content: "new\n"})
+	skipped := NewFileWriteTask("skipped", "Not selected", FileWriteParameters{FilePath: skippedPath, Content: "x\n"})
+	read := NewFileReadTask("read", "Read missing file", FileReadParameters{FilePath: filepath.Join(tempDir, "missing.txt")})
+	read.DependsOn = []string{"write"}
+	plan := NewPlan("plan-selected-rollback", "Selected rollback", []*Task{write, skipped, read})
+
+	err := plan.RunSelected(context.Background(), NewMapRegistry(), "read")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "task read failed")
+	assert.NoFileExists(t, writtenPath, "The dependency should be rolled back")
+	assert.NoFileExists(t, skippedPath)
+	assert.True(t, skipped.Status.IsPending())
+}
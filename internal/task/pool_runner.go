@@ -0,0 +1,152 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// Error constants for PoolRunner
+const (
+	errPoolShutDown = "pool runner is shut down"
+	errPoolStopped  = "pool runner stopped before the task started"
+
+	// Status messages
+	msgPoolStartFailed = "Failed to start task."
+	msgPoolRejected    = "Task rejected because the pool runner is shut down."
+	msgPoolCancelled   = "Task cancelled before it started because the pool runner stopped."
+)
+
+// PoolRunner runs independent tasks concurrently with the executors of a registry,
+// never running more than MaxWorkers of them at the same time.
+// It is safe for concurrent use.
+type PoolRunner struct {
+	// MaxWorkers is the maximum number of tasks executed at once. It is fixed by NewPoolRunner.
+	MaxWorkers int
+
+	registry TaskRegistry
+	slots    chan struct{}
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	closed   bool
+}
+
+// NewPoolRunner creates a PoolRunner that dispatches tasks through registry.
+// A maxWorkers of zero or less defaults to the number of CPUs.
+func NewPoolRunner(registry TaskRegistry, maxWorkers int) *PoolRunner {
+	if maxWorkers <= 0 {
+		maxWorkers = runtime.NumCPU()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &PoolRunner{
+		MaxWorkers: maxWorkers,
+		registry:   registry,
+		slots:      make(chan struct{}, maxWorkers),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// Submit queues task for execution and returns a channel carrying its results,
+// which is closed after the final result. Submit never blocks; the task starts
+// once a worker is free. Callers must drain the channel, as a worker stays busy
+// until its results have been received.
+//
+// A task that cannot be started, or that is submitted after Shutdown, produces
+// a single FAILED result.
+func (p *PoolRunner) Submit(task *Task) <-chan OutputResult {
+	results := make(chan OutputResult, 1)
+
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		results <- poolFailure(task, msgPoolRejected, errors.New(errPoolShutDown), FailureCancelled)
+		close(results)
+		return results
+	}
+	p.wg.Add(1)
+	p.mu.Unlock()
+
+	go func() {
+		defer p.wg.Done()
+		defer close(results)
+
+		select {
+		case p.slots <- struct{}{}:
+		case <-p.ctx.Done():
+			results <- poolFailure(task, msgPoolCancelled, errors.New(errPoolStopped), FailureCancelled)
+			return
+		}
+		defer func() { <-p.slots }()
+
+		p.run(task, results)
+	}()
+	return results
+}
+
+// run executes task on the calling goroutine and forwards its results.
+func (p *PoolRunner) run(task *Task, results chan<- OutputResult) {
+	executor, err := p.registry.GetExecutor(task.Type)
+	if err != nil {
+		results <- poolFailure(task, msgPoolStartFailed, err, FailureValidationError)
+		return
+	}
+	ctx, s := startSpan(p.ctx, task)
+	taskResults, err := executor.Execute(ctx, task)
+	if err != nil {
+		results <- poolFailure(task, msgPoolStartFailed, err, FailureValidationError)
+		return
+	}
+	// Stop forwarding once the pool is stopped and the consumer no longer receives,
+	// but keep draining so the executor can finish
+	forwarding := true
+	for result := range taskResults {
+		if forwarding {
+			forwarding = sendResult(p.ctx, results, s.stamp(result), realClock{}, DefaultResultSendTimeout)
+		}
+	}
+	task.Output = s.stamp(task.Output)
+}
+
+// Shutdown stops accepting new tasks and waits for every submitted task to finish.
+// If ctx is done first, running tasks are cancelled, tasks still waiting for a
+// worker fail without starting, and ctx's error is returned once they have all stopped.
+func (p *PoolRunner) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	p.closed = true
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// poolFailure builds the FAILED result for a task the pool could not run,
+// recording it on the task as well.
+func poolFailure(task *Task, message string, err error, kind FailureKind) OutputResult {
+	result := OutputResult{
+		TaskID:      task.TaskId,
+		Status:      StatusFailed,
+		Message:     message,
+		Error:       err.Error(),
+		FailureKind: kind,
+	}
+	task.Status = result.Status
+	task.UpdateOutput(&result)
+	return result
+}
@@ -0,0 +1,150 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// trackingExecutor records how many of its tasks run at the same time.
+type trackingExecutor struct {
+	inFlight    atomic.Int32
+	maxInFlight atomic.Int32
+	delay       time.Duration
+}
+
+func (e *trackingExecutor) Execute(ctx context.Context, t *Task) (<-chan OutputResult, error) {
+	results := make(chan OutputResult, 1)
+	go func() {
+		defer close(results)
+		current := e.inFlight.Add(1)
+		defer e.inFlight.Add(-1)
+		for {
+			seen := e.maxInFlight.Load()
+			if current <= seen || e.maxInFlight.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+
+		status := StatusSucceeded
+		select {
+		case <-time.After(e.delay):
+		case <-ctx.Done():
+			status = StatusFailed
+		}
+		results <- OutputResult{TaskID: t.TaskId, Status: status, ResultData: t.TaskId}
+	}()
+	return results, nil
+}
+
+const testPoolTaskType = TaskType("TEST_POOL")
+
+func newTrackingPool(maxWorkers int, delay time.Duration) (*PoolRunner, *trackingExecutor) {
+	registry := NewMapRegistry()
+	executor := &trackingExecutor{delay: delay}
+	registry.Register(testPoolTaskType, executor)
+	return NewPoolRunner(registry, maxWorkers), executor
+}
+
+func newPoolTask(id string) *Task {
+	return &Task{BaseTask: BaseTask{TaskId: id, Type: testPoolTaskType}}
+}
+
+func TestPoolRunner_BoundsConcurrency(t *testing.T) {
+	pool, executor := newTrackingPool(3, 20*time.Millisecond)
+
+	const taskCount = 12
+	channels := make([]<-chan OutputResult, taskCount)
+	for i := range channels {
+		channels[i] = pool.Submit(newPoolTask(fmt.Sprintf("task-%d", i)))
+	}
+
+	var wg sync.WaitGroup
+	finals := make([]OutputResult, taskCount)
+	for i, results := range channels {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for result := range results {
+				finals[i] = result
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i, final := range finals {
+		assert.Equal(t, StatusSucceeded, final.Status, "task-%d", i)
+		assert.Equal(t, fmt.Sprintf("task-%d", i), final.ResultData)
+	}
+	assert.Equal(t, int32(3), executor.maxInFlight.Load(), "At most MaxWorkers tasks should run at once")
+	require.NoError(t, pool.Shutdown(context.Background()))
+}
+
+func TestPoolRunner_StartFailure(t *testing.T) {
+	pool := NewPoolRunner(NewMapRegistry(), 1)
+	defer pool.Shutdown(context.Background())
+
+	unknown := &Task{BaseTask: BaseTask{TaskId: "unknown", Type: TaskType("NO_SUCH_TYPE")}}
+	final, received := readFinalResult(t, pool.Submit(unknown), 5*time.Second)
+	require.True(t, received)
+	assert.Equal(t, StatusFailed, final.Status)
+	assert.Contains(t, final.Error, "no executor registered")
+	assert.Equal(t, StatusFailed, unknown.Status)
+}
+
+func TestPoolRunner_Shutdown(t *testing.T) {
+	t.Run("waits for submitted tasks", func(t *testing.T) {
+		pool, _ := newTrackingPool(1, 20*time.Millisecond)
+		first := pool.Submit(newPoolTask("first"))
+		second := pool.Submit(newPoolTask("second"))
+
+		var wg sync.WaitGroup
+		finals := make([]OutputResult, 2)
+		for i, results := range []<-chan OutputResult{first, second} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				finals[i], _ = readFinalResult(t, results, 5*time.Second)
+			}()
+		}
+		require.NoError(t, pool.Shutdown(context.Background()))
+		wg.Wait()
+		assert.Equal(t, StatusSucceeded, finals[0].Status)
+		assert.Equal(t, StatusSucceeded, finals[1].Status)
+
+		// New work is rejected once shut down
+		final, received := readFinalResult(t, pool.Submit(newPoolTask("late")), 5*time.Second)
+		require.True(t, received)
+		assert.Equal(t, StatusFailed, final.Status)
+		assert.Contains(t, final.Error, "shut down")
+	})
+
+	t.Run("cancels on deadline", func(t *testing.T) {
+		pool, _ := newTrackingPool(1, time.Hour)
+		running := pool.Submit(newPoolTask("running"))
+		queued := pool.Submit(newPoolTask("queued"))
+
+		var wg sync.WaitGroup
+		finals := make([]OutputResult, 2)
+		for i, results := range []<-chan OutputResult{running, queued} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				finals[i], _ = readFinalResult(t, results, 5*time.Second)
+			}()
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, pool.Shutdown(ctx), context.DeadlineExceeded)
+		wg.Wait()
+		assert.Equal(t, StatusFailed, finals[0].Status)
+		assert.Equal(t, StatusFailed, finals[1].Status)
+	})
+}
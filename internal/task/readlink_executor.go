@@ -0,0 +1,119 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Error constants for ReadLinkExecutor
+const (
+	// Command validation errors
+	errReadLinkInvalidCommandType = "invalid command type for ReadLinkExecutor: %T"
+	errReadLinkNoPath             = "no path provided for READ_LINK"
+
+	// File operation errors
+	errReadLinkResolvePath = "failed to resolve path: %w"
+	errReadLinkNotSymlink  = "'%s' is not a symbolic link"
+	errReadLinkFailed      = "failed to read link '%s': %w"
+
+	// Status messages
+	msgReadLinkFailed    = "Reading link failed: %v"
+	msgReadLinkSucceeded = "'%s' links to '%s'."
+)
+
+// ReadLinkExecutor handles the execution of ReadLinkTask.
+// It reports the target a symbolic link points to, as stored in the link.
+type ReadLinkExecutor struct {
+	config ExecutorConfig
+}
+
+var _ TaskExecutor = (*ReadLinkExecutor)(nil)
+
+// NewReadLinkExecutor creates a new ReadLinkExecutor.
+func NewReadLinkExecutor() *ReadLinkExecutor {
+	return &ReadLinkExecutor{}
+}
+
+// NewReadLinkExecutorWithConfig creates a new ReadLinkExecutor using the shared executor config.
+func NewReadLinkExecutorWithConfig(cfg ExecutorConfig) *ReadLinkExecutor {
+	return &ReadLinkExecutor{config: cfg}
+}
+
+// Execute implements the TaskExecutor interface for ReadLinkTask.
+// The final result's ResultData holds the link's target, which may be relative to
+// the directory containing the link and need not exist.
+func (e *ReadLinkExecutor) Execute(ctx context.Context, linkCmd *Task) (<-chan OutputResult, error) {
+	if linkCmd.Type != TaskReadLink {
+		return nil, fmt.Errorf(errReadLinkInvalidCommandType, linkCmd)
+	}
+
+	// Check if task is already in a terminal state
+	terminalChan, err := HandleTerminalTask(linkCmd.TaskId, linkCmd.Status, linkCmd.Output)
+	if err != nil || terminalChan != nil {
+		return terminalChan, err
+	}
+
+	if linkCmd.Parameters.(ReadLinkParameters).Path == "" {
+		return nil, errors.New(errReadLinkNoPath)
+	}
+
+	results := make(chan OutputResult, 1)
+	go func() {
+		defer close(results)
+
+		startedAt := e.config.clock().Now()
+		linkCmd.Status = StatusRunning
+		path, target, err := e.readLink(linkCmd.Parameters.(ReadLinkParameters))
+
+		finalResult := createReadLinkResult(linkCmd.TaskId, path, target, err)
+		linkCmd.Status = finalResult.Status
+		finalResult.setTimes(e.config.clock(), startedAt)
+		linkCmd.UpdateOutput(&finalResult)
+		e.config.send(ctx, results, finalResult)
+	}()
+
+	return results, nil
+}
+
+// readLink resolves params.Path and returns it along with the target of the link.
+func (e *ReadLinkExecutor) readLink(params ReadLinkParameters) (string, string, error) {
+	path, err := e.config.resolvePath(params.Path, params.WorkingDirectory)
+	if err != nil {
+		return "", "", fmt.Errorf(errReadLinkResolvePath, err)
+	}
+
+	// Lstat first so that a regular file gets a clearer error than EINVAL
+	info, err := os.Lstat(path)
+	if err != nil {
+		return path, "", fmt.Errorf(errReadLinkFailed, path, err)
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		return path, "", fmt.Errorf(errReadLinkNotSymlink, path)
+	}
+	target, err := os.Readlink(path)
+	if err != nil {
+		return path, "", fmt.Errorf(errReadLinkFailed, path, err)
+	}
+	return path, target, nil
+}
+
+// createReadLinkResult constructs the final OutputResult for a ReadLinkTask.
+func createReadLinkResult(taskID, path, target string, err error) OutputResult {
+	if err != nil {
+		return OutputResult{
+			TaskID:      taskID,
+			Status:      StatusFailed,
+			Message:     fmt.Sprintf(msgReadLinkFailed, err),
+			Error:       err.Error(),
+			FailureKind: failureKind(err),
+		}
+	}
+	return OutputResult{
+		TaskID:     taskID,
+		Status:     StatusSucceeded,
+		Message:    fmt.Sprintf(msgReadLinkSucceeded, path, target),
+		ResultData: target,
+	}
+}
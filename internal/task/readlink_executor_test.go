@@ -0,0 +1,59 @@
+package task
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runReadLink(t *testing.T, params ReadLinkParameters) OutputResult {
+	t.Helper()
+	cmd := NewReadLinkTask("readlink-test", "Read link", params)
+	resultsChan, err := NewReadLinkExecutor().Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+	assert.Equal(t, finalResult.Status, cmd.Status)
+	return finalResult
+}
+
+func TestReadLinkExecutor_Execute(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("a: 1\n"), 0644))
+	require.NoError(t, os.Symlink("config.yaml", filepath.Join(dir, "current")))
+	require.NoError(t, os.Symlink("/nonexistent/target", filepath.Join(dir, "dangling")))
+
+	t.Run("RelativeTarget", func(t *testing.T) {
+		finalResult := runReadLink(t, ReadLinkParameters{Path: "current", BaseParameters: BaseParameters{WorkingDirectory: dir}})
+		require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+		assert.Equal(t, "config.yaml", finalResult.ResultData)
+		assert.Contains(t, finalResult.Message, "links to 'config.yaml'")
+	})
+
+	t.Run("DanglingLink", func(t *testing.T) {
+		finalResult := runReadLink(t, ReadLinkParameters{Path: filepath.Join(dir, "dangling")})
+		require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+		assert.Equal(t, "/nonexistent/target", finalResult.ResultData)
+	})
+
+	t.Run("NotASymlink", func(t *testing.T) {
+		finalResult := runReadLink(t, ReadLinkParameters{Path: filepath.Join(dir, "config.yaml")})
+		assert.Equal(t, StatusFailed, finalResult.Status)
+		assert.Contains(t, finalResult.Error, "is not a symbolic link")
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		finalResult := runReadLink(t, ReadLinkParameters{Path: filepath.Join(dir, "missing")})
+		assert.Equal(t, StatusFailed, finalResult.Status)
+		assert.Equal(t, FailureExecutionError, finalResult.FailureKind)
+	})
+
+	_, err := NewReadLinkExecutor().Execute(context.Background(), NewReadLinkTask("no-path", "No path", ReadLinkParameters{}))
+	assert.ErrorContains(t, err, "no path provided")
+}
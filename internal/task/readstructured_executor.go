@@ -0,0 +1,316 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Error constants for ReadStructuredExecutor
+const (
+	// Command validation errors
+	errReadStructuredInvalidCommandType = "invalid command type for ReadStructuredExecutor: %T"
+	errReadStructuredEmptyFilePath      = "file path cannot be empty"
+	errReadStructuredUnknownFormat      = "cannot infer structured format of '%s' from its extension; set format to json, yaml or toml"
+	errReadStructuredUnsupportedFormat  = "unsupported structured format '%s' (supported: json, yaml, toml)"
+
+	// File operation errors
+	errReadStructuredResolveFilePath = "failed to resolve file path: %w"
+	errReadStructuredReadFailed      = "failed to read file '%s': %w"
+	errReadStructuredTrailingData    = "unexpected data after top-level value"
+	errReadStructuredEncodeFailed    = "failed to encode parsed %s document: %w"
+
+	// Status messages
+	msgReadStructuredCancelled = "Structured read cancelled."
+	msgReadStructuredTimedOut  = "Structured read timed out."
+	msgReadStructuredFailed    = "Structured read failed: %v"
+	msgReadStructuredSucceeded = "Parsed %s document '%s'."
+)
+
+// Structured file formats understood by READ_STRUCTURED
+const (
+	StructuredFormatJSON = "json"
+	StructuredFormatYAML = "yaml"
+	StructuredFormatTOML = "toml"
+)
+
+// yamlErrorLine extracts the line number from yaml.v3 error messages such as
+// "yaml: line 3: did not find expected key".
+var yamlErrorLine = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+// ParseError reports a structured file that could not be parsed.
+// Line and Column are 1-based; Column is zero when the parser does not report it.
+type ParseError struct {
+	Format string
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	switch {
+	case e.Line > 0 && e.Column > 0:
+		return fmt.Sprintf("invalid %s at line %d, column %d: %v", e.Format, e.Line, e.Column, e.Err)
+	case e.Line > 0:
+		return fmt.Sprintf("invalid %s at line %d: %v", e.Format, e.Line, e.Err)
+	default:
+		return fmt.Sprintf("invalid %s: %v", e.Format, e.Err)
+	}
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ReadStructuredExecutor handles the execution of ReadStructuredTask.
+// It parses a JSON, YAML or TOML file and returns the document as the result Payload.
+type ReadStructuredExecutor struct {
+	fs     FileSystem
+	config ExecutorConfig
+}
+
+var _ TaskExecutor = (*ReadStructuredExecutor)(nil)
+
+// NewReadStructuredExecutor creates a new ReadStructuredExecutor.
+func NewReadStructuredExecutor() *ReadStructuredExecutor {
+	return NewReadStructuredExecutorWithConfig(ExecutorConfig{})
+}
+
+// NewReadStructuredExecutorWithConfig creates a new ReadStructuredExecutor using the shared executor config.
+func NewReadStructuredExecutorWithConfig(cfg ExecutorConfig) *ReadStructuredExecutor {
+	return &ReadStructuredExecutor{
+		fs:     &defaultFileSystem{locks: cfg.fileLocks(), dirMode: cfg.dirMode()},
+		config: cfg,
+	}
+}
+
+// Execute implements the TaskExecutor interface for ReadStructuredTask.
+// On success Payload holds the parsed document, built from maps, slices, strings,
+// numbers, bools and nils, and ResultData holds it pretty-printed as JSON. TOML
+// integers decode as int64 and TOML dates and times as RFC 3339 strings.
+func (e *ReadStructuredExecutor) Execute(ctx context.Context, readCmd *Task) (<-chan OutputResult, error) {
+	if readCmd.Type != TaskReadStructured {
+		return nil, fmt.Errorf(errReadStructuredInvalidCommandType, readCmd)
+	}
+
+	// Check if task is already in a terminal state
+	terminalChan, err := HandleTerminalTask(readCmd.TaskId, readCmd.Status, readCmd.Output)
+	if err != nil || terminalChan != nil {
+		return terminalChan, err
+	}
+
+	params := readCmd.Parameters.(ReadStructuredParameters)
+	if params.FilePath == "" {
+		return nil, errors.New(errReadStructuredEmptyFilePath)
+	}
+	format, err := structuredFormat(params)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(chan OutputResult, 1)
+	go func() {
+		defer close(results)
+
+		ctx, cancel := e.config.withTimeout(ctx, readCmd)
+		defer cancel()
+
+		startedAt := e.config.clock().Now()
+		readCmd.Status = StatusRunning
+		filePath, payload, err := e.read(ctx, params.FilePath, params.WorkingDirectory, format)
+
+		finalResult := createReadStructuredResult(readCmd.TaskId, filePath, format, payload, err)
+		readCmd.Status = finalResult.Status
+		finalResult.setTimes(e.config.clock(), startedAt)
+		readCmd.UpdateOutput(&finalResult)
+		e.config.send(ctx, results, finalResult)
+	}()
+
+	return results, nil
+}
+
+// structuredFormat returns the format requested by params, inferring it from the
+// file extension when Format is empty.
+func structuredFormat(params ReadStructuredParameters) (string, error) {
+	format := strings.ToLower(params.Format)
+	if format == "" {
+		ext := strings.ToLower(filepath.Ext(params.FilePath))
+		switch ext {
+		case ".json":
+			format = StructuredFormatJSON
+		case ".yaml", ".yml":
+			format = StructuredFormatYAML
+		case ".toml":
+			format = StructuredFormatTOML
+		case "":
+			return "", fmt.Errorf(errReadStructuredUnknownFormat, params.FilePath)
+		default:
+			format = strings.TrimPrefix(ext, ".")
+		}
+	}
+	if format != StructuredFormatJSON && format != StructuredFormatYAML && format != StructuredFormatTOML {
+		return "", fmt.Errorf(errReadStructuredUnsupportedFormat, format)
+	}
+	return format, nil
+}
+
+// read loads and parses the file and returns its resolved path and parsed document.
+func (e *ReadStructuredExecutor) read(ctx context.Context, path, workingDir, format string) (string, any, error) {
+	if err := ctx.Err(); err != nil {
+		return "", nil, err
+	}
+
+	filePath, err := e.config.resolvePath(path, workingDir)
+	if err != nil {
+		return "", nil, fmt.Errorf(errReadStructuredResolveFilePath, err)
+	}
+	data, err := e.fs.ReadFile(filePath)
+	if err != nil {
+		return filePath, nil, fmt.Errorf(errReadStructuredReadFailed, filePath, err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return filePath, nil, err
+	}
+
+	var payload any
+	switch format {
+	case StructuredFormatJSON:
+		payload, err = parseJSONDocument(data)
+	case StructuredFormatYAML:
+		payload, err = parseYAMLDocument(data)
+	default:
+		payload, err = parseTOMLDocument(data)
+	}
+	return filePath, payload, err
+}
+
+// parseJSONDocument decodes a single JSON value, locating syntax errors by line and column.
+func parseJSONDocument(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	var payload any
+	if err := decoder.Decode(&payload); err != nil {
+		var syntaxErr *json.SyntaxError
+		switch {
+		case errors.As(err, &syntaxErr):
+			// Offset counts the bytes read, including the offending one
+			line, column := lineColumn(data, syntaxErr.Offset-1)
+			return nil, &ParseError{Format: StructuredFormatJSON, Line: line, Column: column, Err: err}
+		case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+			line, column := lineColumn(data, int64(len(data)))
+			return nil, &ParseError{Format: StructuredFormatJSON, Line: line, Column: column, Err: io.ErrUnexpectedEOF}
+		default:
+			return nil, &ParseError{Format: StructuredFormatJSON, Err: err}
+		}
+	}
+
+	// Anything but whitespace after the value means the file is not a single document
+	end := decoder.InputOffset()
+	if _, err := decoder.Token(); err != io.EOF {
+		line, column := lineColumn(data, skipJSONSpace(data, end))
+		return nil, &ParseError{Format: StructuredFormatJSON, Line: line, Column: column, Err: errors.New(errReadStructuredTrailingData)}
+	}
+	return payload, nil
+}
+
+// skipJSONSpace returns the offset of the first non-whitespace byte at or after offset.
+func skipJSONSpace(data []byte, offset int64) int64 {
+	for offset < int64(len(data)) && strings.IndexByte(" \t\r\n", data[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+// parseYAMLDocument decodes the first YAML document in data. Mappings are returned as
+// map[string]any, with non-string keys formatted as strings, so that the payload can
+// always be encoded as JSON.
+func parseYAMLDocument(data []byte) (any, error) {
+	var payload any
+	if err := yaml.Unmarshal(data, &payload); err != nil {
+		if m := yamlErrorLine.FindStringSubmatch(err.Error()); m != nil {
+			line, _ := strconv.Atoi(m[1])
+			return nil, &ParseError{Format: StructuredFormatYAML, Line: line, Err: errors.New(m[2])}
+		}
+		return nil, &ParseError{Format: StructuredFormatYAML, Err: err}
+	}
+	return normalizeYAML(payload), nil
+}
+
+// normalizeYAML converts the map[any]any values yaml.v3 produces for mappings with
+// non-string keys into map[string]any.
+func normalizeYAML(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			v[key] = normalizeYAML(value)
+		}
+		return v
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for key, value := range v {
+			m[fmt.Sprint(key)] = normalizeYAML(value)
+		}
+		return m
+	case []any:
+		for i, value := range v {
+			v[i] = normalizeYAML(value)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// lineColumn returns the 1-based line and column of the byte at offset in data.
+func lineColumn(data []byte, offset int64) (int, int) {
+	offset = max(0, min(offset, int64(len(data))))
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := len(before) - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
+
+// createReadStructuredResult constructs the final OutputResult for a ReadStructuredTask.
+func createReadStructuredResult(taskID, filePath, format string, payload any, err error) OutputResult {
+	if err == nil {
+		var pretty []byte
+		pretty, err = json.MarshalIndent(payload, "", "  ")
+		if err == nil {
+			return OutputResult{
+				TaskID:     taskID,
+				Status:     StatusSucceeded,
+				Message:    fmt.Sprintf(msgReadStructuredSucceeded, format, filePath),
+				ResultData: string(pretty),
+				Payload:    payload,
+			}
+		}
+		err = fmt.Errorf(errReadStructuredEncodeFailed, format, err)
+	}
+
+	var message string
+	switch {
+	case errors.Is(err, context.Canceled):
+		message = msgReadStructuredCancelled
+	case errors.Is(err, context.DeadlineExceeded):
+		message = msgReadStructuredTimedOut
+	default:
+		message = fmt.Sprintf(msgReadStructuredFailed, err)
+	}
+	return OutputResult{
+		TaskID:      taskID,
+		Status:      StatusFailed,
+		Message:     message,
+		Error:       err.Error(),
+		Err:         err,
+		FailureKind: failureKind(err),
+	}
+}
@@ -0,0 +1,183 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runReadStructured(t *testing.T, params ReadStructuredParameters) OutputResult {
+	t.Helper()
+	cmd := NewReadStructuredTask("read-structured", "Read structured file", params)
+	resultsChan, err := NewReadStructuredExecutor().Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+	assert.Equal(t, finalResult.Status, cmd.Status)
+	return finalResult
+}
+
+func TestReadStructuredExecutor_Execute(t *testing.T) {
+	expected := map[string]any{
+		"name":    "agent",
+		"retries": float64(3),
+		"enabled": true,
+		"tags":    []any{"a", "b"},
+		"limits":  map[string]any{"cpu": 1.5},
+	}
+
+	testCases := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{
+			name:    "JSON",
+			file:    "config.json",
+			content: `{"name": "agent", "retries": 3, "enabled": true, "tags": ["a", "b"], "limits": {"cpu": 1.5}}`,
+		},
+		{
+			name:    "YAML",
+			file:    "config.yml",
+			content: "name: agent\nretries: 3\nenabled: true\ntags:\n  - a\n  - b\nlimits:\n  cpu: 1.5\n",
+		},
+		{
+			name:    "TOML",
+			file:    "config.toml",
+			content: "name = \"agent\"\nretries = 3\nenabled = true\ntags = [\"a\", \"b\"]\n\n[limits]\ncpu = 1.5\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), tc.file)
+			require.NoError(t, os.WriteFile(filePath, []byte(tc.content), 0644))
+
+			finalResult := runReadStructured(t, ReadStructuredParameters{FilePath: filePath})
+			require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+
+			// YAML and TOML integers decode as integers; compare through JSON to ignore numeric types
+			payload, err := json.Marshal(finalResult.Payload)
+			require.NoError(t, err)
+			want, err := json.Marshal(expected)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(want), string(payload))
+
+			assert.JSONEq(t, string(want), finalResult.ResultData)
+			assert.Contains(t, finalResult.ResultData, "\n  \"enabled\": true", "ResultData should be pretty-printed")
+		})
+	}
+}
+
+func TestReadStructuredExecutor_Execute_YAMLNonStringKeys(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "ports.yaml")
+	require.NoError(t, os.WriteFile(filePath, []byte("ports:\n  80: http\n  443: https\n"), 0644))
+
+	finalResult := runReadStructured(t, ReadStructuredParameters{FilePath: filePath})
+	require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+	assert.Equal(t, map[string]any{"ports": map[string]any{"80": "http", "443": "https"}}, finalResult.Payload)
+}
+
+func TestReadStructuredExecutor_Execute_Malformed(t *testing.T) {
+	testCases := []struct {
+		name    string
+		file    string
+		content string
+		line    int
+		column  int
+		message string
+	}{
+		{
+			name:    "JSONSyntax",
+			file:    "bad.json",
+			content: "{\n  \"a\": 1,\n  \"b\": ]\n}\n",
+			line:    3,
+			column:  8,
+			message: "invalid json at line 3, column 8",
+		},
+		{
+			name:    "JSONTrailingData",
+			file:    "bad.json",
+			content: "{\"a\": 1}\n  {\"b\": 2}\n",
+			line:    2,
+			column:  3,
+			message: "unexpected data after top-level value",
+		},
+		{
+			name:    "JSONTruncated",
+			file:    "bad.json",
+			content: "{\n  \"a\": [1, 2",
+			line:    2,
+			column:  13,
+			message: "unexpected EOF",
+		},
+		{
+			name:    "YAML",
+			file:    "bad.yaml",
+			content: "name: agent\nretries: 3\n  enabled: true\n",
+			line:    3,
+			message: "invalid yaml at line 3: mapping values are not allowed",
+		},
+		{
+			name:    "TOML",
+			file:    "bad.toml",
+			content: "[server]\nhost = \"localhost\"\nport = 80 80\n",
+			line:    3,
+			column:  11,
+			message: "invalid toml at line 3, column 11: expected newline",
+		},
+		{
+			name:    "TOMLDuplicateKey",
+			file:    "bad.toml",
+			content: "name = \"a\"\n  name = \"b\"\n",
+			line:    2,
+			column:  3,
+			message: "key name is already defined",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), tc.file)
+			require.NoError(t, os.WriteFile(filePath, []byte(tc.content), 0644))
+
+			finalResult := runReadStructured(t, ReadStructuredParameters{FilePath: filePath})
+			assert.Equal(t, StatusFailed, finalResult.Status)
+			assert.Nil(t, finalResult.Payload)
+			assert.Contains(t, finalResult.Error, tc.message)
+
+			var parseErr *ParseError
+			require.True(t, errors.As(finalResult.Err, &parseErr), "Err should be a *ParseError, got %v", finalResult.Err)
+			assert.Equal(t, tc.line, parseErr.Line)
+			assert.Equal(t, tc.column, parseErr.Column)
+		})
+	}
+}
+
+func TestReadStructuredExecutor_Execute_InvalidParameters(t *testing.T) {
+	executor := NewReadStructuredExecutor()
+
+	_, err := executor.Execute(context.Background(), NewReadStructuredTask("rs-path", "No path", ReadStructuredParameters{}))
+	assert.ErrorContains(t, err, "file path cannot be empty")
+
+	_, err = executor.Execute(context.Background(), NewReadStructuredTask("rs-ini", "INI", ReadStructuredParameters{FilePath: "config.ini"}))
+	assert.ErrorContains(t, err, "unsupported structured format 'ini'")
+
+	_, err = executor.Execute(context.Background(), NewReadStructuredTask("rs-noext", "No extension", ReadStructuredParameters{FilePath: "Makefile"}))
+	assert.ErrorContains(t, err, "cannot infer structured format")
+
+	// An explicit format overrides the extension
+	filePath := filepath.Join(t.TempDir(), "settings.conf")
+	require.NoError(t, os.WriteFile(filePath, []byte("key: value\n"), 0644))
+	finalResult := runReadStructured(t, ReadStructuredParameters{FilePath: filePath, Format: StructuredFormatYAML})
+	require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+	assert.Equal(t, map[string]any{"key": "value"}, finalResult.Payload)
+}
@@ -1,6 +1,7 @@
 package task
 
 import (
+	"encoding/json"
 	"fmt"
 	"sync"
 )
@@ -24,29 +25,55 @@ type MapRegistry struct {
 // NewMapRegistry creates and returns a new MapRegistry, automatically registering
 // all known standard task executors.
 func NewMapRegistry() *MapRegistry {
+	return NewMapRegistryWithConfig(ExecutorConfig{})
+}
+
+// NewMapRegistryWithConfig creates a new MapRegistry whose standard executors all share
+// the provided ExecutorConfig, so settings such as RootDir and DefaultTimeout
+// apply uniformly without repeating them on every task.
+func NewMapRegistryWithConfig(cfg ExecutorConfig) *MapRegistry {
 	r := &MapRegistry{
 		executors: make(map[TaskType]TaskExecutor),
 	}
 
 	// Register all known executors automatically
-	r.Register(TaskBashExec, NewBashExecExecutor())
-	r.Register(TaskFileRead, NewFileReadExecutor()) // Consider if buffer size needs configuration
-	r.Register(TaskFileWrite, NewFileWriteExecutor())
-	r.Register(TaskPatchFile, NewPatchFileExecutor())
-	r.Register(TaskListDirectory, NewListDirectoryExecutor())
-	r.Register(TaskRequestUserInput, NewRequestUserInputExecutor())
+	r.Register(TaskBashExec, NewBashExecExecutorWithConfig(cfg))
+	r.Register(TaskFileRead, NewFileReadExecutorWithConfig(cfg))
+	r.Register(TaskFileWrite, NewFileWriteExecutorWithConfig(cfg))
+	r.Register(TaskPatchFile, NewPatchFileExecutorWithConfig(cfg))
+	r.Register(TaskListDirectory, NewListDirectoryExecutorWithConfig(cfg))
+	r.Register(TaskRequestUserInput, NewRequestUserInputExecutorWithConfig(cfg))
+	r.Register(TaskWriteFiles, NewWriteFilesExecutorWithConfig(cfg))
+	r.Register(TaskTouch, NewTouchExecutorWithConfig(cfg))
+	r.Register(TaskDiskUsage, NewDiskUsageExecutorWithConfig(cfg))
+	r.Register(TaskWhich, NewWhichExecutorWithConfig(cfg))
+	r.Register(TaskEval, NewEvalExecutorWithConfig(cfg))
+	r.Register(TaskNormalizeEOL, NewNormalizeEOLExecutorWithConfig(cfg))
+	r.Register(TaskFileCompareAndSwap, NewFileCompareAndSwapExecutorWithConfig(cfg))
+	r.Register(TaskReadStructured, NewReadStructuredExecutorWithConfig(cfg))
+	r.Register(TaskExtractJSON, NewExtractJSONExecutorWithConfig(cfg))
+	r.Register(TaskValidatePatch, NewValidatePatchExecutorWithConfig(cfg))
+	r.Register(TaskManifest, NewManifestExecutorWithConfig(cfg))
+	r.Register(TaskReadLink, NewReadLinkExecutorWithConfig(cfg))
+	r.Register(TaskPatchFiles, NewPatchFilesExecutorWithConfig(cfg))
+	r.Register(TaskTruncate, NewTruncateExecutorWithConfig(cfg))
+	r.Register(TaskCompareTrees, NewCompareTreesExecutorWithConfig(cfg))
+	r.Register(TaskFileMove, NewFileMoveExecutorWithConfig(cfg))
+	r.Register(TaskFileCopy, NewFileCopyExecutorWithConfig(cfg))
 
 	// Register the GroupExecutor which needs the registry itself
-	r.Register(TaskGroup, NewGroupExecutor(r))
+	r.Register(TaskGroup, NewGroupExecutorWithConfig(r, cfg))
 
 	// Add future executors here...
 
 	return r
 }
 
-// Register associates a CommandExecutor with a specific CommandType.
+// Register associates a TaskExecutor with a specific TaskType.
 // If an executor is already registered for the given type, it will be overwritten.
-// This is kept public in case users want to override or add custom executors.
+// Besides overriding a standard executor, this adds custom task types: define a
+// TaskType constant, an executor for it, and register its parameters with
+// RegisterParameterDecoder so that tasks of the type can be read from JSON.
 func (r *MapRegistry) Register(cmdType TaskType, executor TaskExecutor) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -65,3 +92,36 @@ func (r *MapRegistry) GetExecutor(cmdType TaskType) (TaskExecutor, error) {
 	}
 	return executor, nil
 }
+
+// ParameterDecoder decodes the raw "parameters" object of a task into the
+// parameters value its executor expects.
+type ParameterDecoder func(data json.RawMessage) (interface{}, error)
+
+// parameterDecoders holds the ParameterDecoders of custom task types by type.
+var (
+	parameterDecodersMu sync.RWMutex
+	parameterDecoders   = map[TaskType]ParameterDecoder{}
+)
+
+// RegisterParameterDecoder makes Task.UnmarshalJSON decode the parameters of tasks of
+// a custom type with decode. The standard types always use their own parameter
+// structs, so a decoder registered for one of them is never called. Parameters of a
+// type without a decoder are dropped when the task is unmarshaled.
+func RegisterParameterDecoder(taskType TaskType, decode ParameterDecoder) {
+	parameterDecodersMu.Lock()
+	defer parameterDecodersMu.Unlock()
+	parameterDecoders[taskType] = decode
+}
+
+// decodeCustomParameters decodes data with the decoder registered for taskType.
+// ok is false when no decoder is registered.
+func decodeCustomParameters(taskType TaskType, data json.RawMessage) (params interface{}, ok bool, err error) {
+	parameterDecodersMu.RLock()
+	decode, found := parameterDecoders[taskType]
+	parameterDecodersMu.RUnlock()
+	if !found {
+		return nil, false, nil
+	}
+	params, err = decode(data)
+	return params, true, err
+}
@@ -2,8 +2,11 @@ package task
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 // MockExecutor is a simple mock for testing registry functionality.
@@ -28,7 +31,7 @@ func TestMapRegistry_NewMapRegistry(t *testing.T) {
 	}
 
 	// After refactoring, the registry should be initialized with standard executors.
-	expectedCount := 7 // Bash, FileRead, FileWrite, PatchFile, ListDir, RequestUserInput, Group
+	expectedCount := 24 // Bash, FileRead, FileWrite, PatchFile, ListDir, RequestUserInput, WriteFiles, Touch, DiskUsage, Which, Eval, NormalizeEOL, FileCompareAndSwap, ReadStructured, ExtractJSON, ValidatePatch, Manifest, ReadLink, PatchFiles, Truncate, CompareTrees, FileMove, FileCopy, Group
 	if len(r.executors) != expectedCount {
 		t.Errorf("Expected initial executors map to contain %d standard executors, got size %d", expectedCount, len(r.executors))
 	}
@@ -106,3 +109,198 @@ func TestMapRegistry_GetExecutor_Unregistered(t *testing.T) {
 		t.Errorf("Expected error message containing '%s', got '%s'", expectedErrorSubstr, err.Error())
 	}
 }
+
+func TestMapRegistryWithConfig_RootDirEnforced(t *testing.T) {
+	rootDir := t.TempDir()
+	outsideFile := filepath.Join(t.TempDir(), "outside.txt")
+	if err := os.WriteFile(outsideFile, []byte("secret\n"), 0644); err != nil {
+		t.Fatalf("Failed to create outside file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(rootDir, "inside.txt"), []byte("hello\n"), 0644); err != nil {
+		t.Fatalf("Failed to create inside file: %v", err)
+	}
+
+	r := NewMapRegistryWithConfig(ExecutorConfig{RootDir: rootDir})
+	executor, err := r.GetExecutor(TaskFileRead)
+	if err != nil {
+		t.Fatalf("GetExecutor failed: %v", err)
+	}
+
+	// A relative path resolves against the root directory
+	insideTask := NewFileReadTask("root-inside", "Read inside root", FileReadParameters{FilePath: "inside.txt"})
+	resultsChan, err := executor.Execute(context.Background(), insideTask)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	result := CombineOutputResults(context.Background(), resultsChan)
+	if result.Status != StatusSucceeded || result.ResultData != "hello\n" {
+		t.Errorf("Expected inside read to succeed with content, got status %s, data %q, error %q", result.Status, result.ResultData, result.Error)
+	}
+
+	// Absolute paths outside the root are rejected
+	for _, path := range []string{outsideFile, "../outside.txt"} {
+		outsideTask := NewFileReadTask("root-outside", "Read outside root", FileReadParameters{FilePath: path})
+		resultsChan, err = executor.Execute(context.Background(), outsideTask)
+		if err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+		result = CombineOutputResults(context.Background(), resultsChan)
+		if result.Status != StatusFailed {
+			t.Errorf("Expected read of %s to fail, got %s", path, result.Status)
+		}
+		if !strings.Contains(result.Error, "outside the root directory") {
+			t.Errorf("Expected root violation error for %s, got %q", path, result.Error)
+		}
+	}
+}
+
+func TestMapRegistryWithConfig_RootDirSymlinkEscape(t *testing.T) {
+	rootDir := t.TempDir()
+	outsideDir := t.TempDir()
+	outsideFile := filepath.Join(outsideDir, "outside.txt")
+	if err := os.WriteFile(outsideFile, []byte("secret\n"), 0644); err != nil {
+		t.Fatalf("Failed to create outside file: %v", err)
+	}
+	links := map[string]string{
+		"file-link": outsideFile,
+		"dir-link":  outsideDir,
+		"dangling":  filepath.Join(outsideDir, "created.txt"),
+		"internal":  "inside.txt",
+	}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(rootDir, name)); err != nil {
+			t.Fatalf("Failed to create symlink %s: %v", name, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(rootDir, "inside.txt"), []byte("hello\n"), 0644); err != nil {
+		t.Fatalf("Failed to create inside file: %v", err)
+	}
+
+	r := NewMapRegistryWithConfig(ExecutorConfig{RootDir: rootDir})
+	readExecutor, err := r.GetExecutor(TaskFileRead)
+	if err != nil {
+		t.Fatalf("GetExecutor failed: %v", err)
+	}
+	writeExecutor, err := r.GetExecutor(TaskFileWrite)
+	if err != nil {
+		t.Fatalf("GetExecutor failed: %v", err)
+	}
+
+	// Links leading outside the root are rejected, whether the file exists or not
+	for _, path := range []string{"file-link", "dir-link/outside.txt", "dir-link/new.txt"} {
+		resultsChan, err := readExecutor.Execute(context.Background(), NewFileReadTask("symlink-read", "Read through symlink", FileReadParameters{FilePath: path}))
+		if err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+		result := CombineOutputResults(context.Background(), resultsChan)
+		if result.Status != StatusFailed || !strings.Contains(result.Error, "outside the root directory") {
+			t.Errorf("Expected read of %s to fail with a root violation, got status %s, error %q", path, result.Status, result.Error)
+		}
+	}
+	for _, path := range []string{"dangling", "dir-link/new.txt"} {
+		resultsChan, err := writeExecutor.Execute(context.Background(), NewFileWriteTask("symlink-write", "Write through symlink", FileWriteParameters{FilePath: path, Content: "pwned"}))
+		if err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+		result := CombineOutputResults(context.Background(), resultsChan)
+		if result.Status != StatusFailed || !strings.Contains(result.Error, "outside the root directory") {
+			t.Errorf("Expected write of %s to fail with a root violation, got status %s, error %q", path, result.Status, result.Error)
+		}
+	}
+	for _, name := range []string{"created.txt", "new.txt"} {
+		if _, err := os.Stat(filepath.Join(outsideDir, name)); !os.IsNotExist(err) {
+			t.Errorf("Expected %s not to be created outside the root, got %v", name, err)
+		}
+	}
+
+	// Links that stay within the root still work
+	resultsChan, err := readExecutor.Execute(context.Background(), NewFileReadTask("symlink-inside", "Read through internal symlink", FileReadParameters{FilePath: "internal"}))
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	result := CombineOutputResults(context.Background(), resultsChan)
+	if result.Status != StatusSucceeded || result.ResultData != "hello\n" {
+		t.Errorf("Expected read through internal symlink to succeed, got status %s, data %q, error %q", result.Status, result.ResultData, result.Error)
+	}
+}
+
+func TestMapRegistryWithConfig_DefaultTimeoutEnforced(t *testing.T) {
+	r := NewMapRegistryWithConfig(ExecutorConfig{DefaultTimeout: 200 * time.Millisecond})
+	executor, err := r.GetExecutor(TaskBashExec)
+	if err != nil {
+		t.Fatalf("GetExecutor failed: %v", err)
+	}
+
+	bashTask := NewBashExecTask("timeout-default", "Sleep past the default timeout", BashExecParameters{Command: "sleep 5"})
+	start := time.Now()
+	resultsChan, err := executor.Execute(context.Background(), bashTask)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	result := CombineOutputResults(context.Background(), resultsChan)
+
+	if result.Status != StatusFailed {
+		t.Errorf("Expected timed out command to fail, got %s", result.Status)
+	}
+	if !strings.Contains(result.Message, "timed out") {
+		t.Errorf("Expected timeout message, got %q", result.Message)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("Default timeout was not enforced, command ran for %v", elapsed)
+	}
+}
+
+func TestMapRegistry_TaskDeadline(t *testing.T) {
+	r := NewMapRegistry()
+	filePath := filepath.Join(t.TempDir(), "deadline.txt")
+	if err := os.WriteFile(filePath, []byte("content\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	newTasks := func(deadline time.Time) []*Task {
+		readTask := NewFileReadTask("deadline-read", "Read with deadline", FileReadParameters{FilePath: filePath})
+		bashTask := NewBashExecTask("deadline-bash", "Run with deadline", BashExecParameters{Command: "echo done"})
+		groupTask := NewGroupTask("deadline-group", "Group with deadline", []*Task{
+			NewBashExecTask("deadline-child", "Child of group with deadline", BashExecParameters{Command: "echo done"}),
+		})
+		tasks := []*Task{readTask, bashTask, groupTask}
+		for _, task := range tasks {
+			task.DeadlineUnix = deadline.Unix()
+		}
+		return tasks
+	}
+
+	t.Run("past deadline fails immediately", func(t *testing.T) {
+		for _, task := range newTasks(time.Now().Add(-time.Hour)) {
+			executor, err := r.GetExecutor(task.Type)
+			if err != nil {
+				t.Fatalf("GetExecutor(%s) failed: %v", task.Type, err)
+			}
+			resultsChan, err := executor.Execute(context.Background(), task)
+			if err != nil {
+				t.Fatalf("Execute(%s) failed: %v", task.Type, err)
+			}
+			result := CombineOutputResults(context.Background(), resultsChan)
+			if result.Status != StatusFailed {
+				t.Errorf("Expected %s with past deadline to fail, got %s", task.Type, result.Status)
+			}
+		}
+	})
+
+	t.Run("future deadline completes", func(t *testing.T) {
+		for _, task := range newTasks(time.Now().Add(time.Hour)) {
+			executor, err := r.GetExecutor(task.Type)
+			if err != nil {
+				t.Fatalf("GetExecutor(%s) failed: %v", task.Type, err)
+			}
+			resultsChan, err := executor.Execute(context.Background(), task)
+			if err != nil {
+				t.Fatalf("Execute(%s) failed: %v", task.Type, err)
+			}
+			result := CombineOutputResults(context.Background(), resultsChan)
+			if result.Status != StatusSucceeded {
+				t.Errorf("Expected %s with future deadline to succeed, got %s: %s", task.Type, result.Status, result.Error)
+			}
+		}
+	})
+}
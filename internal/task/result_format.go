@@ -0,0 +1,139 @@
+package task
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Result formats accepted by FormatResult.
+const (
+	// ResultFormatJSON renders the result as single-line JSON, as WriteJSON does.
+	ResultFormatJSON = "json"
+	// ResultFormatPretty renders one labelled field per line, followed by the output.
+	ResultFormatPretty = "pretty"
+	// ResultFormatText renders a concise one-line summary.
+	ResultFormatText = "text"
+)
+
+// resultFormatters holds the result formats by name.
+var (
+	resultFormattersMu sync.RWMutex
+	resultFormatters   = map[string]func(OutputResult) string{
+		ResultFormatJSON:   formatResultJSON,
+		ResultFormatPretty: formatResultPretty,
+		ResultFormatText:   formatResultText,
+	}
+)
+
+// RegisterResultFormatter makes a result format available to FormatResult under name,
+// replacing any format already registered under it.
+func RegisterResultFormatter(name string, format func(OutputResult) string) {
+	resultFormattersMu.Lock()
+	defer resultFormattersMu.Unlock()
+	resultFormatters[name] = format
+}
+
+// FormatResult renders r for display by a CLI. format is ResultFormatJSON,
+// ResultFormatPretty, ResultFormatText or a name passed to RegisterResultFormatter;
+// an unknown format falls back to ResultFormatText.
+func FormatResult(r OutputResult, format string) string {
+	resultFormattersMu.RLock()
+	formatter, ok := resultFormatters[format]
+	resultFormattersMu.RUnlock()
+	if !ok {
+		formatter = formatResultText
+	}
+	return formatter(r)
+}
+
+// formatResultJSON renders r as JSON without the trailing newline.
+func formatResultJSON(r OutputResult) string {
+	var buf bytes.Buffer
+	if err := r.WriteJSON(&buf); err != nil {
+		// Only a Payload that cannot be encoded gets here
+		return fmt.Sprintf(`{"task_id": %q, "error": %q}`, r.TaskID, err.Error())
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// formatResultPretty renders the fields of r that are set, one per line, followed by
+// the indented ResultData.
+func formatResultPretty(r OutputResult) string {
+	var b strings.Builder
+	field := func(label, value string) {
+		if value != "" {
+			// Continuation lines are aligned with the first
+			value = strings.ReplaceAll(strings.TrimSuffix(value, "\n"), "\n", "\n"+strings.Repeat(" ", 13))
+			fmt.Fprintf(&b, "%-12s %s\n", label+":", value)
+		}
+	}
+
+	name := r.TaskID
+	if r.TaskType != "" {
+		name += " (" + string(r.TaskType) + ")"
+	}
+	field("Task", name)
+	field("Description", r.Description)
+	field("Status", statusName(r.Status))
+	field("Message", r.Message)
+	field("Error", r.Error)
+	field("Failure", string(r.FailureKind))
+	if d := r.Duration(); d > 0 {
+		field("Duration", d.Round(time.Millisecond).String())
+	}
+	if r.Truncated {
+		field("Truncated", "yes")
+	}
+	if r.ResultData != "" {
+		b.WriteString("Output:\n")
+		b.WriteString(prefixLines(r.ResultData, "  "))
+		if !strings.HasSuffix(r.ResultData, "\n") {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// formatResultText renders r as a single line: status, task, message and, for a
+// failure, the error and its kind.
+func formatResultText(r OutputResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] %s", statusName(r.Status), r.TaskID)
+	if r.TaskType != "" {
+		fmt.Fprintf(&b, " (%s)", r.TaskType)
+	}
+	if r.Message != "" {
+		b.WriteString(": ")
+		b.WriteString(singleLine(r.Message))
+	}
+	if r.Error != "" && r.Error != r.Message {
+		b.WriteString(": ")
+		b.WriteString(singleLine(r.Error))
+	}
+	if r.FailureKind != "" {
+		fmt.Fprintf(&b, " [%s]", r.FailureKind)
+	}
+	return b.String()
+}
+
+// statusName returns the display name of status, which is empty while pending.
+func statusName(status TaskStatus) string {
+	if status.IsPending() {
+		return "PENDING"
+	}
+	return string(status)
+}
+
+// singleLine joins the non-blank lines of s with "; ".
+func singleLine(s string) string {
+	var lines []string
+	for line := range strings.Lines(s) {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "; ")
+}
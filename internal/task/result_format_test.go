@@ -0,0 +1,90 @@
+package task
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func formatTestResults() (OutputResult, OutputResult) {
+	started := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	success := OutputResult{
+		TaskID:      "build",
+		TaskType:    TaskBashExec,
+		Description: "Build the project",
+		Status:      StatusSucceeded,
+		Message:     "Command finished",
+		ResultData:  "compiled 3 packages\nok\n",
+		StartedAt:   started,
+		FinishedAt:  started.Add(1500 * time.Millisecond),
+	}
+	failure := OutputResult{
+		TaskID:      "read-config",
+		TaskType:    TaskFileRead,
+		Status:      StatusFailed,
+		Message:     "File reading failed",
+		Error:       "failed to open file 'config.yaml':\nno such file or directory",
+		FailureKind: FailureExecutionError,
+	}
+	return success, failure
+}
+
+func TestFormatResult_JSON(t *testing.T) {
+	success, failure := formatTestResults()
+
+	for _, r := range []OutputResult{success, failure} {
+		formatted := FormatResult(r, ResultFormatJSON)
+		assert.NotContains(t, formatted, "\n")
+
+		var decoded OutputResult
+		require.NoError(t, json.Unmarshal([]byte(formatted), &decoded))
+		assert.Equal(t, r, decoded)
+	}
+}
+
+func TestFormatResult_Pretty(t *testing.T) {
+	success, failure := formatTestResults()
+
+	formatted := FormatResult(success, ResultFormatPretty)
+	assert.Contains(t, formatted, "Task:        build (BASH_EXEC)\n")
+	assert.Contains(t, formatted, "Description: Build the project\n")
+	assert.Contains(t, formatted, "Status:      SUCCEEDED\n")
+	assert.Contains(t, formatted, "Duration:    1.5s\n")
+	assert.True(t, strings.HasSuffix(formatted, "Output:\n  compiled 3 packages\n  ok\n"), formatted)
+	assert.NotContains(t, formatted, "Error:")
+
+	formatted = FormatResult(failure, ResultFormatPretty)
+	assert.Contains(t, formatted, "Status:      FAILED\n")
+	assert.Contains(t, formatted, "Error:       failed to open file 'config.yaml':\n             no such file or directory\n")
+	assert.Contains(t, formatted, "Failure:     EXECUTION_ERROR\n")
+	assert.NotContains(t, formatted, "Output:")
+}
+
+func TestFormatResult_Text(t *testing.T) {
+	success, failure := formatTestResults()
+
+	assert.Equal(t, "[SUCCEEDED] build (BASH_EXEC): Command finished", FormatResult(success, ResultFormatText))
+	assert.Equal(t,
+		"[FAILED] read-config (FILE_READ): File reading failed: failed to open file 'config.yaml':; no such file or directory [EXECUTION_ERROR]",
+		FormatResult(failure, ResultFormatText))
+	assert.Equal(t, "[PENDING] queued", FormatResult(OutputResult{TaskID: "queued"}, ResultFormatText))
+
+	// Unknown formats fall back to the one-line summary
+	assert.Equal(t, FormatResult(failure, ResultFormatText), FormatResult(failure, "yaml"))
+}
+
+func TestRegisterResultFormatter(t *testing.T) {
+	success, _ := formatTestResults()
+	RegisterResultFormatter("status-only", func(r OutputResult) string { return string(r.Status) })
+	t.Cleanup(func() {
+		resultFormattersMu.Lock()
+		delete(resultFormatters, "status-only")
+		resultFormattersMu.Unlock()
+	})
+
+	assert.Equal(t, "SUCCEEDED", FormatResult(success, "status-only"))
+}
@@ -0,0 +1,31 @@
+package task
+
+import "sync"
+
+// resultStore holds the combined output of finished child tasks keyed by TaskId.
+// It is safe for concurrent use so children running in parallel can publish
+// results while dependent children read them.
+type resultStore struct {
+	mu      sync.RWMutex
+	outputs map[string]string
+}
+
+// newResultStore creates an empty resultStore.
+func newResultStore() *resultStore {
+	return &resultStore{outputs: make(map[string]string)}
+}
+
+// set records the output of a finished task.
+func (s *resultStore) set(taskID, output string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.outputs[taskID] = output
+}
+
+// get returns the output of a finished task and whether it has been recorded.
+func (s *resultStore) get(taskID string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	output, ok := s.outputs[taskID]
+	return output, ok
+}
@@ -0,0 +1,92 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestResultStore_ParallelChildren runs children concurrently, as the parallel
+// group mode does, while a dependent child waits for and reads a stored result.
+// Run with -race to verify the store's synchronization.
+func TestResultStore_ParallelChildren(t *testing.T) {
+	const numChildren = 8
+	executor := NewGroupExecutor(NewMapRegistry())
+	group := NewGroupTask("group", "Parallel children", nil)
+	store := newResultStore()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	// Drain forwarded child results
+	parentResults := make(chan OutputResult)
+	drained := make(chan struct{})
+	go func() {
+		defer close(drained)
+		for range parentResults {
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < numChildren; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			child := NewBashExecTask(fmt.Sprintf("child-%d", i), "Parallel child", BashExecParameters{
+				Command: fmt.Sprintf("echo value-%d", i),
+			})
+			result := executor.processChildTask(ctx, child, parentResults, group, GroupParameters{}, i, numChildren)
+			if result.Status == StatusSucceeded {
+				store.set(child.TaskId, result.ResultData)
+			}
+		}(i)
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "dependent.txt")
+	dependent := NewFileWriteTask("dependent", "Reads child-3 output", FileWriteParameters{
+		FilePath: outputPath,
+		Content:  "${child-3.result:1}",
+	})
+	dependent.DependsOn = []string{"child-3"}
+
+	var dependentResult OutputResult
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			if _, ok := store.get("child-3"); ok {
+				break
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		if err := resolveDependencies(dependent, store); err != nil {
+			dependentResult = OutputResult{Status: StatusFailed, Error: err.Error()}
+			return
+		}
+		dependentResult = executor.processChildTask(ctx, dependent, parentResults, group, GroupParameters{}, numChildren, numChildren+1)
+	}()
+
+	wg.Wait()
+	close(parentResults)
+	<-drained
+
+	for i := 0; i < numChildren; i++ {
+		output, ok := store.get(fmt.Sprintf("child-%d", i))
+		require.True(t, ok, "Missing result for child-%d", i)
+		assert.Contains(t, output, fmt.Sprintf("value-%d\n", i))
+	}
+
+	require.Equal(t, StatusSucceeded, dependentResult.Status, dependentResult.Error)
+	content, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Equal(t, "value-3", string(content))
+}
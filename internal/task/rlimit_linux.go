@@ -0,0 +1,40 @@
+//go:build linux
+
+package task
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// resourceLimitsSupported reports whether BASH_EXEC can apply CPU and memory limits.
+const resourceLimitsSupported = true
+
+// setProcessLimits applies the task's resource limits to the running process pid with
+// prlimit(2); every process it starts afterwards inherits them. The CPU limit is soft so
+// that the kernel sends SIGXCPU when it is reached, with a hard limit one second later
+// forcing SIGKILL.
+func setProcessLimits(pid int, params BashExecParameters) error {
+	if params.CPUTimeLimitSeconds > 0 {
+		limit := syscall.Rlimit{Cur: uint64(params.CPUTimeLimitSeconds), Max: uint64(params.CPUTimeLimitSeconds) + 1}
+		if err := prlimit(pid, syscall.RLIMIT_CPU, &limit); err != nil {
+			return err
+		}
+	}
+	if params.MemoryLimitBytes > 0 {
+		limit := syscall.Rlimit{Cur: uint64(params.MemoryLimitBytes), Max: uint64(params.MemoryLimitBytes)}
+		if err := prlimit(pid, syscall.RLIMIT_AS, &limit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prlimit sets the resource limit of process pid.
+func prlimit(pid, resource int, limit *syscall.Rlimit) error {
+	_, _, errno := syscall.RawSyscall6(syscall.SYS_PRLIMIT64, uintptr(pid), uintptr(resource), uintptr(unsafe.Pointer(limit)), 0, 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package task
+
+import "errors"
+
+// resourceLimitsSupported reports whether BASH_EXEC can apply CPU and memory limits.
+const resourceLimitsSupported = false
+
+// setProcessLimits is not supported on this platform.
+func setProcessLimits(pid int, params BashExecParameters) error {
+	return errors.New(errBashLimitsUnsupported)
+}
@@ -0,0 +1,142 @@
+package task
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Error constants for result reference substitution
+const (
+	errReferenceNotDependency = "parameter references task '%s' which is not listed in depends_on"
+	errReferenceNoResult      = "parameter references task '%s' which has no result"
+	errReferenceLineRange     = "parameter references line %d of task '%s' which has %d lines"
+)
+
+// resultReferencePattern matches ${<task_id>.result} and ${<task_id>.result:N}.
+var resultReferencePattern = regexp.MustCompile(`\$\{([^{}]+?)\.result(?::(\d+))?\}`)
+
+// substituteResultReferences returns a copy of params in which every result reference
+// inside a string field has been replaced with the referenced task's output.
+// References may only name tasks listed in dependsOn. A plain reference expands to the
+// output with surrounding whitespace trimmed; a line reference expands to the Nth
+// line (1-based) of the output.
+func substituteResultReferences(params any, dependsOn []string, outputs *resultStore) (any, error) {
+	if params == nil {
+		return nil, nil
+	}
+
+	replace := func(s string) (string, error) {
+		var replaceErr error
+		result := resultReferencePattern.ReplaceAllStringFunc(s, func(ref string) string {
+			if replaceErr != nil {
+				return ref
+			}
+			match := resultReferencePattern.FindStringSubmatch(ref)
+			value, err := resolveResultReference(match[1], match[2], dependsOn, outputs)
+			if err != nil {
+				replaceErr = err
+				return ref
+			}
+			return value
+		})
+		return result, replaceErr
+	}
+
+	copied := reflect.New(reflect.TypeOf(params)).Elem()
+	copied.Set(reflect.ValueOf(params))
+	if err := substituteValue(copied, replace); err != nil {
+		return nil, err
+	}
+	return copied.Interface(), nil
+}
+
+// resolveResultReference looks up the output for a single reference.
+func resolveResultReference(taskID, line string, dependsOn []string, outputs *resultStore) (string, error) {
+	if !slices.Contains(dependsOn, taskID) {
+		return "", fmt.Errorf(errReferenceNotDependency, taskID)
+	}
+	output, ok := outputs.get(taskID)
+	if !ok {
+		return "", fmt.Errorf(errReferenceNoResult, taskID)
+	}
+	if line == "" {
+		return strings.TrimSpace(output), nil
+	}
+
+	lineNum, _ := strconv.Atoi(line)
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if lineNum < 1 || lineNum > len(lines) {
+		return "", fmt.Errorf(errReferenceLineRange, lineNum, taskID, len(lines))
+	}
+	return strings.TrimRight(lines[lineNum-1], "\r"), nil
+}
+
+// substituteValue applies replace to every settable string reachable from v.
+// Slices, maps and pointers are copied before modification so the original
+// parameters are never changed.
+func substituteValue(v reflect.Value, replace func(string) (string, error)) error {
+	switch v.Kind() {
+	case reflect.String:
+		s, err := replace(v.String())
+		if err != nil {
+			return err
+		}
+		v.SetString(s)
+
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Field(i)
+			if !field.CanSet() {
+				continue
+			}
+			if err := substituteValue(field, replace); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(copied, v)
+		for i := 0; i < copied.Len(); i++ {
+			if err := substituteValue(copied.Index(i), replace); err != nil {
+				return err
+			}
+		}
+		v.Set(copied)
+
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(iter.Value())
+			if err := substituteValue(elem, replace); err != nil {
+				return err
+			}
+			copied.SetMapIndex(iter.Key(), elem)
+		}
+		v.Set(copied)
+
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+		copied := reflect.New(v.Type().Elem())
+		copied.Elem().Set(v.Elem())
+		if err := substituteValue(copied.Elem(), replace); err != nil {
+			return err
+		}
+		v.Set(copied)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package task
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubstituteResultReferences(t *testing.T) {
+	outputs := newResultStore()
+	outputs.set("a", "  first line\nsecond line\n")
+	outputs.set("b", "value")
+
+	params := WriteFilesParameters{
+		Files: []FileWriteEntry{
+			{Path: "${a.result:2}.txt", Content: "${a.result} and ${b.result}"},
+		},
+	}
+
+	substituted, err := substituteResultReferences(params, []string{"a", "b"}, outputs)
+	require.NoError(t, err)
+
+	got := substituted.(WriteFilesParameters)
+	assert.Equal(t, "second line.txt", got.Files[0].Path)
+	assert.Equal(t, "first line\nsecond line and value", got.Files[0].Content)
+
+	// The original parameters must be left untouched
+	assert.Equal(t, "${a.result:2}.txt", params.Files[0].Path)
+}
+
+func TestSubstituteResultReferences_Errors(t *testing.T) {
+	outputs := newResultStore()
+	outputs.set("a", "one line")
+
+	testCases := []struct {
+		name      string
+		value     string
+		dependsOn []string
+		errSubstr string
+	}{
+		{"NotADependency", "${a.result}", nil, "not listed in depends_on"},
+		{"NoResult", "${c.result}", []string{"c"}, "has no result"},
+		{"LineOutOfRange", "${a.result:3}", []string{"a"}, "line 3"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			params := FileReadParameters{FilePath: tc.value}
+			_, err := substituteResultReferences(params, tc.dependsOn, outputs)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.errSubstr)
+		})
+	}
+}
+
+func TestSubstituteResultReferences_NoReferences(t *testing.T) {
+	params := BashExecParameters{Command: "echo ${HOME}"}
+	substituted, err := substituteResultReferences(params, nil, newResultStore())
+	require.NoError(t, err)
+	assert.Equal(t, params.Command, substituted.(BashExecParameters).Command)
+}
@@ -0,0 +1,693 @@
+package task
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// TOML value syntax, checked before the values are converted so that forms Go accepts
+// but TOML does not, such as leading zeros or a sign on a hex integer, are rejected.
+var (
+	tomlDecimal  = regexp.MustCompile(`^[+-]?(0|[1-9](_?[0-9])*)$`)
+	tomlPrefixed = regexp.MustCompile(`^0(x[0-9A-Fa-f](_?[0-9A-Fa-f])*|o[0-7](_?[0-7])*|b[01](_?[01])*)$`)
+	tomlFloat    = regexp.MustCompile(`^[+-]?(0|[1-9](_?[0-9])*)(\.[0-9](_?[0-9])*)?([eE][+-]?[0-9](_?[0-9])*)?$`)
+	tomlDate     = regexp.MustCompile(`^[0-9]{4}-[0-9]{2}-[0-9]{2}$`)
+	tomlDateTime = regexp.MustCompile(`^([0-9]{4}-[0-9]{2}-[0-9]{2})[Tt ]([0-9]{2}:[0-9]{2}:[0-9]{2}(\.[0-9]+)?)([Zz]|[+-][0-9]{2}:[0-9]{2})?$`)
+	tomlTime     = regexp.MustCompile(`^[0-9]{2}:[0-9]{2}:[0-9]{2}(\.[0-9]+)?$`)
+)
+
+// tomlTable is a table being decoded, recording how it was defined so that the
+// redefinitions TOML forbids can be detected.
+type tomlTable struct {
+	values map[string]any
+	// defined is set for a table opened by a [table] header
+	defined bool
+	// dotted is set for a table created by a dotted key
+	dotted bool
+	// inline is set for an inline table, which cannot be extended
+	inline bool
+}
+
+func newTOMLTable() *tomlTable {
+	return &tomlTable{values: make(map[string]any)}
+}
+
+// tomlTableArray is an array of tables built from [[table]] headers.
+type tomlTableArray struct {
+	tables []*tomlTable
+}
+
+// tomlDecoder decodes a TOML 1.0 document.
+type tomlDecoder struct {
+	data    []byte
+	pos     int
+	root    *tomlTable
+	current *tomlTable
+}
+
+// parseTOMLDocument decodes a TOML document into map[string]any. Integers decode as
+// int64, floats as float64, and dates and times as strings in RFC 3339 form.
+func parseTOMLDocument(data []byte) (any, error) {
+	d := &tomlDecoder{data: data, root: newTOMLTable()}
+	d.current = d.root
+	if err := d.decode(); err != nil {
+		return nil, err
+	}
+	return tomlValue(d.root), nil
+}
+
+// tomlValue converts a decoded value into the maps and slices of a payload.
+func tomlValue(v any) any {
+	switch v := v.(type) {
+	case *tomlTable:
+		m := make(map[string]any, len(v.values))
+		for key, value := range v.values {
+			m[key] = tomlValue(value)
+		}
+		return m
+	case *tomlTableArray:
+		tables := make([]any, len(v.tables))
+		for i, table := range v.tables {
+			tables[i] = tomlValue(table)
+		}
+		return tables
+	case []any:
+		for i, value := range v {
+			v[i] = tomlValue(value)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// errorf returns a ParseError locating the byte at offset.
+func (d *tomlDecoder) errorf(offset int, format string, args ...any) error {
+	line, column := lineColumn(d.data, int64(offset))
+	return &ParseError{Format: StructuredFormatTOML, Line: line, Column: column, Err: fmt.Errorf(format, args...)}
+}
+
+func (d *tomlDecoder) eof() bool {
+	return d.pos >= len(d.data)
+}
+
+// peek returns the current byte, or 0 at the end of the document.
+func (d *tomlDecoder) peek() byte {
+	if d.eof() {
+		return 0
+	}
+	return d.data[d.pos]
+}
+
+func (d *tomlDecoder) hasPrefix(prefix string) bool {
+	return bytes.HasPrefix(d.data[d.pos:], []byte(prefix))
+}
+
+func (d *tomlDecoder) decode() error {
+	if i := invalidUTF8(d.data); i >= 0 {
+		return d.errorf(i, "invalid UTF-8")
+	}
+	if bytes.HasPrefix(d.data, bomUTF8) {
+		d.pos = len(bomUTF8)
+	}
+	for {
+		d.skipSpace()
+		switch {
+		case d.eof():
+			return nil
+		case d.peek() == '#' || d.peek() == '\n' || d.peek() == '\r':
+			if err := d.endLine(); err != nil {
+				return err
+			}
+		case d.peek() == '[':
+			if err := d.header(); err != nil {
+				return err
+			}
+		default:
+			if err := d.keyValue(d.current); err != nil {
+				return err
+			}
+			if err := d.endLine(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// invalidUTF8 returns the offset of the first invalid UTF-8 sequence in data, or -1.
+func invalidUTF8(data []byte) int {
+	for i := 0; i < len(data); {
+		r, size := utf8.DecodeRune(data[i:])
+		if r == utf8.RuneError && size == 1 {
+			return i
+		}
+		i += size
+	}
+	return -1
+}
+
+// skipSpace skips spaces and tabs.
+func (d *tomlDecoder) skipSpace() {
+	for d.peek() == ' ' || d.peek() == '\t' {
+		d.pos++
+	}
+}
+
+// endLine consumes an optional comment and the newline ending a line.
+func (d *tomlDecoder) endLine() error {
+	d.skipSpace()
+	if d.peek() == '#' {
+		for !d.eof() && d.peek() != '\n' {
+			if c := d.peek(); c == '\r' && d.hasPrefix("\r\n") {
+				break
+			} else if isTOMLControl(c) {
+				return d.errorf(d.pos, "control character %q in comment", c)
+			}
+			d.pos++
+		}
+	}
+	switch {
+	case d.eof():
+		return nil
+	case d.peek() == '\n':
+		d.pos++
+		return nil
+	case d.hasPrefix("\r\n"):
+		d.pos += 2
+		return nil
+	default:
+		return d.errorf(d.pos, "expected newline, found %q", d.peek())
+	}
+}
+
+// skipBlank skips whitespace, newlines and comments, as allowed between array values.
+func (d *tomlDecoder) skipBlank() error {
+	for {
+		d.skipSpace()
+		if d.eof() || (d.peek() != '#' && d.peek() != '\n' && d.peek() != '\r') {
+			return nil
+		}
+		if err := d.endLine(); err != nil {
+			return err
+		}
+	}
+}
+
+// isTOMLControl reports whether c is a control character other than tab, which TOML
+// does not allow in comments and strings.
+func isTOMLControl(c byte) bool {
+	return (c < 0x20 && c != '\t') || c == 0x7f
+}
+
+func isBareKeyChar(c byte) bool {
+	return c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+// header decodes a [table] or [[table]] header and makes it the current table.
+func (d *tomlDecoder) header() error {
+	start := d.pos
+	array := d.hasPrefix("[[")
+	if array {
+		d.pos += 2
+	} else {
+		d.pos++
+	}
+	keys, err := d.key()
+	if err != nil {
+		return err
+	}
+	d.skipSpace()
+	closing := "]"
+	if array {
+		closing = "]]"
+	}
+	if !d.hasPrefix(closing) {
+		return d.errorf(d.pos, "expected %q to close table header", closing)
+	}
+	d.pos += len(closing)
+	if err := d.endLine(); err != nil {
+		return err
+	}
+
+	table := d.root
+	for i, key := range keys {
+		name := strings.Join(keys[:i+1], ".")
+		last := i == len(keys)-1
+		existing, ok := table.values[key]
+		if last && array {
+			if !ok {
+				existing = &tomlTableArray{}
+				table.values[key] = existing
+			}
+			tables, ok := existing.(*tomlTableArray)
+			if !ok {
+				return d.errorf(start, "cannot define array of tables %s: key is already defined", name)
+			}
+			d.current = newTOMLTable()
+			d.current.defined = true
+			tables.tables = append(tables.tables, d.current)
+			return nil
+		}
+		if !ok {
+			child := newTOMLTable()
+			table.values[key] = child
+			table = child
+			continue
+		}
+		switch existing := existing.(type) {
+		case *tomlTable:
+			if existing.inline {
+				return d.errorf(start, "cannot extend inline table %s", name)
+			}
+			if last && (existing.defined || existing.dotted) {
+				return d.errorf(start, "table %s is already defined", name)
+			}
+			table = existing
+		case *tomlTableArray:
+			if last {
+				return d.errorf(start, "table %s is already defined as an array of tables", name)
+			}
+			table = existing.tables[len(existing.tables)-1]
+		default:
+			return d.errorf(start, "key %s is already defined as a value", name)
+		}
+	}
+	table.defined = true
+	d.current = table
+	return nil
+}
+
+// key decodes a possibly dotted key.
+func (d *tomlDecoder) key() ([]string, error) {
+	var keys []string
+	for {
+		d.skipSpace()
+		var key string
+		switch c := d.peek(); {
+		case c == '"':
+			s, err := d.basicString()
+			if err != nil {
+				return nil, err
+			}
+			key = s
+		case c == '\'':
+			s, err := d.literalString()
+			if err != nil {
+				return nil, err
+			}
+			key = s
+		default:
+			start := d.pos
+			for isBareKeyChar(d.peek()) {
+				d.pos++
+			}
+			if d.pos == start {
+				if d.eof() {
+					return nil, d.errorf(d.pos, "expected key, found end of file")
+				}
+				return nil, d.errorf(d.pos, "expected key, found %q", d.peek())
+			}
+			key = string(d.data[start:d.pos])
+		}
+		keys = append(keys, key)
+		d.skipSpace()
+		if d.peek() != '.' {
+			return keys, nil
+		}
+		d.pos++
+	}
+}
+
+// keyValue decodes a key/value pair into table.
+func (d *tomlDecoder) keyValue(table *tomlTable) error {
+	start := d.pos
+	keys, err := d.key()
+	if err != nil {
+		return err
+	}
+	if d.peek() != '=' {
+		return d.errorf(d.pos, "expected '=' after key %s", strings.Join(keys, "."))
+	}
+	d.pos++
+	d.skipSpace()
+	value, err := d.value()
+	if err != nil {
+		return err
+	}
+
+	for i, key := range keys[:len(keys)-1] {
+		name := strings.Join(keys[:i+1], ".")
+		switch existing := table.values[key].(type) {
+		case nil:
+			child := newTOMLTable()
+			child.dotted = true
+			table.values[key] = child
+			table = child
+		case *tomlTable:
+			if existing.inline {
+				return d.errorf(start, "cannot extend inline table %s", name)
+			}
+			if !existing.dotted {
+				return d.errorf(start, "cannot add keys to table %s with a dotted key", name)
+			}
+			table = existing
+		default:
+			return d.errorf(start, "key %s is already defined", name)
+		}
+	}
+	last := keys[len(keys)-1]
+	if _, ok := table.values[last]; ok {
+		return d.errorf(start, "key %s is already defined", strings.Join(keys, "."))
+	}
+	table.values[last] = value
+	return nil
+}
+
+// value decodes the value starting at the current position.
+func (d *tomlDecoder) value() (any, error) {
+	switch {
+	case d.eof():
+		return nil, d.errorf(d.pos, "expected value, found end of file")
+	case d.hasPrefix(`"""`):
+		return d.multilineString(`"""`, true)
+	case d.hasPrefix(`'''`):
+		return d.multilineString(`'''`, false)
+	case d.peek() == '"':
+		return d.basicString()
+	case d.peek() == '\'':
+		return d.literalString()
+	case d.peek() == '[':
+		return d.array()
+	case d.peek() == '{':
+		return d.inlineTable()
+	}
+
+	start := d.pos
+	d.scanToken()
+	// A local date may be followed by a space and a time
+	if tomlDate.Match(d.data[start:d.pos]) && d.hasPrefix(" ") && d.pos+3 < len(d.data) &&
+		isDigit(d.data[d.pos+1]) && isDigit(d.data[d.pos+2]) && d.data[d.pos+3] == ':' {
+		d.pos++
+		d.scanToken()
+	}
+	token := string(d.data[start:d.pos])
+	if token == "" {
+		return nil, d.errorf(start, "expected value, found %q", d.peek())
+	}
+	value, err := tomlScalar(token)
+	if err != nil {
+		return nil, d.errorf(start, "%v", err)
+	}
+	return value, nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// scanToken advances over the characters that can make up a bare value.
+func (d *tomlDecoder) scanToken() {
+	for !d.eof() && (isBareKeyChar(d.peek()) || strings.IndexByte("+.:", d.peek()) >= 0) {
+		d.pos++
+	}
+}
+
+// tomlScalar converts a boolean, number, date or time.
+func tomlScalar(token string) (any, error) {
+	switch token {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "inf", "+inf":
+		return math.Inf(1), nil
+	case "-inf":
+		return math.Inf(-1), nil
+	case "nan", "+nan", "-nan":
+		return math.NaN(), nil
+	}
+	switch {
+	case tomlDecimal.MatchString(token), tomlPrefixed.MatchString(token):
+		n, err := strconv.ParseInt(token, 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("integer %s is out of range", token)
+		}
+		return n, nil
+	case tomlFloat.MatchString(token):
+		f, err := strconv.ParseFloat(strings.ReplaceAll(token, "_", ""), 64)
+		if err != nil {
+			return nil, fmt.Errorf("float %s is out of range", token)
+		}
+		return f, nil
+	case tomlDate.MatchString(token):
+		if _, err := time.Parse(time.DateOnly, token); err != nil {
+			return nil, fmt.Errorf("invalid date %s", token)
+		}
+		return token, nil
+	case tomlTime.MatchString(token):
+		if _, err := time.Parse("15:04:05.999999999", token); err != nil {
+			return nil, fmt.Errorf("invalid time %s", token)
+		}
+		return token, nil
+	}
+	if m := tomlDateTime.FindStringSubmatch(token); m != nil {
+		normalized := m[1] + "T" + m[2] + strings.ToUpper(m[4])
+		layout := "2006-01-02T15:04:05.999999999"
+		if m[4] != "" {
+			layout = time.RFC3339Nano
+		}
+		if _, err := time.Parse(layout, normalized); err != nil {
+			return nil, fmt.Errorf("invalid date-time %s", token)
+		}
+		return normalized, nil
+	}
+	return nil, fmt.Errorf("invalid value %s", token)
+}
+
+// basicString decodes a single-line "basic" string.
+func (d *tomlDecoder) basicString() (string, error) {
+	start := d.pos
+	d.pos++
+	var b strings.Builder
+	for {
+		switch c := d.peek(); {
+		case d.eof() || c == '\n' || c == '\r':
+			return "", d.errorf(start, "unterminated string")
+		case c == '"':
+			d.pos++
+			return b.String(), nil
+		case c == '\\':
+			if err := d.escape(&b); err != nil {
+				return "", err
+			}
+		case isTOMLControl(c):
+			return "", d.errorf(d.pos, "control character %q in string", c)
+		default:
+			b.WriteByte(c)
+			d.pos++
+		}
+	}
+}
+
+// literalString decodes a single-line 'literal' string.
+func (d *tomlDecoder) literalString() (string, error) {
+	start := d.pos
+	d.pos++
+	for {
+		switch c := d.peek(); {
+		case d.eof() || c == '\n' || c == '\r':
+			return "", d.errorf(start, "unterminated string")
+		case c == '\'':
+			d.pos++
+			return string(d.data[start+1 : d.pos-1]), nil
+		case isTOMLControl(c):
+			return "", d.errorf(d.pos, "control character %q in string", c)
+		default:
+			d.pos++
+		}
+	}
+}
+
+// multilineString decodes a string delimited by delim, three double or single quotes,
+// processing escapes in the former. A newline right after the opening delimiter is trimmed.
+func (d *tomlDecoder) multilineString(delim string, escapes bool) (string, error) {
+	start := d.pos
+	d.pos += len(delim)
+	if d.peek() == '\n' {
+		d.pos++
+	} else if d.hasPrefix("\r\n") {
+		d.pos += 2
+	}
+	var b strings.Builder
+	for {
+		switch c := d.peek(); {
+		case d.eof():
+			return "", d.errorf(start, "unterminated string")
+		case d.hasPrefix(delim):
+			// Up to two quotes may directly precede the closing delimiter
+			n := 0
+			for d.pos+n < len(d.data) && d.data[d.pos+n] == delim[0] {
+				n++
+			}
+			if n > len(delim)+2 {
+				return "", d.errorf(d.pos, "too many quotes closing string")
+			}
+			b.WriteString(delim[:n-len(delim)])
+			d.pos += n
+			return b.String(), nil
+		case escapes && c == '\\':
+			if d.lineEndingBackslash() {
+				continue
+			}
+			if err := d.escape(&b); err != nil {
+				return "", err
+			}
+		case c == '\n':
+			b.WriteByte(c)
+			d.pos++
+		case d.hasPrefix("\r\n"):
+			b.WriteString("\r\n")
+			d.pos += 2
+		case isTOMLControl(c):
+			return "", d.errorf(d.pos, "control character %q in string", c)
+		default:
+			b.WriteByte(c)
+			d.pos++
+		}
+	}
+}
+
+// lineEndingBackslash skips a backslash ending a line of a multi-line basic string,
+// together with the whitespace and newlines after it, reporting whether it did.
+func (d *tomlDecoder) lineEndingBackslash() bool {
+	i := d.pos + 1
+	for i < len(d.data) && (d.data[i] == ' ' || d.data[i] == '\t') {
+		i++
+	}
+	if i < len(d.data) && d.data[i] == '\r' {
+		i++
+	}
+	if i >= len(d.data) || d.data[i] != '\n' {
+		return false
+	}
+	for i < len(d.data) && strings.IndexByte(" \t\r\n", d.data[i]) >= 0 {
+		i++
+	}
+	d.pos = i
+	return true
+}
+
+// escape decodes the escape sequence at the current position into b.
+func (d *tomlDecoder) escape(b *strings.Builder) error {
+	start := d.pos
+	d.pos++
+	c := d.peek()
+	d.pos++
+	switch c {
+	case 'b':
+		b.WriteByte('\b')
+	case 't':
+		b.WriteByte('\t')
+	case 'n':
+		b.WriteByte('\n')
+	case 'f':
+		b.WriteByte('\f')
+	case 'r':
+		b.WriteByte('\r')
+	case '"':
+		b.WriteByte('"')
+	case '\\':
+		b.WriteByte('\\')
+	case 'u', 'U':
+		digits := 4
+		if c == 'U' {
+			digits = 8
+		}
+		if d.pos+digits > len(d.data) {
+			return d.errorf(start, "invalid unicode escape")
+		}
+		code, err := strconv.ParseUint(string(d.data[d.pos:d.pos+digits]), 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return d.errorf(start, "invalid unicode escape")
+		}
+		b.WriteRune(rune(code))
+		d.pos += digits
+	default:
+		return d.errorf(start, "invalid escape sequence")
+	}
+	return nil
+}
+
+// array decodes an array, which may span lines and contain comments.
+func (d *tomlDecoder) array() ([]any, error) {
+	start := d.pos
+	d.pos++
+	values := []any{}
+	for {
+		if err := d.skipBlank(); err != nil {
+			return nil, err
+		}
+		if d.eof() {
+			return nil, d.errorf(start, "unterminated array")
+		}
+		if d.peek() == ']' {
+			d.pos++
+			return values, nil
+		}
+		value, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		if err := d.skipBlank(); err != nil {
+			return nil, err
+		}
+		switch {
+		case d.peek() == ',':
+			d.pos++
+		case d.peek() == ']':
+			d.pos++
+			return values, nil
+		case d.eof():
+			return nil, d.errorf(start, "unterminated array")
+		default:
+			return nil, d.errorf(d.pos, "expected ',' or ']' in array, found %q", d.peek())
+		}
+	}
+}
+
+// inlineTable decodes an inline table, which must fit on one line.
+func (d *tomlDecoder) inlineTable() (*tomlTable, error) {
+	d.pos++
+	table := newTOMLTable()
+	d.skipSpace()
+	if d.peek() == '}' {
+		d.pos++
+		table.inline = true
+		return table, nil
+	}
+	for {
+		if err := d.keyValue(table); err != nil {
+			return nil, err
+		}
+		d.skipSpace()
+		switch {
+		case d.peek() == ',':
+			d.pos++
+		case d.peek() == '}':
+			d.pos++
+			table.inline = true
+			return table, nil
+		case d.eof():
+			return nil, d.errorf(d.pos, "unterminated inline table")
+		default:
+			return nil, d.errorf(d.pos, "expected ',' or '}' in inline table, found %q", d.peek())
+		}
+	}
+}
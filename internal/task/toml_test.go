@@ -0,0 +1,130 @@
+package task
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTOMLDocument(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  string
+		expected map[string]any
+	}{
+		{
+			name:     "Integers",
+			content:  "dec = +1_000\nneg = -17\nhex = 0xdead_beef\noct = 0o755\nbin = 0b1101\n",
+			expected: map[string]any{"dec": int64(1000), "neg": int64(-17), "hex": int64(0xdeadbeef), "oct": int64(0o755), "bin": int64(13)},
+		},
+		{
+			name:     "Floats",
+			content:  "pi = 3.14_15\nexp = -2E-2\nint_exp = 5e+22\n",
+			expected: map[string]any{"pi": 3.1415, "exp": -0.02, "int_exp": 5e22},
+		},
+		{
+			name:    "Strings",
+			content: "basic = \"tab\\there \\u00e9\"\nliteral = 'C:\\path'\nmulti = \"\"\"\none \\\n    two\"\"\"\"\nraw = '''\nline\n'''\n",
+			expected: map[string]any{
+				"basic":   "tab\there é",
+				"literal": `C:\path`,
+				"multi":   "one two\"",
+				"raw":     "line\n",
+			},
+		},
+		{
+			name:    "DatesAndTimes",
+			content: "odt = 1979-05-27 07:32:00.5z\noffset = 1979-05-27T00:32:00-07:00\nldt = 1979-05-27T07:32:00\ndate = 1979-05-27\ntime = 07:32:00\n",
+			expected: map[string]any{
+				"odt":    "1979-05-27T07:32:00.5Z",
+				"offset": "1979-05-27T00:32:00-07:00",
+				"ldt":    "1979-05-27T07:32:00",
+				"date":   "1979-05-27",
+				"time":   "07:32:00",
+			},
+		},
+		{
+			name:    "Tables",
+			content: "# comment\ntitle = \"t\" # trailing\n[owner]\nname.first = \"a\"\n\"quoted key\" = true\n\n[a.b.c]\nd = 1\n[a]\ne = 2\n[owner.name.extra]\nf = 3\n",
+			expected: map[string]any{
+				"title": "t",
+				"owner": map[string]any{
+					"name":       map[string]any{"first": "a", "extra": map[string]any{"f": int64(3)}},
+					"quoted key": true,
+				},
+				"a": map[string]any{"b": map[string]any{"c": map[string]any{"d": int64(1)}}, "e": int64(2)},
+			},
+		},
+		{
+			name:    "ArraysAndInlineTables",
+			content: "nested = [ [1, 2], [\"a\"], ]\nmulti = [\n  1, # one\n  2\n]\npoint = { x = 1, y.z = 2 }\n\n[[fruit]]\nname = \"apple\"\n[fruit.physical]\ncolor = \"red\"\n[[fruit]]\nname = \"banana\"\n",
+			expected: map[string]any{
+				"nested": []any{[]any{int64(1), int64(2)}, []any{"a"}},
+				"multi":  []any{int64(1), int64(2)},
+				"point":  map[string]any{"x": int64(1), "y": map[string]any{"z": int64(2)}},
+				"fruit": []any{
+					map[string]any{"name": "apple", "physical": map[string]any{"color": "red"}},
+					map[string]any{"name": "banana"},
+				},
+			},
+		},
+		{
+			name:     "CRLFAndBOM",
+			content:  "\ufeffa = 1\r\nb = 'x'\r\n",
+			expected: map[string]any{"a": int64(1), "b": "x"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			payload, err := parseTOMLDocument([]byte(tc.content))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, payload)
+		})
+	}
+}
+
+func TestParseTOMLDocument_Invalid(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+		line    int
+		column  int
+		message string
+	}{
+		{name: "MissingEquals", content: "a 1\n", line: 1, column: 3, message: "expected '=' after key a"},
+		{name: "MissingValue", content: "a =\n", line: 1, column: 4, message: "expected value"},
+		{name: "LeadingZero", content: "a = 012\n", line: 1, column: 5, message: "invalid value 012"},
+		{name: "IntegerOverflow", content: "a = 9223372036854775808\n", line: 1, column: 5, message: "out of range"},
+		{name: "InvalidDate", content: "a = 1979-02-30\n", line: 1, column: 5, message: "invalid date"},
+		{name: "UnterminatedString", content: "a = \"abc\nb = 1\n", line: 1, column: 5, message: "unterminated string"},
+		{name: "InvalidEscape", content: "a = \"\\x41\"\n", line: 1, column: 6, message: "invalid escape sequence"},
+		{name: "UnterminatedArray", content: "a = [1,\n2\n", line: 1, column: 5, message: "unterminated array"},
+		{name: "InlineTableNewline", content: "a = { b = 1,\n c = 2 }\n", line: 1, column: 13, message: "expected key"},
+		{name: "InlineTableTrailingComma", content: "a = { b = 1, }\n", line: 1, column: 14, message: "expected key"},
+		{name: "DuplicateTable", content: "[a]\nb = 1\n[a]\n", line: 3, column: 1, message: "table a is already defined"},
+		{name: "HeaderOnDottedTable", content: "[a]\nb.c = 1\n[a.b]\n", line: 3, column: 1, message: "table a.b is already defined"},
+		{name: "DottedKeyIntoTable", content: "[a.b]\nc = 1\n[a]\nb.d = 2\n", line: 4, column: 1, message: "cannot add keys to table b with a dotted key"},
+		{name: "ExtendInlineTable", content: "a = { b = 1 }\n[a.c]\n", line: 2, column: 1, message: "cannot extend inline table a"},
+		{name: "TableArrayOverValue", content: "a = [1]\n[[a]]\n", line: 2, column: 1, message: "cannot define array of tables a"},
+		{name: "KeyAsTable", content: "a = 1\n[a.b]\n", line: 2, column: 1, message: "key a is already defined as a value"},
+		{name: "ControlCharacter", content: "a = \"x\x01\"\n", line: 1, column: 7, message: "control character"},
+		{name: "InvalidUTF8", content: "a = \"\xff\"\n", line: 1, column: 6, message: "invalid UTF-8"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseTOMLDocument([]byte(tc.content))
+			require.Error(t, err)
+			assert.ErrorContains(t, err, tc.message)
+
+			var parseErr *ParseError
+			require.True(t, errors.As(err, &parseErr), "error should be a *ParseError, got %v", err)
+			assert.Equal(t, StructuredFormatTOML, parseErr.Format)
+			assert.Equal(t, tc.line, parseErr.Line)
+			assert.Equal(t, tc.column, parseErr.Column)
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Error constants for TouchExecutor
+const (
+	// Command validation errors
+	errTouchInvalidCommandType = "invalid command type for TouchExecutor: %T"
+
+	// File operation errors
+	errTouchResolveFilePath = "failed to resolve file path: %w"
+	errTouchStatFailed      = "failed to stat file '%s': %w"
+	errTouchFileMissing     = "file '%s' does not exist and create_if_missing is false"
+	errTouchCreateFailed    = "failed to create file '%s': %w"
+	errTouchChtimesFailed   = "failed to set times on file '%s': %w"
+
+	// Status messages
+	msgTouchCancelled = "Touch cancelled."
+	msgTouchTimedOut  = "Touch timed out."
+	msgTouchFailed    = "Touch failed: %v"
+	msgTouchSucceeded = "Updated times of '%s' to %s."
+	msgTouchCreated   = "Created '%s' with times set to %s."
+)
+
+// TouchExecutor handles the execution of TouchTask.
+// It updates the access and modification times of a file, optionally creating it.
+type TouchExecutor struct {
+	config ExecutorConfig
+}
+
+var _ TaskExecutor = (*TouchExecutor)(nil)
+
+// NewTouchExecutor creates a new TouchExecutor.
+func NewTouchExecutor() *TouchExecutor {
+	return &TouchExecutor{}
+}
+
+// NewTouchExecutorWithConfig creates a new TouchExecutor using the shared executor config.
+func NewTouchExecutorWithConfig(cfg ExecutorConfig) *TouchExecutor {
+	return &TouchExecutor{config: cfg}
+}
+
+// Execute implements the TaskExecutor interface for TouchTask.
+func (e *TouchExecutor) Execute(ctx context.Context, touchCmd *Task) (<-chan OutputResult, error) {
+	if touchCmd.Type != TaskTouch {
+		return nil, fmt.Errorf(errTouchInvalidCommandType, touchCmd)
+	}
+
+	// Check if task is already in a terminal state
+	terminalChan, err := HandleTerminalTask(touchCmd.TaskId, touchCmd.Status, touchCmd.Output)
+	if err != nil || terminalChan != nil {
+		return terminalChan, err
+	}
+
+	results := make(chan OutputResult, 1)
+	go func() {
+		defer close(results)
+
+		ctx, cancel := e.config.withTimeout(ctx, touchCmd)
+		defer cancel()
+
+		startedAt := e.config.clock().Now()
+		touchCmd.Status = StatusRunning
+		message, err := e.touch(ctx, touchCmd.Parameters.(TouchParameters))
+
+		finalResult := createTouchResult(touchCmd.TaskId, message, err)
+		touchCmd.Status = finalResult.Status
+		finalResult.setTimes(e.config.clock(), startedAt)
+		touchCmd.UpdateOutput(&finalResult)
+		e.config.send(ctx, results, finalResult)
+	}()
+
+	return results, nil
+}
+
+// touch sets the file times described by params and returns a success message.
+func (e *TouchExecutor) touch(ctx context.Context, params TouchParameters) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	filePath, err := e.config.resolvePath(params.FilePath, params.WorkingDirectory)
+	if err != nil {
+		return "", fmt.Errorf(errTouchResolveFilePath, err)
+	}
+
+	touchTime := time.Now()
+	if params.Time != nil {
+		touchTime = *params.Time
+	}
+
+	created := false
+	if _, err := os.Stat(filePath); err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf(errTouchStatFailed, filePath, err)
+		}
+		if !params.CreateIfMissing {
+			return "", fmt.Errorf(errTouchFileMissing, filePath)
+		}
+		file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE, e.config.fileMode())
+		if err != nil {
+			return "", fmt.Errorf(errTouchCreateFailed, filePath, err)
+		}
+		file.Close()
+		created = true
+	}
+
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	if err := os.Chtimes(filePath, touchTime, touchTime); err != nil {
+		return "", fmt.Errorf(errTouchChtimesFailed, filePath, err)
+	}
+
+	if created {
+		return fmt.Sprintf(msgTouchCreated, filePath, touchTime.Format(time.RFC3339)), nil
+	}
+	return fmt.Sprintf(msgTouchSucceeded, filePath, touchTime.Format(time.RFC3339)), nil
+}
+
+// createTouchResult constructs the final OutputResult for a TouchTask.
+func createTouchResult(taskID, message string, err error) OutputResult {
+	if err == nil {
+		return OutputResult{
+			TaskID:  taskID,
+			Status:  StatusSucceeded,
+			Message: message,
+		}
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		message = msgTouchCancelled
+	case errors.Is(err, context.DeadlineExceeded):
+		message = msgTouchTimedOut
+	default:
+		message = fmt.Sprintf(msgTouchFailed, err)
+	}
+	return OutputResult{
+		TaskID:      taskID,
+		Status:      StatusFailed,
+		Message:     message,
+		Error:       err.Error(),
+		FailureKind: failureKind(err),
+	}
+}
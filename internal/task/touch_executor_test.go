@@ -0,0 +1,131 @@
+package task
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTouchExecutor_Execute_UpdatesExistingFile(t *testing.T) {
+	executor := NewTouchExecutor()
+	filePath := filepath.Join(t.TempDir(), "existing.txt")
+	require.NoError(t, os.WriteFile(filePath, []byte("content"), 0644))
+
+	touchTime := time.Date(2020, time.January, 2, 3, 4, 5, 0, time.UTC)
+	cmd := NewTouchTask("touch-existing", "Touch existing file", TouchParameters{
+		FilePath: filePath,
+		Time:     &touchTime,
+	})
+
+	resultsChan, err := executor.Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+	assert.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+	assert.Contains(t, finalResult.Message, "Updated times")
+
+	info, err := os.Stat(filePath)
+	require.NoError(t, err)
+	assert.True(t, info.ModTime().Equal(touchTime), "Expected mtime %v, got %v", touchTime, info.ModTime())
+
+	content, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, "content", string(content), "Touch must not modify content")
+}
+
+func TestTouchExecutor_Execute_DefaultsToNow(t *testing.T) {
+	executor := NewTouchExecutor()
+	filePath := filepath.Join(t.TempDir(), "old.txt")
+	require.NoError(t, os.WriteFile(filePath, nil, 0644))
+	oldTime := time.Now().Add(-48 * time.Hour)
+	require.NoError(t, os.Chtimes(filePath, oldTime, oldTime))
+
+	before := time.Now().Add(-time.Second)
+	cmd := NewTouchTask("touch-now", "Touch with current time", TouchParameters{FilePath: filePath})
+	resultsChan, err := executor.Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+	assert.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+
+	info, err := os.Stat(filePath)
+	require.NoError(t, err)
+	assert.True(t, info.ModTime().After(before), "Expected mtime to be updated to now, got %v", info.ModTime())
+}
+
+func TestTouchExecutor_Execute_CreatesMissingFile(t *testing.T) {
+	executor := NewTouchExecutor()
+	tempDir := t.TempDir()
+
+	cmd := NewTouchTask("touch-create", "Touch creates missing file", TouchParameters{
+		BaseParameters:  BaseParameters{WorkingDirectory: tempDir},
+		FilePath:        "created.txt",
+		CreateIfMissing: true,
+	})
+
+	resultsChan, err := executor.Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+	assert.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+	assert.Contains(t, finalResult.Message, "Created")
+
+	info, err := os.Stat(filepath.Join(tempDir, "created.txt"))
+	require.NoError(t, err, "File should have been created")
+	assert.Equal(t, int64(0), info.Size())
+}
+
+func TestTouchExecutor_Execute_MissingFileWithoutCreate(t *testing.T) {
+	executor := NewTouchExecutor()
+	filePath := filepath.Join(t.TempDir(), "missing.txt")
+
+	cmd := NewTouchTask("touch-missing", "Touch missing file", TouchParameters{FilePath: filePath})
+	resultsChan, err := executor.Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Contains(t, finalResult.Error, "does not exist")
+	assert.Equal(t, StatusFailed, cmd.Status)
+
+	_, statErr := os.Stat(filePath)
+	assert.True(t, os.IsNotExist(statErr), "File should not have been created")
+}
+
+func TestTouchExecutor_Execute_InvalidCommandType(t *testing.T) {
+	executor := NewTouchExecutor()
+	cmd := NewFileReadTask("touch-invalid", "Wrong type", FileReadParameters{FilePath: "x"})
+
+	resultsChan, err := executor.Execute(context.Background(), cmd)
+	assert.Error(t, err)
+	assert.Nil(t, resultsChan)
+}
+
+func TestTouchExecutor_Execute_ConfiguredFileMode(t *testing.T) {
+	executor := NewTouchExecutorWithConfig(ExecutorConfig{FileMode: 0600})
+	filePath := filepath.Join(t.TempDir(), "private.txt")
+
+	cmd := NewTouchTask("touch-mode", "Touch creates file with configured mode", TouchParameters{
+		FilePath:        filePath,
+		CreateIfMissing: true,
+	})
+	resultsChan, err := executor.Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+	require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+
+	info, err := os.Stat(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
@@ -0,0 +1,75 @@
+package task
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// spanKey is the context key holding the span ID of the enclosing task.
+type spanKey struct{}
+
+// executionKey is the context key holding the execution a caller started a task in.
+type executionKey struct{}
+
+// ContextWithSpan returns a copy of ctx in which spanID is the current span.
+// Tasks started with the returned context record spanID as their parent span, so
+// callers can attach task spans to a trace of their own.
+func ContextWithSpan(ctx context.Context, spanID string) context.Context {
+	return context.WithValue(ctx, spanKey{}, spanID)
+}
+
+// SpanFromContext returns the current span ID in ctx, or "" if there is none.
+func SpanFromContext(ctx context.Context) string {
+	spanID, _ := ctx.Value(spanKey{}).(string)
+	return spanID
+}
+
+// newSpanID returns a random 16-character hex span ID.
+func newSpanID() string {
+	var id [8]byte
+	rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
+
+// span identifies one execution of a task in a trace. It is owned by the caller
+// that started the execution rather than stored on the task, as the same task may
+// be executing more than once at a time.
+type span struct {
+	task   *Task
+	id     string
+	parent string
+}
+
+// startSpan starts a new span for an execution of task, whose parent is the current
+// span in ctx, and returns the context to execute task in.
+func startSpan(ctx context.Context, task *Task) (context.Context, span) {
+	s := span{task: task, id: newSpanID(), parent: SpanFromContext(ctx)}
+	return context.WithValue(ctx, executionKey{}, s), s
+}
+
+// executionSpan returns the span the caller started for executing task in ctx, if any.
+func executionSpan(ctx context.Context, task *Task) (span, bool) {
+	s, ok := ctx.Value(executionKey{}).(span)
+	return s, ok && s.task == task
+}
+
+// stamp returns r carrying the span if r is a result of the span's task that does
+// not carry one yet. Results forwarded from nested executions keep their own span.
+func (s span) stamp(r OutputResult) OutputResult {
+	if s.id == "" || r.SpanID != "" || r.TaskID != s.task.TaskId {
+		return r
+	}
+	r.SpanID = s.id
+	r.ParentSpanID = s.parent
+	return r
+}
+
+// describeIn is describe, also recording the span the task is executing in within ctx.
+func (t *Task) describeIn(ctx context.Context, r OutputResult) OutputResult {
+	r = t.describe(r)
+	if s, ok := executionSpan(ctx, t); ok {
+		r = s.stamp(r)
+	}
+	return r
+}
@@ -0,0 +1,32 @@
+package task
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpanFromContext(t *testing.T) {
+	assert.Empty(t, SpanFromContext(context.Background()))
+	assert.Equal(t, "abc", SpanFromContext(ContextWithSpan(context.Background(), "abc")))
+}
+
+func TestRunAndCapture_StartsSpan(t *testing.T) {
+	registry := NewMapRegistry()
+	ctx := ContextWithSpan(context.Background(), "parent")
+
+	first := NewEvalTask("eval", "Evaluate", EvalParameters{Expression: "1 < 2"})
+	_, final, err := RunAndCapture(ctx, registry, first)
+	require.NoError(t, err)
+	assert.Len(t, final.SpanID, 16)
+	assert.Equal(t, "parent", final.ParentSpanID)
+
+	// Every execution is a new span
+	second := NewEvalTask("eval", "Evaluate", EvalParameters{Expression: "1 < 2"})
+	_, again, err := RunAndCapture(context.Background(), registry, second)
+	require.NoError(t, err)
+	assert.NotEqual(t, final.SpanID, again.SpanID)
+	assert.Empty(t, again.ParentSpanID)
+}
@@ -0,0 +1,170 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Error constants for TruncateExecutor
+const (
+	// Command validation errors
+	errTruncateInvalidCommandType = "invalid command type for TruncateExecutor: %T"
+	errTruncateNegativeSize       = "invalid size: %d (must be >= 0)"
+
+	// File operation errors
+	errTruncateResolveFilePath = "failed to resolve file path: %w"
+	errTruncateStatFailed      = "failed to stat file '%s': %w"
+	errTruncateFileMissing     = "file '%s' does not exist; only a size of 0 creates it"
+	errTruncateNotRegular      = "'%s' is not a regular file"
+	errTruncateCreateFailed    = "failed to create file '%s': %w"
+	errTruncateFailed          = "failed to truncate file '%s': %w"
+
+	// Status messages
+	msgTruncateCancelled = "Truncate cancelled."
+	msgTruncateTimedOut  = "Truncate timed out."
+	msgTruncateFailed    = "Truncate failed: %v"
+	msgTruncateSucceeded = "Truncated '%s' from %d to %d bytes."
+	msgTruncateCreated   = "Created empty file '%s'."
+)
+
+// TruncateExecutor handles the execution of TruncateTask.
+// It shrinks or extends a file to a given size.
+type TruncateExecutor struct {
+	config ExecutorConfig
+}
+
+var (
+	_ TaskExecutor = (*TruncateExecutor)(nil)
+	_ Compensator  = (*TruncateExecutor)(nil)
+)
+
+// NewTruncateExecutor creates a new TruncateExecutor.
+func NewTruncateExecutor() *TruncateExecutor {
+	return &TruncateExecutor{}
+}
+
+// NewTruncateExecutorWithConfig creates a new TruncateExecutor using the shared executor config.
+func NewTruncateExecutorWithConfig(cfg ExecutorConfig) *TruncateExecutor {
+	return &TruncateExecutor{config: cfg}
+}
+
+// Execute implements the TaskExecutor interface for TruncateTask.
+func (e *TruncateExecutor) Execute(ctx context.Context, truncateCmd *Task) (<-chan OutputResult, error) {
+	if truncateCmd.Type != TaskTruncate {
+		return nil, fmt.Errorf(errTruncateInvalidCommandType, truncateCmd)
+	}
+
+	// Check if task is already in a terminal state
+	terminalChan, err := HandleTerminalTask(truncateCmd.TaskId, truncateCmd.Status, truncateCmd.Output)
+	if err != nil || terminalChan != nil {
+		return terminalChan, err
+	}
+
+	if size := truncateCmd.Parameters.(TruncateParameters).Size; size < 0 {
+		return nil, invalidf(errTruncateNegativeSize, size)
+	}
+
+	results := make(chan OutputResult, 1)
+	go func() {
+		defer close(results)
+
+		ctx, cancel := e.config.withTimeout(ctx, truncateCmd)
+		defer cancel()
+
+		startedAt := e.config.clock().Now()
+		truncateCmd.Status = StatusRunning
+		message, err := e.truncate(ctx, truncateCmd.Parameters.(TruncateParameters))
+
+		finalResult := createTruncateResult(truncateCmd.TaskId, message, err)
+		truncateCmd.Status = finalResult.Status
+		finalResult.setTimes(e.config.clock(), startedAt)
+		truncateCmd.UpdateOutput(&finalResult)
+		e.config.send(ctx, results, finalResult)
+	}()
+
+	return results, nil
+}
+
+// truncate sets the size of the file described by params and returns a success message.
+// A file that grows is padded with zero bytes.
+func (e *TruncateExecutor) truncate(ctx context.Context, params TruncateParameters) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	filePath, err := e.config.resolvePath(params.FilePath, params.WorkingDirectory)
+	if err != nil {
+		return "", fmt.Errorf(errTruncateResolveFilePath, err)
+	}
+
+	unlock := e.config.fileLocks().Lock(filePath)
+	defer unlock()
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf(errTruncateStatFailed, filePath, err)
+		}
+		if params.Size != 0 {
+			return "", fmt.Errorf(errTruncateFileMissing, filePath)
+		}
+		file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE, e.config.fileMode())
+		if err != nil {
+			return "", fmt.Errorf(errTruncateCreateFailed, filePath, err)
+		}
+		file.Close()
+		return fmt.Sprintf(msgTruncateCreated, filePath), nil
+	}
+	if !info.Mode().IsRegular() {
+		return "", invalidf(errTruncateNotRegular, filePath)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	if err := os.Truncate(filePath, params.Size); err != nil {
+		return "", fmt.Errorf(errTruncateFailed, filePath, err)
+	}
+	return fmt.Sprintf(msgTruncateSucceeded, filePath, info.Size(), params.Size), nil
+}
+
+// PrepareCompensation implements Compensator by backing up the target file
+// so that a rollback restores the content truncation discarded.
+func (e *TruncateExecutor) PrepareCompensation(ctx context.Context, truncateCmd *Task) (Compensation, error) {
+	params := truncateCmd.Parameters.(TruncateParameters)
+	filePath, err := e.config.resolvePath(params.FilePath, params.WorkingDirectory)
+	if err != nil {
+		return nil, err
+	}
+	return fileBackupCompensation(filePath)
+}
+
+// createTruncateResult constructs the final OutputResult for a TruncateTask.
+func createTruncateResult(taskID, message string, err error) OutputResult {
+	if err == nil {
+		return OutputResult{
+			TaskID:  taskID,
+			Status:  StatusSucceeded,
+			Message: message,
+		}
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		message = msgTruncateCancelled
+	case errors.Is(err, context.DeadlineExceeded):
+		message = msgTruncateTimedOut
+	default:
+		message = fmt.Sprintf(msgTruncateFailed, err)
+	}
+	return OutputResult{
+		TaskID:      taskID,
+		Status:      StatusFailed,
+		Message:     message,
+		Error:       err.Error(),
+		FailureKind: failureKind(err),
+	}
+}
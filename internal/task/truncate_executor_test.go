@@ -0,0 +1,101 @@
+package task
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runTruncate(t *testing.T, params TruncateParameters) OutputResult {
+	t.Helper()
+	cmd := NewTruncateTask("truncate", "Truncate a file", params)
+	resultsChan, err := NewTruncateExecutor().Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+	return finalResult
+}
+
+func TestTruncateExecutor_Execute_Shrinks(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "app.log")
+	require.NoError(t, os.WriteFile(filePath, []byte("0123456789"), 0644))
+
+	finalResult := runTruncate(t, TruncateParameters{FilePath: filePath, Size: 4})
+	require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+	assert.Contains(t, finalResult.Message, "from 10 to 4 bytes")
+
+	content, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, "0123", string(content))
+}
+
+func TestTruncateExecutor_Execute_GrowsWithZeros(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "fixture.bin")
+	require.NoError(t, os.WriteFile(filePath, []byte("ab"), 0644))
+
+	finalResult := runTruncate(t, TruncateParameters{FilePath: filePath, Size: 5})
+	require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+
+	content, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{'a', 'b', 0, 0, 0}, content)
+}
+
+func TestTruncateExecutor_Execute_MissingFile(t *testing.T) {
+	t.Run("Size zero creates the file", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "new.log")
+		finalResult := runTruncate(t, TruncateParameters{FilePath: filePath})
+		require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+		assert.Contains(t, finalResult.Message, "Created empty file")
+
+		info, err := os.Stat(filePath)
+		require.NoError(t, err)
+		assert.Zero(t, info.Size())
+	})
+
+	t.Run("Other sizes fail", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "new.log")
+		finalResult := runTruncate(t, TruncateParameters{FilePath: filePath, Size: 3})
+		assert.Equal(t, StatusFailed, finalResult.Status)
+		assert.Contains(t, finalResult.Error, "does not exist")
+		assert.NoFileExists(t, filePath)
+	})
+}
+
+func TestTruncateExecutor_Execute_Invalid(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := NewTruncateExecutor().Execute(context.Background(), NewTruncateTask("truncate-negative", "", TruncateParameters{FilePath: "a", Size: -1}))
+	assert.ErrorContains(t, err, "invalid size: -1")
+
+	finalResult := runTruncate(t, TruncateParameters{FilePath: dir})
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Equal(t, FailureValidationError, finalResult.FailureKind)
+	assert.Contains(t, finalResult.Error, "is not a regular file")
+}
+
+func TestTruncateExecutor_PrepareCompensation(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "app.log")
+	require.NoError(t, os.WriteFile(filePath, []byte("keep me"), 0644))
+	cmd := NewTruncateTask("truncate-undo", "Truncate then roll back", TruncateParameters{FilePath: filePath})
+
+	executor := NewTruncateExecutor()
+	compensation, err := executor.PrepareCompensation(context.Background(), cmd)
+	require.NoError(t, err)
+	resultsChan, err := executor.Execute(context.Background(), cmd)
+	require.NoError(t, err)
+	finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+	require.True(t, received)
+	require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+
+	require.NoError(t, compensation(context.Background()))
+	content, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, "keep me", string(content))
+}
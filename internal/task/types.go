@@ -2,6 +2,9 @@ package task
 
 import (
 	"encoding/json"
+	"io"
+	"slices"
+	"time"
 )
 
 // TaskType represents the specific kind of command/step.
@@ -21,6 +24,40 @@ const (
 	TaskListDirectory TaskType = "LIST_DIRECTORY"
 	// TaskRequestUserInput represents a command to prompt the user for input.
 	TaskRequestUserInput TaskType = "REQUEST_USER_INPUT"
+	// TaskWriteFiles represents a command to write several files in a single invocation.
+	TaskWriteFiles TaskType = "WRITE_FILES"
+	// TaskTouch represents a command to update a file's access and modification times.
+	TaskTouch TaskType = "TOUCH"
+	// TaskDiskUsage represents a command to compute the total size of a directory tree.
+	TaskDiskUsage TaskType = "DISK_USAGE"
+	// TaskWhich represents a command to locate an executable in PATH.
+	TaskWhich TaskType = "WHICH"
+	// TaskEval represents a command to evaluate a boolean expression.
+	TaskEval TaskType = "EVAL"
+	// TaskNormalizeEOL represents a command to rewrite a file's line endings uniformly.
+	TaskNormalizeEOL TaskType = "NORMALIZE_EOL"
+	// TaskFileCompareAndSwap represents a command to replace a file's content only if it still matches an expected value.
+	TaskFileCompareAndSwap TaskType = "FILE_COMPARE_AND_SWAP"
+	// TaskReadStructured represents a command to parse a JSON, YAML or TOML file into a typed payload.
+	TaskReadStructured TaskType = "READ_STRUCTURED"
+	// TaskExtractJSON represents a command to select a single value from a JSON document.
+	TaskExtractJSON TaskType = "EXTRACT_JSON"
+	// TaskValidatePatch represents a command to check that a unified diff parses, without applying it.
+	TaskValidatePatch TaskType = "VALIDATE_PATCH"
+	// TaskManifest represents a command to write a checksum manifest of a directory tree.
+	TaskManifest TaskType = "MANIFEST"
+	// TaskReadLink represents a command to read the target of a symbolic link.
+	TaskReadLink TaskType = "READ_LINK"
+	// TaskPatchFiles represents a command to apply the same patch to several files.
+	TaskPatchFiles TaskType = "PATCH_FILES"
+	// TaskTruncate represents a command to shrink or extend a file to a given size.
+	TaskTruncate TaskType = "TRUNCATE"
+	// TaskCompareTrees represents a command to compare two directory trees by content.
+	TaskCompareTrees TaskType = "COMPARE_TREES"
+	// TaskFileMove represents a command to move or rename a file or directory.
+	TaskFileMove TaskType = "FILE_MOVE"
+	// TaskFileCopy represents a command to copy a file or directory tree.
+	TaskFileCopy TaskType = "FILE_COPY"
 	// TaskGroup represents a group of tasks to be executed in sequence.
 	// If any task fails, the group fails.
 	TaskGroup TaskType = "GROUP"
@@ -66,6 +103,16 @@ type BaseTask struct {
 	Type TaskType `json:"type"`
 	// Children is an array of sub-tasks. Only used for TaskGroup type.
 	Children []*Task `json:"children,omitempty"`
+	// DependsOn lists the IDs of sibling tasks in the same group that must succeed
+	// before this task runs. Their results may be referenced from string parameters
+	// as ${<task_id>.result}, or ${<task_id>.result:N} for the Nth line.
+	DependsOn []string `json:"depends_on,omitempty"`
+	// Optional marks a task whose failure does not fail its enclosing group.
+	// The failure is reported in the group's Message and remaining tasks still run.
+	Optional bool `json:"optional,omitempty"`
+	// DeadlineUnix is an absolute deadline, in seconds since the Unix epoch, after which
+	// the task is cancelled. Zero means no deadline.
+	DeadlineUnix int64 `json:"deadline_unix,omitempty"`
 	// Output holds the result of the command execution.
 	// This is set by the executor when the command is finished.
 	Output OutputResult `json:"output,omitempty"`
@@ -82,6 +129,8 @@ type BaseParameters struct {
 	// WorkingDirectory is the directory in which the command will be executed.
 	// If not provided, the command will run in the default directory.
 	WorkingDirectory string `json:"working_directory"`
+	// Env sets environment variables for the task on top of the agent's own environment.
+	Env map[string]string `json:"env,omitempty"`
 }
 
 // BashExecParameters holds parameters specific to the BashExecTask.
@@ -90,6 +139,63 @@ type BashExecParameters struct {
 	// Command is the actual bash command string to be executed.
 	// Multiple commands can be provided as a multi-line string.
 	Command string `json:"command"`
+	// CaptureOutput controls whether output lines are streamed as RUNNING results.
+	// When explicitly false, only the final status and exit code are reported. Defaults to true.
+	CaptureOutput *bool `json:"capture_output,omitempty"`
+	// LineTransform names a built-in transform applied to each output line before it is
+	// forwarded, such as "strip-ansi". Lines are forwarded unchanged when empty.
+	LineTransform string `json:"line_transform,omitempty"`
+	// RequiredGlobs lists glob patterns the command depends on. Before the command starts,
+	// each is expanded relative to the working directory and the task fails without running
+	// the command if any of them matches nothing.
+	RequiredGlobs []string `json:"required_globs,omitempty"`
+	// LoginShell runs the command with bash -lc, so that /etc/profile and the user's
+	// ~/.bash_profile (or ~/.profile) are read first and their PATH and functions apply.
+	LoginShell bool `json:"login_shell,omitempty"`
+	// CapturePID reports the PID of the last process the command started in the
+	// background (bash's $!) as a BackgroundProcess payload, so a daemon it launched
+	// can be managed later. The process must not hold the command's stdout or stderr
+	// open, or the task waits for it to exit.
+	CapturePID bool `json:"capture_pid,omitempty"`
+	// PIDFile, when set, is written with the captured PID. It implies CapturePID.
+	PIDFile string `json:"pid_file,omitempty"`
+	// JSONLines treats the output as line-delimited JSON. Each output line that holds a
+	// JSON object or array is forwarded with the decoded value as its Payload; other
+	// lines are forwarded as plain text. ResultData always carries the line itself.
+	JSONLines bool `json:"json_lines,omitempty"`
+	// AllowedExitCodes lists the exit codes that count as success, such as 1 for a grep
+	// that matched nothing. Defaults to [0] when empty.
+	AllowedExitCodes []int `json:"allowed_exit_codes,omitempty"`
+	// CPUTimeLimitSeconds caps the CPU time, in seconds, of the command and of each
+	// process it starts (RLIMIT_CPU). A process reaching it is killed. Zero means no
+	// limit. Limits are only supported on Linux.
+	CPUTimeLimitSeconds int `json:"cpu_time_limit_seconds,omitempty"`
+	// MemoryLimitBytes caps the virtual memory of the command and of each process it
+	// starts (RLIMIT_AS). Allocations beyond it fail. Zero means no limit.
+	MemoryLimitBytes int64 `json:"memory_limit_bytes,omitempty"`
+}
+
+// allowsExitCode reports whether a command exiting with code succeeded.
+func (p BashExecParameters) allowsExitCode(code int) bool {
+	if len(p.AllowedExitCodes) == 0 {
+		return code == 0
+	}
+	return slices.Contains(p.AllowedExitCodes, code)
+}
+
+// capturesOutput reports whether command output should be streamed.
+func (p BashExecParameters) capturesOutput() bool {
+	return p.CaptureOutput == nil || *p.CaptureOutput
+}
+
+// limitsResources reports whether the command runs under CPU or memory limits.
+func (p BashExecParameters) limitsResources() bool {
+	return p.CPUTimeLimitSeconds > 0 || p.MemoryLimitBytes > 0
+}
+
+// capturesPID reports whether the PID of a background process should be reported.
+func (p BashExecParameters) capturesPID() bool {
+	return p.CapturePID || p.PIDFile != ""
 }
 
 // BashExecTask defines the structure for executing a bash command.
@@ -105,8 +211,82 @@ type FileReadParameters struct {
 	FilePath  string `json:"file_path"`
 	StartLine int    `json:"start_line,omitempty"`
 	EndLine   int    `json:"end_line,omitempty"`
+	// StartByte seeks to this byte offset before reading. Line numbers are counted from there.
+	// Use the OffsetReached of an interrupted read to resume it.
+	StartByte int64 `json:"start_byte,omitempty"`
+	// HeadBytes reads at most this many bytes, verbatim, and stops without scanning the rest
+	// of the file. It cannot be combined with StartLine or EndLine.
+	HeadBytes int64 `json:"head_bytes,omitempty"`
+	// Encoding selects how the content is returned. FileReadEncodingBase64 streams the raw
+	// bytes base64-encoded; it cannot be combined with StartLine or EndLine.
+	Encoding string `json:"encoding,omitempty"`
+	// Incremental reads only the complete lines appended after the StartByte baseline,
+	// typically the OffsetReached of the previous read. A trailing line without its
+	// newline is left for the next read. If the file has shrunk below the baseline
+	// it is read from the beginning. It cannot be combined with StartLine, EndLine,
+	// HeadBytes or Encoding.
+	Incremental bool `json:"incremental,omitempty"`
+	// MaxLines stops reading after this many lines, counted from StartLine, and marks the
+	// result Truncated if more lines remained. It cannot be combined with HeadBytes or Encoding.
+	MaxLines int `json:"max_lines,omitempty"`
+	// StartMarker and EndMarker return only the content strictly between the first
+	// occurrence of StartMarker and the first EndMarker after it, verbatim. An empty
+	// StartMarker reads from the beginning and an empty EndMarker reads to the end of
+	// the file. They cannot be combined with StartLine, EndLine, HeadBytes, Incremental
+	// or MaxLines.
+	StartMarker string `json:"start_marker,omitempty"`
+	EndMarker   string `json:"end_marker,omitempty"`
+	// OnMissingEndMarker selects what happens when EndMarker does not follow StartMarker:
+	// FileReadMissingEndMarkerError (the default) fails the read and
+	// FileReadMissingEndMarkerEOF returns everything up to the end of the file.
+	OnMissingEndMarker string `json:"on_missing_end_marker,omitempty"`
+	// TrimTrailingWhitespace removes spaces and tabs from the end of every line.
+	TrimTrailingWhitespace bool `json:"trim_trailing_whitespace,omitempty"`
+	// ExpandTabs replaces tabs with spaces up to the next multiple of this many columns,
+	// like expand -t. Zero leaves tabs as they are. Neither option can be combined with
+	// HeadBytes, Encoding or the markers, which return content verbatim.
+	ExpandTabs int `json:"expand_tabs,omitempty"`
+	// AutoDetectEncoding reads a file of unknown charset, such as UTF-16 or Latin-1, and
+	// streams it decoded to UTF-8. The detected charset is reported in the final Message.
+	// Content that matches no charset with confidence is streamed as raw bytes. The charset
+	// is detected from the first 64 KiB, and the rest is decoded as it is read. No
+	// OffsetReached is reported, and it cannot be combined with StartByte, HeadBytes,
+	// Encoding, Incremental or the markers.
+	AutoDetectEncoding bool `json:"auto_detect_encoding,omitempty"`
+	// LinesPerChunk is the number of lines sent together in each RUNNING result when
+	// reading lines. Defaults to 1, one line per result.
+	LinesPerChunk int `json:"lines_per_chunk,omitempty"`
+	// OutputFormat selects how lines are returned. FileReadOutputLinesJSON streams
+	// nothing and returns the lines read, without their terminators, as a JSON array
+	// of strings in the final ResultData. It cannot be combined with HeadBytes,
+	// Encoding, the markers or LinesPerChunk.
+	OutputFormat string `json:"output_format,omitempty"`
 }
 
+// Encodings supported by FileReadParameters.Encoding.
+const (
+	// FileReadEncodingText returns the content as text, line by line.
+	FileReadEncodingText = ""
+	// FileReadEncodingBase64 returns the raw content base64-encoded.
+	FileReadEncodingBase64 = "base64"
+)
+
+// Formats supported by FileReadParameters.OutputFormat.
+const (
+	// FileReadOutputText streams the lines as text.
+	FileReadOutputText = ""
+	// FileReadOutputLinesJSON returns the lines as a JSON array of strings.
+	FileReadOutputLinesJSON = "lines-json"
+)
+
+// Behaviors supported by FileReadParameters.OnMissingEndMarker.
+const (
+	// FileReadMissingEndMarkerError fails the read when the end marker is missing.
+	FileReadMissingEndMarkerError = "error"
+	// FileReadMissingEndMarkerEOF reads to the end of the file when the end marker is missing.
+	FileReadMissingEndMarkerEOF = "eof"
+)
+
 func NewFileReadTask(taskId string, description string, parameters FileReadParameters) *Task {
 	return &Task{
 		BaseTask:   BaseTask{TaskId: taskId, Type: TaskFileRead, Description: description},
@@ -119,8 +299,21 @@ type FileWriteParameters struct {
 	FilePath  string `json:"file_path"`
 	Content   string `json:"content"`
 	Overwrite bool   `json:"overwrite,omitempty"`
+	// Append adds Content to the end of the file instead of replacing it, creating
+	// the file if it does not exist. It cannot be combined with Overwrite.
+	Append bool `json:"append,omitempty"`
+	// Checksum names a hash, ChecksumSHA256 or ChecksumSHA512, computed over the content
+	// as it is written. The hex-encoded digest is returned in ResultData and in a
+	// FileWriteResult payload. No hash is computed when empty.
+	Checksum string `json:"checksum,omitempty"`
 }
 
+// Hashes accepted by FileWriteParameters.Checksum.
+const (
+	ChecksumSHA256 = "sha256"
+	ChecksumSHA512 = "sha512"
+)
+
 func NewFileWriteTask(taskId string, description string, parameters FileWriteParameters) *Task {
 	return &Task{
 		BaseTask:   BaseTask{TaskId: taskId, Type: TaskFileWrite, Description: description},
@@ -128,10 +321,76 @@ func NewFileWriteTask(taskId string, description string, parameters FileWritePar
 	}
 }
 
+// FileWriteEntry describes a single file written by a WriteFilesTask.
+type FileWriteEntry struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// WriteFilesParameters holds parameters specific to the WriteFilesTask.
+type WriteFilesParameters struct {
+	BaseParameters
+	Files []FileWriteEntry `json:"files"`
+	// Transactional writes all files or none by staging them in temporary files first.
+	Transactional bool `json:"transactional,omitempty"`
+}
+
+// WriteFilesTask defines the structure for writing several files at once.
+func NewWriteFilesTask(taskId string, description string, parameters WriteFilesParameters) *Task {
+	return &Task{
+		BaseTask:   BaseTask{TaskId: taskId, Type: TaskWriteFiles, Description: description},
+		Parameters: parameters,
+	}
+}
+
 type PatchFileParameters struct {
 	BaseParameters
 	FilePath string `json:"file_path"`
 	Patch    string `json:"patch"`
+	// PatchPath is a file to read the patch from when Patch is empty.
+	// It is resolved against WorkingDirectory like FilePath.
+	PatchPath string `json:"patch_path,omitempty"`
+	// PatchReader supplies the patch incrementally when neither Patch nor PatchPath is
+	// set, such as a large diff that is still being generated. The patch is parsed as it
+	// is read instead of being held as one string. It is not serialized, is consumed by
+	// a single execution and cannot be combined with BaseDirectory.
+	PatchReader io.Reader `json:"-"`
+	// ExpectedResult is the hex-encoded SHA-256 of the content the patch should produce.
+	// When set, the patch is not written if the result differs, and the written file is
+	// read back and rolled back to its original state if it does not match.
+	ExpectedResult string `json:"expected_result,omitempty"`
+	// ExpectedSHA lists the hex-encoded SHA-256 hashes of the file versions the patch
+	// may be applied to. When set, the patch is only applied if the current content
+	// matches one of them; a missing file has the hash of empty content.
+	ExpectedSHA []string `json:"expected_sha,omitempty"`
+	// IgnoreTrailingWhitespace matches context and deleted lines while ignoring
+	// trailing spaces and tabs. Matching is strict by default.
+	IgnoreTrailingWhitespace bool `json:"ignore_trailing_whitespace,omitempty"`
+	// IncludeDiff returns the effective unified diff between the original and
+	// patched content in ResultData on success.
+	IncludeDiff bool `json:"include_diff,omitempty"`
+	// BaseDirectory applies a patch covering any number of files, such as one produced
+	// by git diff at a repository root, to the tree rooted at this directory. Each file
+	// diff is applied to the file named in its header, relative to BaseDirectory, after
+	// removing Strip leading path components. It replaces FilePath and cannot be
+	// combined with ExpectedResult or ExpectedSHA.
+	BaseDirectory string `json:"base_directory,omitempty"`
+	// Strip is the number of leading path components removed from the file names in
+	// the patch, as with patch -p. Use 1 for the a/ and b/ prefixes written by git diff.
+	Strip int `json:"strip,omitempty"`
+	// AllowEmptyResult controls whether a patch may leave a file empty. When explicitly
+	// false, a patch that removes all content fails instead of writing an empty file.
+	// File deletions in BaseDirectory mode are not affected. Defaults to true.
+	AllowEmptyResult *bool `json:"allow_empty_result,omitempty"`
+	// BestEffort skips hunks whose context or deleted lines do not match the file, such
+	// as a hunk that was already applied, and applies the rest. The skipped hunks are
+	// reported as a []SkippedHunk payload. It cannot be combined with BaseDirectory.
+	BestEffort bool `json:"best_effort,omitempty"`
+}
+
+// allowsEmptyResult reports whether the patch may produce an empty file.
+func (p PatchFileParameters) allowsEmptyResult() bool {
+	return p.AllowEmptyResult == nil || *p.AllowEmptyResult
 }
 
 // PatchFileTask defines the structure for applying a patch to a file.
@@ -145,8 +404,22 @@ func NewPatchFileTask(taskId string, description string, parameters PatchFilePar
 type ListDirectoryParameters struct {
 	BaseParameters
 	Path string `json:"path"`
+	// SortBy orders the entries: ListSortNameAsc (the default), ListSortNameDesc,
+	// ListSortSizeAsc, ListSortSizeDesc, ListSortModTimeAsc or ListSortModTimeDesc.
+	// Entries that compare equal are ordered by name.
+	SortBy string `json:"sort_by,omitempty"`
 }
 
+// Orders accepted by ListDirectoryParameters.SortBy.
+const (
+	ListSortNameAsc     = "name-asc"
+	ListSortNameDesc    = "name-desc"
+	ListSortSizeAsc     = "size-asc"
+	ListSortSizeDesc    = "size-desc"
+	ListSortModTimeAsc  = "modtime-asc"
+	ListSortModTimeDesc = "modtime-desc"
+)
+
 // ListDirectoryTask defines the structure for listing directory contents.
 func NewListDirectoryTask(taskId string, description string, parameters ListDirectoryParameters) *Task {
 	return &Task{
@@ -167,6 +440,341 @@ func NewRequestUserInputTask(taskId string, description string, parameters Reque
 	}
 }
 
+// TouchParameters holds parameters specific to the TouchTask.
+type TouchParameters struct {
+	BaseParameters
+	FilePath string `json:"file_path"`
+	// CreateIfMissing creates an empty file when the path does not exist.
+	CreateIfMissing bool `json:"create_if_missing,omitempty"`
+	// Time is the access and modification time to set. Defaults to the current time.
+	Time *time.Time `json:"time,omitempty"`
+}
+
+// TouchTask defines the structure for updating a file's timestamps.
+func NewTouchTask(taskId string, description string, parameters TouchParameters) *Task {
+	return &Task{
+		BaseTask:   BaseTask{TaskId: taskId, Type: TaskTouch, Description: description},
+		Parameters: parameters,
+	}
+}
+
+// DiskUsageParameters holds parameters specific to the DiskUsageTask.
+type DiskUsageParameters struct {
+	BaseParameters
+	// Path is the root of the tree to measure. Defaults to the working directory.
+	Path string `json:"path"`
+}
+
+// DiskUsageTask defines the structure for measuring a directory tree.
+func NewDiskUsageTask(taskId string, description string, parameters DiskUsageParameters) *Task {
+	return &Task{
+		BaseTask:   BaseTask{TaskId: taskId, Type: TaskDiskUsage, Description: description},
+		Parameters: parameters,
+	}
+}
+
+// WhichParameters holds parameters specific to the WhichTask.
+type WhichParameters struct {
+	BaseParameters
+	// Name is the executable to look up, e.g. "go".
+	Name string `json:"name"`
+}
+
+// WhichTask defines the structure for locating an executable.
+func NewWhichTask(taskId string, description string, parameters WhichParameters) *Task {
+	return &Task{
+		BaseTask:   BaseTask{TaskId: taskId, Type: TaskWhich, Description: description},
+		Parameters: parameters,
+	}
+}
+
+// EvalParameters holds parameters specific to the EvalTask.
+type EvalParameters struct {
+	BaseParameters
+	// Expression is the boolean expression to evaluate, e.g. `fileExists("go.mod") && retries < 3`.
+	// Results of earlier tasks can be inserted with ${<task_id>.result} references.
+	Expression string `json:"expression"`
+	// Variables are the values the expression may refer to by name.
+	// Values must be booleans, numbers or strings.
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+// EvalTask defines the structure for evaluating a condition.
+func NewEvalTask(taskId string, description string, parameters EvalParameters) *Task {
+	return &Task{
+		BaseTask:   BaseTask{TaskId: taskId, Type: TaskEval, Description: description},
+		Parameters: parameters,
+	}
+}
+
+// NormalizeEOLParameters holds parameters specific to the NormalizeEOLTask.
+type NormalizeEOLParameters struct {
+	BaseParameters
+	FilePath string `json:"file_path"`
+	// Target is the line ending to convert to, EOLTargetLF or EOLTargetCRLF.
+	Target string `json:"target"`
+}
+
+// Line endings supported by NormalizeEOLParameters.Target.
+const (
+	// EOLTargetLF converts CRLF line endings to LF.
+	EOLTargetLF = "lf"
+	// EOLTargetCRLF converts LF line endings to CRLF.
+	EOLTargetCRLF = "crlf"
+)
+
+// NormalizeEOLTask defines the structure for normalizing a file's line endings.
+func NewNormalizeEOLTask(taskId string, description string, parameters NormalizeEOLParameters) *Task {
+	return &Task{
+		BaseTask:   BaseTask{TaskId: taskId, Type: TaskNormalizeEOL, Description: description},
+		Parameters: parameters,
+	}
+}
+
+// FileCompareAndSwapParameters holds parameters specific to the FileCompareAndSwapTask.
+type FileCompareAndSwapParameters struct {
+	BaseParameters
+	FilePath string `json:"file_path"`
+	// ExpectedContent must equal the file's current content for the write to happen.
+	// An empty ExpectedContent also matches a file that does not exist, which is then created.
+	ExpectedContent string `json:"expected_content"`
+	// NewContent replaces the file's content when ExpectedContent matches.
+	NewContent string `json:"new_content"`
+}
+
+// FileCompareAndSwapTask defines the structure for a conditional file replacement.
+func NewFileCompareAndSwapTask(taskId string, description string, parameters FileCompareAndSwapParameters) *Task {
+	return &Task{
+		BaseTask:   BaseTask{TaskId: taskId, Type: TaskFileCompareAndSwap, Description: description},
+		Parameters: parameters,
+	}
+}
+
+// ReadStructuredParameters holds parameters specific to the ReadStructuredTask.
+type ReadStructuredParameters struct {
+	BaseParameters
+	FilePath string `json:"file_path"`
+	// Format is StructuredFormatJSON, StructuredFormatYAML or StructuredFormatTOML.
+	// When empty it is inferred from the file extension (.json, .yaml, .yml or .toml).
+	Format string `json:"format,omitempty"`
+}
+
+// ReadStructuredTask defines the structure for parsing a structured file.
+func NewReadStructuredTask(taskId string, description string, parameters ReadStructuredParameters) *Task {
+	return &Task{
+		BaseTask:   BaseTask{TaskId: taskId, Type: TaskReadStructured, Description: description},
+		Parameters: parameters,
+	}
+}
+
+// ExtractJSONParameters holds parameters specific to the ExtractJSONTask.
+type ExtractJSONParameters struct {
+	BaseParameters
+	// Input is the JSON document. It is typically the output of an earlier task,
+	// inserted with a ${<task_id>.result} reference.
+	Input string `json:"input"`
+	// Path selects the value to extract, e.g. `$.items[0].name` or `$["key.with.dots"]`.
+	// The leading `$` is optional, and `$` alone selects the whole document.
+	Path string `json:"path"`
+}
+
+// ExtractJSONTask defines the structure for extracting a value from JSON.
+func NewExtractJSONTask(taskId string, description string, parameters ExtractJSONParameters) *Task {
+	return &Task{
+		BaseTask:   BaseTask{TaskId: taskId, Type: TaskExtractJSON, Description: description},
+		Parameters: parameters,
+	}
+}
+
+// ValidatePatchParameters holds parameters specific to the ValidatePatchTask.
+type ValidatePatchParameters struct {
+	BaseParameters
+	// Patch is the unified diff to check. It may cover several files.
+	Patch string `json:"patch"`
+}
+
+// ValidatePatchTask defines the structure for checking a patch without a target file.
+func NewValidatePatchTask(taskId string, description string, parameters ValidatePatchParameters) *Task {
+	return &Task{
+		BaseTask:   BaseTask{TaskId: taskId, Type: TaskValidatePatch, Description: description},
+		Parameters: parameters,
+	}
+}
+
+// ManifestParameters holds parameters specific to the ManifestTask.
+type ManifestParameters struct {
+	BaseParameters
+	// Path is the root of the tree to hash. Defaults to the working directory.
+	Path string `json:"path"`
+	// OutputPath is the manifest file to write. If it lies within Path it is not listed.
+	OutputPath string `json:"output_path"`
+	// Format is ManifestFormatSHA256Sums (the default) or ManifestFormatJSON.
+	Format string `json:"format,omitempty"`
+}
+
+// Manifest formats supported by ManifestParameters.Format.
+const (
+	// ManifestFormatSHA256Sums writes "<digest>  <path>" lines, as read by sha256sum -c.
+	ManifestFormatSHA256Sums = "sha256sums"
+	// ManifestFormatJSON writes a JSON object mapping each path to its digest.
+	ManifestFormatJSON = "json"
+)
+
+// ManifestTask defines the structure for writing a checksum manifest of a directory.
+func NewManifestTask(taskId string, description string, parameters ManifestParameters) *Task {
+	return &Task{
+		BaseTask:   BaseTask{TaskId: taskId, Type: TaskManifest, Description: description},
+		Parameters: parameters,
+	}
+}
+
+// ReadLinkParameters holds parameters specific to the ReadLinkTask.
+type ReadLinkParameters struct {
+	BaseParameters
+	// Path is the symbolic link to read. It is resolved against WorkingDirectory but
+	// the link itself is not followed.
+	Path string `json:"path"`
+}
+
+// ReadLinkTask defines the structure for reading the target of a symbolic link.
+func NewReadLinkTask(taskId string, description string, parameters ReadLinkParameters) *Task {
+	return &Task{
+		BaseTask:   BaseTask{TaskId: taskId, Type: TaskReadLink, Description: description},
+		Parameters: parameters,
+	}
+}
+
+// PatchFilesParameters holds parameters specific to the PatchFilesTask.
+type PatchFilesParameters struct {
+	BaseParameters
+	// Files lists the files to patch. Each is resolved against WorkingDirectory.
+	Files []string `json:"files"`
+	// Patch is a single-file unified diff used as a template. The file names in its
+	// --- and +++ headers are replaced with each entry of Files before it is applied.
+	Patch string `json:"patch"`
+	// Transactional patches all files or none. Every file is patched in memory and
+	// staged before any of them is replaced.
+	Transactional bool `json:"transactional,omitempty"`
+	// IgnoreTrailingWhitespace matches context and deleted lines while ignoring
+	// trailing spaces and tabs.
+	IgnoreTrailingWhitespace bool `json:"ignore_trailing_whitespace,omitempty"`
+}
+
+// PatchFilesTask defines the structure for applying one patch to several files.
+func NewPatchFilesTask(taskId string, description string, parameters PatchFilesParameters) *Task {
+	return &Task{
+		BaseTask:   BaseTask{TaskId: taskId, Type: TaskPatchFiles, Description: description},
+		Parameters: parameters,
+	}
+}
+
+// TruncateParameters holds parameters specific to the TruncateTask.
+type TruncateParameters struct {
+	BaseParameters
+	FilePath string `json:"file_path"`
+	// Size is the length to cut the file to. A file shorter than Size is extended with
+	// zero bytes. A missing file is created empty when Size is 0 and is an error otherwise.
+	Size int64 `json:"size"`
+}
+
+// TruncateTask defines the structure for setting the size of a file.
+func NewTruncateTask(taskId string, description string, parameters TruncateParameters) *Task {
+	return &Task{
+		BaseTask:   BaseTask{TaskId: taskId, Type: TaskTruncate, Description: description},
+		Parameters: parameters,
+	}
+}
+
+// CompareTreesParameters holds parameters specific to the CompareTreesTask.
+type CompareTreesParameters struct {
+	BaseParameters
+	// Left is the reference tree, such as the source of a copy.
+	Left string `json:"left"`
+	// Right is the tree compared against Left, such as the copy's destination.
+	// Files only under Right are reported as added and files only under Left as removed.
+	Right string `json:"right"`
+}
+
+// CompareTreesTask defines the structure for comparing two directory trees.
+func NewCompareTreesTask(taskId string, description string, parameters CompareTreesParameters) *Task {
+	return &Task{
+		BaseTask:   BaseTask{TaskId: taskId, Type: TaskCompareTrees, Description: description},
+		Parameters: parameters,
+	}
+}
+
+// FileMoveParameters holds parameters specific to the FileMoveTask.
+type FileMoveParameters struct {
+	BaseParameters
+	// Source is the file or directory to move.
+	Source string `json:"source"`
+	// Destination is the new path of Source. Missing parent directories are created.
+	Destination string `json:"destination"`
+	// Overwrite allows an existing destination to be replaced.
+	Overwrite bool `json:"overwrite,omitempty"`
+}
+
+// FileMoveTask defines the structure for moving or renaming a file or directory.
+func NewFileMoveTask(taskId string, description string, parameters FileMoveParameters) *Task {
+	return &Task{
+		BaseTask:   BaseTask{TaskId: taskId, Type: TaskFileMove, Description: description},
+		Parameters: parameters,
+	}
+}
+
+// FileCopyParameters holds parameters specific to the FileCopyTask.
+type FileCopyParameters struct {
+	BaseParameters
+	// Source is the file or directory to copy.
+	Source string `json:"source"`
+	// Destination is the path of the copy. Missing parent directories are created.
+	Destination string `json:"destination"`
+	// Recursive allows Source to be a directory, whose tree is recreated at Destination.
+	Recursive bool `json:"recursive,omitempty"`
+	// Overwrite allows an existing destination to be replaced. Existing directories
+	// are merged into, replacing the files they share with Source.
+	Overwrite bool `json:"overwrite,omitempty"`
+	// Include, when set, limits a recursive copy to the files matching one of these
+	// glob patterns. Directories are always walked.
+	Include []string `json:"include,omitempty"`
+	// Exclude lists glob patterns for files and directories a recursive copy skips,
+	// such as ".git" or "node_modules". An excluded directory is skipped entirely.
+	// Patterns containing a slash match the path relative to Source, others the base name.
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// FileCopyTask defines the structure for copying a file or directory tree.
+func NewFileCopyTask(taskId string, description string, parameters FileCopyParameters) *Task {
+	return &Task{
+		BaseTask:   BaseTask{TaskId: taskId, Type: TaskFileCopy, Description: description},
+		Parameters: parameters,
+	}
+}
+
+// GroupParameters holds the optional parameters of a GroupTask.
+type GroupParameters struct {
+	// ForwardChildOutput re-emits every RUNNING output chunk of a child on the group's own
+	// stream, with each line prefixed by "[<task_id>] " of the task that produced it.
+	// Nested groups in this mode produce chunks prefixed with the full path of task IDs.
+	ForwardChildOutput bool `json:"forward_child_output,omitempty"`
+	// MaxRuntimeMs caps the wall-clock time, in milliseconds, of the whole group.
+	// Once it is exceeded the running child is cancelled, the remaining children are
+	// not started and the group fails with a *BudgetExceededError. Zero means no budget.
+	MaxRuntimeMs int64 `json:"max_runtime_ms,omitempty"`
+	// Parallel runs the children concurrently instead of one after another. A failed
+	// child does not stop its siblings, and the group fails if any required child failed.
+	// A child still waits for the earlier siblings listed in its DependsOn.
+	Parallel bool `json:"parallel,omitempty"`
+	// MaxConcurrency bounds how many children of a Parallel group run at the same time.
+	// Zero defaults to the number of CPUs.
+	MaxConcurrency int `json:"max_concurrency,omitempty"`
+}
+
+// maxRuntime returns the group's runtime budget, or zero if it has none.
+func (p GroupParameters) maxRuntime() time.Duration {
+	return time.Duration(p.MaxRuntimeMs) * time.Millisecond
+}
+
 // GroupTask defines the structure for a group of tasks that will be executed in sequence.
 func NewGroupTask(taskId string, description string, children []*Task) *Task {
 	return &Task{
@@ -179,11 +787,29 @@ func NewGroupTask(taskId string, description string, children []*Task) *Task {
 	}
 }
 
+// FailureKind classifies the cause of a failed task.
+type FailureKind string
+
+const (
+	// FailureCancelled means the task's context was cancelled.
+	FailureCancelled FailureKind = "CANCELLED"
+	// FailureTimedOut means the task exceeded its timeout or deadline.
+	FailureTimedOut FailureKind = "TIMED_OUT"
+	// FailureExecutionError means the task ran but its operation failed, e.g. a non-zero exit code.
+	FailureExecutionError FailureKind = "EXECUTION_ERROR"
+	// FailureValidationError means the task's parameters were rejected before it ran.
+	FailureValidationError FailureKind = "VALIDATION_ERROR"
+)
+
 // OutputResult defines the structure of the result returned after executing a command.
 // It provides status, messages, potential errors, and command-specific data.
 type OutputResult struct {
 	// TaskID links the result back to the specific command instance that was executed.
 	TaskID string `json:"task_id"`
+	// TaskType and Description echo the task's own fields so that consumers can
+	// correlate a result with its task without looking the task up.
+	TaskType    TaskType `json:"task_type,omitempty"`
+	Description string   `json:"description,omitempty"`
 	// Status reflects the final execution status (RUNNING, SUCCEEDED, FAILED).
 	Status TaskStatus `json:"status"`
 	// Message provides a human-readable summary or status update about the execution.
@@ -196,6 +822,56 @@ type OutputResult struct {
 	// For ListDirectory, it's a newline-separated list of entries.
 	// For others like FileWrite or PatchFile, it might be empty if success is indicated by Status.
 	ResultData string `json:"resultData,omitempty"`
+	// FailureKind classifies why a FAILED final result failed. It is empty otherwise.
+	FailureKind FailureKind `json:"failure_kind,omitempty"`
+	// Truncated reports that the output was cut short by a size or line limit. Set on final results only.
+	Truncated bool `json:"truncated,omitempty"`
+	// OffsetReached is the byte offset a FileRead had consumed when it finished or was interrupted.
+	// It can be passed as StartByte to resume reading.
+	OffsetReached int64 `json:"offset_reached,omitempty"`
+	// StartedAt is when the executor began running the task. Set on final results only.
+	StartedAt time.Time `json:"started_at,omitzero"`
+	// FinishedAt is when the executor produced the final result. Set on final results only.
+	FinishedAt time.Time `json:"finished_at,omitzero"`
+	// Payload holds a typed representation of the result for executors that produce one,
+	// such as the parsed document of a READ_STRUCTURED task.
+	Payload any `json:"payload,omitempty"`
+	// SpanID identifies the execution of the task that produced the result, and
+	// ParentSpanID the group or caller span it ran in, so that the execution tree can be
+	// reconstructed from results alone. Tasks get a span when run by a group, a Plan, a
+	// PoolRunner or RunAndCapture; other than groups, a task run directly through its
+	// executor has none.
+	SpanID       string `json:"span_id,omitempty"`
+	ParentSpanID string `json:"parent_span_id,omitempty"`
+	// ExitCode is the exit status of a BASH_EXEC command that ran to completion.
+	// It is nil when the command did not exit on its own, such as on a timeout.
+	ExitCode *int `json:"exit_code,omitempty"`
+	// Err is the structured error behind Error, for executors that provide one.
+	// It is not serialized; use errors.As to inspect it.
+	Err error `json:"-"`
+}
+
+// isZero reports whether r holds no result.
+func (r OutputResult) isZero() bool {
+	// Err and Payload are excluded from the comparison because their dynamic types may not be comparable
+	if r.Err != nil || r.Payload != nil {
+		return false
+	}
+	return r == OutputResult{}
+}
+
+// Duration returns how long the task ran, or zero if the timestamps are not set.
+func (r OutputResult) Duration() time.Duration {
+	if r.StartedAt.IsZero() || r.FinishedAt.IsZero() {
+		return 0
+	}
+	return r.FinishedAt.Sub(r.StartedAt)
+}
+
+// setTimes records startedAt as the start time and the current time on clock as the finish time.
+func (r *OutputResult) setTimes(clock Clock, startedAt time.Time) {
+	r.StartedAt = startedAt
+	r.FinishedAt = clock.Now()
 }
 
 // Command is a generic interface that all command structs should implicitly satisfy.
@@ -213,11 +889,14 @@ type OutputResult struct {
 // func (bc BaseCommand) IsCommand() {}
 
 // UpdateOutput updates the task's Output field with the provided OutputResult.
-// It ensures that the Status field in both the BaseTask and its Output are consistent.
+// It ensures that the Status field in both the BaseTask and its Output are consistent,
+// and records the task's type and description on output itself so that the result an
+// executor goes on to send carries them too.
 func (bt *BaseTask) UpdateOutput(output *OutputResult) {
 	if output == nil {
 		return
 	}
+	*output = bt.describe(*output)
 
 	// Make a copy of the output
 	outputCopy := *output
@@ -229,6 +908,13 @@ func (bt *BaseTask) UpdateOutput(output *OutputResult) {
 	bt.Output = outputCopy
 }
 
+// describe returns r with the task's type and description filled in.
+func (bt *BaseTask) describe(r OutputResult) OutputResult {
+	r.TaskType = bt.Type
+	r.Description = bt.Description
+	return r
+}
+
 // MarshalJSON implements custom JSON marshaling for Task to handle dynamic Parameters typing
 func (t *Task) MarshalJSON() ([]byte, error) {
 	// Create a map to hold all fields
@@ -245,8 +931,23 @@ func (t *Task) MarshalJSON() ([]byte, error) {
 		data["children"] = t.Children
 	}
 
+	// Add DependsOn if set
+	if len(t.DependsOn) > 0 {
+		data["depends_on"] = t.DependsOn
+	}
+
+	// Add Optional if set
+	if t.Optional {
+		data["optional"] = t.Optional
+	}
+
+	// Add DeadlineUnix if set
+	if t.DeadlineUnix != 0 {
+		data["deadline_unix"] = t.DeadlineUnix
+	}
+
 	// Add Output if not empty
-	if t.Output != (OutputResult{}) {
+	if !t.Output.isZero() {
 		data["output"] = t.Output
 	}
 
@@ -330,8 +1031,142 @@ func (t *Task) UnmarshalJSON(data []byte) error {
 			}
 			t.Parameters = params
 
+		case TaskWriteFiles:
+			var params WriteFilesParameters
+			if err := json.Unmarshal(paramsData, &params); err != nil {
+				return err
+			}
+			t.Parameters = params
+
+		case TaskTouch:
+			var params TouchParameters
+			if err := json.Unmarshal(paramsData, &params); err != nil {
+				return err
+			}
+			t.Parameters = params
+
+		case TaskDiskUsage:
+			var params DiskUsageParameters
+			if err := json.Unmarshal(paramsData, &params); err != nil {
+				return err
+			}
+			t.Parameters = params
+
+		case TaskWhich:
+			var params WhichParameters
+			if err := json.Unmarshal(paramsData, &params); err != nil {
+				return err
+			}
+			t.Parameters = params
+
+		case TaskEval:
+			var params EvalParameters
+			if err := json.Unmarshal(paramsData, &params); err != nil {
+				return err
+			}
+			t.Parameters = params
+
+		case TaskNormalizeEOL:
+			var params NormalizeEOLParameters
+			if err := json.Unmarshal(paramsData, &params); err != nil {
+				return err
+			}
+			t.Parameters = params
+
+		case TaskFileCompareAndSwap:
+			var params FileCompareAndSwapParameters
+			if err := json.Unmarshal(paramsData, &params); err != nil {
+				return err
+			}
+			t.Parameters = params
+
+		case TaskReadStructured:
+			var params ReadStructuredParameters
+			if err := json.Unmarshal(paramsData, &params); err != nil {
+				return err
+			}
+			t.Parameters = params
+
+		case TaskExtractJSON:
+			var params ExtractJSONParameters
+			if err := json.Unmarshal(paramsData, &params); err != nil {
+				return err
+			}
+			t.Parameters = params
+
+		case TaskValidatePatch:
+			var params ValidatePatchParameters
+			if err := json.Unmarshal(paramsData, &params); err != nil {
+				return err
+			}
+			t.Parameters = params
+
+		case TaskManifest:
+			var params ManifestParameters
+			if err := json.Unmarshal(paramsData, &params); err != nil {
+				return err
+			}
+			t.Parameters = params
+
+		case TaskReadLink:
+			var params ReadLinkParameters
+			if err := json.Unmarshal(paramsData, &params); err != nil {
+				return err
+			}
+			t.Parameters = params
+
+		case TaskPatchFiles:
+			var params PatchFilesParameters
+			if err := json.Unmarshal(paramsData, &params); err != nil {
+				return err
+			}
+			t.Parameters = params
+
+		case TaskTruncate:
+			var params TruncateParameters
+			if err := json.Unmarshal(paramsData, &params); err != nil {
+				return err
+			}
+			t.Parameters = params
+
+		case TaskCompareTrees:
+			var params CompareTreesParameters
+			if err := json.Unmarshal(paramsData, &params); err != nil {
+				return err
+			}
+			t.Parameters = params
+
+		case TaskFileMove:
+			var params FileMoveParameters
+			if err := json.Unmarshal(paramsData, &params); err != nil {
+				return err
+			}
+			t.Parameters = params
+
+		case TaskFileCopy:
+			var params FileCopyParameters
+			if err := json.Unmarshal(paramsData, &params); err != nil {
+				return err
+			}
+			t.Parameters = params
+
 		case TaskGroup:
-			// GroupTask doesn't have parameters - it uses Children
+			// Group parameters are optional; the tasks themselves are in Children
+			var params GroupParameters
+			if err := json.Unmarshal(paramsData, &params); err != nil {
+				return err
+			}
+			t.Parameters = params
+
+		default:
+			// Custom task types decode their parameters with a registered decoder
+			params, ok, err := decodeCustomParameters(taskType, paramsData)
+			if err != nil {
+				return err
+			}
+			if ok {
+				t.Parameters = params
+			}
 		}
 	}
 
@@ -1,7 +1,10 @@
 package task
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestBaseTask_UpdateOutput(t *testing.T) {
@@ -83,3 +86,35 @@ func TestBaseTask_UpdateOutput(t *testing.T) {
 		}
 	})
 }
+
+func TestOutputResult_TimestampsJSON(t *testing.T) {
+	// Zero timestamps are omitted
+	data, err := json.Marshal(OutputResult{TaskID: "no-times", Status: StatusRunning})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if strings.Contains(string(data), "started_at") || strings.Contains(string(data), "finished_at") {
+		t.Errorf("Expected zero timestamps to be omitted, got %s", data)
+	}
+	if d := (OutputResult{}).Duration(); d != 0 {
+		t.Errorf("Expected zero duration without timestamps, got %v", d)
+	}
+
+	started := time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)
+	result := OutputResult{TaskID: "times", Status: StatusSucceeded, StartedAt: started, FinishedAt: started.Add(1500 * time.Millisecond)}
+	data, err = json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var decoded OutputResult
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !decoded.StartedAt.Equal(result.StartedAt) || !decoded.FinishedAt.Equal(result.FinishedAt) {
+		t.Errorf("Timestamps did not round-trip: got %v - %v", decoded.StartedAt, decoded.FinishedAt)
+	}
+	if d := decoded.Duration(); d != 1500*time.Millisecond {
+		t.Errorf("Expected duration 1.5s, got %v", d)
+	}
+}
@@ -0,0 +1,197 @@
+package task
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContextLines is the number of unchanged lines surrounding each change in a generated hunk.
+const diffContextLines = 3
+
+// diffNoNewline follows the last line of a side that does not end in a newline.
+// Lines never contain a newline themselves, so a line carrying the marker never
+// equals the same text terminated by a newline on the other side.
+const diffNoNewline = "\n\\ No newline at end of file"
+
+// diffOpKind identifies how a line participates in a line-based diff.
+type diffOpKind byte
+
+const (
+	diffEqual  diffOpKind = ' '
+	diffDelete diffOpKind = '-'
+	diffInsert diffOpKind = '+'
+)
+
+// diffOp is a single line of a computed diff.
+type diffOp struct {
+	kind diffOpKind
+	text string
+}
+
+// unifiedDiff returns a unified diff that transforms original into updated.
+// The file headers use the conventional a/ and b/ prefixes for the given name.
+// An empty string is returned when the contents are identical.
+func unifiedDiff(name string, original, updated []byte) string {
+	if string(original) == string(updated) {
+		return ""
+	}
+
+	ops := diffLines(splitDiffLines(string(original)), splitDiffLines(string(updated)))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- a/%s\n+++ b/%s\n", name, name)
+
+	origLine, newLine := 1, 1
+	for start := 0; start < len(ops); {
+		// Find the next change
+		for start < len(ops) && ops[start].kind == diffEqual {
+			start++
+			origLine++
+			newLine++
+		}
+		if start == len(ops) {
+			break
+		}
+
+		// Extend the hunk backwards with leading context
+		hunkStart := start - diffContextLines
+		if hunkStart < 0 {
+			hunkStart = 0
+		}
+		origStart := origLine - (start - hunkStart)
+		newStart := newLine - (start - hunkStart)
+
+		// Extend the hunk forwards until a gap of unchanged lines is large enough to split
+		end := start
+		for end < len(ops) {
+			if ops[end].kind != diffEqual {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].kind == diffEqual {
+				run++
+			}
+			if run == len(ops) || run-end > 2*diffContextLines {
+				end += min(run-end, diffContextLines)
+				break
+			}
+			end = run
+		}
+
+		var origCount, newCount int
+		var body strings.Builder
+		for _, op := range ops[hunkStart:end] {
+			body.WriteByte(byte(op.kind))
+			body.WriteString(op.text)
+			body.WriteByte('\n')
+			if op.kind != diffInsert {
+				origCount++
+			}
+			if op.kind != diffDelete {
+				newCount++
+			}
+		}
+
+		// Empty ranges are addressed by the line before them, as in GNU diff
+		if origCount == 0 {
+			origStart--
+		}
+		if newCount == 0 {
+			newStart--
+		}
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", origStart, origCount, newStart, newCount)
+		b.WriteString(body.String())
+
+		// Advance the line counters past the hunk
+		for _, op := range ops[start:end] {
+			if op.kind != diffInsert {
+				origLine++
+			}
+			if op.kind != diffDelete {
+				newLine++
+			}
+		}
+		start = end
+	}
+
+	return b.String()
+}
+
+// splitDiffLines splits content into lines without their terminating newlines.
+// A last line that is not terminated carries diffNoNewline.
+func splitDiffLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	if trimmed, ok := strings.CutSuffix(content, "\n"); ok {
+		return strings.Split(trimmed, "\n")
+	}
+	lines := strings.Split(content, "\n")
+	lines[len(lines)-1] += diffNoNewline
+	return lines
+}
+
+// diffLines computes a minimal line diff using the longest common subsequence.
+// Common leading and trailing lines are stripped first to keep the table small
+// for the typical case of localized edits.
+func diffLines(a, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{diffEqual, line})
+	}
+
+	midA := a[prefix : len(a)-suffix]
+	midB := b[prefix : len(b)-suffix]
+
+	// lcs[i][j] is the LCS length of midA[i:] and midB[j:]
+	lcs := make([][]int, len(midA)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(midB)+1)
+	}
+	for i := len(midA) - 1; i >= 0; i-- {
+		for j := len(midB) - 1; j >= 0; j-- {
+			if midA[i] == midB[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(midA) && j < len(midB) {
+		switch {
+		case midA[i] == midB[j]:
+			ops = append(ops, diffOp{diffEqual, midA[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{diffDelete, midA[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{diffInsert, midB[j]})
+			j++
+		}
+	}
+	for ; i < len(midA); i++ {
+		ops = append(ops, diffOp{diffDelete, midA[i]})
+	}
+	for ; j < len(midB); j++ {
+		ops = append(ops, diffOp{diffInsert, midB[j]})
+	}
+
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{diffEqual, line})
+	}
+	return ops
+}
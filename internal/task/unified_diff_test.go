@@ -0,0 +1,116 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnifiedDiff_RoundTrip(t *testing.T) {
+	var longOriginal, longUpdated strings.Builder
+	for i := 1; i <= 40; i++ {
+		fmt.Fprintf(&longOriginal, "line %d\n", i)
+		switch i {
+		case 3:
+			longUpdated.WriteString("line 3 changed\n")
+		case 20:
+			// deleted
+		case 35:
+			longUpdated.WriteString("inserted before 35\n")
+			fmt.Fprintf(&longUpdated, "line %d\n", i)
+		default:
+			fmt.Fprintf(&longUpdated, "line %d\n", i)
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		original string
+		updated  string
+	}{
+		{name: "Single line change", original: "a\nb\nc\n", updated: "a\nB\nc\n"},
+		{name: "Insertion at start", original: "a\nb\n", updated: "start\na\nb\n"},
+		{name: "Append at end", original: "a\nb\n", updated: "a\nb\nc\nd\n"},
+		{name: "Delete lines", original: "a\nb\nc\nd\n", updated: "a\nd\n"},
+		{name: "Replace everything", original: "x\ny\n", updated: "1\n2\n3\n"},
+		{name: "Multiple separated hunks", original: longOriginal.String(), updated: longUpdated.String()},
+		{name: "From empty", original: "", updated: "new\ncontent\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			patch := unifiedDiff("file.txt", []byte(tc.original), []byte(tc.updated))
+			require.NotEmpty(t, patch)
+			assert.True(t, strings.HasPrefix(patch, "--- a/file.txt\n+++ b/file.txt\n"), "Unexpected headers: %q", patch)
+
+			applied, err := applyPatch(context.Background(), []byte(tc.original), []byte(patch), PatchOptions{})
+			require.NoError(t, err, "Generated diff failed to apply:\n%s", patch)
+			assert.Equal(t, tc.updated, string(applied), "Generated diff:\n%s", patch)
+		})
+	}
+}
+
+func TestUnifiedDiff_SeparateHunks(t *testing.T) {
+	var original, updated strings.Builder
+	for i := 1; i <= 30; i++ {
+		fmt.Fprintf(&original, "line %d\n", i)
+		if i == 2 || i == 28 {
+			fmt.Fprintf(&updated, "changed %d\n", i)
+		} else {
+			fmt.Fprintf(&updated, "line %d\n", i)
+		}
+	}
+
+	patch := unifiedDiff("f", []byte(original.String()), []byte(updated.String()))
+	assert.Equal(t, 2, strings.Count(patch, "@@ -"), "Distant changes should produce separate hunks:\n%s", patch)
+	assert.Contains(t, patch, "@@ -1,5 +1,5 @@")
+	assert.Contains(t, patch, "@@ -25,6 +25,6 @@")
+}
+
+func TestUnifiedDiff_Identical(t *testing.T) {
+	assert.Empty(t, unifiedDiff("f", []byte("same\n"), []byte("same\n")))
+}
+
+func TestUnifiedDiff_MissingFinalNewline(t *testing.T) {
+	testCases := []struct {
+		name     string
+		original string
+		updated  string
+		expected string
+	}{
+		{
+			name:     "Newline added",
+			original: "a\nb",
+			updated:  "a\nb\n",
+			expected: "--- a/f\n+++ b/f\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+		},
+		{
+			name:     "Newline removed",
+			original: "a\nb\n",
+			updated:  "a\nb",
+			expected: "--- a/f\n+++ b/f\n@@ -1,2 +1,2 @@\n a\n-b\n+b\n\\ No newline at end of file\n",
+		},
+		{
+			name:     "Last line changed and newline removed",
+			original: "a\nb\n",
+			updated:  "a\nc",
+			expected: "--- a/f\n+++ b/f\n@@ -1,2 +1,2 @@\n a\n-b\n+c\n\\ No newline at end of file\n",
+		},
+		{
+			name:     "Neither side ends in a newline",
+			original: "a\nb",
+			updated:  "x\nb",
+			expected: "--- a/f\n+++ b/f\n@@ -1,2 +1,2 @@\n-a\n+x\n b\n\\ No newline at end of file\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, unifiedDiff("f", []byte(tc.original), []byte(tc.updated)))
+		})
+	}
+}
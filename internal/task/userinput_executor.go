@@ -1,29 +1,68 @@
 package task
 
 import (
+	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
 )
 
 // Error constants for RequestUserInputExecutor
 const (
 	errUserInputInvalidCommandType = "invalid command type for RequestUserInputExecutor: %T"
+	errUserInputClosed             = "input closed before a line was entered"
+	errUserInputRead               = "failed to read user input: %w"
+
+	// Status messages
+	msgUserInputCancelled = "Waiting for user input cancelled."
+	msgUserInputTimedOut  = "Waiting for user input timed out."
+	msgUserInputFailed    = "Reading user input failed: %v"
+	msgUserInputReceived  = "User provided input."
 )
 
 // RequestUserInputExecutor handles the execution of RequestUserInput.
+// It shows the prompt and reads the user's answer as one line from its input.
 type RequestUserInputExecutor struct {
-	// Dependencies for handling user input requests can be added here.
+	config ExecutorConfig
+
+	// The reader of the executor's input, looked up on first use
+	startReading sync.Once
+	input        *lineReader
 }
 
-// NewRequestUserInputExecutor creates a new RequestUserInputExecutor.
+// userInputLine is one line read from the input, or the error that ended it.
+type userInputLine struct {
+	text string
+	err  error
+}
+
+var _ TaskExecutor = (*RequestUserInputExecutor)(nil)
+
+// NewRequestUserInputExecutor creates a new RequestUserInputExecutor reading from os.Stdin.
 func NewRequestUserInputExecutor() *RequestUserInputExecutor {
-	return &RequestUserInputExecutor{}
+	return NewRequestUserInputExecutorWithConfig(ExecutorConfig{})
+}
+
+// NewRequestUserInputExecutorWithReader creates a new RequestUserInputExecutor reading from r.
+func NewRequestUserInputExecutorWithReader(r io.Reader) *RequestUserInputExecutor {
+	return NewRequestUserInputExecutorWithConfig(ExecutorConfig{UserInput: r})
+}
+
+// NewRequestUserInputExecutorWithConfig creates a new RequestUserInputExecutor using the shared executor config.
+func NewRequestUserInputExecutorWithConfig(cfg ExecutorConfig) *RequestUserInputExecutor {
+	return &RequestUserInputExecutor{config: cfg}
 }
 
 // Execute handles the request for user input specified in the RequestUserInput command.
-// It expects the cmd argument to be of type *RequestUserInputTask.
-// The actual user interaction mechanism is assumed to be handled elsewhere;
-// this method just returns the prompt message.
+// It sends the prompt as the Message of a RUNNING result, then waits for a line of
+// input and returns it, without its line terminator, as the final ResultData.
+// If ctx is done first, the task fails with ctx's error and the line, once entered,
+// goes to the next task. A final line without a newline counts as an answer; input
+// that ends without one fails the task.
 func (e *RequestUserInputExecutor) Execute(ctx context.Context, userInputCmd *Task) (<-chan OutputResult, error) {
 	// Type assertion to ensure we have a RequestUserInputTask command
 	if userInputCmd.Type != TaskRequestUserInput {
@@ -46,15 +85,132 @@ func (e *RequestUserInputExecutor) Execute(ctx context.Context, userInputCmd *Ta
 	go func() {
 		defer close(results)
 
-		// Send the prompt message as the result, regardless of context state
-		// Context cancellation is not really applicable for user input prompts
-		// as they are essentially just messages being passed
-		results <- OutputResult{
+		ctx, cancel := e.config.withDeadline(ctx, userInputCmd)
+		defer cancel()
+
+		startedAt := e.config.clock().Now()
+		userInputCmd.Status = StatusRunning
+
+		prompt := userInputCmd.describe(OutputResult{
 			TaskID:  userInputCmd.TaskId,
-			Status:  StatusSucceeded,
+			Status:  StatusRunning,
 			Message: userInputCmd.Parameters.(RequestUserInputParameters).Prompt,
+		})
+		var finalResult OutputResult
+		if !e.config.send(ctx, results, prompt) {
+			finalResult = createUserInputErrorResult(userInputCmd.TaskId, ctx.Err())
+		} else if answer, err := e.readLine(ctx); err != nil {
+			finalResult = createUserInputErrorResult(userInputCmd.TaskId, err)
+		} else {
+			finalResult = OutputResult{
+				TaskID:     userInputCmd.TaskId,
+				Status:     StatusSucceeded,
+				Message:    msgUserInputReceived,
+				ResultData: answer,
+			}
 		}
+
+		userInputCmd.Status = finalResult.Status
+		finalResult.setTimes(e.config.clock(), startedAt)
+		userInputCmd.UpdateOutput(&finalResult)
+		e.config.send(ctx, results, finalResult)
 	}()
 
 	return results, nil
 }
+
+// readLine waits for the next line of input or for ctx to be done.
+func (e *RequestUserInputExecutor) readLine(ctx context.Context) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	e.startReading.Do(func() { e.input = sharedLineReader(e.config.userInput()) })
+	select {
+	case line, ok := <-e.input.lines:
+		if !ok {
+			return "", errors.New(errUserInputClosed)
+		}
+		return line.text, line.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// lineReader hands the lines of one input to the tasks waiting on it. Lines are read
+// by a single goroutine per input, shared by every executor reading that input, so
+// that a read abandoned by a cancelled task leaves its line for the next task and
+// executors sharing os.Stdin do not each leave a goroutine blocked reading it.
+type lineReader struct {
+	lines chan userInputLine
+}
+
+var (
+	lineReadersMu sync.Mutex
+	lineReaders   = make(map[io.Reader]*lineReader)
+)
+
+// sharedLineReader returns the lineReader of r, starting it if r has none. An input
+// whose dynamic type cannot be a map key gets a lineReader of its own.
+func sharedLineReader(r io.Reader) *lineReader {
+	lr := &lineReader{lines: make(chan userInputLine)}
+	if !reflect.TypeOf(r).Comparable() {
+		go lr.read(r, false)
+		return lr
+	}
+
+	lineReadersMu.Lock()
+	defer lineReadersMu.Unlock()
+	if existing, ok := lineReaders[r]; ok {
+		return existing
+	}
+	lineReaders[r] = lr
+	go lr.read(r, true)
+	return lr
+}
+
+// read hands each line of r to the waiting tasks until r ends, then forgets a shared
+// reader so that the goroutine and r can be released.
+func (lr *lineReader) read(r io.Reader, shared bool) {
+	defer close(lr.lines)
+	if shared {
+		defer func() {
+			lineReadersMu.Lock()
+			delete(lineReaders, r)
+			lineReadersMu.Unlock()
+		}()
+	}
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			lr.lines <- userInputLine{text: strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")}
+		}
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				lr.lines <- userInputLine{err: fmt.Errorf(errUserInputRead, err)}
+			}
+			return
+		}
+	}
+}
+
+// createUserInputErrorResult constructs the final OutputResult for a failed RequestUserInputTask.
+func createUserInputErrorResult(taskID string, err error) OutputResult {
+	var message string
+	switch {
+	case errors.Is(err, context.Canceled):
+		message = msgUserInputCancelled
+	case errors.Is(err, context.DeadlineExceeded):
+		message = msgUserInputTimedOut
+	default:
+		message = fmt.Sprintf(msgUserInputFailed, err)
+	}
+	return OutputResult{
+		TaskID:      taskID,
+		Status:      StatusFailed,
+		Message:     message,
+		Error:       err.Error(),
+		Err:         err,
+		FailureKind: failureKind(err),
+	}
+}
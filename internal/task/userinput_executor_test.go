@@ -2,6 +2,8 @@ package task
 
 import (
 	"context"
+	"io"
+	"strings"
 	"testing"
 	"time"
 
@@ -9,32 +11,65 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func TestRequestUserInputExecutor_Execute(t *testing.T) {
-	executor := NewRequestUserInputExecutor()
+// collectUserInputResults returns the prompt results and the final result of a REQUEST_USER_INPUT task.
+func collectUserInputResults(t *testing.T, resultsChan <-chan OutputResult) ([]OutputResult, OutputResult) {
+	t.Helper()
+	var prompts []OutputResult
+	var finalResult OutputResult
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case result, ok := <-resultsChan:
+			if !ok {
+				return prompts, finalResult
+			}
+			if result.Status == StatusRunning {
+				prompts = append(prompts, result)
+			} else {
+				finalResult = result
+			}
+		case <-timeout:
+			t.Fatal("Timed out waiting for user input results")
+		}
+	}
+}
 
+func TestRequestUserInputExecutor_Execute(t *testing.T) {
 	tests := []struct {
-		name          string
-		prompt        string
-		taskId        string
-		description   string
-		expectError   bool
-		expectMessage string
+		name         string
+		prompt       string
+		input        string
+		expectAnswer string
 	}{
 		{
-			name:          "basic prompt",
-			prompt:        "Please enter your name:",
-			taskId:        "test-1",
-			description:   "Test prompt",
-			expectError:   false,
-			expectMessage: "Please enter your name:",
+			name:         "basic prompt",
+			prompt:       "Please enter your name:",
+			input:        "Alice\n",
+			expectAnswer: "Alice",
+		},
+		{
+			name:         "empty prompt",
+			prompt:       "",
+			input:        "yes\n",
+			expectAnswer: "yes",
 		},
 		{
-			name:          "empty prompt",
-			prompt:        "",
-			taskId:        "test-2",
-			description:   "Empty prompt",
-			expectError:   false,
-			expectMessage: "",
+			name:         "CRLF line",
+			prompt:       "Continue?",
+			input:        "y\r\n",
+			expectAnswer: "y",
+		},
+		{
+			name:         "last line without newline",
+			prompt:       "Continue?",
+			input:        "n",
+			expectAnswer: "n",
+		},
+		{
+			name:         "empty line",
+			prompt:       "Optional comment:",
+			input:        "\nignored\n",
+			expectAnswer: "",
 		},
 	}
 
@@ -43,30 +78,86 @@ func TestRequestUserInputExecutor_Execute(t *testing.T) {
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
 
-			// Create the command with the prompt from the test case
-			cmd := NewRequestUserInputTask(tt.taskId, tt.description, RequestUserInputParameters{
+			executor := NewRequestUserInputExecutorWithReader(strings.NewReader(tt.input))
+			cmd := NewRequestUserInputTask("test-input", "Test prompt", RequestUserInputParameters{
 				Prompt: tt.prompt,
 			})
 
 			resultsChan, err := executor.Execute(ctx, cmd)
 			require.NoError(t, err, "Execute should not return an error")
+			prompts, finalResult := collectUserInputResults(t, resultsChan)
 
-			// Collect results
-			var finalResult OutputResult
-			for result := range resultsChan {
-				finalResult = result
-			}
+			// The prompt is echoed before waiting for input
+			require.Len(t, prompts, 1)
+			assert.Equal(t, tt.prompt, prompts[0].Message)
 
-			// Verify the result
 			assert.Equal(t, cmd.TaskId, finalResult.TaskID)
-			assert.Equal(t, StatusSucceeded, finalResult.Status)
-			assert.Equal(t, tt.expectMessage, finalResult.Message)
+			assert.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+			assert.Equal(t, tt.expectAnswer, finalResult.ResultData)
 			assert.Empty(t, finalResult.Error)
-			assert.Empty(t, finalResult.ResultData)
+			assert.Equal(t, StatusSucceeded, cmd.Status)
+			assert.Equal(t, tt.expectAnswer, cmd.Output.ResultData)
 		})
 	}
 }
 
+func TestRequestUserInputExecutor_Execute_ReadsSuccessiveLines(t *testing.T) {
+	executor := NewRequestUserInputExecutorWithReader(strings.NewReader("first\nsecond\n"))
+
+	for _, expected := range []string{"first", "second"} {
+		resultsChan, err := executor.Execute(context.Background(), NewRequestUserInputTask("test-lines", "", RequestUserInputParameters{Prompt: "Next?"}))
+		require.NoError(t, err)
+		_, finalResult := collectUserInputResults(t, resultsChan)
+		assert.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+		assert.Equal(t, expected, finalResult.ResultData)
+	}
+
+	// The input is exhausted
+	resultsChan, err := executor.Execute(context.Background(), NewRequestUserInputTask("test-eof", "", RequestUserInputParameters{Prompt: "Next?"}))
+	require.NoError(t, err)
+	_, finalResult := collectUserInputResults(t, resultsChan)
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Equal(t, FailureExecutionError, finalResult.FailureKind)
+	assert.Contains(t, finalResult.Error, "input closed")
+}
+
+func TestRequestUserInputExecutor_Execute_SharedReader(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+	first := NewRequestUserInputExecutorWithReader(r)
+	second := NewRequestUserInputExecutorWithReader(r)
+
+	ask := func(executor *RequestUserInputExecutor) <-chan OutputResult {
+		resultsChan, err := executor.Execute(context.Background(), NewRequestUserInputTask("test-shared", "", RequestUserInputParameters{Prompt: "Next?"}))
+		require.NoError(t, err)
+		return resultsChan
+	}
+
+	// Both lines arrive in one write; a reader per executor would buffer both in one
+	// executor's reader and leave the other waiting forever
+	firstResults := ask(first)
+	go w.Write([]byte("one\ntwo\n"))
+	_, finalResult := collectUserInputResults(t, firstResults)
+	assert.Equal(t, "one", finalResult.ResultData, finalResult.Error)
+
+	_, finalResult = collectUserInputResults(t, ask(second))
+	assert.Equal(t, "two", finalResult.ResultData, finalResult.Error)
+
+	lineReadersMu.Lock()
+	assert.Same(t, lineReaders[r], first.input)
+	lineReadersMu.Unlock()
+	assert.Same(t, first.input, second.input, "executors on one reader should share its line reader")
+
+	// Once the input ends its reader is released
+	w.Close()
+	_, finalResult = collectUserInputResults(t, ask(second))
+	assert.Contains(t, finalResult.Error, "input closed")
+	lineReadersMu.Lock()
+	_, ok := lineReaders[r]
+	lineReadersMu.Unlock()
+	assert.False(t, ok, "the reader of a closed input should be released")
+}
+
 func TestRequestUserInputExecutor_Execute_InvalidCommandType(t *testing.T) {
 	executor := NewRequestUserInputExecutor()
 
@@ -81,7 +172,7 @@ func TestRequestUserInputExecutor_Execute_InvalidCommandType(t *testing.T) {
 }
 
 func TestRequestUserInputExecutor_Execute_ContextCancellation(t *testing.T) {
-	executor := NewRequestUserInputExecutor()
+	executor := NewRequestUserInputExecutorWithReader(strings.NewReader("unused\n"))
 	cmd := NewRequestUserInputTask("test-cancel", "Test cancellation", RequestUserInputParameters{
 		Prompt: "This should be cancelled",
 	})
@@ -91,21 +182,45 @@ func TestRequestUserInputExecutor_Execute_ContextCancellation(t *testing.T) {
 
 	resultsChan, err := executor.Execute(ctx, cmd)
 	require.NoError(t, err, "Execute should not return an error even when context is cancelled")
+	_, finalResult := collectUserInputResults(t, resultsChan)
 
-	// Collect results
-	var finalResult OutputResult
-	for result := range resultsChan {
-		finalResult = result
-	}
-
-	// Verify the result
 	assert.Equal(t, cmd.TaskId, finalResult.TaskID)
-	assert.Equal(t, StatusSucceeded, finalResult.Status)
-	assert.Equal(t, cmd.Parameters.(RequestUserInputParameters).Prompt, finalResult.Message)
-	assert.Empty(t, finalResult.Error)
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Equal(t, FailureCancelled, finalResult.FailureKind)
+	assert.ErrorIs(t, finalResult.Err, context.Canceled)
 	assert.Empty(t, finalResult.ResultData)
 }
 
+func TestRequestUserInputExecutor_Execute_CancelledWhileWaiting(t *testing.T) {
+	reader, writer := io.Pipe()
+	defer writer.Close()
+	executor := NewRequestUserInputExecutorWithReader(reader)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	resultsChan, err := executor.Execute(ctx, NewRequestUserInputTask("test-wait", "", RequestUserInputParameters{Prompt: "Name?"}))
+	require.NoError(t, err)
+
+	// Cancel once the prompt shows the task is waiting for input
+	select {
+	case prompt := <-resultsChan:
+		require.Equal(t, StatusRunning, prompt.Status)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the prompt")
+	}
+	cancel()
+	_, finalResult := collectUserInputResults(t, resultsChan)
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.ErrorIs(t, finalResult.Err, context.Canceled)
+
+	// A line entered after the cancellation answers the next task
+	go func() { _, _ = writer.Write([]byte("late answer\n")) }()
+	resultsChan, err = executor.Execute(context.Background(), NewRequestUserInputTask("test-next", "", RequestUserInputParameters{Prompt: "Name?"}))
+	require.NoError(t, err)
+	_, finalResult = collectUserInputResults(t, resultsChan)
+	assert.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+	assert.Equal(t, "late answer", finalResult.ResultData)
+}
+
 func TestRequestUserInputExecutor_Execute_TerminalTaskHandling(t *testing.T) {
 	executor := NewRequestUserInputExecutor()
 
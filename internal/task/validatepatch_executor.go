@@ -0,0 +1,233 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sourcegraph/go-diff/diff"
+)
+
+// Error constants for ValidatePatchExecutor
+const (
+	// Command validation errors
+	errValidatePatchInvalidCommandType = "invalid command type for ValidatePatchExecutor: %T"
+	errValidatePatchEmptyPatch         = "patch cannot be empty"
+
+	// Parse errors
+	errValidatePatchNoFiles       = "no file diffs found"
+	errValidatePatchHunkLineCount = "hunk expects %d original and %d new lines, found %d and %d"
+
+	// Status messages
+	msgValidatePatchCancelled = "Patch validation cancelled."
+	msgValidatePatchTimedOut  = "Patch validation timed out."
+	msgValidatePatchFailed    = "Patch validation failed: %v"
+	msgValidatePatchSucceeded = "Patch is valid: %d file(s), %d hunk(s)."
+)
+
+// formatPatch names unified diffs in ParseError.
+const formatPatch = "patch"
+
+// PatchFileSummary describes one file touched by a validated patch.
+type PatchFileSummary struct {
+	// Path is the file the patch applies to, without the a/ or b/ prefix.
+	// It is the original name for deletions and the new name otherwise.
+	Path string `json:"path"`
+	// OrigName and NewName are the names from the ---/+++ headers; /dev/null
+	// marks a created or deleted file.
+	OrigName string `json:"orig_name"`
+	NewName  string `json:"new_name"`
+	// Hunks is the number of hunks for the file.
+	Hunks int `json:"hunks"`
+}
+
+// ValidatePatchExecutor handles the execution of ValidatePatchTask.
+// It checks that a unified diff parses without needing the files it targets.
+type ValidatePatchExecutor struct {
+	config ExecutorConfig
+}
+
+var _ TaskExecutor = (*ValidatePatchExecutor)(nil)
+
+// NewValidatePatchExecutor creates a new ValidatePatchExecutor.
+func NewValidatePatchExecutor() *ValidatePatchExecutor {
+	return NewValidatePatchExecutorWithConfig(ExecutorConfig{})
+}
+
+// NewValidatePatchExecutorWithConfig creates a new ValidatePatchExecutor using the shared executor config.
+func NewValidatePatchExecutorWithConfig(cfg ExecutorConfig) *ValidatePatchExecutor {
+	return &ValidatePatchExecutor{config: cfg}
+}
+
+// Execute implements the TaskExecutor interface for ValidatePatchTask.
+// On success Payload holds a []PatchFileSummary and ResultData lists each file with its
+// hunk count. An invalid patch fails with a *ParseError giving the offending patch line.
+func (e *ValidatePatchExecutor) Execute(ctx context.Context, validateCmd *Task) (<-chan OutputResult, error) {
+	if validateCmd.Type != TaskValidatePatch {
+		return nil, fmt.Errorf(errValidatePatchInvalidCommandType, validateCmd)
+	}
+
+	// Check if task is already in a terminal state
+	terminalChan, err := HandleTerminalTask(validateCmd.TaskId, validateCmd.Status, validateCmd.Output)
+	if err != nil || terminalChan != nil {
+		return terminalChan, err
+	}
+
+	params := validateCmd.Parameters.(ValidatePatchParameters)
+	if strings.TrimSpace(params.Patch) == "" {
+		return nil, errors.New(errValidatePatchEmptyPatch)
+	}
+
+	results := make(chan OutputResult, 1)
+	go func() {
+		defer close(results)
+
+		ctx, cancel := e.config.withTimeout(ctx, validateCmd)
+		defer cancel()
+
+		startedAt := e.config.clock().Now()
+		validateCmd.Status = StatusRunning
+		files, err := validatePatch(ctx, []byte(params.Patch))
+
+		finalResult := createValidatePatchResult(validateCmd.TaskId, files, err)
+		validateCmd.Status = finalResult.Status
+		finalResult.setTimes(e.config.clock(), startedAt)
+		validateCmd.UpdateOutput(&finalResult)
+		e.config.send(ctx, results, finalResult)
+	}()
+
+	return results, nil
+}
+
+// validatePatch parses patch and summarizes the files it touches.
+//
+// go-diff stops reading a file's hunks at the first line that is not part of a hunk
+// and silently ignores the rest, so each hunk body is also checked against the line
+// counts in its header. This catches truncated hunks and stray lines inside a hunk.
+func validatePatch(ctx context.Context, patch []byte) ([]PatchFileSummary, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	fileDiffs, err := diff.ParseMultiFileDiff(patch)
+	if err != nil {
+		var diffErr *diff.ParseError
+		if errors.As(err, &diffErr) {
+			return nil, &ParseError{Format: formatPatch, Line: diffErr.Line, Err: diffErr.Err}
+		}
+		return nil, &ParseError{Format: formatPatch, Err: err}
+	}
+	if len(fileDiffs) == 0 {
+		return nil, &ParseError{Format: formatPatch, Err: errors.New(errValidatePatchNoFiles)}
+	}
+
+	headerLines := hunkHeaderLines(patch)
+	files := make([]PatchFileSummary, 0, len(fileDiffs))
+	hunkIndex := 0
+	for _, fileDiff := range fileDiffs {
+		for _, hunk := range fileDiff.Hunks {
+			origLines, newLines, bodyLines := countHunkLines(hunk.Body)
+			if origLines != int(hunk.OrigLines) || newLines != int(hunk.NewLines) {
+				parseErr := &ParseError{
+					Format: formatPatch,
+					Err:    fmt.Errorf(errValidatePatchHunkLineCount, hunk.OrigLines, hunk.NewLines, origLines, newLines),
+				}
+				// The first line that does not belong to the hunk is the one after its body
+				if hunkIndex < len(headerLines) {
+					parseErr.Line = headerLines[hunkIndex] + bodyLines + 1
+				}
+				return nil, parseErr
+			}
+			hunkIndex++
+		}
+
+		path := fileDiff.NewName
+		if path == "/dev/null" {
+			path = fileDiff.OrigName
+		}
+		files = append(files, PatchFileSummary{
+			Path:     stripDiffPrefix(path),
+			OrigName: fileDiff.OrigName,
+			NewName:  fileDiff.NewName,
+			Hunks:    len(fileDiff.Hunks),
+		})
+	}
+	return files, nil
+}
+
+// hunkHeaderLines returns the 1-based line numbers of the hunk headers in patch.
+func hunkHeaderLines(patch []byte) []int {
+	var lines []int
+	for i, line := range bytes.Split(patch, []byte("\n")) {
+		if bytes.HasPrefix(line, []byte("@@ ")) {
+			lines = append(lines, i+1)
+		}
+	}
+	return lines
+}
+
+// countHunkLines counts the original and new file lines in a hunk body,
+// along with the number of body lines it spans.
+func countHunkLines(body []byte) (origLines, newLines, bodyLines int) {
+	for _, line := range bytes.Split(bytes.TrimSuffix(body, []byte("\n")), []byte("\n")) {
+		bodyLines++
+		switch {
+		case len(line) == 0, line[0] == ' ':
+			// Some tools strip the space from empty context lines
+			origLines++
+			newLines++
+		case line[0] == '-':
+			origLines++
+		case line[0] == '+':
+			newLines++
+		}
+	}
+	return origLines, newLines, bodyLines
+}
+
+// stripDiffPrefix removes the a/ or b/ prefix git adds to file names in a diff.
+func stripDiffPrefix(name string) string {
+	if strings.HasPrefix(name, "a/") || strings.HasPrefix(name, "b/") {
+		return name[2:]
+	}
+	return name
+}
+
+// createValidatePatchResult constructs the final OutputResult for a ValidatePatchTask.
+func createValidatePatchResult(taskID string, files []PatchFileSummary, err error) OutputResult {
+	if err == nil {
+		var listing strings.Builder
+		hunks := 0
+		for _, file := range files {
+			fmt.Fprintf(&listing, "%s: %d hunk(s)\n", file.Path, file.Hunks)
+			hunks += file.Hunks
+		}
+		return OutputResult{
+			TaskID:     taskID,
+			Status:     StatusSucceeded,
+			Message:    fmt.Sprintf(msgValidatePatchSucceeded, len(files), hunks),
+			ResultData: listing.String(),
+			Payload:    files,
+		}
+	}
+
+	var message string
+	switch {
+	case errors.Is(err, context.Canceled):
+		message = msgValidatePatchCancelled
+	case errors.Is(err, context.DeadlineExceeded):
+		message = msgValidatePatchTimedOut
+	default:
+		message = fmt.Sprintf(msgValidatePatchFailed, err)
+	}
+	return OutputResult{
+		TaskID:      taskID,
+		Status:      StatusFailed,
+		Message:     message,
+		Error:       err.Error(),
+		Err:         err,
+		FailureKind: failureKind(err),
+	}
+}
@@ -0,0 +1,106 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runValidatePatch(t *testing.T, patch string) OutputResult {
+	t.Helper()
+	cmd := NewValidatePatchTask("validate", "Validate patch", ValidatePatchParameters{Patch: patch})
+	resultsChan, err := NewValidatePatchExecutor().Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+	assert.Equal(t, finalResult.Status, cmd.Status)
+	return finalResult
+}
+
+func TestValidatePatchExecutor_Execute_SingleFile(t *testing.T) {
+	finalResult := runValidatePatch(t, "--- a/main.go\n+++ b/main.go\n"+
+		"@@ -1,3 +1,3 @@\n package main\n-var x = 1\n+var x = 2\n \n"+
+		"@@ -10,2 +10,3 @@\n func f() {\n+\treturn\n }\n")
+	require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+	assert.Equal(t, "Patch is valid: 1 file(s), 2 hunk(s).", finalResult.Message)
+	assert.Equal(t, "main.go: 2 hunk(s)\n", finalResult.ResultData)
+	assert.Equal(t, []PatchFileSummary{
+		{Path: "main.go", OrigName: "a/main.go", NewName: "b/main.go", Hunks: 2},
+	}, finalResult.Payload)
+}
+
+func TestValidatePatchExecutor_Execute_MultiFile(t *testing.T) {
+	finalResult := runValidatePatch(t, "--- a/one.txt\n+++ b/one.txt\n@@ -1 +1 @@\n-a\n+b\n"+
+		"--- /dev/null\n+++ b/two.txt\n@@ -0,0 +1,2 @@\n+new\n+file\n"+
+		"--- a/three.txt\n+++ /dev/null\n@@ -1 +0,0 @@\n-gone\n")
+	require.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+	assert.Equal(t, []PatchFileSummary{
+		{Path: "one.txt", OrigName: "a/one.txt", NewName: "b/one.txt", Hunks: 1},
+		{Path: "two.txt", OrigName: "/dev/null", NewName: "b/two.txt", Hunks: 1},
+		{Path: "three.txt", OrigName: "a/three.txt", NewName: "/dev/null", Hunks: 1},
+	}, finalResult.Payload)
+	assert.Equal(t, "one.txt: 1 hunk(s)\ntwo.txt: 1 hunk(s)\nthree.txt: 1 hunk(s)\n", finalResult.ResultData)
+}
+
+func TestValidatePatchExecutor_Execute_Malformed(t *testing.T) {
+	testCases := []struct {
+		name         string
+		patch        string
+		expectedLine int
+		errContains  string
+	}{
+		{
+			name:         "Bad hunk header",
+			patch:        "--- a/x.txt\n+++ b/x.txt\n@@ -1,2 @@\n one\n",
+			expectedLine: 3,
+			errContains:  "bad hunk header",
+		},
+		{
+			name:         "Stray line inside hunk",
+			patch:        "--- a/x.txt\n+++ b/x.txt\n@@ -1,2 +1,2 @@\n one\n*two\n+three\n",
+			expectedLine: 5,
+			errContains:  "hunk expects 2 original and 2 new lines, found 1 and 1",
+		},
+		{
+			name: "Truncated hunk in second file",
+			patch: "--- a/x.txt\n+++ b/x.txt\n@@ -1 +1 @@\n-a\n+b\n" +
+				"--- a/y.txt\n+++ b/y.txt\n@@ -1,3 +1,3 @@\n one\n-two\n+2\n",
+			expectedLine: 12,
+			errContains:  "found 2 and 2",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			finalResult := runValidatePatch(t, tc.patch)
+			assert.Equal(t, StatusFailed, finalResult.Status)
+			assert.Equal(t, FailureExecutionError, finalResult.FailureKind)
+			assert.Contains(t, finalResult.Error, tc.errContains)
+
+			var parseErr *ParseError
+			require.True(t, errors.As(finalResult.Err, &parseErr), "Err should be a *ParseError, got %T", finalResult.Err)
+			assert.Equal(t, "patch", parseErr.Format)
+			assert.Equal(t, tc.expectedLine, parseErr.Line)
+		})
+	}
+
+	// Text without any file headers is not a patch
+	finalResult := runValidatePatch(t, "just some text\n")
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Contains(t, finalResult.Error, "no file diffs found")
+}
+
+func TestValidatePatchExecutor_Execute_InvalidParameters(t *testing.T) {
+	executor := NewValidatePatchExecutor()
+
+	_, err := executor.Execute(context.Background(), NewValidatePatchTask("empty", "No patch", ValidatePatchParameters{Patch: " \n"}))
+	assert.ErrorContains(t, err, "patch cannot be empty")
+
+	_, err = executor.Execute(context.Background(), NewEvalTask("wrong", "Wrong type", EvalParameters{Expression: "true"}))
+	assert.Error(t, err)
+}
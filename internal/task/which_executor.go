@@ -0,0 +1,136 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Error constants for WhichExecutor
+const (
+	// Command validation errors
+	errWhichInvalidCommandType = "invalid command type for WhichExecutor: %T"
+	errWhichNoName             = "no executable name provided for WHICH"
+
+	// Lookup errors
+	errWhichNotFound      = "executable '%s' not found in PATH"
+	errWhichNotExecutable = "'%s' is not an executable file"
+
+	// Status messages
+	msgWhichFailed    = "Executable lookup failed: %v"
+	msgWhichSucceeded = "Found '%s' at %s."
+)
+
+// WhichExecutor handles the execution of WhichTask.
+// It resolves an executable name the same way the shell would, using the PATH
+// from the task's Env or, if unset there, from the agent's environment.
+type WhichExecutor struct {
+	config ExecutorConfig
+}
+
+var _ TaskExecutor = (*WhichExecutor)(nil)
+
+// NewWhichExecutor creates a new WhichExecutor.
+func NewWhichExecutor() *WhichExecutor {
+	return &WhichExecutor{}
+}
+
+// NewWhichExecutorWithConfig creates a new WhichExecutor using the shared executor config.
+func NewWhichExecutorWithConfig(cfg ExecutorConfig) *WhichExecutor {
+	return &WhichExecutor{config: cfg}
+}
+
+// Execute implements the TaskExecutor interface for WhichTask.
+// The final result's ResultData holds the absolute path of the executable.
+func (e *WhichExecutor) Execute(ctx context.Context, whichCmd *Task) (<-chan OutputResult, error) {
+	if whichCmd.Type != TaskWhich {
+		return nil, fmt.Errorf(errWhichInvalidCommandType, whichCmd)
+	}
+
+	// Check if task is already in a terminal state
+	terminalChan, err := HandleTerminalTask(whichCmd.TaskId, whichCmd.Status, whichCmd.Output)
+	if err != nil || terminalChan != nil {
+		return terminalChan, err
+	}
+
+	if whichCmd.Parameters.(WhichParameters).Name == "" {
+		return nil, errors.New(errWhichNoName)
+	}
+
+	results := make(chan OutputResult, 1)
+	go func() {
+		defer close(results)
+
+		startedAt := e.config.clock().Now()
+		whichCmd.Status = StatusRunning
+		params := whichCmd.Parameters.(WhichParameters)
+		path, err := lookPath(params.Name, getEnv(params.Env, "PATH"), params.WorkingDirectory)
+
+		finalResult := createWhichResult(whichCmd.TaskId, params.Name, path, err)
+		whichCmd.Status = finalResult.Status
+		finalResult.setTimes(e.config.clock(), startedAt)
+		whichCmd.UpdateOutput(&finalResult)
+		e.config.send(ctx, results, finalResult)
+	}()
+
+	return results, nil
+}
+
+// lookPath searches pathList for an executable named name and returns its absolute path.
+// Names containing a path separator are not searched for but checked directly,
+// relative to workingDir. Like exec.LookPath, relative PATH entries are ignored.
+func lookPath(name, pathList, workingDir string) (string, error) {
+	if strings.Contains(name, string(filepath.Separator)) {
+		path := name
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(workingDir, path)
+		}
+		path, err := filepath.Abs(path)
+		if err != nil {
+			return "", err
+		}
+		if !isExecutable(path) {
+			return "", fmt.Errorf(errWhichNotExecutable, path)
+		}
+		return path, nil
+	}
+
+	for _, dir := range filepath.SplitList(pathList) {
+		if !filepath.IsAbs(dir) {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if isExecutable(path) {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf(errWhichNotFound, name)
+}
+
+// isExecutable reports whether path is a regular file with any execute bit set.
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular() && info.Mode().Perm()&0111 != 0
+}
+
+// createWhichResult constructs the final OutputResult for a WhichTask.
+func createWhichResult(taskID, name, path string, err error) OutputResult {
+	if err != nil {
+		return OutputResult{
+			TaskID:      taskID,
+			Status:      StatusFailed,
+			Message:     fmt.Sprintf(msgWhichFailed, err),
+			Error:       err.Error(),
+			FailureKind: failureKind(err),
+		}
+	}
+	return OutputResult{
+		TaskID:     taskID,
+		Status:     StatusSucceeded,
+		Message:    fmt.Sprintf(msgWhichSucceeded, name, path),
+		ResultData: path,
+	}
+}
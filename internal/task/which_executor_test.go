@@ -0,0 +1,91 @@
+package task
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWhichExecutor_Execute_ExistingBinary(t *testing.T) {
+	expected, err := exec.LookPath("ls")
+	require.NoError(t, err, "ls must be available to run this test")
+
+	cmd := NewWhichTask("which-ls", "Locate ls", WhichParameters{Name: "ls"})
+	resultsChan, err := NewWhichExecutor().Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+	assert.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+	assert.Equal(t, expected, finalResult.ResultData)
+	assert.True(t, filepath.IsAbs(finalResult.ResultData))
+	assert.Equal(t, StatusSucceeded, cmd.Status)
+}
+
+func TestWhichExecutor_Execute_NotFound(t *testing.T) {
+	cmd := NewWhichTask("which-missing", "Locate a missing binary", WhichParameters{Name: "definitely-not-a-real-binary-xyz"})
+	resultsChan, err := NewWhichExecutor().Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Contains(t, finalResult.Error, "not found in PATH")
+	assert.Empty(t, finalResult.ResultData)
+}
+
+func TestWhichExecutor_Execute_UsesTaskEnv(t *testing.T) {
+	binDir := t.TempDir()
+	toolPath := filepath.Join(binDir, "my-tool")
+	require.NoError(t, os.WriteFile(toolPath, []byte("#!/bin/sh\n"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "not-executable"), []byte("data"), 0644))
+
+	testCases := []struct {
+		name           string
+		binary         string
+		env            map[string]string
+		expectedStatus TaskStatus
+		expectedPath   string
+	}{
+		{"found via task PATH", "my-tool", map[string]string{"PATH": "relative/dir" + string(os.PathListSeparator) + binDir}, StatusSucceeded, toolPath},
+		{"not in agent PATH", "my-tool", nil, StatusFailed, ""},
+		{"ignores non-executable files", "not-executable", map[string]string{"PATH": binDir}, StatusFailed, ""},
+		{"path names are checked directly", toolPath, map[string]string{"PATH": ""}, StatusSucceeded, toolPath},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := NewWhichTask("which-env", tc.name, WhichParameters{
+				BaseParameters: BaseParameters{Env: tc.env},
+				Name:           tc.binary,
+			})
+			resultsChan, err := NewWhichExecutor().Execute(context.Background(), cmd)
+			require.NoError(t, err)
+
+			finalResult, received := readFinalResult(t, resultsChan, 5*time.Second)
+			require.True(t, received, "Did not receive final result")
+			assert.Equal(t, tc.expectedStatus, finalResult.Status, finalResult.Error)
+			assert.Equal(t, tc.expectedPath, finalResult.ResultData)
+		})
+	}
+}
+
+func TestWhichExecutor_Execute_EmptyName(t *testing.T) {
+	cmd := NewWhichTask("which-empty", "No name", WhichParameters{})
+	resultsChan, err := NewWhichExecutor().Execute(context.Background(), cmd)
+	assert.Error(t, err)
+	assert.Nil(t, resultsChan)
+}
+
+func TestWhichExecutor_Execute_InvalidCommandType(t *testing.T) {
+	cmd := NewFileReadTask("which-invalid", "Wrong type", FileReadParameters{FilePath: "x"})
+	resultsChan, err := NewWhichExecutor().Execute(context.Background(), cmd)
+	assert.Error(t, err)
+	assert.Nil(t, resultsChan)
+}
@@ -0,0 +1,299 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Error constants for WriteFilesExecutor
+const (
+	// Command validation errors
+	errWriteFilesInvalidCommandType = "invalid command type for WriteFilesExecutor: %T"
+	errWriteFilesNoFiles            = "no files provided for WRITE_FILES"
+
+	// File operation errors
+	errWriteFilesResolvePath   = "failed to resolve path for entry %d: %w"
+	errWriteFilesStageFailed   = "failed to stage file '%s': %w"
+	errWriteFilesCommitFailed  = "failed to commit file '%s': %w"
+	errWriteFilesSomeFailed    = "%d of %d files failed to write"
+	errWriteFilesTransactional = "transactional write aborted, no files were written: %w"
+
+	// Status messages
+	msgWriteFilesCancelled   = "Batch file writing cancelled."
+	msgWriteFilesTimedOut    = "Batch file writing timed out."
+	msgWriteFilesFailed      = "Batch file writing failed: %v"
+	msgWriteFilesSucceeded   = "Wrote %d files in %v."
+	msgWriteFilesEntryOK     = "OK %s\n"
+	msgWriteFilesEntryFailed = "FAILED %s: %v\n"
+)
+
+// WriteFilesExecutor handles the execution of WriteFilesTask.
+// It writes several files in one invocation, optionally as an all-or-nothing transaction.
+type WriteFilesExecutor struct {
+	config ExecutorConfig
+	writer *FileWriteExecutor
+}
+
+var (
+	_ TaskExecutor = (*WriteFilesExecutor)(nil)
+	_ Compensator  = (*WriteFilesExecutor)(nil)
+)
+
+// NewWriteFilesExecutor creates a new WriteFilesExecutor.
+func NewWriteFilesExecutor() *WriteFilesExecutor {
+	return NewWriteFilesExecutorWithConfig(ExecutorConfig{})
+}
+
+// NewWriteFilesExecutorWithConfig creates a new WriteFilesExecutor using the shared executor config.
+func NewWriteFilesExecutorWithConfig(cfg ExecutorConfig) *WriteFilesExecutor {
+	return &WriteFilesExecutor{
+		config: cfg,
+		writer: NewFileWriteExecutorWithConfig(cfg),
+	}
+}
+
+// Execute implements the TaskExecutor interface for WriteFilesTask.
+// Each file outcome is streamed as a RUNNING result whose ResultData is a single
+// "OK <path>" or "FAILED <path>: <error>" line.
+func (e *WriteFilesExecutor) Execute(ctx context.Context, writeCmd *Task) (<-chan OutputResult, error) {
+	if writeCmd.Type != TaskWriteFiles {
+		return nil, fmt.Errorf(errWriteFilesInvalidCommandType, writeCmd)
+	}
+
+	// Check if task is already in a terminal state
+	terminalChan, err := HandleTerminalTask(writeCmd.TaskId, writeCmd.Status, writeCmd.Output)
+	if err != nil || terminalChan != nil {
+		return terminalChan, err
+	}
+
+	if len(writeCmd.Parameters.(WriteFilesParameters).Files) == 0 {
+		return nil, errors.New(errWriteFilesNoFiles)
+	}
+
+	results := make(chan OutputResult, 1)
+	go func() {
+		defer close(results)
+
+		ctx, cancel := e.config.withTimeout(ctx, writeCmd)
+		defer cancel()
+
+		writeCmd.Status = StatusRunning
+		startTime := e.config.clock().Now()
+
+		params := writeCmd.Parameters.(WriteFilesParameters)
+		var written int
+		var err error
+		if params.Transactional {
+			written, err = e.writeTransactional(ctx, writeCmd, params, results)
+		} else {
+			written, err = e.writeEach(ctx, writeCmd, params, results)
+		}
+
+		finalResult := createWriteFilesResult(writeCmd.TaskId, written, err, e.config.since(startTime))
+		writeCmd.Status = finalResult.Status
+		finalResult.setTimes(e.config.clock(), startTime)
+		writeCmd.UpdateOutput(&finalResult)
+		e.config.send(ctx, results, finalResult)
+	}()
+
+	return results, nil
+}
+
+// writeEach writes every entry independently, continuing past individual failures.
+func (e *WriteFilesExecutor) writeEach(ctx context.Context, writeCmd *Task, params WriteFilesParameters, results chan<- OutputResult) (int, error) {
+	written := 0
+	for i, entry := range params.Files {
+		if err := ctx.Err(); err != nil {
+			return written, err
+		}
+
+		filePath, err := e.config.resolvePath(entry.Path, params.WorkingDirectory)
+		if err != nil {
+			err = fmt.Errorf(errWriteFilesResolvePath, i, err)
+			e.sendWriteFilesEntry(ctx, results, writeCmd, entry.Path, err)
+			continue
+		}
+
+		err = e.writer.writeFileContent(ctx, filePath, entry.Content, false, true, nil)
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return written, err
+		}
+		e.sendWriteFilesEntry(ctx, results, writeCmd, filePath, err)
+		if err == nil {
+			written++
+		}
+	}
+
+	if written != len(params.Files) {
+		return written, fmt.Errorf(errWriteFilesSomeFailed, len(params.Files)-written, len(params.Files))
+	}
+	return written, nil
+}
+
+// writeTransactional stages every entry in a temporary file next to its destination
+// and only renames them into place once all of them were staged successfully.
+// On any failure the staged files are removed and no destination is modified.
+func (e *WriteFilesExecutor) writeTransactional(ctx context.Context, writeCmd *Task, params WriteFilesParameters, results chan<- OutputResult) (int, error) {
+	type stagedFile struct {
+		tempPath string
+		destPath string
+	}
+	var staged []stagedFile
+	cleanup := func() {
+		for _, s := range staged {
+			os.Remove(s.tempPath)
+		}
+	}
+
+	for i, entry := range params.Files {
+		if err := ctx.Err(); err != nil {
+			cleanup()
+			return 0, err
+		}
+
+		destPath, err := e.config.resolvePath(entry.Path, params.WorkingDirectory)
+		if err != nil {
+			cleanup()
+			err = fmt.Errorf(errWriteFilesResolvePath, i, err)
+			e.sendWriteFilesEntry(ctx, results, writeCmd, entry.Path, err)
+			return 0, fmt.Errorf(errWriteFilesTransactional, err)
+		}
+
+		tempPath, err := stageFile(destPath, entry.Content, e.config.fileMode())
+		if err != nil {
+			cleanup()
+			e.sendWriteFilesEntry(ctx, results, writeCmd, destPath, err)
+			return 0, fmt.Errorf(errWriteFilesTransactional, err)
+		}
+		staged = append(staged, stagedFile{tempPath: tempPath, destPath: destPath})
+	}
+
+	// Lock in path order so that concurrent batches cannot deadlock; a path listed
+	// twice is locked once
+	lockPaths := make([]string, 0, len(staged))
+	for _, s := range staged {
+		lockPaths = append(lockPaths, filepath.Clean(s.destPath))
+	}
+	sort.Strings(lockPaths)
+	for i, path := range lockPaths {
+		if i > 0 && path == lockPaths[i-1] {
+			continue
+		}
+		unlock, err := e.writer.fs.LockFile(path)
+		if err != nil {
+			cleanup()
+			return 0, fmt.Errorf(errWriteFilesTransactional, err)
+		}
+		defer unlock()
+	}
+
+	if err := ctx.Err(); err != nil {
+		cleanup()
+		return 0, err
+	}
+
+	for i, s := range staged {
+		if err := os.Rename(s.tempPath, s.destPath); err != nil {
+			// Files renamed so far cannot be restored; report how far we got
+			for _, remaining := range staged[i:] {
+				os.Remove(remaining.tempPath)
+			}
+			err = fmt.Errorf(errWriteFilesCommitFailed, s.destPath, err)
+			e.sendWriteFilesEntry(ctx, results, writeCmd, s.destPath, err)
+			return i, err
+		}
+		e.sendWriteFilesEntry(ctx, results, writeCmd, s.destPath, nil)
+	}
+	return len(staged), nil
+}
+
+// PrepareCompensation implements Compensator by backing up every target file
+// so that a rollback restores the state before the batch was written.
+func (e *WriteFilesExecutor) PrepareCompensation(ctx context.Context, writeCmd *Task) (Compensation, error) {
+	params := writeCmd.Parameters.(WriteFilesParameters)
+	compensations := make([]Compensation, 0, len(params.Files))
+	for i, entry := range params.Files {
+		filePath, err := e.config.resolvePath(entry.Path, params.WorkingDirectory)
+		if err != nil {
+			return nil, fmt.Errorf(errWriteFilesResolvePath, i, err)
+		}
+		compensation, err := fileBackupCompensation(filePath)
+		if err != nil {
+			return nil, err
+		}
+		compensations = append(compensations, compensation)
+	}
+	return combineCompensations(compensations), nil
+}
+
+// stageFile writes content to a temporary file in the destination's directory
+// so that a later rename is atomic. The staged file is given the provided mode.
+func stageFile(destPath, content string, mode os.FileMode) (string, error) {
+	tempFile, err := os.CreateTemp(filepath.Dir(destPath), "."+filepath.Base(destPath)+".tmp-*")
+	if err != nil {
+		return "", fmt.Errorf(errWriteFilesStageFailed, destPath, err)
+	}
+	tempPath := tempFile.Name()
+
+	if _, err := tempFile.WriteString(content); err != nil {
+		tempFile.Close()
+		os.Remove(tempPath)
+		return "", fmt.Errorf(errWriteFilesStageFailed, destPath, err)
+	}
+	if err := tempFile.Chmod(mode); err != nil {
+		tempFile.Close()
+		os.Remove(tempPath)
+		return "", fmt.Errorf(errWriteFilesStageFailed, destPath, err)
+	}
+	if err := tempFile.Close(); err != nil {
+		os.Remove(tempPath)
+		return "", fmt.Errorf(errWriteFilesStageFailed, destPath, err)
+	}
+	return tempPath, nil
+}
+
+// sendWriteFilesEntry streams the outcome of a single file write.
+func (e *WriteFilesExecutor) sendWriteFilesEntry(ctx context.Context, results chan<- OutputResult, writeCmd *Task, path string, err error) {
+	data := fmt.Sprintf(msgWriteFilesEntryOK, path)
+	if err != nil {
+		data = fmt.Sprintf(msgWriteFilesEntryFailed, path, err)
+	}
+	e.config.send(ctx, results, writeCmd.describe(OutputResult{
+		TaskID:     writeCmd.TaskId,
+		Status:     StatusRunning,
+		ResultData: data,
+	}))
+}
+
+// createWriteFilesResult constructs the final OutputResult for a WriteFilesTask.
+func createWriteFilesResult(taskID string, written int, err error, duration time.Duration) OutputResult {
+	if err == nil {
+		return OutputResult{
+			TaskID:  taskID,
+			Status:  StatusSucceeded,
+			Message: fmt.Sprintf(msgWriteFilesSucceeded, written, duration.Round(time.Millisecond)),
+		}
+	}
+
+	var message string
+	switch {
+	case errors.Is(err, context.Canceled):
+		message = msgWriteFilesCancelled
+	case errors.Is(err, context.DeadlineExceeded):
+		message = msgWriteFilesTimedOut
+	default:
+		message = fmt.Sprintf(msgWriteFilesFailed, err)
+	}
+	return OutputResult{
+		TaskID:      taskID,
+		Status:      StatusFailed,
+		Message:     message,
+		Error:       err.Error(),
+		FailureKind: failureKind(err),
+	}
+}
@@ -0,0 +1,136 @@
+package task
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteFilesExecutor_Execute_AllSucceed(t *testing.T) {
+	executor := NewWriteFilesExecutor()
+	tempDir := t.TempDir()
+
+	cmd := NewWriteFilesTask("write-files-ok", "Write several files", WriteFilesParameters{
+		BaseParameters: BaseParameters{WorkingDirectory: tempDir},
+		Files: []FileWriteEntry{
+			{Path: "a.txt", Content: "alpha"},
+			{Path: "b.txt", Content: "beta\n"},
+			{Path: filepath.Join(tempDir, "c.txt"), Content: ""},
+		},
+	})
+
+	resultsChan, err := executor.Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	finalResult, output, received := collectStreamingResults(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+	assert.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+	assert.Contains(t, finalResult.Message, "Wrote 3 files")
+	assert.Equal(t, 3, strings.Count(output, "OK "), "Expected one OK line per file, got %q", output)
+
+	for name, expected := range map[string]string{"a.txt": "alpha", "b.txt": "beta\n", "c.txt": ""} {
+		content, err := os.ReadFile(filepath.Join(tempDir, name))
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(content))
+	}
+}
+
+func TestWriteFilesExecutor_Execute_PartialFailure(t *testing.T) {
+	executor := NewWriteFilesExecutor()
+	tempDir := t.TempDir()
+
+	cmd := NewWriteFilesTask("write-files-partial", "Write with one bad path", WriteFilesParameters{
+		Files: []FileWriteEntry{
+			{Path: filepath.Join(tempDir, "good.txt"), Content: "good"},
+			{Path: filepath.Join(tempDir, "missing", "bad.txt"), Content: "bad"},
+		},
+	})
+
+	resultsChan, err := executor.Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	finalResult, output, received := collectStreamingResults(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Contains(t, finalResult.Error, "1 of 2 files failed")
+	assert.Contains(t, output, "OK "+filepath.Join(tempDir, "good.txt"))
+	assert.Contains(t, output, "FAILED "+filepath.Join(tempDir, "missing", "bad.txt"))
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "good.txt"))
+	require.NoError(t, err, "Non-transactional batch should keep successful writes")
+	assert.Equal(t, "good", string(content))
+}
+
+func TestWriteFilesExecutor_Execute_TransactionalRollback(t *testing.T) {
+	executor := NewWriteFilesExecutor()
+	tempDir := t.TempDir()
+	existingPath := filepath.Join(tempDir, "existing.txt")
+	require.NoError(t, os.WriteFile(existingPath, []byte("original"), 0644))
+
+	cmd := NewWriteFilesTask("write-files-tx", "Transactional batch with invalid path", WriteFilesParameters{
+		Files: []FileWriteEntry{
+			{Path: filepath.Join(tempDir, "new.txt"), Content: "new"},
+			{Path: existingPath, Content: "changed"},
+			{Path: filepath.Join(tempDir, "missing", "bad.txt"), Content: "bad"},
+		},
+		Transactional: true,
+	})
+
+	resultsChan, err := executor.Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	finalResult, _, received := collectStreamingResults(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+	assert.Equal(t, StatusFailed, finalResult.Status)
+	assert.Contains(t, finalResult.Error, "no files were written")
+
+	_, statErr := os.Stat(filepath.Join(tempDir, "new.txt"))
+	assert.True(t, os.IsNotExist(statErr), "New file should not have been written")
+	content, err := os.ReadFile(existingPath)
+	require.NoError(t, err)
+	assert.Equal(t, "original", string(content), "Existing file should be untouched")
+
+	entries, err := os.ReadDir(tempDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "Staged temporary files should be cleaned up")
+}
+
+func TestWriteFilesExecutor_Execute_TransactionalSuccess(t *testing.T) {
+	executor := NewWriteFilesExecutor()
+	tempDir := t.TempDir()
+
+	cmd := NewWriteFilesTask("write-files-tx-ok", "Transactional batch", WriteFilesParameters{
+		BaseParameters: BaseParameters{WorkingDirectory: tempDir},
+		Files: []FileWriteEntry{
+			{Path: "one.txt", Content: "1"},
+			{Path: "two.txt", Content: "2"},
+		},
+		Transactional: true,
+	})
+
+	resultsChan, err := executor.Execute(context.Background(), cmd)
+	require.NoError(t, err)
+
+	finalResult, _, received := collectStreamingResults(t, resultsChan, 5*time.Second)
+	require.True(t, received, "Did not receive final result")
+	assert.Equal(t, StatusSucceeded, finalResult.Status, finalResult.Error)
+
+	entries, err := os.ReadDir(tempDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "Only the destination files should remain")
+}
+
+func TestWriteFilesExecutor_Execute_NoFiles(t *testing.T) {
+	executor := NewWriteFilesExecutor()
+	cmd := NewWriteFilesTask("write-files-empty", "Empty batch", WriteFilesParameters{})
+
+	resultsChan, err := executor.Execute(context.Background(), cmd)
+	assert.Error(t, err)
+	assert.Nil(t, resultsChan)
+}